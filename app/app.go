@@ -18,6 +18,7 @@ import (
 	"github.com/kaspanet/kaspad/util/panics"
 	"github.com/kaspanet/kaspad/util/profiling"
 	"github.com/kaspanet/kaspad/version"
+	"github.com/pkg/errors"
 )
 
 const leveldbCacheSizeMiB = 256
@@ -104,6 +105,30 @@ func (app *kaspadApp) main(startedChan chan<- struct{}) error {
 		}
 	}
 
+	if app.cfg.Reindex {
+		err := reindexDatabase(app.cfg, interrupt)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+
+		// Return now if an interrupt signal was triggered.
+		if signal.InterruptRequested(interrupt) {
+			return nil
+		}
+	} else {
+		wasReindexInterrupted, err := isReindexInProgress(app.cfg)
+		if err != nil {
+			log.Error(err)
+			return err
+		}
+		if wasReindexInterrupted {
+			err := errors.New("a previous reindex was interrupted. Restart with --reindex to resume it")
+			log.Error(err)
+			return err
+		}
+	}
+
 	// Open the database
 	databaseContext, err := openDB(app.cfg)
 	if err != nil {
@@ -63,6 +63,7 @@ const (
 	CmdBlockHeaders
 	CmdRequestNextPruningPointUTXOSetChunk
 	CmdDonePruningPointUTXOSetChunks
+	CmdDandelionTx
 
 	// rpc
 	CmdGetCurrentNetworkRequestMessage
@@ -137,6 +138,30 @@ const (
 	CmdPruningPointUTXOSetOverrideNotificationMessage
 	CmdStopNotifyingPruningPointUTXOSetOverrideRequestMessage
 	CmdStopNotifyingPruningPointUTXOSetOverrideResponseMessage
+	CmdGetDAGSubgraphRequestMessage
+	CmdGetDAGSubgraphResponseMessage
+	CmdGetBalanceByAddressRequestMessage
+	CmdGetBalanceByAddressResponseMessage
+	CmdGetBalancesByAddressesRequestMessage
+	CmdGetBalancesByAddressesResponseMessage
+	CmdDumpMempoolRequestMessage
+	CmdDumpMempoolResponseMessage
+	CmdGetMempoolAncestorsRequestMessage
+	CmdGetMempoolAncestorsResponseMessage
+	CmdGetMempoolDescendantsRequestMessage
+	CmdGetMempoolDescendantsResponseMessage
+	CmdGetMempoolStatsRequestMessage
+	CmdGetMempoolStatsResponseMessage
+	CmdNotifyMempoolTransactionsRequestMessage
+	CmdNotifyMempoolTransactionsResponseMessage
+	CmdStopNotifyingMempoolTransactionsRequestMessage
+	CmdStopNotifyingMempoolTransactionsResponseMessage
+	CmdTransactionAddedToMempoolNotificationMessage
+	CmdTransactionRemovedFromMempoolNotificationMessage
+	CmdGetBlockAcceptanceStatusRequestMessage
+	CmdGetBlockAcceptanceStatusResponseMessage
+	CmdGetTransactionConfirmationsRequestMessage
+	CmdGetTransactionConfirmationsResponseMessage
 )
 
 // ProtocolMessageCommandToString maps all MessageCommands to their string representation
@@ -174,6 +199,7 @@ var ProtocolMessageCommandToString = map[MessageCommand]string{
 	CmdBlockHeaders:                        "BlockHeaders",
 	CmdRequestNextPruningPointUTXOSetChunk: "RequestNextPruningPointUTXOSetChunk",
 	CmdDonePruningPointUTXOSetChunks:       "DonePruningPointUTXOSetChunks",
+	CmdDandelionTx:                         "DandelionTx",
 }
 
 // RPCMessageCommandToString maps all MessageCommands to their string representation
@@ -248,6 +274,30 @@ var RPCMessageCommandToString = map[MessageCommand]string{
 	CmdPruningPointUTXOSetOverrideNotificationMessage:             "PruningPointUTXOSetOverrideNotification",
 	CmdStopNotifyingPruningPointUTXOSetOverrideRequestMessage:     "StopNotifyingPruningPointUTXOSetOverrideRequest",
 	CmdStopNotifyingPruningPointUTXOSetOverrideResponseMessage:    "StopNotifyingPruningPointUTXOSetOverrideResponse",
+	CmdGetDAGSubgraphRequestMessage:                               "GetDAGSubgraphRequest",
+	CmdGetDAGSubgraphResponseMessage:                              "GetDAGSubgraphResponse",
+	CmdGetBalanceByAddressRequestMessage:                          "GetBalanceByAddressRequest",
+	CmdGetBalanceByAddressResponseMessage:                         "GetBalanceByAddressResponse",
+	CmdGetBalancesByAddressesRequestMessage:                       "GetBalancesByAddressesRequest",
+	CmdGetBalancesByAddressesResponseMessage:                      "GetBalancesByAddressesResponse",
+	CmdDumpMempoolRequestMessage:                                  "DumpMempoolRequest",
+	CmdDumpMempoolResponseMessage:                                 "DumpMempoolResponse",
+	CmdGetMempoolAncestorsRequestMessage:                          "GetMempoolAncestorsRequest",
+	CmdGetMempoolAncestorsResponseMessage:                         "GetMempoolAncestorsResponse",
+	CmdGetMempoolDescendantsRequestMessage:                        "GetMempoolDescendantsRequest",
+	CmdGetMempoolDescendantsResponseMessage:                       "GetMempoolDescendantsResponse",
+	CmdGetMempoolStatsRequestMessage:                              "GetMempoolStatsRequest",
+	CmdGetMempoolStatsResponseMessage:                             "GetMempoolStatsResponse",
+	CmdNotifyMempoolTransactionsRequestMessage:                    "NotifyMempoolTransactionsRequest",
+	CmdNotifyMempoolTransactionsResponseMessage:                   "NotifyMempoolTransactionsResponse",
+	CmdStopNotifyingMempoolTransactionsRequestMessage:             "StopNotifyingMempoolTransactionsRequest",
+	CmdStopNotifyingMempoolTransactionsResponseMessage:            "StopNotifyingMempoolTransactionsResponse",
+	CmdTransactionAddedToMempoolNotificationMessage:               "TransactionAddedToMempoolNotification",
+	CmdTransactionRemovedFromMempoolNotificationMessage:           "TransactionRemovedFromMempoolNotification",
+	CmdGetBlockAcceptanceStatusRequestMessage:                     "GetBlockAcceptanceStatusRequest",
+	CmdGetBlockAcceptanceStatusResponseMessage:                    "GetBlockAcceptanceStatusResponse",
+	CmdGetTransactionConfirmationsRequestMessage:                  "GetTransactionConfirmationsRequest",
+	CmdGetTransactionConfirmationsResponseMessage:                 "GetTransactionConfirmationsResponse",
 }
 
 // Message is an interface that describes a kaspa message. A type that
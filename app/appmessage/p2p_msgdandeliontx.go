@@ -0,0 +1,22 @@
+package appmessage
+
+// MsgDandelionTx implements the Message interface and represents a kaspa
+// DandelionTx message. It is used to pass a transaction to the next peer
+// along its Dandelion stem, before the transaction is announced to the rest
+// of the network with MsgInvTransaction messages.
+type MsgDandelionTx struct {
+	baseMessage
+	*MsgTx
+}
+
+// Command returns the protocol command string for the message. This is part
+// of the Message interface implementation.
+func (msg *MsgDandelionTx) Command() MessageCommand {
+	return CmdDandelionTx
+}
+
+// NewMsgDandelionTx returns a new kaspa DandelionTx message that conforms to the
+// Message interface. See MsgDandelionTx for details.
+func NewMsgDandelionTx(msgTx *MsgTx) *MsgDandelionTx {
+	return &MsgDandelionTx{MsgTx: msgTx}
+}
@@ -44,16 +44,21 @@ const (
 	// SFNodeCF is a flag used to indicate a peer supports committed
 	// filters (CFs).
 	SFNodeCF
+
+	// SFNodeDandelion is a flag used to indicate a peer relays transactions
+	// it receives in MsgDandelionTx messages along the Dandelion stem.
+	SFNodeDandelion
 )
 
 // Map of service flags back to their constant names for pretty printing.
 var sfStrings = map[ServiceFlag]string{
-	SFNodeNetwork: "SFNodeNetwork",
-	SFNodeGetUTXO: "SFNodeGetUTXO",
-	SFNodeBloom:   "SFNodeBloom",
-	SFNodeXthin:   "SFNodeXthin",
-	SFNodeBit5:    "SFNodeBit5",
-	SFNodeCF:      "SFNodeCF",
+	SFNodeNetwork:   "SFNodeNetwork",
+	SFNodeGetUTXO:   "SFNodeGetUTXO",
+	SFNodeBloom:     "SFNodeBloom",
+	SFNodeXthin:     "SFNodeXthin",
+	SFNodeBit5:      "SFNodeBit5",
+	SFNodeCF:        "SFNodeCF",
+	SFNodeDandelion: "SFNodeDandelion",
 }
 
 // orderedSFStrings is an ordered list of service flags from highest to
@@ -65,6 +70,7 @@ var orderedSFStrings = []ServiceFlag{
 	SFNodeXthin,
 	SFNodeBit5,
 	SFNodeCF,
+	SFNodeDandelion,
 }
 
 // String returns the ServiceFlag in human-readable form.
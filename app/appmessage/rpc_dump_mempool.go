@@ -0,0 +1,40 @@
+package appmessage
+
+// DumpMempoolRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type DumpMempoolRequestMessage struct {
+	baseMessage
+}
+
+// Command returns the protocol command string for the message
+func (msg *DumpMempoolRequestMessage) Command() MessageCommand {
+	return CmdDumpMempoolRequestMessage
+}
+
+// NewDumpMempoolRequestMessage returns a instance of the message
+func NewDumpMempoolRequestMessage() *DumpMempoolRequestMessage {
+	return &DumpMempoolRequestMessage{}
+}
+
+// DumpMempoolResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type DumpMempoolResponseMessage struct {
+	baseMessage
+	Path             string
+	TransactionCount uint32
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *DumpMempoolResponseMessage) Command() MessageCommand {
+	return CmdDumpMempoolResponseMessage
+}
+
+// NewDumpMempoolResponseMessage returns a instance of the message
+func NewDumpMempoolResponseMessage(path string, transactionCount uint32) *DumpMempoolResponseMessage {
+	return &DumpMempoolResponseMessage{
+		Path:             path,
+		TransactionCount: transactionCount,
+	}
+}
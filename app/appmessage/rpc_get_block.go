@@ -77,6 +77,10 @@ type TransactionVerboseData struct {
 	BlockHash                 string
 	Time                      uint64
 	BlockTime                 uint64
+	Fee                       uint64
+	Mass                      uint64
+	AcceptingBlockHash        string
+	Confirmations             uint64
 }
 
 // TransactionVerboseInput holds data about a transaction input
@@ -85,6 +89,8 @@ type TransactionVerboseInput struct {
 	OutputIndex uint32
 	ScriptSig   *ScriptSig
 	Sequence    uint64
+	Value       uint64
+	Address     string
 }
 
 // ScriptSig holds data about a script signature
@@ -102,6 +108,7 @@ type TransactionVerboseOutput struct {
 
 // ScriptPubKeyResult holds data about a script public key
 type ScriptPubKeyResult struct {
+	Asm     string
 	Hex     string
 	Type    string
 	Address string
@@ -0,0 +1,51 @@
+package appmessage
+
+// GetBlockAcceptanceStatusRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetBlockAcceptanceStatusRequestMessage struct {
+	baseMessage
+	BlockHash string
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetBlockAcceptanceStatusRequestMessage) Command() MessageCommand {
+	return CmdGetBlockAcceptanceStatusRequestMessage
+}
+
+// NewGetBlockAcceptanceStatusRequestMessage returns a instance of the message
+func NewGetBlockAcceptanceStatusRequestMessage(blockHash string) *GetBlockAcceptanceStatusRequestMessage {
+	return &GetBlockAcceptanceStatusRequestMessage{
+		BlockHash: blockHash,
+	}
+}
+
+// GetBlockAcceptanceStatusResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetBlockAcceptanceStatusResponseMessage struct {
+	baseMessage
+	AcceptingBlockHash string
+	Confirmations      uint64
+	IsBlue             bool
+	IsChainBlock       bool
+	IsFinalized        bool
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetBlockAcceptanceStatusResponseMessage) Command() MessageCommand {
+	return CmdGetBlockAcceptanceStatusResponseMessage
+}
+
+// NewGetBlockAcceptanceStatusResponseMessage returns a instance of the message
+func NewGetBlockAcceptanceStatusResponseMessage(acceptingBlockHash string, confirmations uint64,
+	isBlue bool, isChainBlock bool, isFinalized bool) *GetBlockAcceptanceStatusResponseMessage {
+
+	return &GetBlockAcceptanceStatusResponseMessage{
+		AcceptingBlockHash: acceptingBlockHash,
+		Confirmations:      confirmations,
+		IsBlue:             isBlue,
+		IsChainBlock:       isChainBlock,
+		IsFinalized:        isFinalized,
+	}
+}
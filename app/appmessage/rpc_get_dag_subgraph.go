@@ -0,0 +1,74 @@
+package appmessage
+
+// GetDAGSubgraphRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetDAGSubgraphRequestMessage struct {
+	baseMessage
+	LowBlueScore    uint64
+	HighBlueScore   uint64
+	AnticoneOfHash  string
+	IncludeDotGraph bool
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetDAGSubgraphRequestMessage) Command() MessageCommand {
+	return CmdGetDAGSubgraphRequestMessage
+}
+
+// NewGetDAGSubgraphRequestMessage returns a instance of the message
+func NewGetDAGSubgraphRequestMessage(lowBlueScore uint64, highBlueScore uint64, anticoneOfHash string,
+	includeDotGraph bool) *GetDAGSubgraphRequestMessage {
+
+	return &GetDAGSubgraphRequestMessage{
+		LowBlueScore:    lowBlueScore,
+		HighBlueScore:   highBlueScore,
+		AnticoneOfHash:  anticoneOfHash,
+		IncludeDotGraph: includeDotGraph,
+	}
+}
+
+// GetDAGSubgraphResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetDAGSubgraphResponseMessage struct {
+	baseMessage
+	Blocks      []*DAGSubgraphBlock
+	DotGraph    string
+	IsTruncated bool
+
+	Error *RPCError
+}
+
+// DAGSubgraphBlock represents a single block in a GetDAGSubgraphResponseMessage
+type DAGSubgraphBlock struct {
+	Hash                string
+	BlueScore           uint64
+	ParentHashes        []string
+	SelectedParentHash  string
+	MergeSetBluesHashes []string
+	MergeSetRedsHashes  []string
+	IsChainBlock        bool
+	Color               string
+}
+
+// The colors a DAGSubgraphBlock may have
+const (
+	DAGSubgraphBlockColorBlue     = "blue"
+	DAGSubgraphBlockColorRed      = "red"
+	DAGSubgraphBlockColorUnmerged = "unmerged"
+)
+
+// Command returns the protocol command string for the message
+func (msg *GetDAGSubgraphResponseMessage) Command() MessageCommand {
+	return CmdGetDAGSubgraphResponseMessage
+}
+
+// NewGetDAGSubgraphResponseMessage returns a instance of the message
+func NewGetDAGSubgraphResponseMessage(blocks []*DAGSubgraphBlock, dotGraph string,
+	isTruncated bool) *GetDAGSubgraphResponseMessage {
+
+	return &GetDAGSubgraphResponseMessage{
+		Blocks:      blocks,
+		DotGraph:    dotGraph,
+		IsTruncated: isTruncated,
+	}
+}
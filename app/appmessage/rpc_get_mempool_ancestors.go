@@ -0,0 +1,43 @@
+package appmessage
+
+// GetMempoolAncestorsRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetMempoolAncestorsRequestMessage struct {
+	baseMessage
+	TxID string
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetMempoolAncestorsRequestMessage) Command() MessageCommand {
+	return CmdGetMempoolAncestorsRequestMessage
+}
+
+// NewGetMempoolAncestorsRequestMessage returns a instance of the message
+func NewGetMempoolAncestorsRequestMessage(txID string) *GetMempoolAncestorsRequestMessage {
+	return &GetMempoolAncestorsRequestMessage{TxID: txID}
+}
+
+// GetMempoolAncestorsResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetMempoolAncestorsResponseMessage struct {
+	baseMessage
+	Entries   []*MempoolEntry
+	TotalMass uint64
+	TotalFee  uint64
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetMempoolAncestorsResponseMessage) Command() MessageCommand {
+	return CmdGetMempoolAncestorsResponseMessage
+}
+
+// NewGetMempoolAncestorsResponseMessage returns a instance of the message
+func NewGetMempoolAncestorsResponseMessage(entries []*MempoolEntry, totalMass uint64, totalFee uint64) *GetMempoolAncestorsResponseMessage {
+	return &GetMempoolAncestorsResponseMessage{
+		Entries:   entries,
+		TotalMass: totalMass,
+		TotalFee:  totalFee,
+	}
+}
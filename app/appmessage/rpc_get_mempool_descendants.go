@@ -0,0 +1,43 @@
+package appmessage
+
+// GetMempoolDescendantsRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetMempoolDescendantsRequestMessage struct {
+	baseMessage
+	TxID string
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetMempoolDescendantsRequestMessage) Command() MessageCommand {
+	return CmdGetMempoolDescendantsRequestMessage
+}
+
+// NewGetMempoolDescendantsRequestMessage returns a instance of the message
+func NewGetMempoolDescendantsRequestMessage(txID string) *GetMempoolDescendantsRequestMessage {
+	return &GetMempoolDescendantsRequestMessage{TxID: txID}
+}
+
+// GetMempoolDescendantsResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetMempoolDescendantsResponseMessage struct {
+	baseMessage
+	Entries   []*MempoolEntry
+	TotalMass uint64
+	TotalFee  uint64
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetMempoolDescendantsResponseMessage) Command() MessageCommand {
+	return CmdGetMempoolDescendantsResponseMessage
+}
+
+// NewGetMempoolDescendantsResponseMessage returns a instance of the message
+func NewGetMempoolDescendantsResponseMessage(entries []*MempoolEntry, totalMass uint64, totalFee uint64) *GetMempoolDescendantsResponseMessage {
+	return &GetMempoolDescendantsResponseMessage{
+		Entries:   entries,
+		TotalMass: totalMass,
+		TotalFee:  totalFee,
+	}
+}
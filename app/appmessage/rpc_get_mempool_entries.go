@@ -4,6 +4,8 @@ package appmessage
 // its respective RPC message
 type GetMempoolEntriesRequestMessage struct {
 	baseMessage
+	Addresses      []string
+	MinimumFeeRate float64
 }
 
 // Command returns the protocol command string for the message
@@ -12,8 +14,11 @@ func (msg *GetMempoolEntriesRequestMessage) Command() MessageCommand {
 }
 
 // NewGetMempoolEntriesRequestMessage returns a instance of the message
-func NewGetMempoolEntriesRequestMessage() *GetMempoolEntriesRequestMessage {
-	return &GetMempoolEntriesRequestMessage{}
+func NewGetMempoolEntriesRequestMessage(addresses []string, minimumFeeRate float64) *GetMempoolEntriesRequestMessage {
+	return &GetMempoolEntriesRequestMessage{
+		Addresses:      addresses,
+		MinimumFeeRate: minimumFeeRate,
+	}
 }
 
 // GetMempoolEntriesResponseMessage is an appmessage corresponding to
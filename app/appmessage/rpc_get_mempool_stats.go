@@ -0,0 +1,56 @@
+package appmessage
+
+// GetMempoolStatsRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetMempoolStatsRequestMessage struct {
+	baseMessage
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetMempoolStatsRequestMessage) Command() MessageCommand {
+	return CmdGetMempoolStatsRequestMessage
+}
+
+// NewGetMempoolStatsRequestMessage returns a instance of the message
+func NewGetMempoolStatsRequestMessage() *GetMempoolStatsRequestMessage {
+	return &GetMempoolStatsRequestMessage{}
+}
+
+// GetMempoolStatsResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetMempoolStatsResponseMessage struct {
+	baseMessage
+	TransactionCount uint64
+	OrphanCount      uint64
+	TotalMass        uint64
+	TotalFee         uint64
+	FeeRateHistogram []*MempoolFeeRateBucket
+
+	Error *RPCError
+}
+
+// MempoolFeeRateBucket represents the transactions in the mempool
+// whose fee rate is within a certain range
+type MempoolFeeRateBucket struct {
+	MinimumFeeRate   float64
+	TransactionCount uint64
+	TotalMass        uint64
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetMempoolStatsResponseMessage) Command() MessageCommand {
+	return CmdGetMempoolStatsResponseMessage
+}
+
+// NewGetMempoolStatsResponseMessage returns a instance of the message
+func NewGetMempoolStatsResponseMessage(transactionCount uint64, orphanCount uint64, totalMass uint64,
+	totalFee uint64, feeRateHistogram []*MempoolFeeRateBucket) *GetMempoolStatsResponseMessage {
+
+	return &GetMempoolStatsResponseMessage{
+		TransactionCount: transactionCount,
+		OrphanCount:      orphanCount,
+		TotalMass:        totalMass,
+		TotalFee:         totalFee,
+		FeeRateHistogram: feeRateHistogram,
+	}
+}
@@ -0,0 +1,53 @@
+package appmessage
+
+// GetTransactionConfirmationsRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type GetTransactionConfirmationsRequestMessage struct {
+	baseMessage
+	TransactionID string
+	BlockHash     string
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetTransactionConfirmationsRequestMessage) Command() MessageCommand {
+	return CmdGetTransactionConfirmationsRequestMessage
+}
+
+// NewGetTransactionConfirmationsRequestMessage returns a instance of the message
+func NewGetTransactionConfirmationsRequestMessage(transactionID string,
+	blockHash string) *GetTransactionConfirmationsRequestMessage {
+
+	return &GetTransactionConfirmationsRequestMessage{
+		TransactionID: transactionID,
+		BlockHash:     blockHash,
+	}
+}
+
+// GetTransactionConfirmationsResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type GetTransactionConfirmationsResponseMessage struct {
+	baseMessage
+	IsAccepted         bool
+	AcceptingBlockHash string
+	Confirmations      uint64
+	IsFinalized        bool
+
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *GetTransactionConfirmationsResponseMessage) Command() MessageCommand {
+	return CmdGetTransactionConfirmationsResponseMessage
+}
+
+// NewGetTransactionConfirmationsResponseMessage returns a instance of the message
+func NewGetTransactionConfirmationsResponseMessage(isAccepted bool, acceptingBlockHash string,
+	confirmations uint64, isFinalized bool) *GetTransactionConfirmationsResponseMessage {
+
+	return &GetTransactionConfirmationsResponseMessage{
+		IsAccepted:         isAccepted,
+		AcceptingBlockHash: acceptingBlockHash,
+		Confirmations:      confirmations,
+		IsFinalized:        isFinalized,
+	}
+}
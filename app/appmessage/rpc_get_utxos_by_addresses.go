@@ -4,7 +4,10 @@ package appmessage
 // its respective RPC message
 type GetUTXOsByAddressesRequestMessage struct {
 	baseMessage
-	Addresses []string
+	Addresses      []string
+	Limit          uint32
+	Cursor         string
+	IncludeMempool bool
 }
 
 // Command returns the protocol command string for the message
@@ -19,11 +22,24 @@ func NewGetUTXOsByAddressesRequestMessage(addresses []string) *GetUTXOsByAddress
 	}
 }
 
+// NewGetUTXOsByAddressesPageRequestMessage returns a instance of the message
+// that requests a single page of UTXOs
+func NewGetUTXOsByAddressesPageRequestMessage(addresses []string, limit uint32,
+	cursor string) *GetUTXOsByAddressesRequestMessage {
+
+	return &GetUTXOsByAddressesRequestMessage{
+		Addresses: addresses,
+		Limit:     limit,
+		Cursor:    cursor,
+	}
+}
+
 // GetUTXOsByAddressesResponseMessage is an appmessage corresponding to
 // its respective RPC message
 type GetUTXOsByAddressesResponseMessage struct {
 	baseMessage
-	Entries []*UTXOsByAddressesEntry
+	Entries    []*UTXOsByAddressesEntry
+	NextCursor string
 
 	Error *RPCError
 }
@@ -34,8 +50,11 @@ func (msg *GetUTXOsByAddressesResponseMessage) Command() MessageCommand {
 }
 
 // NewGetUTXOsByAddressesResponseMessage returns a instance of the message
-func NewGetUTXOsByAddressesResponseMessage(entries []*UTXOsByAddressesEntry) *GetUTXOsByAddressesResponseMessage {
+func NewGetUTXOsByAddressesResponseMessage(entries []*UTXOsByAddressesEntry,
+	nextCursor string) *GetUTXOsByAddressesResponseMessage {
+
 	return &GetUTXOsByAddressesResponseMessage{
-		Entries: entries,
+		Entries:    entries,
+		NextCursor: nextCursor,
 	}
 }
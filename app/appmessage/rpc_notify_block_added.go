@@ -4,6 +4,8 @@ package appmessage
 // its respective RPC message
 type NotifyBlockAddedRequestMessage struct {
 	baseMessage
+	StartSequenceNumber uint64
+	StartStreamID       string
 }
 
 // Command returns the protocol command string for the message
@@ -39,6 +41,8 @@ type BlockAddedNotificationMessage struct {
 	baseMessage
 	Block            *MsgBlock
 	BlockVerboseData *BlockVerboseData
+	SequenceNumber   uint64
+	StreamID         string
 }
 
 // Command returns the protocol command string for the message
@@ -0,0 +1,105 @@
+package appmessage
+
+// NotifyMempoolTransactionsRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type NotifyMempoolTransactionsRequestMessage struct {
+	baseMessage
+	Addresses []string
+}
+
+// Command returns the protocol command string for the message
+func (msg *NotifyMempoolTransactionsRequestMessage) Command() MessageCommand {
+	return CmdNotifyMempoolTransactionsRequestMessage
+}
+
+// NewNotifyMempoolTransactionsRequestMessage returns a instance of the message
+func NewNotifyMempoolTransactionsRequestMessage(addresses []string) *NotifyMempoolTransactionsRequestMessage {
+	return &NotifyMempoolTransactionsRequestMessage{
+		Addresses: addresses,
+	}
+}
+
+// NotifyMempoolTransactionsResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type NotifyMempoolTransactionsResponseMessage struct {
+	baseMessage
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *NotifyMempoolTransactionsResponseMessage) Command() MessageCommand {
+	return CmdNotifyMempoolTransactionsResponseMessage
+}
+
+// NewNotifyMempoolTransactionsResponseMessage returns a instance of the message
+func NewNotifyMempoolTransactionsResponseMessage() *NotifyMempoolTransactionsResponseMessage {
+	return &NotifyMempoolTransactionsResponseMessage{}
+}
+
+// TransactionAddedToMempoolNotificationMessage is an appmessage corresponding to
+// its respective RPC message
+type TransactionAddedToMempoolNotificationMessage struct {
+	baseMessage
+	TransactionID string
+	Transaction   *RPCTransaction
+}
+
+// Command returns the protocol command string for the message
+func (msg *TransactionAddedToMempoolNotificationMessage) Command() MessageCommand {
+	return CmdTransactionAddedToMempoolNotificationMessage
+}
+
+// NewTransactionAddedToMempoolNotificationMessage returns a instance of the message
+func NewTransactionAddedToMempoolNotificationMessage(transactionID string,
+	transaction *RPCTransaction) *TransactionAddedToMempoolNotificationMessage {
+
+	return &TransactionAddedToMempoolNotificationMessage{
+		TransactionID: transactionID,
+		Transaction:   transaction,
+	}
+}
+
+// TransactionRemovalReason is the reason a transaction was removed from the mempool
+type TransactionRemovalReason byte
+
+// TransactionRemovalReason constants
+const (
+	TransactionRemovalReasonMined      TransactionRemovalReason = 0
+	TransactionRemovalReasonConflicted TransactionRemovalReason = 1
+	TransactionRemovalReasonExpired    TransactionRemovalReason = 2
+	TransactionRemovalReasonEvicted    TransactionRemovalReason = 3
+)
+
+var transactionRemovalReasonToString = map[TransactionRemovalReason]string{
+	TransactionRemovalReasonMined:      "Mined",
+	TransactionRemovalReasonConflicted: "Conflicted",
+	TransactionRemovalReasonExpired:    "Expired",
+	TransactionRemovalReasonEvicted:    "Evicted",
+}
+
+func (r TransactionRemovalReason) String() string {
+	return transactionRemovalReasonToString[r]
+}
+
+// TransactionRemovedFromMempoolNotificationMessage is an appmessage corresponding to
+// its respective RPC message
+type TransactionRemovedFromMempoolNotificationMessage struct {
+	baseMessage
+	TransactionID string
+	Reason        TransactionRemovalReason
+}
+
+// Command returns the protocol command string for the message
+func (msg *TransactionRemovedFromMempoolNotificationMessage) Command() MessageCommand {
+	return CmdTransactionRemovedFromMempoolNotificationMessage
+}
+
+// NewTransactionRemovedFromMempoolNotificationMessage returns a instance of the message
+func NewTransactionRemovedFromMempoolNotificationMessage(transactionID string,
+	reason TransactionRemovalReason) *TransactionRemovedFromMempoolNotificationMessage {
+
+	return &TransactionRemovedFromMempoolNotificationMessage{
+		TransactionID: transactionID,
+		Reason:        reason,
+	}
+}
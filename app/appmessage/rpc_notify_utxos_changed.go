@@ -4,7 +4,10 @@ package appmessage
 // its respective RPC message
 type NotifyUTXOsChangedRequestMessage struct {
 	baseMessage
-	Addresses []string
+	Addresses           []string
+	IncludeMempool      bool
+	StartSequenceNumber uint64
+	StartStreamID       string
 }
 
 // Command returns the protocol command string for the message
@@ -40,15 +43,18 @@ func NewNotifyUTXOsChangedResponseMessage() *NotifyUTXOsChangedResponseMessage {
 // its respective RPC message
 type UTXOsChangedNotificationMessage struct {
 	baseMessage
-	Added   []*UTXOsByAddressesEntry
-	Removed []*UTXOsByAddressesEntry
+	Added          []*UTXOsByAddressesEntry
+	Removed        []*UTXOsByAddressesEntry
+	SequenceNumber uint64
+	StreamID       string
 }
 
 // UTXOsByAddressesEntry represents a UTXO of some address
 type UTXOsByAddressesEntry struct {
-	Address   string
-	Outpoint  *RPCOutpoint
-	UTXOEntry *RPCUTXOEntry
+	Address       string
+	Outpoint      *RPCOutpoint
+	UTXOEntry     *RPCUTXOEntry
+	IsUnconfirmed bool
 }
 
 // Command returns the protocol command string for the message
@@ -4,6 +4,8 @@ package appmessage
 // its respective RPC message
 type NotifyVirtualSelectedParentChainChangedRequestMessage struct {
 	baseMessage
+	StartSequenceNumber uint64
+	StartStreamID       string
 }
 
 // Command returns the protocol command string for the message
@@ -39,6 +41,8 @@ type VirtualSelectedParentChainChangedNotificationMessage struct {
 	baseMessage
 	RemovedChainBlockHashes []string
 	AddedChainBlocks        []*ChainBlock
+	SequenceNumber          uint64
+	StreamID                string
 }
 
 // ChainBlock represents a DAG chain-block
@@ -0,0 +1,34 @@
+package appmessage
+
+// StopNotifyingMempoolTransactionsRequestMessage is an appmessage corresponding to
+// its respective RPC message
+type StopNotifyingMempoolTransactionsRequestMessage struct {
+	baseMessage
+}
+
+// Command returns the protocol command string for the message
+func (msg *StopNotifyingMempoolTransactionsRequestMessage) Command() MessageCommand {
+	return CmdStopNotifyingMempoolTransactionsRequestMessage
+}
+
+// NewStopNotifyingMempoolTransactionsRequestMessage returns a instance of the message
+func NewStopNotifyingMempoolTransactionsRequestMessage() *StopNotifyingMempoolTransactionsRequestMessage {
+	return &StopNotifyingMempoolTransactionsRequestMessage{}
+}
+
+// StopNotifyingMempoolTransactionsResponseMessage is an appmessage corresponding to
+// its respective RPC message
+type StopNotifyingMempoolTransactionsResponseMessage struct {
+	baseMessage
+	Error *RPCError
+}
+
+// Command returns the protocol command string for the message
+func (msg *StopNotifyingMempoolTransactionsResponseMessage) Command() MessageCommand {
+	return CmdStopNotifyingMempoolTransactionsResponseMessage
+}
+
+// NewStopNotifyingMempoolTransactionsResponseMessage returns a instance of the message
+func NewStopNotifyingMempoolTransactionsResponseMessage() *StopNotifyingMempoolTransactionsResponseMessage {
+	return &StopNotifyingMempoolTransactionsResponseMessage{}
+}
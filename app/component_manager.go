@@ -9,14 +9,17 @@ import (
 	infrastructuredatabase "github.com/kaspanet/kaspad/infrastructure/db/database"
 
 	"github.com/kaspanet/kaspad/domain"
+	"github.com/kaspanet/kaspad/domain/miningmanager/mempoolpersistence"
 
 	"github.com/kaspanet/kaspad/infrastructure/network/addressmanager"
 
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/id"
 
 	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/health"
 	"github.com/kaspanet/kaspad/app/protocol"
 	"github.com/kaspanet/kaspad/app/rpc"
+	"github.com/kaspanet/kaspad/app/stratum"
 	"github.com/kaspanet/kaspad/infrastructure/config"
 	"github.com/kaspanet/kaspad/infrastructure/network/connmanager"
 	"github.com/kaspanet/kaspad/infrastructure/network/dnsseed"
@@ -27,11 +30,14 @@ import (
 // ComponentManager is a wrapper for all the kaspad services
 type ComponentManager struct {
 	cfg               *config.Config
+	domain            domain.Domain
 	addressManager    *addressmanager.AddressManager
 	protocolManager   *protocol.Manager
 	rpcManager        *rpc.Manager
 	connectionManager *connmanager.ConnectionManager
 	netAdapter        *netadapter.NetAdapter
+	healthServer      *health.Server
+	stratumServer     *stratum.Server
 
 	started, shutdown int32
 }
@@ -53,6 +59,20 @@ func (a *ComponentManager) Start() {
 	a.maybeSeedFromDNS()
 
 	a.connectionManager.Start()
+
+	if a.healthServer != nil {
+		err := a.healthServer.Start()
+		if err != nil {
+			panics.Exit(log, fmt.Sprintf("Error starting the health check server: %+v", err))
+		}
+	}
+
+	if a.stratumServer != nil {
+		err := a.stratumServer.Start()
+		if err != nil {
+			panics.Exit(log, fmt.Sprintf("Error starting the stratum server: %+v", err))
+		}
+	}
 }
 
 // Stop gracefully shuts down all the kaspad services.
@@ -65,6 +85,20 @@ func (a *ComponentManager) Stop() {
 
 	log.Warnf("Kaspad shutting down")
 
+	if a.stratumServer != nil {
+		err := a.stratumServer.Stop()
+		if err != nil {
+			log.Errorf("Error stopping the stratum server: %+v", err)
+		}
+	}
+
+	if a.healthServer != nil {
+		err := a.healthServer.Stop()
+		if err != nil {
+			log.Errorf("Error stopping the health check server: %+v", err)
+		}
+	}
+
 	a.connectionManager.Stop()
 
 	err := a.netAdapter.Stop()
@@ -72,6 +106,16 @@ func (a *ComponentManager) Stop() {
 		log.Errorf("Error stopping the net adapter: %+v", err)
 	}
 
+	if !a.cfg.NoPersistMempool {
+		path := mempoolpersistence.DumpFilePath(a.cfg.DataDir)
+		transactionCount, err := mempoolpersistence.Dump(a.domain.MiningManager(), path)
+		if err != nil {
+			log.Errorf("Error dumping the mempool: %+v", err)
+		} else {
+			log.Infof("Dumped %d mempool transactions to '%s'", transactionCount, path)
+		}
+	}
+
 	return
 }
 
@@ -80,11 +124,18 @@ func (a *ComponentManager) Stop() {
 func NewComponentManager(cfg *config.Config, db infrastructuredatabase.Database, interrupt chan<- struct{}) (
 	*ComponentManager, error) {
 
-	domain, err := domain.New(cfg.ActiveNetParams, db, cfg.IsArchivalNode)
+	domain, err := domain.New(cfg.ActiveNetParams, db, cfg.IsArchivalNode, cfg.MempoolExpiry)
 	if err != nil {
 		return nil, err
 	}
 
+	if !cfg.NoPersistMempool {
+		err = mempoolpersistence.Load(domain.MiningManager(), mempoolpersistence.DumpFilePath(cfg.DataDir))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	netAdapter, err := netadapter.NewNetAdapter(cfg)
 	if err != nil {
 		return nil, err
@@ -97,7 +148,7 @@ func NewComponentManager(cfg *config.Config, db infrastructuredatabase.Database,
 
 	var utxoIndex *utxoindex.UTXOIndex
 	if cfg.UTXOIndex {
-		utxoIndex, err = utxoindex.New(domain.Consensus(), db)
+		utxoIndex, err = utxoindex.New(domain.Consensus(), db, cfg.UTXOIndexDustThreshold)
 		if err != nil {
 			return nil, err
 		}
@@ -115,13 +166,29 @@ func NewComponentManager(cfg *config.Config, db infrastructuredatabase.Database,
 	}
 	rpcManager := setupRPC(cfg, domain, netAdapter, protocolManager, connectionManager, addressManager, utxoIndex, interrupt)
 
+	var healthServer *health.Server
+	if cfg.HealthListen != "" {
+		healthServer = health.NewServer(cfg, domain, db, protocolManager)
+	}
+
+	var stratumServer *stratum.Server
+	if cfg.StratumListen != "" {
+		stratumServer, err = stratum.NewServer(cfg, domain, protocolManager)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &ComponentManager{
 		cfg:               cfg,
+		domain:            domain,
 		protocolManager:   protocolManager,
 		rpcManager:        rpcManager,
 		connectionManager: connectionManager,
 		netAdapter:        netAdapter,
 		addressManager:    addressManager,
+		healthServer:      healthServer,
+		stratumServer:     stratumServer,
 	}, nil
 
 }
@@ -149,6 +216,8 @@ func setupRPC(
 	)
 	protocolManager.SetOnBlockAddedToDAGHandler(rpcManager.NotifyBlockAddedToDAG)
 	protocolManager.SetOnPruningPointUTXOSetOverrideHandler(rpcManager.NotifyPruningPointUTXOSetOverride)
+	protocolManager.SetOnTransactionAddedToMempoolHandler(rpcManager.NotifyTransactionAddedToMempool)
+	domain.MiningManager().SetOnTransactionsRemovedHandler(rpcManager.NotifyTransactionsRemovedFromMempool)
 
 	return rpcManager
 }
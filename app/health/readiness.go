@@ -0,0 +1,58 @@
+package health
+
+import (
+	"fmt"
+	"time"
+)
+
+// nodeState is a snapshot of the node properties that determine
+// whether it is ready to serve traffic
+type nodeState struct {
+	peerCount                      int
+	isIBDRunning                   bool
+	virtualSelectedParentTimestamp int64
+	now                            int64
+}
+
+// readinessThresholds are the limits a nodeState must be within for
+// the node to be considered ready
+type readinessThresholds struct {
+	minPeers  int
+	maxTipAge time.Duration
+}
+
+// ReadinessStatus is the response body of the /readyz endpoint
+type ReadinessStatus struct {
+	IsReady                        bool     `json:"isReady"`
+	Reasons                        []string `json:"reasons,omitempty"`
+	PeerCount                      int      `json:"peerCount"`
+	IsIBDRunning                   bool     `json:"isIbdRunning"`
+	VirtualSelectedParentTimestamp int64    `json:"virtualSelectedParentTimestamp"`
+	VirtualSelectedParentAge       string   `json:"virtualSelectedParentAge"`
+}
+
+func evaluateReadiness(state *nodeState, thresholds *readinessThresholds) *ReadinessStatus {
+	tipAge := time.Duration(state.now-state.virtualSelectedParentTimestamp) * time.Millisecond
+
+	var reasons []string
+	if state.peerCount < thresholds.minPeers {
+		reasons = append(reasons, fmt.Sprintf("connected to %d peers while at least %d are required",
+			state.peerCount, thresholds.minPeers))
+	}
+	if state.isIBDRunning {
+		reasons = append(reasons, "IBD is running")
+	}
+	if tipAge >= thresholds.maxTipAge {
+		reasons = append(reasons, fmt.Sprintf("the virtual selected parent is %s old while it may be at most %s old",
+			tipAge, thresholds.maxTipAge))
+	}
+
+	return &ReadinessStatus{
+		IsReady:                        len(reasons) == 0,
+		Reasons:                        reasons,
+		PeerCount:                      state.peerCount,
+		IsIBDRunning:                   state.isIBDRunning,
+		VirtualSelectedParentTimestamp: state.virtualSelectedParentTimestamp,
+		VirtualSelectedParentAge:       tipAge.String(),
+	}
+}
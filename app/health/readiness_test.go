@@ -0,0 +1,64 @@
+package health
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvaluateReadiness(t *testing.T) {
+	thresholds := &readinessThresholds{
+		minPeers:  2,
+		maxTipAge: time.Hour,
+	}
+	now := int64(10 * time.Hour / time.Millisecond)
+	recent := now - int64(time.Minute/time.Millisecond)
+	old := now - int64(2*time.Hour/time.Millisecond)
+
+	tests := []struct {
+		name            string
+		state           *nodeState
+		expectedIsReady bool
+		expectedReasons int
+	}{
+		{
+			name:            "ready",
+			state:           &nodeState{peerCount: 2, virtualSelectedParentTimestamp: recent, now: now},
+			expectedIsReady: true,
+		},
+		{
+			name:            "not enough peers",
+			state:           &nodeState{peerCount: 1, virtualSelectedParentTimestamp: recent, now: now},
+			expectedIsReady: false,
+			expectedReasons: 1,
+		},
+		{
+			name:            "IBD running",
+			state:           &nodeState{peerCount: 3, isIBDRunning: true, virtualSelectedParentTimestamp: recent, now: now},
+			expectedIsReady: false,
+			expectedReasons: 1,
+		},
+		{
+			name:            "old tip",
+			state:           &nodeState{peerCount: 3, virtualSelectedParentTimestamp: old, now: now},
+			expectedIsReady: false,
+			expectedReasons: 1,
+		},
+		{
+			name:            "everything wrong",
+			state:           &nodeState{peerCount: 0, isIBDRunning: true, virtualSelectedParentTimestamp: old, now: now},
+			expectedIsReady: false,
+			expectedReasons: 3,
+		},
+	}
+
+	for _, test := range tests {
+		status := evaluateReadiness(test.state, thresholds)
+		if status.IsReady != test.expectedIsReady {
+			t.Errorf("%s: expected IsReady %t but got %t", test.name, test.expectedIsReady, status.IsReady)
+		}
+		if len(status.Reasons) != test.expectedReasons {
+			t.Errorf("%s: expected %d reasons but got %d: %v",
+				test.name, test.expectedReasons, len(status.Reasons), status.Reasons)
+		}
+	}
+}
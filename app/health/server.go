@@ -0,0 +1,159 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/protocol"
+	"github.com/kaspanet/kaspad/domain"
+	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/util/mstime"
+	"github.com/pkg/errors"
+)
+
+const (
+	databaseCheckTimeout = 5 * time.Second
+	shutdownTimeout      = 5 * time.Second
+)
+
+var databaseCheckKey = database.MakeBucket([]byte("health")).Key([]byte("check"))
+
+// LivenessStatus is the response body of the /healthz endpoint
+type LivenessStatus struct {
+	IsAlive bool   `json:"isAlive"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// Server is an HTTP server that exposes the liveness (/healthz) and
+// readiness (/readyz) of the node for orchestration systems
+type Server struct {
+	listenAddress   string
+	thresholds      *readinessThresholds
+	domain          domain.Domain
+	database        database.Database
+	protocolManager *protocol.Manager
+	httpServer      *http.Server
+}
+
+// NewServer creates a new health check Server
+func NewServer(cfg *config.Config, domain domain.Domain, database database.Database,
+	protocolManager *protocol.Manager) *Server {
+
+	server := &Server{
+		listenAddress: cfg.HealthListen,
+		thresholds: &readinessThresholds{
+			minPeers:  cfg.HealthMinPeers,
+			maxTipAge: cfg.HealthMaxTipAge,
+		},
+		domain:          domain,
+		database:        database,
+		protocolManager: protocolManager,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/healthz", server.handleHealthz)
+	mux.HandleFunc("/readyz", server.handleReadyz)
+	server.httpServer = &http.Server{Handler: mux}
+
+	return server
+}
+
+// Start starts listening for health check requests
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.listenAddress)
+	if err != nil {
+		return errors.Wrapf(err, "error listening on %s", s.listenAddress)
+	}
+
+	spawn("health.Server.Start", func() {
+		log.Infof("Health check server listening on %s", listener.Addr())
+		err := s.httpServer.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("Health check server stopped unexpectedly: %s", err)
+		}
+	})
+	return nil
+}
+
+// Stop gracefully shuts down the health check server
+func (s *Server) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return s.httpServer.Shutdown(ctx)
+}
+
+func (s *Server) handleHealthz(writer http.ResponseWriter, _ *http.Request) {
+	status := &LivenessStatus{IsAlive: true}
+	err := s.checkDatabase()
+	if err != nil {
+		status = &LivenessStatus{IsAlive: false, Reason: err.Error()}
+	}
+	writeStatus(writer, status, status.IsAlive)
+}
+
+func (s *Server) handleReadyz(writer http.ResponseWriter, _ *http.Request) {
+	state, err := s.nodeState()
+	if err != nil {
+		status := &ReadinessStatus{IsReady: false, Reasons: []string{err.Error()}}
+		writeStatus(writer, status, false)
+		return
+	}
+
+	status := evaluateReadiness(state, s.thresholds)
+	writeStatus(writer, status, status.IsReady)
+}
+
+// checkDatabase makes sure that the database responds to reads
+// within databaseCheckTimeout
+func (s *Server) checkDatabase() error {
+	errChan := make(chan error, 1)
+	spawn("health.Server.checkDatabase", func() {
+		_, err := s.database.Has(databaseCheckKey)
+		errChan <- err
+	})
+
+	select {
+	case err := <-errChan:
+		if err != nil {
+			return errors.Wrap(err, "database read failed")
+		}
+		return nil
+	case <-time.After(databaseCheckTimeout):
+		return errors.Errorf("database did not respond within %s", databaseCheckTimeout)
+	}
+}
+
+func (s *Server) nodeState() (*nodeState, error) {
+	virtualSelectedParent, err := s.domain.Consensus().GetVirtualSelectedParent()
+	if err != nil {
+		return nil, err
+	}
+	virtualSelectedParentHeader, err := s.domain.Consensus().GetBlockHeader(virtualSelectedParent)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nodeState{
+		peerCount:                      len(s.protocolManager.Peers()),
+		isIBDRunning:                   s.protocolManager.IsIBDRunning(),
+		virtualSelectedParentTimestamp: virtualSelectedParentHeader.TimeInMilliseconds(),
+		now:                            mstime.Now().UnixMilliseconds(),
+	}, nil
+}
+
+func writeStatus(writer http.ResponseWriter, status interface{}, isOK bool) {
+	writer.Header().Set("Content-Type", "application/json")
+	if isOK {
+		writer.WriteHeader(http.StatusOK)
+	} else {
+		writer.WriteHeader(http.StatusServiceUnavailable)
+	}
+	err := json.NewEncoder(writer).Encode(status)
+	if err != nil {
+		log.Warnf("Failed to write health check response: %s", err)
+	}
+}
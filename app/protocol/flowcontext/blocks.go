@@ -39,7 +39,7 @@ func (f *FlowContext) OnNewBlock(block *externalapi.DomainBlock,
 
 	for i, newBlock := range newBlocks {
 		log.Debugf("OnNewBlock: passing block %s transactions to mining manager", hash)
-		_, err = f.Domain().MiningManager().HandleNewBlockTransactions(newBlock.Transactions)
+		acceptedOrphans, err := f.Domain().MiningManager().HandleNewBlockTransactions(newBlock.Transactions)
 		if err != nil {
 			return err
 		}
@@ -52,6 +52,16 @@ func (f *FlowContext) OnNewBlock(block *externalapi.DomainBlock,
 				return err
 			}
 		}
+
+		err = f.OnTransactionAddedToMempool(acceptedOrphans)
+		if err != nil {
+			return err
+		}
+
+		err = f.broadcastTransactionsAfterBlockAdded(newBlock, acceptedOrphans)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -76,19 +86,19 @@ func (f *FlowContext) broadcastTransactionsAfterBlockAdded(
 		return nil
 	}
 
-	var txIDsToRebroadcast []*externalapi.DomainTransactionID
-	if f.shouldRebroadcastTransactions() {
-		txIDsToRebroadcast = f.txIDsToRebroadcast()
+	err := f.fluffStemTransactions(f.removeStemTransactionsWithExpiredEmbargo())
+	if err != nil {
+		return err
 	}
 
-	txIDsToBroadcast := make([]*externalapi.DomainTransactionID, len(transactionsAcceptedToMempool)+len(txIDsToRebroadcast))
-	for i, tx := range transactionsAcceptedToMempool {
-		txIDsToBroadcast[i] = consensushashing.TransactionID(tx)
-	}
-	offset := len(transactionsAcceptedToMempool)
-	for i, txID := range txIDsToRebroadcast {
-		txIDsToBroadcast[offset+i] = txID
+	txIDsToRebroadcast := f.txIDsToRebroadcast()
+
+	txIDsToBroadcast := make([]*externalapi.DomainTransactionID, 0,
+		len(transactionsAcceptedToMempool)+len(txIDsToRebroadcast))
+	for _, tx := range transactionsAcceptedToMempool {
+		txIDsToBroadcast = append(txIDsToBroadcast, consensushashing.TransactionID(tx))
 	}
+	txIDsToBroadcast = append(txIDsToBroadcast, txIDsToRebroadcast...)
 
 	if len(txIDsToBroadcast) == 0 {
 		return nil
@@ -0,0 +1,270 @@
+package flowcontext
+
+import (
+	"crypto/rand"
+	"math/big"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	peerpkg "github.com/kaspanet/kaspad/app/protocol/peer"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/miningmanager/mempool"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter"
+	"github.com/pkg/errors"
+)
+
+// The Dandelion relay mode hides the node a transaction originated from. Instead
+// of announcing a new transaction to all its peers at once, a node passes it to a
+// single outbound peer (the stem peer) in a MsgDandelionTx message. Every node along
+// the stem either passes the transaction on to its own stem peer, or "fluffs" it,
+// that is, announces it to all its peers with the regular MsgInvTransaction.
+const (
+	// dandelionEpochDuration is how long a node keeps passing transactions to
+	// the same stem peer before it chooses a new one
+	dandelionEpochDuration = 10 * time.Minute
+
+	// dandelionFluffProbability is the probability that a node fluffs a transaction
+	// it received along the stem rather than passing it on. The number of hops
+	// along the stem is thus random, and is 1/dandelionFluffProbability on average
+	dandelionFluffProbability = 0.1
+
+	// dandelionMinEmbargoDuration and dandelionMaxEmbargoDuration bound the random
+	// time after which a node fluffs a transaction it passed along the stem, if it
+	// hasn't seen any peer announce it by then. This makes sure that a transaction
+	// is eventually broadcast even if a node along the stem drops it
+	dandelionMinEmbargoDuration = 10 * time.Second
+	dandelionMaxEmbargoDuration = 30 * time.Second
+
+	// dandelionMaxStempoolSize is the maximum number of transactions in the stempool.
+	// Transactions are fluffed rather than passed along the stem while it's full
+	dandelionMaxStempoolSize = 10000
+)
+
+// stemTransaction is a transaction that was passed along its stem and wasn't fluffed
+// yet. Stem transactions are kept in the stempool rather than in the mempool, so that
+// peers cannot learn about them before they're fluffed
+type stemTransaction struct {
+	tx         *externalapi.DomainTransaction
+	embargoEnd time.Time
+}
+
+// RelayDandelionTransaction relays a transaction that was received along its stem.
+// The transaction is either fluffed, that is, added to the mempool and announced to
+// all peers, or is added to the stempool and passed on to the stem peer of the
+// current epoch. An error is returned if the transaction is not valid
+func (f *FlowContext) RelayDandelionTransaction(tx *externalapi.DomainTransaction) error {
+	// Float64 values are uniform over multiples of 2^-53 in [0, 1)
+	const float64Precision = 1 << 53
+	randomValue, err := randomInt63n(float64Precision)
+	if err != nil {
+		return err
+	}
+	if float64(randomValue)/float64Precision >= dandelionFluffProbability {
+		wasStemmed, err := f.stemTransaction(tx)
+		if err != nil || wasStemmed {
+			return err
+		}
+	}
+	return f.fluffTransaction(tx)
+}
+
+// IsInStempool returns whether the transaction with the given ID is in the stempool
+func (f *FlowContext) IsInStempool(txID *externalapi.DomainTransactionID) bool {
+	f.dandelionLock.Lock()
+	defer f.dandelionLock.Unlock()
+
+	_, ok := f.stempool[*txID]
+	return ok
+}
+
+// OnTransactionsAnnounced is called when a peer announces transactions with a
+// MsgInvTransaction, which means that the transactions have been fluffed. The
+// announced transactions that are in the stempool are fluffed by this node as well
+func (f *FlowContext) OnTransactionsAnnounced(txIDs []*externalapi.DomainTransactionID) error {
+	return f.fluffStemTransactions(f.removeFromStempool(txIDs))
+}
+
+// stemTransaction validates the given transaction, adds it to the stempool and passes it
+// to the stem peer of the current epoch. It returns false if there's no stem peer, in which
+// case the transaction should be fluffed instead.
+// The transaction is embargoed for a random duration, after which it's fluffed if no
+// peer announced it by then
+func (f *FlowContext) stemTransaction(tx *externalapi.DomainTransaction) (wasStemmed bool, err error) {
+	// The mempool doesn't know about the outputs of stem transactions, so the stem
+	// transactions that this one spends from are fluffed first
+	err = f.fluffStemTransactions(f.removeFromStempool(parentTxIDs(tx)))
+	if err != nil {
+		return false, err
+	}
+	err = f.Domain().MiningManager().ValidateTransaction(tx)
+	if err != nil {
+		return false, err
+	}
+
+	transactionID := consensushashing.TransactionID(tx)
+	stemPeer, err := f.addToStempool(transactionID, tx)
+	if err != nil || stemPeer == nil {
+		return false, err
+	}
+
+	log.Debugf("Passing transaction %s along the stem to %s", transactionID, stemPeer)
+	msgDandelionTx := appmessage.NewMsgDandelionTx(appmessage.DomainTransactionToMsgTx(tx))
+	err = f.netAdapter.P2PBroadcast([]*netadapter.NetConnection{stemPeer.Connection()}, msgDandelionTx)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// addToStempool returns the stem peer of the current epoch, and if there's one, adds
+// the given transaction to the stempool with an embargo of a random duration.
+// It returns nil if there's no stem peer or if the stempool is full
+func (f *FlowContext) addToStempool(transactionID *externalapi.DomainTransactionID,
+	tx *externalapi.DomainTransaction) (*peerpkg.Peer, error) {
+
+	f.dandelionLock.Lock()
+	defer f.dandelionLock.Unlock()
+
+	if len(f.stempool) >= dandelionMaxStempoolSize {
+		return nil, nil
+	}
+	stemPeer, err := f.dandelionStemPeerForEpoch()
+	if err != nil || stemPeer == nil {
+		return nil, err
+	}
+	randomEmbargoDuration, err := randomInt63n(int64(dandelionMaxEmbargoDuration - dandelionMinEmbargoDuration))
+	if err != nil {
+		return nil, err
+	}
+	f.stempool[*transactionID] = &stemTransaction{
+		tx:         tx,
+		embargoEnd: time.Now().Add(dandelionMinEmbargoDuration + time.Duration(randomEmbargoDuration)),
+	}
+	return stemPeer, nil
+}
+
+// removeFromStempool removes the transactions with the given IDs from the stempool,
+// and returns the ones that were in it
+func (f *FlowContext) removeFromStempool(txIDs []*externalapi.DomainTransactionID) []*externalapi.DomainTransaction {
+	f.dandelionLock.Lock()
+	defer f.dandelionLock.Unlock()
+
+	var removedTransactions []*externalapi.DomainTransaction
+	for _, txID := range txIDs {
+		stemTx, ok := f.stempool[*txID]
+		if !ok {
+			continue
+		}
+		delete(f.stempool, *txID)
+		removedTransactions = append(removedTransactions, stemTx.tx)
+	}
+	return removedTransactions
+}
+
+// fluffTransaction adds the given transaction to the mempool and announces it to all peers
+func (f *FlowContext) fluffTransaction(tx *externalapi.DomainTransaction) error {
+	err := f.fluffStemTransactions(f.removeFromStempool(parentTxIDs(tx)))
+	if err != nil {
+		return err
+	}
+	acceptedTransactions, err := f.Domain().MiningManager().ValidateAndInsertTransaction(tx, false)
+	if err != nil {
+		return err
+	}
+
+	acceptedTransactionIDs := make([]*externalapi.DomainTransactionID, len(acceptedTransactions))
+	for i, acceptedTransaction := range acceptedTransactions {
+		acceptedTransactionIDs[i] = consensushashing.TransactionID(acceptedTransaction)
+	}
+	err = f.Broadcast(appmessage.NewMsgInvTransaction(acceptedTransactionIDs))
+	if err != nil {
+		return err
+	}
+	return f.OnTransactionAddedToMempool(acceptedTransactions)
+}
+
+// fluffStemTransactions fluffs the given transactions, which were removed from the
+// stempool. Transactions that are no longer valid, for example since they were
+// accepted to the DAG or double spent in the meantime, are dropped
+func (f *FlowContext) fluffStemTransactions(txs []*externalapi.DomainTransaction) error {
+	for _, tx := range txs {
+		err := f.fluffTransaction(tx)
+		if err != nil {
+			if !errors.As(err, &mempool.RuleError{}) {
+				return err
+			}
+			log.Debugf("Dropping stem transaction %s: %s", consensushashing.TransactionID(tx), err)
+		}
+	}
+	return nil
+}
+
+// parentTxIDs returns the IDs of the transactions the given transaction spends from
+func parentTxIDs(tx *externalapi.DomainTransaction) []*externalapi.DomainTransactionID {
+	txIDs := make([]*externalapi.DomainTransactionID, len(tx.Inputs))
+	for i, input := range tx.Inputs {
+		txIDs[i] = &input.PreviousOutpoint.TransactionID
+	}
+	return txIDs
+}
+
+// dandelionStemPeerForEpoch returns the stem peer of the current epoch, and chooses
+// a new one at random if the epoch is over or if the stem peer disconnected.
+// It returns nil if no outbound peer supports Dandelion.
+// This function must be called with dandelionLock held.
+func (f *FlowContext) dandelionStemPeerForEpoch() (*peerpkg.Peer, error) {
+	now := time.Now()
+	if f.dandelionStemPeer != nil && now.Before(f.dandelionEpochEnd) && f.isPeerReady(f.dandelionStemPeer) {
+		return f.dandelionStemPeer, nil
+	}
+
+	var candidates []*peerpkg.Peer
+	for _, peer := range f.Peers() {
+		if peer.IsOutbound() && peer.Services()&appmessage.SFNodeDandelion == appmessage.SFNodeDandelion {
+			candidates = append(candidates, peer)
+		}
+	}
+	if len(candidates) == 0 {
+		f.dandelionStemPeer = nil
+		return nil, nil
+	}
+
+	stemPeerIndex, err := randomInt63n(int64(len(candidates)))
+	if err != nil {
+		return nil, err
+	}
+	f.dandelionStemPeer = candidates[stemPeerIndex]
+	f.dandelionEpochEnd = now.Add(dandelionEpochDuration)
+	log.Debugf("Chose %s as the Dandelion stem peer until %s", f.dandelionStemPeer, f.dandelionEpochEnd)
+	return f.dandelionStemPeer, nil
+}
+
+// randomInt63n returns a uniformly random number in [0, n). The random choices
+// of Dandelion must be unpredictable to observers, so they're drawn from crypto/rand
+func randomInt63n(n int64) (int64, error) {
+	randomNumber, err := rand.Int(rand.Reader, big.NewInt(n))
+	if err != nil {
+		return 0, err
+	}
+	return randomNumber.Int64(), nil
+}
+
+// removeStemTransactionsWithExpiredEmbargo removes the transactions whose embargo
+// expired without any peer announcing them from the stempool, and returns them
+func (f *FlowContext) removeStemTransactionsWithExpiredEmbargo() []*externalapi.DomainTransaction {
+	f.dandelionLock.Lock()
+	defer f.dandelionLock.Unlock()
+
+	now := time.Now()
+	var txs []*externalapi.DomainTransaction
+	for txID, stemTx := range f.stempool {
+		if now.Before(stemTx.embargoEnd) {
+			continue
+		}
+		delete(f.stempool, txID)
+		log.Debugf("The embargo of transaction %s expired without any peer announcing it", &txID)
+		txs = append(txs, stemTx.tx)
+	}
+	return txs
+}
@@ -28,8 +28,8 @@ type OnBlockAddedToDAGHandler func(block *externalapi.DomainBlock, blockInsertio
 type OnPruningPointUTXOSetOverrideHandler func() error
 
 // OnTransactionAddedToMempoolHandler is a handler function that's triggered
-// when a transaction is added to the mempool
-type OnTransactionAddedToMempoolHandler func()
+// when transactions are added to the mempool
+type OnTransactionAddedToMempoolHandler func(transactions []*externalapi.DomainTransaction) error
 
 // FlowContext holds state that is relevant to more than one flow or one peer, and allows communication between
 // different flows that can be associated to different peers.
@@ -47,10 +47,14 @@ type FlowContext struct {
 	onTransactionAddedToMempoolHandler   OnTransactionAddedToMempoolHandler
 
 	transactionsToRebroadcastLock sync.Mutex
-	transactionsToRebroadcast     map[externalapi.DomainTransactionID]*externalapi.DomainTransaction
-	lastRebroadcastTime           time.Time
+	transactionsToRebroadcast     map[externalapi.DomainTransactionID]*transactionToRebroadcast
 	sharedRequestedTransactions   *transactionrelay.SharedRequestedTransactions
 
+	dandelionLock     sync.Mutex
+	dandelionStemPeer *peerpkg.Peer
+	dandelionEpochEnd time.Time
+	stempool          map[externalapi.DomainTransactionID]*stemTransaction
+
 	sharedRequestedBlocks *blockrelay.SharedRequestedBlocks
 
 	ibdPeer      *peerpkg.Peer
@@ -76,7 +80,8 @@ func New(cfg *config.Config, domain domain.Domain, addressManager *addressmanage
 		sharedRequestedTransactions: transactionrelay.NewSharedRequestedTransactions(),
 		sharedRequestedBlocks:       blockrelay.NewSharedRequestedBlocks(),
 		peers:                       make(map[id.ID]*peerpkg.Peer),
-		transactionsToRebroadcast:   make(map[externalapi.DomainTransactionID]*externalapi.DomainTransaction),
+		transactionsToRebroadcast:   make(map[externalapi.DomainTransactionID]*transactionToRebroadcast),
+		stempool:                    make(map[externalapi.DomainTransactionID]*stemTransaction),
 		orphans:                     make(map[externalapi.DomainHash]*externalapi.DomainBlock),
 		timeStarted:                 mstime.Now().UnixMilliseconds(),
 	}
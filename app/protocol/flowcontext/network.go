@@ -41,6 +41,15 @@ func (f *FlowContext) RemoveFromPeers(peer *peerpkg.Peer) {
 	delete(f.peers, *peer.ID())
 }
 
+// isPeerReady returns whether the given peer is in the ready peers list.
+func (f *FlowContext) isPeerReady(peer *peerpkg.Peer) bool {
+	f.peersMutex.RLock()
+	defer f.peersMutex.RUnlock()
+
+	readyPeer, ok := f.peers[*peer.ID()]
+	return ok && readyPeer == peer
+}
+
 // readyPeerConnections returns the NetConnections of all the ready peers.
 func (f *FlowContext) readyPeerConnections() []*netadapter.NetConnection {
 	f.peersMutex.RLock()
@@ -9,20 +9,88 @@ import (
 	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
 )
 
+const (
+	// initialRebroadcastInterval is the time after which a local transaction
+	// is rebroadcast for the first time if it's still in the mempool
+	initialRebroadcastInterval = 30 * time.Second
+
+	// maxRebroadcastInterval is the maximum time between two rebroadcasts of
+	// the same local transaction. The interval doubles after every rebroadcast
+	// until it reaches this value
+	maxRebroadcastInterval = 30 * time.Minute
+)
+
+// transactionToRebroadcast is a local transaction, that is, a transaction that
+// was submitted to this node directly rather than relayed to it by a peer.
+// Local transactions are rebroadcast on a back-off schedule until they're
+// no longer in the mempool, in case their first announcement got lost
+type transactionToRebroadcast struct {
+	tx                  *externalapi.DomainTransaction
+	nextRebroadcastTime time.Time
+	rebroadcastInterval time.Duration
+}
+
 // AddTransaction adds transaction to the mempool and propagates it.
+// If Dandelion is enabled, the transaction is added to the stempool and
+// passed along the stem instead, and it's added to the mempool once
+// it's fluffed.
+// The transaction is tracked as a local transaction, and is rebroadcast
+// until it's accepted to the DAG.
 func (f *FlowContext) AddTransaction(tx *externalapi.DomainTransaction) error {
+	if f.cfg.Dandelion {
+		wasStemmed, err := f.stemLocalTransaction(tx)
+		if err != nil || wasStemmed {
+			return err
+		}
+	}
+
+	acceptedTransactions, err := f.addTransactionAndBroadcast(tx)
+	if err != nil {
+		return err
+	}
+	return f.OnTransactionAddedToMempool(acceptedTransactions)
+}
+
+func (f *FlowContext) stemLocalTransaction(tx *externalapi.DomainTransaction) (wasStemmed bool, err error) {
+	f.transactionsToRebroadcastLock.Lock()
+	defer f.transactionsToRebroadcastLock.Unlock()
+
+	wasStemmed, err = f.stemTransaction(tx)
+	if err != nil || !wasStemmed {
+		return false, err
+	}
+	f.trackTransactionToRebroadcast(tx)
+	return true, nil
+}
+
+func (f *FlowContext) addTransactionAndBroadcast(tx *externalapi.DomainTransaction) (
+	acceptedTransactions []*externalapi.DomainTransaction, err error) {
+
 	f.transactionsToRebroadcastLock.Lock()
 	defer f.transactionsToRebroadcastLock.Unlock()
 
-	err := f.Domain().MiningManager().ValidateAndInsertTransaction(tx, false)
+	acceptedTransactions, err = f.Domain().MiningManager().ValidateAndInsertTransaction(tx, false)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	f.trackTransactionToRebroadcast(tx)
+	inv := appmessage.NewMsgInvTransaction([]*externalapi.DomainTransactionID{consensushashing.TransactionID(tx)})
+	err = f.Broadcast(inv)
+	if err != nil {
+		return nil, err
 	}
+	return acceptedTransactions, nil
+}
 
-	transactionID := consensushashing.TransactionID(tx)
-	f.transactionsToRebroadcast[*transactionID] = tx
-	inv := appmessage.NewMsgInvTransaction([]*externalapi.DomainTransactionID{transactionID})
-	return f.Broadcast(inv)
+// trackTransactionToRebroadcast starts tracking the given local transaction.
+// This function must be called with transactionsToRebroadcastLock held.
+func (f *FlowContext) trackTransactionToRebroadcast(tx *externalapi.DomainTransaction) {
+	f.transactionsToRebroadcast[*consensushashing.TransactionID(tx)] = &transactionToRebroadcast{
+		tx:                  tx,
+		nextRebroadcastTime: time.Now().Add(initialRebroadcastInterval),
+		rebroadcastInterval: initialRebroadcastInterval,
+	}
 }
 
 func (f *FlowContext) updateTransactionsToRebroadcast(block *externalapi.DomainBlock) {
@@ -36,20 +104,36 @@ func (f *FlowContext) updateTransactionsToRebroadcast(block *externalapi.DomainB
 	}
 }
 
-func (f *FlowContext) shouldRebroadcastTransactions() bool {
-	const rebroadcastInterval = 30 * time.Second
-	return time.Since(f.lastRebroadcastTime) > rebroadcastInterval
-}
-
+// txIDsToRebroadcast returns the IDs of the local transactions that are due to be
+// rebroadcast, and schedules their next rebroadcast. Local transactions that are
+// still in the stempool are not rebroadcast until they're fluffed. Local transactions
+// that are in neither pool, either since they were accepted to the DAG or since they
+// were evicted, are no longer tracked.
 func (f *FlowContext) txIDsToRebroadcast() []*externalapi.DomainTransactionID {
 	f.transactionsToRebroadcastLock.Lock()
 	defer f.transactionsToRebroadcastLock.Unlock()
 
-	txIDs := make([]*externalapi.DomainTransactionID, len(f.transactionsToRebroadcast))
-	i := 0
-	for _, tx := range f.transactionsToRebroadcast {
-		txIDs[i] = consensushashing.TransactionID(tx)
-		i++
+	now := time.Now()
+	var txIDs []*externalapi.DomainTransactionID
+	for txID, transaction := range f.transactionsToRebroadcast {
+		txID := txID
+		if f.IsInStempool(&txID) {
+			continue
+		}
+		if _, ok := f.Domain().MiningManager().GetTransaction(&txID); !ok {
+			delete(f.transactionsToRebroadcast, txID)
+			continue
+		}
+		if now.Before(transaction.nextRebroadcastTime) {
+			continue
+		}
+
+		txIDs = append(txIDs, &txID)
+		transaction.rebroadcastInterval *= 2
+		if transaction.rebroadcastInterval > maxRebroadcastInterval {
+			transaction.rebroadcastInterval = maxRebroadcastInterval
+		}
+		transaction.nextRebroadcastTime = now.Add(transaction.rebroadcastInterval)
 	}
 	return txIDs
 }
@@ -60,10 +144,11 @@ func (f *FlowContext) SharedRequestedTransactions() *transactionrelay.SharedRequ
 	return f.sharedRequestedTransactions
 }
 
-// OnTransactionAddedToMempool notifies the handler function that a transaction
-// has been added to the mempool
-func (f *FlowContext) OnTransactionAddedToMempool() {
-	if f.onTransactionAddedToMempoolHandler != nil {
-		f.onTransactionAddedToMempoolHandler()
+// OnTransactionAddedToMempool notifies the handler function that transactions
+// have been added to the mempool
+func (f *FlowContext) OnTransactionAddedToMempool(transactions []*externalapi.DomainTransaction) error {
+	if f.onTransactionAddedToMempoolHandler != nil && len(transactions) > 0 {
+		return f.onTransactionAddedToMempoolHandler(transactions)
 	}
+	return nil
 }
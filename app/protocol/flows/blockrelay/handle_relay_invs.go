@@ -139,6 +139,7 @@ func (flow *handleRelayInvsFlow) start() error {
 			return err
 		}
 		log.Infof("Accepted block %s via relay", inv.Hash)
+		flow.peer.SetLastBlockTime()
 		err = flow.OnNewBlock(block, blockInsertionResult)
 		if err != nil {
 			return err
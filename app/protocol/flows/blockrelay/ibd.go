@@ -520,6 +520,7 @@ func (flow *handleRelayInvsFlow) syncMissingBlockBodies(highHash *externalapi.Do
 			return err
 		}
 
+		blocks := make([]*externalapi.DomainBlock, 0, len(hashesToRequest))
 		for _, expectedHash := range hashesToRequest {
 			message, err := flow.dequeueIncomingMessageAndSkipInvs(common.DefaultTimeout)
 			if err != nil {
@@ -542,20 +543,40 @@ func (flow *handleRelayInvsFlow) syncMissingBlockBodies(highHash *externalapi.Do
 			if err != nil {
 				return err
 			}
+			blocks = append(blocks, block)
+		}
 
-			blockInsertionResult, err := flow.Domain().Consensus().ValidateAndInsertBlock(block)
-			if err != nil {
-				if errors.Is(err, ruleerrors.ErrDuplicateBlock) {
-					log.Debugf("Skipping IBD Block %s as it has already been added to the DAG", blockHash)
-					continue
-				}
-				return protocolerrors.ConvertToBanningProtocolErrorIfRuleError(err, "invalid block %s", blockHash)
-			}
-			err = flow.OnNewBlock(block, blockInsertionResult)
+		err = flow.insertIBDBlocks(blocks)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertIBDBlocks inserts a batch of IBD blocks into the consensus at once,
+// skipping any of them that were already added to the DAG
+func (flow *handleRelayInvsFlow) insertIBDBlocks(blocks []*externalapi.DomainBlock) error {
+	for len(blocks) > 0 {
+		blockInsertionResults, insertionErr := flow.Domain().Consensus().ValidateAndInsertBlocks(blocks)
+		for i, blockInsertionResult := range blockInsertionResults {
+			err := flow.OnNewBlock(blocks[i], blockInsertionResult)
 			if err != nil {
 				return err
 			}
 		}
+		if insertionErr == nil {
+			return nil
+		}
+
+		// The blocks that precede the failed block were inserted
+		failedBlockHash := consensushashing.BlockHash(blocks[len(blockInsertionResults)])
+		if !errors.Is(insertionErr, ruleerrors.ErrDuplicateBlock) {
+			return protocolerrors.ConvertToBanningProtocolErrorIfRuleError(insertionErr, "invalid block %s", failedBlockHash)
+		}
+		log.Debugf("Skipping IBD Block %s as it has already been added to the DAG", failedBlockHash)
+		blocks = blocks[len(blockInsertionResults)+1:]
 	}
 
 	return nil
@@ -94,6 +94,15 @@ func HandleHandshake(context HandleHandshakeContext, netConnection *netadapter.N
 			return nil, err
 		}
 	}
+
+	// A successful handshake with an outbound peer moves its
+	// address to the tried table of the address manager
+	if peer.IsOutbound() {
+		err := context.AddressManager().MarkConnectionSuccess(netConnection.NetAddress())
+		if err != nil {
+			return nil, err
+		}
+	}
 	return peer, nil
 }
 
@@ -145,6 +145,10 @@ func (f *fakeRelayInvsContext) Anticone(blockHash *externalapi.DomainHash) ([]*e
 	panic(errors.Errorf("called unimplemented function from test '%s'", f.testName))
 }
 
+func (f *fakeRelayInvsContext) IsFinalizedBlock(blockHash *externalapi.DomainHash) (bool, error) {
+	panic(errors.Errorf("called unimplemented function from test '%s'", f.testName))
+}
+
 func (f *fakeRelayInvsContext) BuildBlock(coinbaseData *externalapi.DomainCoinbaseData, transactions []*externalapi.DomainTransaction) (*externalapi.DomainBlock, error) {
 	panic(errors.Errorf("called unimplemented function from test '%s'", f.testName))
 }
@@ -153,6 +157,13 @@ func (f *fakeRelayInvsContext) ValidateAndInsertBlock(block *externalapi.DomainB
 	return nil, f.validateAndInsertBlockResponse
 }
 
+func (f *fakeRelayInvsContext) ValidateAndInsertBlocks(blocks []*externalapi.DomainBlock) ([]*externalapi.BlockInsertionResult, error) {
+	if f.validateAndInsertBlockResponse != nil {
+		return []*externalapi.BlockInsertionResult{}, f.validateAndInsertBlockResponse
+	}
+	return make([]*externalapi.BlockInsertionResult, len(blocks)), nil
+}
+
 func (f *fakeRelayInvsContext) ValidateTransactionAndPopulateWithConsensusData(transaction *externalapi.DomainTransaction) error {
 	panic(errors.Errorf("called unimplemented function from test '%s'", f.testName))
 }
@@ -177,6 +188,10 @@ func (f *fakeRelayInvsContext) GetBlockInfo(blockHash *externalapi.DomainHash) (
 	}, nil
 }
 
+func (f *fakeRelayInvsContext) GetBlockGHOSTDAGInfo(blockHash *externalapi.DomainHash) (*externalapi.BlockGHOSTDAGInfo, error) {
+	panic(errors.Errorf("called unimplemented function from test '%s'", f.testName))
+}
+
 func (f *fakeRelayInvsContext) GetBlockAcceptanceData(blockHash *externalapi.DomainHash) (externalapi.AcceptanceData, error) {
 	panic(errors.Errorf("called unimplemented function from test '%s'", f.testName))
 }
@@ -262,6 +277,10 @@ func (f *fakeRelayInvsContext) IsInSelectedParentChainOf(blockHashA *externalapi
 	panic(errors.Errorf("called unimplemented function from test '%s'", f.testName))
 }
 
+func (f *fakeRelayInvsContext) IsAncestorOf(blockHashA *externalapi.DomainHash, blockHashB *externalapi.DomainHash) (bool, error) {
+	panic(errors.Errorf("called unimplemented function from test '%s'", f.testName))
+}
+
 func (f *fakeRelayInvsContext) GetHeadersSelectedTip() (*externalapi.DomainHash, error) {
 	panic(errors.Errorf("called unimplemented function from test '%s'", f.testName))
 }
@@ -0,0 +1,63 @@
+package transactionrelay
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/domain"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+)
+
+// HandleDandelionTransactionsContext is the interface for the context needed for the
+// HandleDandelionTransactions flow.
+type HandleDandelionTransactionsContext interface {
+	Domain() domain.Domain
+	RelayDandelionTransaction(tx *externalapi.DomainTransaction) error
+	IsInStempool(txID *externalapi.DomainTransactionID) bool
+}
+
+type handleDandelionTransactionsFlow struct {
+	HandleDandelionTransactionsContext
+	incomingRoute *router.Route
+}
+
+// HandleDandelionTransactions listens to appmessage.MsgDandelionTx messages, which carry
+// transactions along their Dandelion stem, and relays the transactions further along
+// the stem or to the rest of the network.
+func HandleDandelionTransactions(context HandleDandelionTransactionsContext, incomingRoute *router.Route) error {
+	flow := &handleDandelionTransactionsFlow{
+		HandleDandelionTransactionsContext: context,
+		incomingRoute:                      incomingRoute,
+	}
+	return flow.start()
+}
+
+func (flow *handleDandelionTransactionsFlow) start() error {
+	for {
+		message, err := flow.incomingRoute.Dequeue()
+		if err != nil {
+			return err
+		}
+		msgDandelionTx := message.(*appmessage.MsgDandelionTx)
+
+		tx := appmessage.MsgTxToDomainTransaction(msgDandelionTx.MsgTx)
+		txID := consensushashing.TransactionID(tx)
+
+		// A transaction that is already known was either fluffed already, or
+		// went around in a loop, in which case its embargo will make sure it's
+		// eventually fluffed
+		if _, ok := flow.Domain().MiningManager().GetTransaction(txID); ok || flow.IsInStempool(txID) {
+			continue
+		}
+
+		// Orphans are not allowed along the stem, since their parents
+		// might not be relayed the same way
+		err = flow.RelayDandelionTransaction(tx)
+		if err != nil {
+			err = transactionValidationError(txID, err)
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -20,7 +20,8 @@ type TransactionsRelayContext interface {
 	Domain() domain.Domain
 	SharedRequestedTransactions() *SharedRequestedTransactions
 	Broadcast(message appmessage.Message) error
-	OnTransactionAddedToMempool()
+	OnTransactionAddedToMempool(transactions []*externalapi.DomainTransaction) error
+	OnTransactionsAnnounced(txIDs []*externalapi.DomainTransactionID) error
 }
 
 type handleRelayedTransactionsFlow struct {
@@ -47,6 +48,10 @@ func (flow *handleRelayedTransactionsFlow) start() error {
 		if err != nil {
 			return err
 		}
+		err = flow.OnTransactionsAnnounced(inv.TxIDs)
+		if err != nil {
+			return err
+		}
 
 		requestedIDs, err := flow.requestInvTransactions(inv)
 		if err != nil {
@@ -173,31 +178,41 @@ func (flow *handleRelayedTransactionsFlow) receiveTransactions(requestedTransact
 				expectedID, txID)
 		}
 
-		err = flow.Domain().MiningManager().ValidateAndInsertTransaction(tx, true)
+		acceptedTransactions, err := flow.Domain().MiningManager().ValidateAndInsertTransaction(tx, true)
 		if err != nil {
-			ruleErr := &mempool.RuleError{}
-			if !errors.As(err, ruleErr) {
-				return errors.Wrapf(err, "failed to process transaction %s", txID)
-			}
-
-			shouldBan := true
-			if txRuleErr := (&mempool.TxRuleError{}); errors.As(ruleErr.Err, txRuleErr) {
-				if txRuleErr.RejectCode != mempool.RejectInvalid {
-					shouldBan = false
-				}
+			err = transactionValidationError(txID, err)
+			if err != nil {
+				return err
 			}
-
-			if !shouldBan {
-				continue
-			}
-
-			return protocolerrors.Errorf(true, "rejected transaction %s: %s", txID, ruleErr)
+			continue
 		}
 		err = flow.broadcastAcceptedTransactions([]*externalapi.DomainTransactionID{txID})
 		if err != nil {
 			return err
 		}
-		flow.OnTransactionAddedToMempool()
+		err = flow.OnTransactionAddedToMempool(acceptedTransactions)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
+
+// transactionValidationError converts an error returned while validating a transaction
+// received from a peer into the error the flow should return: a protocol error if the
+// peer should be banned for sending the transaction, and nil if the transaction should
+// merely be ignored.
+func transactionValidationError(txID *externalapi.DomainTransactionID, err error) error {
+	ruleErr := &mempool.RuleError{}
+	if !errors.As(err, ruleErr) {
+		return errors.Wrapf(err, "failed to process transaction %s", txID)
+	}
+
+	if txRuleErr := (&mempool.TxRuleError{}); errors.As(ruleErr.Err, txRuleErr) {
+		if txRuleErr.RejectCode != mempool.RejectInvalid {
+			return nil
+		}
+	}
+
+	return protocolerrors.Errorf(true, "rejected transaction %s: %s", txID, ruleErr)
+}
@@ -29,9 +29,25 @@ func NewManager(cfg *config.Config, domain domain.Domain, netAdapter *netadapter
 	}
 
 	netAdapter.SetP2PRouterInitializer(manager.routerInitializer)
+	connectionManager.SetPeerEvictionInfoProvider(manager.peerEvictionInfos)
 	return &manager, nil
 }
 
+// peerEvictionInfos returns the data the connection manager uses
+// to decide which incoming peers to evict
+func (m *Manager) peerEvictionInfos() map[string]*connmanager.PeerEvictionInfo {
+	peers := m.context.Peers()
+	peerEvictionInfos := make(map[string]*connmanager.PeerEvictionInfo, len(peers))
+	for _, peer := range peers {
+		peerEvictionInfos[peer.Address()] = &connmanager.PeerEvictionInfo{
+			PingDuration:  peer.LastPingDuration(),
+			LastBlockTime: peer.LastBlockTime(),
+			TimeConnected: peer.TimeConnected(),
+		}
+	}
+	return peerEvictionInfos
+}
+
 // Peers returns the currently active peers
 func (m *Manager) Peers() []*peerpkg.Peer {
 	return m.context.Peers()
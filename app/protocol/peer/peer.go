@@ -35,6 +35,9 @@ type Peer struct {
 	lastPingNonce    uint64        // The nonce of the last ping we sent
 	lastPingTime     time.Time     // Time we sent last ping
 	lastPingDuration time.Duration // Time for last ping to return
+
+	lastBlockTimeLock sync.RWMutex
+	lastBlockTime     time.Time // Time the peer last relayed a block that was new to us
 }
 
 // New returns a new Peer
@@ -81,6 +84,11 @@ func (p *Peer) TimeConnected() time.Duration {
 	return time.Since(p.connectionStarted)
 }
 
+// Services returns the services the peer advertised in its version message.
+func (p *Peer) Services() appmessage.ServiceFlag {
+	return p.services
+}
+
 // IsOutbound returns whether the peer is an outbound connection.
 func (p *Peer) IsOutbound() bool {
 	return p.connection.IsOutbound()
@@ -142,3 +150,21 @@ func (p *Peer) LastPingDuration() time.Duration {
 
 	return p.lastPingDuration
 }
+
+// SetLastBlockTime records that the peer has just relayed a block
+// that was new to us
+func (p *Peer) SetLastBlockTime() {
+	p.lastBlockTimeLock.Lock()
+	defer p.lastBlockTimeLock.Unlock()
+
+	p.lastBlockTime = time.Now()
+}
+
+// LastBlockTime returns the time the peer last relayed a block that
+// was new to us. It is zero if the peer never did
+func (p *Peer) LastBlockTime() time.Time {
+	p.lastBlockTimeLock.RLock()
+	defer p.lastBlockTimeLock.RUnlock()
+
+	return p.lastBlockTime
+}
@@ -77,6 +77,12 @@ func (m *Manager) routerInitializer(router *routerpkg.Router, netConnection *net
 		}
 		defer m.context.RemoveFromPeers(peer)
 
+		// Feeler connections are only opened to test that the
+		// peer is alive, so they're done once the handshake is
+		if m.context.ConnectionManager().DisconnectIfFeeler(netConnection) {
+			return
+		}
+
 		removeHandshakeRoutes(router)
 
 		err = m.runFlows(flows, peer, errChan)
@@ -122,6 +128,9 @@ func (m *Manager) registerFlows(router *routerpkg.Router, errChan chan error, is
 	flows = append(flows, m.registerBlockRelayFlows(router, isStopping, errChan)...)
 	flows = append(flows, m.registerPingFlows(router, isStopping, errChan)...)
 	flows = append(flows, m.registerTransactionRelayFlow(router, isStopping, errChan)...)
+	if m.context.Config().Dandelion {
+		flows = append(flows, m.registerDandelionFlow(router, isStopping, errChan)...)
+	}
 	flows = append(flows, m.registerRejectsFlow(router, isStopping, errChan)...)
 
 	return flows
@@ -244,6 +253,7 @@ func (m *Manager) registerTransactionRelayFlow(router *routerpkg.Router, isStopp
 				return transactionrelay.HandleRelayedTransactions(m.context, incomingRoute, outgoingRoute)
 			},
 		),
+
 		m.registerFlow("HandleRequestTransactions", router,
 			[]appmessage.MessageCommand{appmessage.CmdRequestTransactions}, isStopping, errChan,
 			func(incomingRoute *routerpkg.Route, peer *peerpkg.Peer) error {
@@ -253,6 +263,17 @@ func (m *Manager) registerTransactionRelayFlow(router *routerpkg.Router, isStopp
 	}
 }
 
+func (m *Manager) registerDandelionFlow(router *routerpkg.Router, isStopping *uint32, errChan chan error) []*flow {
+	return []*flow{
+		m.registerFlow("HandleDandelionTransactions", router,
+			[]appmessage.MessageCommand{appmessage.CmdDandelionTx}, isStopping, errChan,
+			func(incomingRoute *routerpkg.Route, peer *peerpkg.Peer) error {
+				return transactionrelay.HandleDandelionTransactions(m.context, incomingRoute)
+			},
+		),
+	}
+}
+
 func (m *Manager) registerRejectsFlow(router *routerpkg.Router, isStopping *uint32, errChan chan error) []*flow {
 	outgoingRoute := router.OutgoingRoute()
 
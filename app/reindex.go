@@ -0,0 +1,225 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/kaspanet/kaspad/domain/consensus"
+	"github.com/kaspanet/kaspad/domain/reindex"
+	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/kaspanet/kaspad/infrastructure/db/database"
+	"github.com/kaspanet/kaspad/infrastructure/db/database/ldb"
+	"github.com/kaspanet/kaspad/infrastructure/network/addressmanager"
+	"github.com/kaspanet/kaspad/infrastructure/os/diskspace"
+	"github.com/pkg/errors"
+)
+
+// reindexSourceDatabasePath returns the path to which the database is moved
+// while it's being reindexed
+func reindexSourceDatabasePath(cfg *config.Config) string {
+	return filepath.Join(cfg.DataDir, "db-reindex-source")
+}
+
+// reindexTargetDatabasePath returns the path of the database that is being
+// built by the reindex. It replaces the original database once the reindex is done
+func reindexTargetDatabasePath(cfg *config.Config) string {
+	return filepath.Join(cfg.DataDir, "db-reindex")
+}
+
+func isReindexInProgress(cfg *config.Config) (bool, error) {
+	return pathExists(reindexSourceDatabasePath(cfg))
+}
+
+// reindexDatabase rebuilds the database by revalidating the blocks stored in it.
+// The original database is moved aside and kept until the reindexed one replaces
+// it, so that an interrupted reindex can be resumed by calling reindexDatabase again.
+//
+// Since both databases are kept until the reindex is done, it needs about as much
+// free disk space as the original database takes, which is checked before it starts.
+// Without --archival, only the pruning point UTXO set is taken from the original
+// database rather than rebuilt, and it's verified against the UTXO commitment of
+// the pruning point before it's imported
+func reindexDatabase(cfg *config.Config, interrupt <-chan struct{}) error {
+	dbPath := databasePath(cfg)
+	sourcePath := reindexSourceDatabasePath(cfg)
+	targetPath := reindexTargetDatabasePath(cfg)
+
+	sourceExists, err := pathExists(sourcePath)
+	if err != nil {
+		return err
+	}
+	dbExists, err := pathExists(dbPath)
+	if err != nil {
+		return err
+	}
+
+	if sourceExists && dbExists {
+		// The reindexed database already replaced the original one,
+		// but the original one wasn't removed yet
+		log.Infof("Removing the database from before the reindex")
+		return os.RemoveAll(sourcePath)
+	}
+	if !sourceExists {
+		if !dbExists {
+			return errors.Errorf("there is no database to reindex at '%s'", dbPath)
+		}
+		err := checkFreeSpaceForReindex(cfg, dbPath, targetPath)
+		if err != nil {
+			return err
+		}
+		err = os.Rename(dbPath, sourcePath)
+		if err != nil {
+			return err
+		}
+	} else {
+		log.Infof("Resuming the reindex of the database")
+		err := checkFreeSpaceForReindex(cfg, sourcePath, targetPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	wasReindexed, err := reindexDatabaseFrom(cfg, sourcePath, targetPath, interrupt)
+	if err != nil {
+		return err
+	}
+	if !wasReindexed {
+		log.Infof("The reindex was interrupted. Restart with --reindex to resume it")
+		return nil
+	}
+
+	err = os.Rename(targetPath, dbPath)
+	if err != nil {
+		return err
+	}
+	log.Infof("Removing the database from before the reindex")
+	return os.RemoveAll(sourcePath)
+}
+
+// checkFreeSpaceForReindex returns an error if there isn't enough free disk space to
+// build the reindexed database at targetPath next to the one at sourcePath. The
+// reindexed database is assumed to grow up to the size of the original one
+func checkFreeSpaceForReindex(cfg *config.Config, sourcePath string, targetPath string) error {
+	sourceSize, err := diskspace.DirectorySize(sourcePath)
+	if err != nil {
+		return err
+	}
+	var targetSize uint64
+	targetExists, err := pathExists(targetPath)
+	if err != nil {
+		return err
+	}
+	if targetExists {
+		targetSize, err = diskspace.DirectorySize(targetPath)
+		if err != nil {
+			return err
+		}
+	}
+	if targetSize >= sourceSize {
+		return nil
+	}
+	requiredSpace := sourceSize - targetSize
+
+	freeSpace, err := diskspace.FreeSpace(cfg.DataDir)
+	if err != nil {
+		return err
+	}
+	if freeSpace < requiredSpace {
+		return errors.Errorf("the reindex requires about %d MiB of free disk space in '%s', "+
+			"but only %d MiB are available", requiredSpace>>20, cfg.DataDir, freeSpace>>20)
+	}
+	return nil
+}
+
+func reindexDatabaseFrom(cfg *config.Config, sourcePath string, targetPath string,
+	interrupt <-chan struct{}) (wasReindexed bool, err error) {
+
+	log.Infof("Loading the database to reindex from '%s'", sourcePath)
+	sourceDatabase, err := ldb.NewLevelDB(sourcePath, leveldbCacheSizeMiB)
+	if err != nil {
+		return false, err
+	}
+	defer closeDatabase(sourceDatabase, sourcePath)
+
+	log.Infof("Loading the reindexed database from '%s'", targetPath)
+	targetDatabase, err := ldb.NewLevelDB(targetPath, leveldbCacheSizeMiB)
+	if err != nil {
+		return false, err
+	}
+	defer closeDatabase(targetDatabase, targetPath)
+
+	consensusFactory := consensus.NewFactory()
+	sourceConsensus, err := consensusFactory.NewConsensus(cfg.ActiveNetParams, sourceDatabase, cfg.IsArchivalNode)
+	if err != nil {
+		return false, err
+	}
+	targetConsensus, err := consensusFactory.NewConsensus(cfg.ActiveNetParams, targetDatabase, cfg.IsArchivalNode)
+	if err != nil {
+		return false, err
+	}
+
+	if cfg.IsArchivalNode {
+		log.Infof("Reindexing the database from genesis")
+	} else {
+		log.Infof("Reindexing the database from the pruning point")
+	}
+	err = reindex.Reindex(sourceConsensus, targetConsensus, cfg.IsArchivalNode, interrupt)
+	if err != nil {
+		if errors.Is(err, reindex.ErrInterrupted) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	// The known peer addresses are not derived from the blocks, so they're copied as is
+	for _, bucket := range addressmanager.StoreBuckets {
+		err := copyBucket(sourceDatabase, targetDatabase, bucket)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+func copyBucket(source database.Database, target database.Database, bucket *database.Bucket) error {
+	cursor, err := source.Cursor(bucket)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	for ok := cursor.First(); ok; ok = cursor.Next() {
+		key, err := cursor.Key()
+		if err != nil {
+			return err
+		}
+		value, err := cursor.Value()
+		if err != nil {
+			return err
+		}
+		err = target.Put(key, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func closeDatabase(db database.Database, path string) {
+	err := db.Close()
+	if err != nil {
+		log.Errorf("Failed to close the database at '%s': %s", path, err)
+	}
+}
+
+func pathExists(path string) (bool, error) {
+	_, err := os.Stat(path)
+	if err == nil {
+		return true, nil
+	}
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return false, err
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
 	"github.com/kaspanet/kaspad/domain"
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
 	"github.com/kaspanet/kaspad/domain/utxoindex"
 	"github.com/kaspanet/kaspad/infrastructure/config"
 	"github.com/kaspanet/kaspad/infrastructure/logger"
@@ -70,7 +71,7 @@ func (m *Manager) NotifyBlockAddedToDAG(block *externalapi.DomainBlock, blockIns
 	}
 
 	msgBlock := appmessage.DomainBlockToMsgBlock(block)
-	blockVerboseData, err := m.context.BuildBlockVerboseData(block.Header, block, false)
+	blockVerboseData, err := m.context.BuildBlockVerboseData(block.Header, block, false, nil)
 	if err != nil {
 		return err
 	}
@@ -94,6 +95,40 @@ func (m *Manager) NotifyPruningPointUTXOSetOverride() error {
 	return nil
 }
 
+// NotifyTransactionAddedToMempool notifies the manager that transactions have been added to the mempool
+func (m *Manager) NotifyTransactionAddedToMempool(transactions []*externalapi.DomainTransaction) error {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "RPCManager.NotifyTransactionAddedToMempool")
+	defer onEnd()
+
+	if m.context.Config.UTXOIndex {
+		utxoChanges := rpccontext.ConvertTransactionsToMempoolUTXOChanges(transactions)
+		err := m.context.NotificationManager.NotifyMempoolUTXOsChanged(utxoChanges)
+		if err != nil {
+			return err
+		}
+	}
+
+	m.context.NotificationManager.NotifyTransactionsAddedToMempool(transactions)
+	return nil
+}
+
+// NotifyTransactionsRemovedFromMempool notifies the manager that transactions have been removed from the mempool
+func (m *Manager) NotifyTransactionsRemovedFromMempool(removedTransactions []*miningmanagermodel.RemovedTransaction) {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "RPCManager.NotifyTransactionsRemovedFromMempool")
+	defer onEnd()
+
+	if m.context.Config.UTXOIndex {
+		utxoChanges := rpccontext.ConvertRemovedTransactionsToMempoolUTXOChanges(removedTransactions)
+		err := m.context.NotificationManager.NotifyMempoolUTXOsChanged(utxoChanges)
+		if err != nil {
+			log.Warnf("Couldn't send the UTXOs changed notification of the transactions removed "+
+				"from the mempool: %s", err)
+		}
+	}
+
+	m.context.NotificationManager.NotifyTransactionsRemovedFromMempool(removedTransactions)
+}
+
 // NotifyFinalityConflict notifies the manager that there's a finality conflict in the DAG
 func (m *Manager) NotifyFinalityConflict(violatingBlockHash string) error {
 	onEnd := logger.LogAndMeasureExecutionTime(log, "RPCManager.NotifyFinalityConflict")
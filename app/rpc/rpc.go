@@ -44,6 +44,17 @@ var handlers = map[appmessage.MessageCommand]handler{
 	appmessage.CmdGetInfoRequestMessage:                                     rpchandlers.HandleGetInfo,
 	appmessage.CmdNotifyPruningPointUTXOSetOverrideRequestMessage:           rpchandlers.HandleNotifyPruningPointUTXOSetOverrideRequest,
 	appmessage.CmdStopNotifyingPruningPointUTXOSetOverrideRequestMessage:    rpchandlers.HandleStopNotifyingPruningPointUTXOSetOverrideRequest,
+	appmessage.CmdGetDAGSubgraphRequestMessage:                              rpchandlers.HandleGetDAGSubgraph,
+	appmessage.CmdGetBalanceByAddressRequestMessage:                         rpchandlers.HandleGetBalanceByAddress,
+	appmessage.CmdGetBalancesByAddressesRequestMessage:                      rpchandlers.HandleGetBalancesByAddresses,
+	appmessage.CmdDumpMempoolRequestMessage:                                 rpchandlers.HandleDumpMempool,
+	appmessage.CmdGetMempoolAncestorsRequestMessage:                         rpchandlers.HandleGetMempoolAncestors,
+	appmessage.CmdGetMempoolDescendantsRequestMessage:                       rpchandlers.HandleGetMempoolDescendants,
+	appmessage.CmdGetMempoolStatsRequestMessage:                             rpchandlers.HandleGetMempoolStats,
+	appmessage.CmdNotifyMempoolTransactionsRequestMessage:                   rpchandlers.HandleNotifyMempoolTransactions,
+	appmessage.CmdStopNotifyingMempoolTransactionsRequestMessage:            rpchandlers.HandleStopNotifyingMempoolTransactions,
+	appmessage.CmdGetBlockAcceptanceStatusRequestMessage:                    rpchandlers.HandleGetBlockAcceptanceStatus,
+	appmessage.CmdGetTransactionConfirmationsRequestMessage:                 rpchandlers.HandleGetTransactionConfirmations,
 }
 
 func (m *Manager) routerInitializer(router *router.Router, netConnection *netadapter.NetConnection) {
@@ -0,0 +1,207 @@
+package rpccontext
+
+import (
+	"sort"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/hashset"
+)
+
+// BlockAcceptance holds data about the acceptance of a
+// block and its transactions by the virtual's selected parent chain
+type BlockAcceptance struct {
+	// AcceptingBlockHash is the chain block whose merge set
+	// contains the block. It's nil if no chain block merged it
+	// yet, in which case it's merged by the virtual
+	AcceptingBlockHash *externalapi.DomainHash
+
+	// Confirmations is the blue score difference between the
+	// virtual and the accepting block, or zero if there's none
+	Confirmations uint64
+
+	// IsBlue is whether the block is blue in the merge set
+	// of the accepting block or of the virtual
+	IsBlue bool
+
+	// TransactionAcceptanceData is ordered in the same way as the
+	// transactions of the block. It's nil if there's no accepting block
+	TransactionAcceptanceData []*externalapi.TransactionAcceptanceData
+}
+
+// BlockAcceptanceResolver finds the acceptance of blocks against a single
+// view of the virtual. It walks down the virtual's selected parent chain only
+// as far as the blocks it was asked about require, and reuses that walk for
+// later blocks, so a request that's about many blocks should use a single
+// BlockAcceptanceResolver for all of them
+type BlockAcceptanceResolver struct {
+	ctx         *Context
+	virtualInfo *externalapi.VirtualInfo
+
+	// selectedParentChain is the part of the virtual's selected parent
+	// chain that was walked so far, starting from the virtual's selected parent
+	selectedParentChain []*externalapi.DomainHash
+
+	// isSelectedParentChainComplete is whether selectedParentChain
+	// reached the lowest chain block that's available
+	isSelectedParentChainComplete bool
+}
+
+// NewBlockAcceptanceResolver returns a BlockAcceptanceResolver
+// over the current state of the virtual
+func (ctx *Context) NewBlockAcceptanceResolver() (*BlockAcceptanceResolver, error) {
+	virtualInfo, err := ctx.Domain.Consensus().GetVirtualInfo()
+	if err != nil {
+		return nil, err
+	}
+	virtualSelectedParent, err := ctx.Domain.Consensus().GetVirtualSelectedParent()
+	if err != nil {
+		return nil, err
+	}
+	return &BlockAcceptanceResolver{
+		ctx:                 ctx,
+		virtualInfo:         virtualInfo,
+		selectedParentChain: []*externalapi.DomainHash{virtualSelectedParent},
+	}, nil
+}
+
+// BlockAcceptance returns data about the acceptance of the given block, which
+// must have a body. It's a shorthand for querying a new BlockAcceptanceResolver
+// about a single block
+func (ctx *Context) BlockAcceptance(blockHash *externalapi.DomainHash) (*BlockAcceptance, error) {
+	resolver, err := ctx.NewBlockAcceptanceResolver()
+	if err != nil {
+		return nil, err
+	}
+	return resolver.BlockAcceptance(blockHash)
+}
+
+// BlockAcceptance returns data about the acceptance of the given block, which
+// must have a body. The transactions of the virtual's selected parent, as well
+// as those of the other blocks that no chain block merged yet, are accepted
+// only by the virtual.
+func (r *BlockAcceptanceResolver) BlockAcceptance(blockHash *externalapi.DomainHash) (*BlockAcceptance, error) {
+	acceptingBlockHash, err := r.acceptingBlock(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if acceptingBlockHash == nil {
+		return &BlockAcceptance{
+			IsBlue: hashset.NewFromSlice(r.virtualInfo.MergeSetBlues...).Contains(blockHash),
+		}, nil
+	}
+
+	acceptingBlockInfo, err := r.ctx.Domain.Consensus().GetBlockInfo(acceptingBlockHash)
+	if err != nil {
+		return nil, err
+	}
+	acceptingBlockGHOSTDAGInfo, err := r.ctx.Domain.Consensus().GetBlockGHOSTDAGInfo(acceptingBlockHash)
+	if err != nil {
+		return nil, err
+	}
+	acceptanceData, err := r.ctx.Domain.Consensus().GetBlockAcceptanceData(acceptingBlockHash)
+	if err != nil {
+		return nil, err
+	}
+	var transactionAcceptanceData []*externalapi.TransactionAcceptanceData
+	for _, blockAcceptanceData := range acceptanceData {
+		if blockAcceptanceData.BlockHash.Equal(blockHash) {
+			transactionAcceptanceData = blockAcceptanceData.TransactionAcceptanceData
+			break
+		}
+	}
+
+	return &BlockAcceptance{
+		AcceptingBlockHash:        acceptingBlockHash,
+		Confirmations:             r.virtualInfo.BlueScore - acceptingBlockInfo.BlueScore,
+		IsBlue:                    hashset.NewFromSlice(acceptingBlockGHOSTDAGInfo.MergeSetBlues...).Contains(blockHash),
+		TransactionAcceptanceData: transactionAcceptanceData,
+	}, nil
+}
+
+// acceptingBlock returns the block in the virtual's selected parent chain
+// whose merge set contains the given block, or nil if there's no such block.
+//
+// That's the lowest chain block that has the given block in its past. Since
+// the past of a chain block contains the pasts of the chain blocks below it,
+// the chain blocks that have the given block in their past are a prefix of
+// selectedParentChain, so the accepting block is found by binary search.
+func (r *BlockAcceptanceResolver) acceptingBlock(blockHash *externalapi.DomainHash) (*externalapi.DomainHash, error) {
+	isInPast, err := r.isInPastOf(blockHash, r.selectedParentChain[0])
+	if err != nil {
+		return nil, err
+	}
+	if !isInPast {
+		return nil, nil
+	}
+
+	// Double the walked chain until it reaches below the given block
+	for !r.isSelectedParentChainComplete {
+		isInPast, err := r.isInPastOf(blockHash, r.selectedParentChain[len(r.selectedParentChain)-1])
+		if err != nil {
+			return nil, err
+		}
+		if !isInPast {
+			break
+		}
+		err = r.walkSelectedParentChain(len(r.selectedParentChain))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Find the first chain block that doesn't have the given block in its past.
+	// The one above it is the accepting block
+	var searchErr error
+	firstNotInPastIndex := sort.Search(len(r.selectedParentChain), func(i int) bool {
+		if searchErr != nil {
+			return true
+		}
+		isInPast, err := r.isInPastOf(blockHash, r.selectedParentChain[i])
+		if err != nil {
+			searchErr = err
+			return true
+		}
+		return !isInPast
+	})
+	if searchErr != nil {
+		return nil, searchErr
+	}
+	return r.selectedParentChain[firstNotInPastIndex-1], nil
+}
+
+// isInPastOf returns whether blockHash is in the past of chainBlockHash,
+// not including chainBlockHash itself
+func (r *BlockAcceptanceResolver) isInPastOf(blockHash, chainBlockHash *externalapi.DomainHash) (bool, error) {
+	if blockHash.Equal(chainBlockHash) {
+		return false, nil
+	}
+	return r.ctx.Domain.Consensus().IsAncestorOf(blockHash, chainBlockHash)
+}
+
+// walkSelectedParentChain appends up to the given number of chain blocks to selectedParentChain
+func (r *BlockAcceptanceResolver) walkSelectedParentChain(numberOfBlocks int) error {
+	for i := 0; i < numberOfBlocks; i++ {
+		lowestChainBlock := r.selectedParentChain[len(r.selectedParentChain)-1]
+		lowestChainBlockGHOSTDAGInfo, err := r.ctx.Domain.Consensus().GetBlockGHOSTDAGInfo(lowestChainBlock)
+		if err != nil {
+			return err
+		}
+		selectedParent := lowestChainBlockGHOSTDAGInfo.SelectedParent
+		// The selected parent of the genesis is nil, and the
+		// selected parent of the pruning point might be pruned
+		if selectedParent == nil {
+			r.isSelectedParentChainComplete = true
+			return nil
+		}
+		selectedParentInfo, err := r.ctx.Domain.Consensus().GetBlockInfo(selectedParent)
+		if err != nil {
+			return err
+		}
+		if !selectedParentInfo.Exists {
+			r.isSelectedParentChainComplete = true
+			return nil
+		}
+		r.selectedParentChain = append(r.selectedParentChain, selectedParent)
+	}
+	return nil
+}
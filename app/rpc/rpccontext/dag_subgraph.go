@@ -0,0 +1,271 @@
+package rpccontext
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/hashes"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/hashset"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxDAGSubgraphBlocks is the maximum amount of blocks a DAG subgraph may contain
+	maxDAGSubgraphBlocks = 1000
+
+	// maxDAGSubgraphTraversedBlocks is the maximum amount of blocks that are
+	// traversed while looking for the blocks of a blue score range
+	maxDAGSubgraphTraversedBlocks = 100_000
+)
+
+// ErrDAGSubgraphTooLarge indicates that the requested DAG subgraph contains, or
+// requires traversing, too many blocks
+var ErrDAGSubgraphTooLarge = errors.New("ErrDAGSubgraphTooLarge")
+
+// BuildDAGSubgraphByBlueScore builds the DAG subgraph that consists of the blocks
+// whose blue score is between lowBlueScore and highBlueScore (inclusive).
+// The blocks are found by traversing the DAG downwards from its tips, stopping
+// at blocks below lowBlueScore. A block may have a larger blue score than its
+// child if it's not the child's selected parent, so a block of the range whose
+// children are all below lowBlueScore isn't found. Since there's no bound on how
+// far below lowBlueScore such a block may be, isTruncated is returned to tell
+// whether the traversal stopped at any block below lowBlueScore, in which case
+// the subgraph might be missing such blocks.
+func (ctx *Context) BuildDAGSubgraphByBlueScore(lowBlueScore uint64, highBlueScore uint64) (
+	blocks []*appmessage.DAGSubgraphBlock, isTruncated bool, err error) {
+
+	onEnd := logger.LogAndMeasureExecutionTime(log, "BuildDAGSubgraphByBlueScore")
+	defer onEnd()
+
+	tips, err := ctx.Domain.Consensus().Tips()
+	if err != nil {
+		return nil, false, err
+	}
+
+	blockInfos := make(map[externalapi.DomainHash]*externalapi.BlockInfo)
+	var subgraphHashes []*externalapi.DomainHash
+	visited := hashset.NewFromSlice(tips...)
+	queue := tips
+	for len(queue) > 0 {
+		if visited.Length() > maxDAGSubgraphTraversedBlocks {
+			return nil, false, errors.Wrapf(ErrDAGSubgraphTooLarge, "finding the blocks of blue scores %d-%d requires "+
+				"traversing more than %d blocks", lowBlueScore, highBlueScore, maxDAGSubgraphTraversedBlocks)
+		}
+
+		var current *externalapi.DomainHash
+		current, queue = queue[0], queue[1:]
+
+		blockInfo, err := ctx.Domain.Consensus().GetBlockInfo(current)
+		if err != nil {
+			return nil, false, err
+		}
+		if !blockInfo.Exists || blockInfo.BlockStatus == externalapi.StatusInvalid {
+			continue
+		}
+		if blockInfo.BlueScore < lowBlueScore {
+			isTruncated = true
+			continue
+		}
+		blockInfos[*current] = blockInfo
+		if blockInfo.BlueScore <= highBlueScore {
+			if len(subgraphHashes) == maxDAGSubgraphBlocks {
+				return nil, false, errors.Wrapf(ErrDAGSubgraphTooLarge, "blue scores %d-%d contain more than %d blocks",
+					lowBlueScore, highBlueScore, maxDAGSubgraphBlocks)
+			}
+			subgraphHashes = append(subgraphHashes, current)
+		}
+
+		header, err := ctx.Domain.Consensus().GetBlockHeader(current)
+		if err != nil {
+			return nil, false, err
+		}
+		for _, parentHash := range header.ParentHashes() {
+			if visited.Contains(parentHash) {
+				continue
+			}
+			visited.Add(parentHash)
+			queue = append(queue, parentHash)
+		}
+	}
+
+	blocks, err = ctx.buildDAGSubgraphBlocks(subgraphHashes, blockInfos)
+	if err != nil {
+		return nil, false, err
+	}
+	return blocks, isTruncated, nil
+}
+
+// BuildDAGSubgraphByAnticone builds the DAG subgraph that consists of the given
+// block and its anticone
+func (ctx *Context) BuildDAGSubgraphByAnticone(blockHash *externalapi.DomainHash) (
+	[]*appmessage.DAGSubgraphBlock, error) {
+
+	onEnd := logger.LogAndMeasureExecutionTime(log, "BuildDAGSubgraphByAnticone")
+	defer onEnd()
+
+	anticone, err := ctx.Domain.Consensus().Anticone(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(anticone)+1 > maxDAGSubgraphBlocks {
+		return nil, errors.Wrapf(ErrDAGSubgraphTooLarge, "the anticone of %s contains more than %d blocks",
+			blockHash, maxDAGSubgraphBlocks)
+	}
+
+	subgraphHashes := append([]*externalapi.DomainHash{blockHash}, anticone...)
+	return ctx.buildDAGSubgraphBlocks(subgraphHashes, make(map[externalapi.DomainHash]*externalapi.BlockInfo))
+}
+
+// buildDAGSubgraphBlocks builds a DAGSubgraphBlock for each of the given hashes.
+// blockInfos may contain the already known block infos of some of the blocks
+func (ctx *Context) buildDAGSubgraphBlocks(subgraphHashes []*externalapi.DomainHash,
+	blockInfos map[externalapi.DomainHash]*externalapi.BlockInfo) ([]*appmessage.DAGSubgraphBlock, error) {
+
+	getBlockInfo := func(blockHash *externalapi.DomainHash) (*externalapi.BlockInfo, error) {
+		blockInfo, ok := blockInfos[*blockHash]
+		if ok {
+			return blockInfo, nil
+		}
+		blockInfo, err := ctx.Domain.Consensus().GetBlockInfo(blockHash)
+		if err != nil {
+			return nil, err
+		}
+		blockInfos[*blockHash] = blockInfo
+		return blockInfo, nil
+	}
+
+	blocks := make([]*appmessage.DAGSubgraphBlock, 0, len(subgraphHashes))
+	minBlueScore := uint64(0)
+	for _, blockHash := range subgraphHashes {
+		blockInfo, err := getBlockInfo(blockHash)
+		if err != nil {
+			return nil, err
+		}
+		if !blockInfo.Exists || blockInfo.BlockStatus == externalapi.StatusInvalid {
+			continue
+		}
+		header, err := ctx.Domain.Consensus().GetBlockHeader(blockHash)
+		if err != nil {
+			return nil, err
+		}
+		ghostdagInfo, err := ctx.Domain.Consensus().GetBlockGHOSTDAGInfo(blockHash)
+		if err != nil {
+			return nil, err
+		}
+
+		block := &appmessage.DAGSubgraphBlock{
+			Hash:                blockHash.String(),
+			BlueScore:           blockInfo.BlueScore,
+			ParentHashes:        hashes.ToStrings(header.ParentHashes()),
+			MergeSetBluesHashes: hashes.ToStrings(ghostdagInfo.MergeSetBlues),
+			MergeSetRedsHashes:  hashes.ToStrings(ghostdagInfo.MergeSetReds),
+			Color:               appmessage.DAGSubgraphBlockColorUnmerged,
+		}
+		if ghostdagInfo.SelectedParent != nil {
+			block.SelectedParentHash = ghostdagInfo.SelectedParent.String()
+		}
+		blocks = append(blocks, block)
+
+		if len(blocks) == 1 || blockInfo.BlueScore < minBlueScore {
+			minBlueScore = blockInfo.BlueScore
+		}
+	}
+
+	// A block is colored by the first chain block in its future, which merges it.
+	// Every such chain block has a higher blue score than the block it merges, so
+	// it's enough to walk down the virtual selected parent chain until minBlueScore
+	colors := make(map[string]string)
+	chainBlocks := make(map[string]struct{})
+	chainBlockHash, err := ctx.Domain.Consensus().GetVirtualSelectedParent()
+	if err != nil {
+		return nil, err
+	}
+	// The virtual selected parent is blue by definition
+	colors[chainBlockHash.String()] = appmessage.DAGSubgraphBlockColorBlue
+	for chainBlockHash != nil {
+		chainBlockInfo, err := getBlockInfo(chainBlockHash)
+		if err != nil {
+			return nil, err
+		}
+		if !chainBlockInfo.Exists || chainBlockInfo.BlueScore < minBlueScore {
+			break
+		}
+		chainBlockGHOSTDAGInfo, err := ctx.Domain.Consensus().GetBlockGHOSTDAGInfo(chainBlockHash)
+		if err != nil {
+			return nil, err
+		}
+		chainBlocks[chainBlockHash.String()] = struct{}{}
+		for _, blueHash := range chainBlockGHOSTDAGInfo.MergeSetBlues {
+			colors[blueHash.String()] = appmessage.DAGSubgraphBlockColorBlue
+		}
+		for _, redHash := range chainBlockGHOSTDAGInfo.MergeSetReds {
+			colors[redHash.String()] = appmessage.DAGSubgraphBlockColorRed
+		}
+		chainBlockHash = chainBlockGHOSTDAGInfo.SelectedParent
+	}
+
+	for _, block := range blocks {
+		_, block.IsChainBlock = chainBlocks[block.Hash]
+		if color, ok := colors[block.Hash]; ok {
+			block.Color = color
+		}
+	}
+
+	sort.Slice(blocks, func(i, j int) bool {
+		if blocks[i].BlueScore != blocks[j].BlueScore {
+			return blocks[i].BlueScore < blocks[j].BlueScore
+		}
+		return blocks[i].Hash < blocks[j].Hash
+	})
+	return blocks, nil
+}
+
+// DAGSubgraphToDot renders the given DAG subgraph in Graphviz DOT format.
+// Blocks are filled according to their color and chain blocks are outlined in bold.
+// Edges point from a block to its parents, and the edge to the selected parent is bold
+func DAGSubgraphToDot(blocks []*appmessage.DAGSubgraphBlock) string {
+	fillColors := map[string]string{
+		appmessage.DAGSubgraphBlockColorBlue:     "lightblue",
+		appmessage.DAGSubgraphBlockColorRed:      "lightcoral",
+		appmessage.DAGSubgraphBlockColorUnmerged: "lightgray",
+	}
+
+	inSubgraph := make(map[string]struct{}, len(blocks))
+	for _, block := range blocks {
+		inSubgraph[block.Hash] = struct{}{}
+	}
+
+	var dotScriptBuilder strings.Builder
+	dotScriptBuilder.WriteString("digraph {\n\trankdir = TB;\n\tnode [style = filled];\n")
+
+	edges := []string{}
+	for _, block := range blocks {
+		penWidth := 1
+		if block.IsChainBlock {
+			penWidth = 3
+		}
+		dotScriptBuilder.WriteString(fmt.Sprintf("\t\"%s\" [label = \"%s\\n%d\", fillcolor = %s, penwidth = %d];\n",
+			block.Hash, block.Hash[:8], block.BlueScore, fillColors[block.Color], penWidth))
+
+		for _, parentHash := range block.ParentHashes {
+			if _, ok := inSubgraph[parentHash]; !ok {
+				continue
+			}
+			edgeStyle := "solid"
+			if parentHash == block.SelectedParentHash {
+				edgeStyle = "bold"
+			}
+			edges = append(edges, fmt.Sprintf("\t\"%s\" -> \"%s\" [style = %s];", block.Hash, parentHash, edgeStyle))
+		}
+	}
+
+	dotScriptBuilder.WriteString("\n")
+	dotScriptBuilder.WriteString(strings.Join(edges, "\n"))
+	dotScriptBuilder.WriteString("\n}\n")
+
+	return dotScriptBuilder.String()
+}
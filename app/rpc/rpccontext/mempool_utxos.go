@@ -0,0 +1,179 @@
+package rpccontext
+
+import (
+	"math"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/utxo"
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
+	"github.com/kaspanet/kaspad/domain/utxoindex"
+)
+
+// unconfirmedBlueScore is the block blue score of the UTXO entries of outputs of
+// transactions in the mempool, which matches the one the mempool gives them
+const unconfirmedBlueScore = math.MaxUint64
+
+// MempoolUTXOs returns the outputs of the transactions in the mempool that
+// are not spent by other transactions in the mempool, grouped by their scriptPublicKey
+func (ctx *Context) MempoolUTXOs() map[utxoindex.ScriptPublicKeyString]utxoindex.UTXOOutpointEntryPairs {
+	mempoolUTXOs := make(map[utxoindex.ScriptPublicKeyString]utxoindex.UTXOOutpointEntryPairs)
+	for _, pair := range ctx.Domain.MiningManager().UnspentOutputs() {
+		scriptPublicKeyString := utxoindex.ConvertScriptPublicKeyToString(pair.UTXOEntry.ScriptPublicKey())
+		if _, ok := mempoolUTXOs[scriptPublicKeyString]; !ok {
+			mempoolUTXOs[scriptPublicKeyString] = make(utxoindex.UTXOOutpointEntryPairs)
+		}
+		mempoolUTXOs[scriptPublicKeyString][*pair.Outpoint] = pair.UTXOEntry
+	}
+	return mempoolUTXOs
+}
+
+// ExcludeUTXOsSpentInMempool returns the given entries without the ones whose
+// outpoints are spent by transactions in the mempool
+func (ctx *Context) ExcludeUTXOsSpentInMempool(
+	pairs []*externalapi.OutpointAndUTXOEntryPair) []*externalapi.OutpointAndUTXOEntryPair {
+
+	unspentPairs := make([]*externalapi.OutpointAndUTXOEntryPair, 0, len(pairs))
+	for _, pair := range pairs {
+		if ctx.Domain.MiningManager().IsOutpointSpent(pair.Outpoint) {
+			continue
+		}
+		unspentPairs = append(unspentPairs, pair)
+	}
+	return unspentPairs
+}
+
+// ConvertTransactionsToMempoolUTXOChanges converts transactions that were added to
+// the mempool to the changes they make to the UTXOs of their addresses
+func ConvertTransactionsToMempoolUTXOChanges(transactions []*externalapi.DomainTransaction) *utxoindex.UTXOChanges {
+	utxoChanges := &utxoindex.UTXOChanges{
+		Added:   make(map[utxoindex.ScriptPublicKeyString]utxoindex.UTXOOutpointEntryPairs),
+		Removed: make(map[utxoindex.ScriptPublicKeyString]utxoindex.UTXOOutpoints),
+	}
+	for _, transaction := range transactions {
+		for _, input := range transaction.Inputs {
+			// The UTXO entries of the inputs are populated when
+			// the transaction is validated by the mempool
+			if input.UTXOEntry == nil {
+				continue
+			}
+			scriptPublicKeyString := utxoindex.ConvertScriptPublicKeyToString(input.UTXOEntry.ScriptPublicKey())
+			if _, ok := utxoChanges.Removed[scriptPublicKeyString]; !ok {
+				utxoChanges.Removed[scriptPublicKeyString] = make(utxoindex.UTXOOutpoints)
+			}
+			utxoChanges.Removed[scriptPublicKeyString][input.PreviousOutpoint] = struct{}{}
+		}
+
+		transactionID := consensushashing.TransactionID(transaction)
+		for i, output := range transaction.Outputs {
+			scriptPublicKeyString := utxoindex.ConvertScriptPublicKeyToString(output.ScriptPublicKey)
+			if _, ok := utxoChanges.Added[scriptPublicKeyString]; !ok {
+				utxoChanges.Added[scriptPublicKeyString] = make(utxoindex.UTXOOutpointEntryPairs)
+			}
+			outpoint := externalapi.DomainOutpoint{TransactionID: *transactionID, Index: uint32(i)}
+			utxoChanges.Added[scriptPublicKeyString][outpoint] =
+				utxo.NewUTXOEntry(output.Value, output.ScriptPublicKey, false, unconfirmedBlueScore)
+		}
+	}
+	return utxoChanges
+}
+
+// ConvertRemovedTransactionsToMempoolUTXOChanges converts transactions that were removed
+// from the mempool to the changes that revert the ones they made when they were added:
+// their outputs are removed, and the outpoints they spent are unspent again.
+// Mined transactions are skipped, since the UTXOsChanged notification of the block
+// that included them covers them. So are the outpoints spent by a transaction whose
+// parent was removed along with it
+func ConvertRemovedTransactionsToMempoolUTXOChanges(
+	removedTransactions []*miningmanagermodel.RemovedTransaction) *utxoindex.UTXOChanges {
+
+	utxoChanges := &utxoindex.UTXOChanges{
+		Added:   make(map[utxoindex.ScriptPublicKeyString]utxoindex.UTXOOutpointEntryPairs),
+		Removed: make(map[utxoindex.ScriptPublicKeyString]utxoindex.UTXOOutpoints),
+	}
+	revertedTransactionIDs := make(map[externalapi.DomainTransactionID]struct{})
+	for _, removedTransaction := range removedTransactions {
+		if removedTransaction.Reason == miningmanagermodel.TransactionRemovalReasonMined {
+			continue
+		}
+		revertedTransactionIDs[*consensushashing.TransactionID(removedTransaction.Transaction)] = struct{}{}
+	}
+
+	for _, removedTransaction := range removedTransactions {
+		transaction := removedTransaction.Transaction
+		transactionID := consensushashing.TransactionID(transaction)
+		if _, ok := revertedTransactionIDs[*transactionID]; !ok {
+			continue
+		}
+
+		for _, input := range transaction.Inputs {
+			if input.UTXOEntry == nil {
+				continue
+			}
+			if _, ok := revertedTransactionIDs[input.PreviousOutpoint.TransactionID]; ok {
+				continue
+			}
+			scriptPublicKeyString := utxoindex.ConvertScriptPublicKeyToString(input.UTXOEntry.ScriptPublicKey())
+			if _, ok := utxoChanges.Added[scriptPublicKeyString]; !ok {
+				utxoChanges.Added[scriptPublicKeyString] = make(utxoindex.UTXOOutpointEntryPairs)
+			}
+			utxoChanges.Added[scriptPublicKeyString][input.PreviousOutpoint] = input.UTXOEntry
+		}
+
+		for i, output := range transaction.Outputs {
+			scriptPublicKeyString := utxoindex.ConvertScriptPublicKeyToString(output.ScriptPublicKey)
+			if _, ok := utxoChanges.Removed[scriptPublicKeyString]; !ok {
+				utxoChanges.Removed[scriptPublicKeyString] = make(utxoindex.UTXOOutpoints)
+			}
+			outpoint := externalapi.DomainOutpoint{TransactionID: *transactionID, Index: uint32(i)}
+			utxoChanges.Removed[scriptPublicKeyString][outpoint] = struct{}{}
+		}
+	}
+	return utxoChanges
+}
+
+// markUnconfirmedUTXOsByAddressesEntries marks the entries that are outputs of transactions
+// in the mempool as unconfirmed. Mempool UTXOsChanged notifications carry such entries,
+// along with confirmed entries that are unspent again after the transactions that spent
+// them were removed from the mempool
+func markUnconfirmedUTXOsByAddressesEntries(entries []*appmessage.UTXOsByAddressesEntry) {
+	for _, entry := range entries {
+		if entry.UTXOEntry.BlockBlueScore == unconfirmedBlueScore {
+			entry.IsUnconfirmed = true
+			entry.UTXOEntry.BlockBlueScore = 0
+		}
+	}
+}
+
+// MarkUTXOsByAddressesEntriesAsUnconfirmed marks the given entries as outputs of
+// transactions in the mempool. Unconfirmed entries have no block blue score
+func MarkUTXOsByAddressesEntriesAsUnconfirmed(entries []*appmessage.UTXOsByAddressesEntry) {
+	for _, entry := range entries {
+		entry.IsUnconfirmed = true
+		entry.UTXOEntry.BlockBlueScore = 0
+	}
+}
+
+// IsTransactionRelatedToScriptPublicKeys returns whether the given mempool transaction
+// spends from or pays to any of the given scriptPublicKeys
+func IsTransactionRelatedToScriptPublicKeys(tx *externalapi.DomainTransaction,
+	scriptPublicKeys map[utxoindex.ScriptPublicKeyString]struct{}) bool {
+
+	for _, output := range tx.Outputs {
+		if _, ok := scriptPublicKeys[utxoindex.ConvertScriptPublicKeyToString(output.ScriptPublicKey)]; ok {
+			return true
+		}
+	}
+	for _, input := range tx.Inputs {
+		// The UTXO entries of the inputs are populated when
+		// the transaction is validated by the mempool
+		if input.UTXOEntry == nil {
+			continue
+		}
+		if _, ok := scriptPublicKeys[utxoindex.ConvertScriptPublicKeyToString(input.UTXOEntry.ScriptPublicKey())]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package rpccontext
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/utxo"
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
+	"github.com/kaspanet/kaspad/domain/utxoindex"
+)
+
+func TestConvertRemovedTransactionsToMempoolUTXOChanges(t *testing.T) {
+	scriptPublicKey := &externalapi.ScriptPublicKey{Script: []byte{1}, Version: 0}
+	scriptPublicKeyString := utxoindex.ConvertScriptPublicKeyToString(scriptPublicKey)
+
+	newTransaction := func(previousOutpoint externalapi.DomainOutpoint,
+		utxoEntry externalapi.UTXOEntry) *externalapi.DomainTransaction {
+
+		return &externalapi.DomainTransaction{
+			Inputs: []*externalapi.DomainTransactionInput{{
+				PreviousOutpoint: previousOutpoint,
+				UTXOEntry:        utxoEntry,
+			}},
+			Outputs: []*externalapi.DomainTransactionOutput{{
+				Value:           utxoEntry.Amount() - 1,
+				ScriptPublicKey: scriptPublicKey,
+			}},
+		}
+	}
+
+	confirmedOutpoint := externalapi.DomainOutpoint{
+		TransactionID: *externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{1})}
+	confirmedEntry := utxo.NewUTXOEntry(100, scriptPublicKey, false, 5)
+	parent := newTransaction(confirmedOutpoint, confirmedEntry)
+	parentOutpoint := externalapi.DomainOutpoint{TransactionID: *consensushashing.TransactionID(parent)}
+	child := newTransaction(parentOutpoint, utxo.NewUTXOEntry(99, scriptPublicKey, false, unconfirmedBlueScore))
+
+	minedOutpoint := externalapi.DomainOutpoint{
+		TransactionID: *externalapi.NewDomainTransactionIDFromByteArray(&[externalapi.DomainHashSize]byte{2})}
+	mined := newTransaction(minedOutpoint, utxo.NewUTXOEntry(200, scriptPublicKey, false, 5))
+
+	// The parent and its child expire together, so only the confirmed outpoint spent
+	// by the parent is unspent again, while the mined transaction is skipped
+	utxoChanges := ConvertRemovedTransactionsToMempoolUTXOChanges([]*miningmanagermodel.RemovedTransaction{
+		{Transaction: mined, Reason: miningmanagermodel.TransactionRemovalReasonMined},
+		{Transaction: parent, Reason: miningmanagermodel.TransactionRemovalReasonExpired},
+		{Transaction: child, Reason: miningmanagermodel.TransactionRemovalReasonExpired},
+	})
+
+	added := utxoChanges.Added[scriptPublicKeyString]
+	if len(added) != 1 {
+		t.Fatalf("Expected exactly one outpoint to be unspent again, but got %d", len(added))
+	}
+	if !added[confirmedOutpoint].Equal(confirmedEntry) {
+		t.Fatalf("Expected the outpoint spent by the parent to be unspent again with its original entry")
+	}
+
+	removed := utxoChanges.Removed[scriptPublicKeyString]
+	expectedRemoved := []externalapi.DomainOutpoint{
+		parentOutpoint,
+		{TransactionID: *consensushashing.TransactionID(child)},
+	}
+	if len(removed) != len(expectedRemoved) {
+		t.Fatalf("Expected %d outputs to be removed, but got %d", len(expectedRemoved), len(removed))
+	}
+	for _, outpoint := range expectedRemoved {
+		if _, ok := removed[outpoint]; !ok {
+			t.Fatalf("Expected output %s to be removed", outpoint)
+		}
+	}
+
+	// When only the child is evicted, the output of the parent it
+	// spent is unspent again, and remains unconfirmed
+	utxoChanges = ConvertRemovedTransactionsToMempoolUTXOChanges([]*miningmanagermodel.RemovedTransaction{
+		{Transaction: child, Reason: miningmanagermodel.TransactionRemovalReasonEvicted},
+	})
+	entries := ConvertUTXOOutpointEntryPairsToUTXOsByAddressesEntries("address", utxoChanges.Added[scriptPublicKeyString])
+	markUnconfirmedUTXOsByAddressesEntries(entries)
+	if len(entries) != 1 || !entries[0].IsUnconfirmed || entries[0].UTXOEntry.BlockBlueScore != 0 {
+		t.Fatalf("Expected the output of the parent to be unspent again and unconfirmed, but got %+v", entries)
+	}
+}
@@ -0,0 +1,82 @@
+package rpccontext
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ErrNotificationsUnavailable indicates that some of the notifications a client
+// asked to replay are not kept in the notification backlog
+var ErrNotificationsUnavailable = errors.New("notifications unavailable")
+
+// notificationBacklog assigns sequence numbers to the notifications of a single
+// notification stream, and keeps the most recent ones in a ring buffer so that
+// they could be replayed to clients that resubscribe.
+//
+// Sequence numbers restart whenever kaspad restarts, so they're only meaningful
+// along with the ID of the stream, which is chosen anew every time
+type notificationBacklog struct {
+	name               string
+	streamID           string
+	notifications      []interface{}
+	lastSequenceNumber uint64
+}
+
+func newNotificationBacklog(name string, streamID string, capacity int) *notificationBacklog {
+	return &notificationBacklog{
+		name:          name,
+		streamID:      streamID,
+		notifications: make([]interface{}, capacity),
+	}
+}
+
+// add adds the given notification to the backlog, evicting the oldest
+// notification if the backlog is full, and returns its sequence number
+func (b *notificationBacklog) add(notification interface{}) uint64 {
+	b.lastSequenceNumber++
+	if len(b.notifications) > 0 {
+		b.notifications[b.lastSequenceNumber%uint64(len(b.notifications))] = notification
+	}
+	return b.lastSequenceNumber
+}
+
+// oldestSequenceNumber returns the sequence number of the oldest notification in the
+// backlog, or the sequence number of the next notification if the backlog is empty
+func (b *notificationBacklog) oldestSequenceNumber() uint64 {
+	keptCount := b.lastSequenceNumber
+	if keptCount > uint64(len(b.notifications)) {
+		keptCount = uint64(len(b.notifications))
+	}
+	return b.lastSequenceNumber + 1 - keptCount
+}
+
+// from calls replay for every notification with startSequenceNumber and onwards,
+// in order. It returns ErrNotificationsUnavailable if startStreamID isn't the ID
+// of this stream, which happens when a client resubscribes after kaspad restarted,
+// if some of these notifications were already evicted, or if startSequenceNumber
+// is beyond the sequence number of the next notification
+func (b *notificationBacklog) from(startStreamID string, startSequenceNumber uint64,
+	replay func(sequenceNumber uint64, notification interface{}) error) error {
+
+	if startStreamID != b.streamID {
+		return errors.Wrapf(ErrNotificationsUnavailable, "%s notifications of stream %s are no longer "+
+			"kept since kaspad restarted; the current stream is %s", b.name, startStreamID, b.streamID)
+	}
+	oldestSequenceNumber := b.oldestSequenceNumber()
+	if startSequenceNumber < oldestSequenceNumber {
+		return errors.Wrapf(ErrNotificationsUnavailable, "%s notifications from sequence number %d "+
+			"are no longer kept; the oldest kept one is %d", b.name, startSequenceNumber, oldestSequenceNumber)
+	}
+	if startSequenceNumber > b.lastSequenceNumber+1 {
+		return errors.Wrapf(ErrNotificationsUnavailable, "sequence number %d of %s notifications "+
+			"was not reached yet; the last one is %d", startSequenceNumber, b.name, b.lastSequenceNumber)
+	}
+
+	for sequenceNumber := startSequenceNumber; sequenceNumber <= b.lastSequenceNumber; sequenceNumber++ {
+		notification := b.notifications[sequenceNumber%uint64(len(b.notifications))]
+		err := replay(sequenceNumber, notification)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package rpccontext
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func TestNotificationBacklog(t *testing.T) {
+	backlog := newNotificationBacklog("test", "stream", 3)
+
+	replayFrom := func(startStreamID string, startSequenceNumber uint64) ([]uint64, error) {
+		var replayed []uint64
+		err := backlog.from(startStreamID, startSequenceNumber, func(sequenceNumber uint64, notification interface{}) error {
+			if notification.(uint64) != sequenceNumber {
+				t.Fatalf("Notification %d was replayed with sequence number %d", notification, sequenceNumber)
+			}
+			replayed = append(replayed, sequenceNumber)
+			return nil
+		})
+		return replayed, err
+	}
+
+	// Nothing is replayed before the first notification, but resubscribing
+	// from its sequence number is allowed
+	replayed, err := replayFrom("stream", 1)
+	if err != nil || len(replayed) != 0 {
+		t.Fatalf("Expected nothing to be replayed from an empty backlog, but got %v, %v", replayed, err)
+	}
+
+	for i := uint64(1); i <= 5; i++ {
+		sequenceNumber := backlog.add(i)
+		if sequenceNumber != i {
+			t.Fatalf("Expected sequence number %d, but got %d", i, sequenceNumber)
+		}
+	}
+
+	tests := []struct {
+		startStreamID       string
+		startSequenceNumber uint64
+		expectedReplayed    []uint64
+		expectedErr         bool
+	}{
+		{startStreamID: "stream", startSequenceNumber: 3, expectedReplayed: []uint64{3, 4, 5}},
+		{startStreamID: "stream", startSequenceNumber: 5, expectedReplayed: []uint64{5}},
+		{startStreamID: "stream", startSequenceNumber: 6, expectedReplayed: nil},
+		{startStreamID: "stream", startSequenceNumber: 2, expectedErr: true},
+		{startStreamID: "stream", startSequenceNumber: 7, expectedErr: true},
+
+		// The sequence numbers of a stream of a previous run of kaspad
+		// are meaningless, even if they're within the kept range
+		{startStreamID: "previous stream", startSequenceNumber: 3, expectedErr: true},
+		{startStreamID: "", startSequenceNumber: 3, expectedErr: true},
+	}
+	for _, test := range tests {
+		replayed, err := replayFrom(test.startStreamID, test.startSequenceNumber)
+		if test.expectedErr {
+			if !errors.Is(err, ErrNotificationsUnavailable) {
+				t.Fatalf("Expected ErrNotificationsUnavailable when replaying from %d of stream %q, but got %v",
+					test.startSequenceNumber, test.startStreamID, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("Unexpected error when replaying from %d: %s", test.startSequenceNumber, err)
+		}
+		if !reflect.DeepEqual(replayed, test.expectedReplayed) {
+			t.Fatalf("Expected %v to be replayed from %d, but got %v",
+				test.expectedReplayed, test.startSequenceNumber, replayed)
+		}
+	}
+}
+
+func TestNotificationBacklogWithZeroCapacity(t *testing.T) {
+	backlog := newNotificationBacklog("test", "stream", 0)
+	backlog.add(struct{}{})
+	backlog.add(struct{}{})
+
+	noReplay := func(uint64, interface{}) error {
+		t.Fatalf("Unexpected replay from a backlog with zero capacity")
+		return nil
+	}
+	err := backlog.from("stream", 3, noReplay)
+	if err != nil {
+		t.Fatalf("Unexpected error when resubscribing from the next sequence number: %s", err)
+	}
+	err = backlog.from("stream", 2, noReplay)
+	if !errors.Is(err, ErrNotificationsUnavailable) {
+		t.Fatalf("Expected ErrNotificationsUnavailable, but got %v", err)
+	}
+}
@@ -1,11 +1,16 @@
 package rpccontext
 
 import (
+	"strconv"
 	"sync"
 
 	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
 	"github.com/kaspanet/kaspad/domain/utxoindex"
 	routerpkg "github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/kaspanet/kaspad/util/random"
 	"github.com/pkg/errors"
 )
 
@@ -13,6 +18,11 @@ import (
 type NotificationManager struct {
 	sync.RWMutex
 	listeners map[*routerpkg.Router]*NotificationListener
+
+	streamID                                 string
+	blockAddedBacklog                        *notificationBacklog
+	virtualSelectedParentChainChangedBacklog *notificationBacklog
+	utxosChangedBacklog                      *notificationBacklog
 }
 
 // UTXOsChangedNotificationAddress represents a kaspad address.
@@ -31,14 +41,32 @@ type NotificationListener struct {
 	propagateUTXOsChangedNotifications                          bool
 	propagateVirtualSelectedParentBlueScoreChangedNotifications bool
 	propagatePruningPointUTXOSetOverrideNotifications           bool
+	propagateMempoolUTXOsChangedNotifications                   bool
+	propagateMempoolTransactionsNotifications                   bool
 
 	propagateUTXOsChangedNotificationAddresses map[utxoindex.ScriptPublicKeyString]*UTXOsChangedNotificationAddress
+
+	propagateMempoolTransactionsScriptPublicKeys map[utxoindex.ScriptPublicKeyString]struct{}
 }
 
-// NewNotificationManager creates a new NotificationManager
-func NewNotificationManager() *NotificationManager {
+// NewNotificationManager creates a new NotificationManager that keeps the
+// backlogSize most recent notifications of every stream that supports replay
+func NewNotificationManager(backlogSize int) *NotificationManager {
+	// The stream ID tells clients whether the sequence numbers they got
+	// were assigned by this run of kaspad, so it must not repeat
+	randomStreamID, err := random.Uint64()
+	if err != nil {
+		panic(errors.Wrap(err, "failed to choose a notification stream ID"))
+	}
+	streamID := strconv.FormatUint(randomStreamID, 16)
+
 	return &NotificationManager{
 		listeners: make(map[*routerpkg.Router]*NotificationListener),
+
+		streamID:                                 streamID,
+		blockAddedBacklog:                        newNotificationBacklog("blockAdded", streamID, backlogSize),
+		virtualSelectedParentChainChangedBacklog: newNotificationBacklog("virtualSelectedParentChainChanged", streamID, backlogSize),
+		utxosChangedBacklog:                      newNotificationBacklog("utxosChanged", streamID, backlogSize),
 	}
 }
 
@@ -71,10 +99,102 @@ func (nm *NotificationManager) Listener(router *routerpkg.Router) (*Notification
 	return listener, nil
 }
 
+// PropagateBlockAddedNotifications instructs the listener registered with the given
+// router to send block added notifications. If startSequenceNumber isn't zero, the
+// notifications of the given stream from that sequence number and onwards are replayed first
+func (nm *NotificationManager) PropagateBlockAddedNotifications(router *routerpkg.Router,
+	startStreamID string, startSequenceNumber uint64) error {
+
+	nm.Lock()
+	defer nm.Unlock()
+
+	listener, ok := nm.listeners[router]
+	if !ok {
+		return errors.Errorf("listener not found")
+	}
+	if startSequenceNumber != 0 {
+		err := nm.blockAddedBacklog.from(startStreamID, startSequenceNumber, func(_ uint64, notification interface{}) error {
+			return router.OutgoingRoute().Enqueue(notification.(*appmessage.BlockAddedNotificationMessage))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	listener.PropagateBlockAddedNotifications()
+	return nil
+}
+
+// PropagateVirtualSelectedParentChainChangedNotifications instructs the listener registered
+// with the given router to send chain changed notifications. If startSequenceNumber isn't zero,
+// the notifications of the given stream from that sequence number and onwards are replayed first
+func (nm *NotificationManager) PropagateVirtualSelectedParentChainChangedNotifications(
+	router *routerpkg.Router, startStreamID string, startSequenceNumber uint64) error {
+
+	nm.Lock()
+	defer nm.Unlock()
+
+	listener, ok := nm.listeners[router]
+	if !ok {
+		return errors.Errorf("listener not found")
+	}
+	if startSequenceNumber != 0 {
+		err := nm.virtualSelectedParentChainChangedBacklog.from(startStreamID, startSequenceNumber, func(_ uint64, notification interface{}) error {
+			return router.OutgoingRoute().Enqueue(notification.(*appmessage.VirtualSelectedParentChainChangedNotificationMessage))
+		})
+		if err != nil {
+			return err
+		}
+	}
+	listener.PropagateVirtualSelectedParentChainChangedNotifications()
+	return nil
+}
+
+// PropagateUTXOsChangedNotifications instructs the listener registered with the given router
+// to send UTXOs changed notifications for the given addresses, and, if includeMempool is set,
+// for the transactions that are added to the mempool. If startSequenceNumber isn't zero, the
+// notifications of changes to the UTXO index of the given stream from that sequence number and
+// onwards are replayed first. Only the changes to the given addresses are replayed
+func (nm *NotificationManager) PropagateUTXOsChangedNotifications(router *routerpkg.Router,
+	addresses []*UTXOsChangedNotificationAddress, includeMempool bool, startStreamID string,
+	startSequenceNumber uint64) error {
+
+	nm.Lock()
+	defer nm.Unlock()
+
+	listener, ok := nm.listeners[router]
+	if !ok {
+		return errors.Errorf("listener not found")
+	}
+	if startSequenceNumber != 0 {
+		replayListener := newNotificationListener()
+		replayListener.PropagateUTXOsChangedNotifications(addresses)
+		err := nm.utxosChangedBacklog.from(startStreamID, startSequenceNumber, func(sequenceNumber uint64, utxoChanges interface{}) error {
+			notification := replayListener.convertUTXOChangesToUTXOsChangedNotification(utxoChanges.(*utxoindex.UTXOChanges))
+			if len(notification.Added) == 0 && len(notification.Removed) == 0 {
+				return nil
+			}
+			notification.SequenceNumber = sequenceNumber
+			notification.StreamID = nm.streamID
+			return router.OutgoingRoute().Enqueue(notification)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	listener.PropagateUTXOsChangedNotifications(addresses)
+	if includeMempool {
+		listener.PropagateMempoolUTXOsChangedNotifications()
+	}
+	return nil
+}
+
 // NotifyBlockAdded notifies the notification manager that a block has been added to the DAG
 func (nm *NotificationManager) NotifyBlockAdded(notification *appmessage.BlockAddedNotificationMessage) error {
-	nm.RLock()
-	defer nm.RUnlock()
+	nm.Lock()
+	defer nm.Unlock()
+
+	notification.SequenceNumber = nm.blockAddedBacklog.add(notification)
+	notification.StreamID = nm.streamID
 
 	for router, listener := range nm.listeners {
 		if listener.propagateBlockAddedNotifications {
@@ -91,8 +211,11 @@ func (nm *NotificationManager) NotifyBlockAdded(notification *appmessage.BlockAd
 
 // NotifyVirtualSelectedParentChainChanged notifies the notification manager that the DAG's selected parent chain has changed
 func (nm *NotificationManager) NotifyVirtualSelectedParentChainChanged(notification *appmessage.VirtualSelectedParentChainChangedNotificationMessage) error {
-	nm.RLock()
-	defer nm.RUnlock()
+	nm.Lock()
+	defer nm.Unlock()
+
+	notification.SequenceNumber = nm.virtualSelectedParentChainChangedBacklog.add(notification)
+	notification.StreamID = nm.streamID
 
 	for router, listener := range nm.listeners {
 		if listener.propagateVirtualSelectedParentChainChangedNotifications {
@@ -139,11 +262,42 @@ func (nm *NotificationManager) NotifyFinalityConflictResolved(notification *appm
 
 // NotifyUTXOsChanged notifies the notification manager that UTXOs have been changed
 func (nm *NotificationManager) NotifyUTXOsChanged(utxoChanges *utxoindex.UTXOChanges) error {
+	nm.Lock()
+	defer nm.Unlock()
+
+	sequenceNumber := nm.utxosChangedBacklog.add(utxoChanges)
+
+	for router, listener := range nm.listeners {
+		if listener.propagateUTXOsChangedNotifications {
+			// Filter utxoChanges and create a notification
+			notification := listener.convertUTXOChangesToUTXOsChangedNotification(utxoChanges)
+
+			// Don't send the notification if it's empty
+			if len(notification.Added) == 0 && len(notification.Removed) == 0 {
+				continue
+			}
+			notification.SequenceNumber = sequenceNumber
+			notification.StreamID = nm.streamID
+
+			// Enqueue the notification
+			err := router.OutgoingRoute().Enqueue(notification)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// NotifyMempoolUTXOsChanged notifies the notification manager that transactions that
+// were added to or removed from the mempool made the given changes to the UTXOs of
+// their addresses
+func (nm *NotificationManager) NotifyMempoolUTXOsChanged(utxoChanges *utxoindex.UTXOChanges) error {
 	nm.RLock()
 	defer nm.RUnlock()
 
 	for router, listener := range nm.listeners {
-		if listener.propagateUTXOsChangedNotifications {
+		if listener.propagateUTXOsChangedNotifications && listener.propagateMempoolUTXOsChangedNotifications {
 			// Filter utxoChanges and create a notification
 			notification := listener.convertUTXOChangesToUTXOsChangedNotification(utxoChanges)
 
@@ -151,6 +305,7 @@ func (nm *NotificationManager) NotifyUTXOsChanged(utxoChanges *utxoindex.UTXOCha
 			if len(notification.Added) == 0 && len(notification.Removed) == 0 {
 				continue
 			}
+			markUnconfirmedUTXOsByAddressesEntries(notification.Added)
 
 			// Enqueue the notification
 			err := router.OutgoingRoute().Enqueue(notification)
@@ -162,6 +317,72 @@ func (nm *NotificationManager) NotifyUTXOsChanged(utxoChanges *utxoindex.UTXOCha
 	return nil
 }
 
+// NotifyTransactionsAddedToMempool notifies the notification manager that the given
+// transactions have been added to the mempool. The mempool was already changed by
+// then, so a listener that can't receive the notification doesn't fail the call
+func (nm *NotificationManager) NotifyTransactionsAddedToMempool(transactions []*externalapi.DomainTransaction) {
+	nm.RLock()
+	defer nm.RUnlock()
+
+	for _, transaction := range transactions {
+		var notification *appmessage.TransactionAddedToMempoolNotificationMessage
+		for router, listener := range nm.listeners {
+			if !listener.shouldPropagateMempoolTransactionNotification(transaction) {
+				continue
+			}
+			if notification == nil {
+				notification = appmessage.NewTransactionAddedToMempoolNotificationMessage(
+					consensushashing.TransactionID(transaction).String(),
+					appmessage.DomainTransactionToRPCTransaction(transaction))
+			}
+			err := router.OutgoingRoute().Enqueue(notification)
+			if err != nil {
+				log.Warnf("Couldn't send transaction added to mempool notification: %s", err)
+			}
+		}
+	}
+}
+
+// NotifyTransactionsRemovedFromMempool notifies the notification manager that the
+// given transactions have been removed from the mempool. See NotifyTransactionsAddedToMempool
+func (nm *NotificationManager) NotifyTransactionsRemovedFromMempool(
+	removedTransactions []*miningmanagermodel.RemovedTransaction) {
+
+	nm.RLock()
+	defer nm.RUnlock()
+
+	for _, removedTransaction := range removedTransactions {
+		var notification *appmessage.TransactionRemovedFromMempoolNotificationMessage
+		for router, listener := range nm.listeners {
+			if !listener.shouldPropagateMempoolTransactionNotification(removedTransaction.Transaction) {
+				continue
+			}
+			if notification == nil {
+				notification = appmessage.NewTransactionRemovedFromMempoolNotificationMessage(
+					consensushashing.TransactionID(removedTransaction.Transaction).String(),
+					convertTransactionRemovalReason(removedTransaction.Reason))
+			}
+			err := router.OutgoingRoute().Enqueue(notification)
+			if err != nil {
+				log.Warnf("Couldn't send transaction removed from mempool notification: %s", err)
+			}
+		}
+	}
+}
+
+func convertTransactionRemovalReason(reason miningmanagermodel.TransactionRemovalReason) appmessage.TransactionRemovalReason {
+	switch reason {
+	case miningmanagermodel.TransactionRemovalReasonMined:
+		return appmessage.TransactionRemovalReasonMined
+	case miningmanagermodel.TransactionRemovalReasonConflicted:
+		return appmessage.TransactionRemovalReasonConflicted
+	case miningmanagermodel.TransactionRemovalReasonExpired:
+		return appmessage.TransactionRemovalReasonExpired
+	default:
+		return appmessage.TransactionRemovalReasonEvicted
+	}
+}
+
 // NotifyVirtualSelectedParentBlueScoreChanged notifies the notification manager that the DAG's
 // virtual selected parent blue score has changed
 func (nm *NotificationManager) NotifyVirtualSelectedParentBlueScoreChanged(
@@ -207,6 +428,8 @@ func newNotificationListener() *NotificationListener {
 		propagateUTXOsChangedNotifications:                          false,
 		propagateVirtualSelectedParentBlueScoreChangedNotifications: false,
 		propagatePruningPointUTXOSetOverrideNotifications:           false,
+		propagateMempoolUTXOsChangedNotifications:                   false,
+		propagateMempoolTransactionsNotifications:                   false,
 	}
 }
 
@@ -250,6 +473,12 @@ func (nl *NotificationListener) PropagateUTXOsChangedNotifications(addresses []*
 	}
 }
 
+// PropagateMempoolUTXOsChangedNotifications instructs the listener to additionally send UTXOs
+// changed notifications when transactions that touch its addresses are added to the mempool
+func (nl *NotificationListener) PropagateMempoolUTXOsChangedNotifications() {
+	nl.propagateMempoolUTXOsChangedNotifications = true
+}
+
 // StopPropagatingUTXOsChangedNotifications instructs the listener to stop sending UTXOs
 // changed notifications to the remote listener for the given addresses. Addresses for which
 // notifications are not currently sent are ignored.
@@ -263,6 +492,40 @@ func (nl *NotificationListener) StopPropagatingUTXOsChangedNotifications(address
 	}
 }
 
+// PropagateMempoolTransactionsNotifications instructs the listener to send transaction added
+// to mempool and transaction removed from mempool notifications to the remote listener. If
+// addresses is not empty, only transactions that pay to or spend from one of them are
+// notified about. Subsequent calls add to the addresses of previous ones.
+func (nl *NotificationListener) PropagateMempoolTransactionsNotifications(addresses []*UTXOsChangedNotificationAddress) {
+	if !nl.propagateMempoolTransactionsNotifications {
+		nl.propagateMempoolTransactionsNotifications = true
+		nl.propagateMempoolTransactionsScriptPublicKeys =
+			make(map[utxoindex.ScriptPublicKeyString]struct{}, len(addresses))
+	}
+
+	for _, address := range addresses {
+		nl.propagateMempoolTransactionsScriptPublicKeys[address.ScriptPublicKeyString] = struct{}{}
+	}
+}
+
+// StopPropagatingMempoolTransactionsNotifications instructs the listener to stop sending
+// transaction added to mempool and transaction removed from mempool notifications to the
+// remote listener
+func (nl *NotificationListener) StopPropagatingMempoolTransactionsNotifications() {
+	nl.propagateMempoolTransactionsNotifications = false
+	nl.propagateMempoolTransactionsScriptPublicKeys = nil
+}
+
+func (nl *NotificationListener) shouldPropagateMempoolTransactionNotification(transaction *externalapi.DomainTransaction) bool {
+	if !nl.propagateMempoolTransactionsNotifications {
+		return false
+	}
+	if len(nl.propagateMempoolTransactionsScriptPublicKeys) == 0 {
+		return true
+	}
+	return IsTransactionRelatedToScriptPublicKeys(transaction, nl.propagateMempoolTransactionsScriptPublicKeys)
+}
+
 func (nl *NotificationListener) convertUTXOChangesToUTXOsChangedNotification(
 	utxoChanges *utxoindex.UTXOChanges) *appmessage.UTXOsChangedNotificationMessage {
 
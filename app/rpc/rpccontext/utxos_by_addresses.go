@@ -7,6 +7,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"github.com/kaspanet/kaspad/domain/utxoindex"
 )
 
@@ -32,6 +33,30 @@ func ConvertUTXOOutpointEntryPairsToUTXOsByAddressesEntries(address string, pair
 	return utxosByAddressesEntries
 }
 
+// ConvertOutpointAndUTXOEntryPairsToUTXOsByAddressesEntries converts
+// OutpointAndUTXOEntryPairs to a slice of UTXOsByAddressesEntry, keeping their order
+func ConvertOutpointAndUTXOEntryPairsToUTXOsByAddressesEntries(address string,
+	pairs []*externalapi.OutpointAndUTXOEntryPair) []*appmessage.UTXOsByAddressesEntry {
+
+	utxosByAddressesEntries := make([]*appmessage.UTXOsByAddressesEntry, len(pairs))
+	for i, pair := range pairs {
+		utxosByAddressesEntries[i] = &appmessage.UTXOsByAddressesEntry{
+			Address: address,
+			Outpoint: &appmessage.RPCOutpoint{
+				TransactionID: pair.Outpoint.TransactionID.String(),
+				Index:         pair.Outpoint.Index,
+			},
+			UTXOEntry: &appmessage.RPCUTXOEntry{
+				Amount:          pair.UTXOEntry.Amount(),
+				ScriptPublicKey: &appmessage.RPCScriptPublicKey{Script: hex.EncodeToString(pair.UTXOEntry.ScriptPublicKey().Script), Version: pair.UTXOEntry.ScriptPublicKey().Version},
+				BlockBlueScore:  pair.UTXOEntry.BlockBlueScore(),
+				IsCoinbase:      pair.UTXOEntry.IsCoinbase(),
+			},
+		}
+	}
+	return utxosByAddressesEntries
+}
+
 // convertUTXOOutpointsToUTXOsByAddressesEntries converts
 // UTXOOutpoints to a slice of UTXOsByAddressesEntry
 func convertUTXOOutpointsToUTXOsByAddressesEntries(address string, outpoints utxoindex.UTXOOutpoints) []*appmessage.UTXOsByAddressesEntry {
@@ -15,6 +15,7 @@ import (
 	"github.com/kaspanet/kaspad/domain/consensus/utils/hashes"
 
 	"github.com/kaspanet/kaspad/domain/consensus/utils/estimatedsize"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txmass"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
 
 	"github.com/kaspanet/kaspad/domain/consensus/utils/subnetworks"
@@ -30,8 +31,10 @@ var ErrBuildBlockVerboseDataInvalidBlock = errors.New("ErrBuildBlockVerboseDataI
 
 // BuildBlockVerboseData builds a BlockVerboseData from the given blockHeader.
 // A block may optionally also be given if it's available in the calling context.
+// So may an acceptanceResolver, if the calling context builds the verbose data of
+// many blocks and includeTransactionVerboseData is set.
 func (ctx *Context) BuildBlockVerboseData(blockHeader externalapi.BlockHeader, block *externalapi.DomainBlock,
-	includeTransactionVerboseData bool) (*appmessage.BlockVerboseData, error) {
+	includeTransactionVerboseData bool, acceptanceResolver *BlockAcceptanceResolver) (*appmessage.BlockVerboseData, error) {
 
 	onEnd := logger.LogAndMeasureExecutionTime(log, "BuildBlockVerboseData")
 	defer onEnd()
@@ -85,14 +88,9 @@ func (ctx *Context) BuildBlockVerboseData(blockHeader externalapi.BlockHeader, b
 		result.TxIDs = txIDs
 
 		if includeTransactionVerboseData {
-			transactionVerboseData := make([]*appmessage.TransactionVerboseData, len(block.Transactions))
-			for i, tx := range block.Transactions {
-				txID := consensushashing.TransactionID(tx).String()
-				data, err := ctx.BuildTransactionVerboseData(tx, txID, blockHeader, hash.String())
-				if err != nil {
-					return nil, err
-				}
-				transactionVerboseData[i] = data
+			transactionVerboseData, err := ctx.buildBlockTransactionsVerboseData(block, blockHeader, hash, acceptanceResolver)
+			if err != nil {
+				return nil, err
 			}
 			result.TransactionVerboseData = transactionVerboseData
 		}
@@ -101,6 +99,68 @@ func (ctx *Context) BuildBlockVerboseData(blockHeader externalapi.BlockHeader, b
 	return result, nil
 }
 
+// buildBlockTransactionsVerboseData builds the TransactionVerboseData of the transactions
+// of the given block. The data of the transactions that were accepted by the virtual's
+// selected parent chain includes the outputs they spent, their fee and mass, and their
+// acceptance by the chain
+func (ctx *Context) buildBlockTransactionsVerboseData(block *externalapi.DomainBlock,
+	blockHeader externalapi.BlockHeader, blockHash *externalapi.DomainHash,
+	acceptanceResolver *BlockAcceptanceResolver) ([]*appmessage.TransactionVerboseData, error) {
+
+	if acceptanceResolver == nil {
+		var err error
+		acceptanceResolver, err = ctx.NewBlockAcceptanceResolver()
+		if err != nil {
+			return nil, err
+		}
+	}
+	acceptance, err := acceptanceResolver.BlockAcceptance(blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	transactionVerboseData := make([]*appmessage.TransactionVerboseData, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		var transactionAcceptanceData *externalapi.TransactionAcceptanceData
+		if i < len(acceptance.TransactionAcceptanceData) {
+			transactionAcceptanceData = acceptance.TransactionAcceptanceData[i]
+		}
+		isAccepted := transactionAcceptanceData != nil && transactionAcceptanceData.IsAccepted
+		if isAccepted {
+			tx = ctx.populateTransactionWithAcceptanceData(tx, transactionAcceptanceData)
+		}
+
+		txID := consensushashing.TransactionID(tx).String()
+		data, err := ctx.BuildTransactionVerboseData(tx, txID, blockHeader, blockHash.String())
+		if err != nil {
+			return nil, err
+		}
+		if isAccepted {
+			data.AcceptingBlockHash = acceptance.AcceptingBlockHash.String()
+			data.Confirmations = acceptance.Confirmations
+		}
+		transactionVerboseData[i] = data
+	}
+	return transactionVerboseData, nil
+}
+
+// populateTransactionWithAcceptanceData returns a clone of the given transaction
+// that's populated with the UTXO entries it spent, its fee and its mass
+func (ctx *Context) populateTransactionWithAcceptanceData(tx *externalapi.DomainTransaction,
+	transactionAcceptanceData *externalapi.TransactionAcceptanceData) *externalapi.DomainTransaction {
+
+	populatedTransaction := tx.Clone()
+	for i, input := range populatedTransaction.Inputs {
+		input.UTXOEntry = transactionAcceptanceData.TransactionInputUTXOEntries[i]
+	}
+	populatedTransaction.Fee = transactionAcceptanceData.Fee
+
+	params := ctx.Config.ActiveNetParams
+	txMassCalculator := txmass.NewCalculator(params.MassPerTxByte, params.MassPerScriptPubKeyByte, params.MassPerSigOp)
+	populatedTransaction.Mass = txMassCalculator.CalculateTransactionMass(populatedTransaction)
+	return populatedTransaction
+}
+
 // GetDifficultyRatio returns the proof-of-work difficulty as a multiple of the
 // minimum difficulty using the passed bits field from the header of a block.
 func (ctx *Context) GetDifficultyRatio(bits uint32, params *dagconfig.Params) float64 {
@@ -145,6 +205,8 @@ func (ctx *Context) BuildTransactionVerboseData(tx *externalapi.DomainTransactio
 		Gas:                       tx.Gas,
 		PayloadHash:               payloadHash,
 		Payload:                   hex.EncodeToString(tx.Payload),
+		Fee:                       tx.Fee,
+		Mass:                      tx.Mass,
 	}
 
 	if blockHeader != nil {
@@ -172,6 +234,16 @@ func (ctx *Context) buildTransactionVerboseInputs(tx *externalapi.DomainTransact
 			Asm: disbuf,
 			Hex: hex.EncodeToString(transactionInput.SignatureScript),
 		}
+		if transactionInput.UTXOEntry != nil {
+			input.Value = transactionInput.UTXOEntry.Amount()
+			// Ignore the error here since an error means the script
+			// couldn't parse and there is no address to report.
+			_, addr, _ := txscript.ExtractScriptPubKeyAddress(
+				transactionInput.UTXOEntry.ScriptPublicKey(), ctx.Config.ActiveNetParams)
+			if addr != nil {
+				input.Address = addr.EncodeAddress()
+			}
+		}
 		inputs[i] = input
 	}
 
@@ -211,7 +283,13 @@ func (ctx *Context) buildTransactionVerboseOutputs(tx *externalapi.DomainTransac
 		output := &appmessage.TransactionVerboseOutput{}
 		output.Index = uint32(i)
 		output.Value = transactionOutput.Value
+		// The disassembled string will contain [error] inline
+		// if the script doesn't fully parse, so ignore the
+		// error here.
+		disbuf, _ := txscript.DisasmString(transactionOutput.ScriptPublicKey.Version, transactionOutput.ScriptPublicKey.Script)
+
 		output.ScriptPubKey = &appmessage.ScriptPubKeyResult{
+			Asm:     disbuf,
 			Version: transactionOutput.ScriptPublicKey.Version,
 			Address: encodedAddr,
 			Hex:     hex.EncodeToString(transactionOutput.ScriptPublicKey.Script),
@@ -0,0 +1,22 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/domain/miningmanager/mempoolpersistence"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+)
+
+// HandleDumpMempool handles the respectively named RPC command
+func HandleDumpMempool(context *rpccontext.Context, _ *router.Router, _ appmessage.Message) (appmessage.Message, error) {
+	path := mempoolpersistence.DumpFilePath(context.Config.DataDir)
+	transactionCount, err := mempoolpersistence.Dump(context.Domain.MiningManager(), path)
+	if err != nil {
+		errorMessage := &appmessage.DumpMempoolResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not dump the mempool: %s", err)
+		return errorMessage, nil
+	}
+	log.Infof("Dumped %d mempool transactions to '%s'", transactionCount, path)
+
+	return appmessage.NewDumpMempoolResponseMessage(path, uint32(transactionCount)), nil
+}
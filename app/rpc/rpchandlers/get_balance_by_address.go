@@ -0,0 +1,42 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/kaspanet/kaspad/util"
+)
+
+// HandleGetBalanceByAddress handles the respectively named RPC command
+func HandleGetBalanceByAddress(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	if !context.Config.UTXOIndex {
+		errorMessage := &appmessage.GetBalanceByAddressResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Method unavailable when kaspad is run without --utxoindex")
+		return errorMessage, nil
+	}
+
+	getBalanceByAddressRequest := request.(*appmessage.GetBalanceByAddressRequestMessage)
+
+	address, err := util.DecodeAddress(getBalanceByAddressRequest.Address, context.Config.ActiveNetParams.Prefix)
+	if err != nil {
+		errorMessage := &appmessage.GetBalanceByAddressResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not decode address '%s': %s",
+			getBalanceByAddressRequest.Address, err)
+		return errorMessage, nil
+	}
+	scriptPublicKey, err := txscript.PayToAddrScript(address)
+	if err != nil {
+		errorMessage := &appmessage.GetBalanceByAddressResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Could not create a scriptPublicKey for address '%s': %s",
+			getBalanceByAddressRequest.Address, err)
+		return errorMessage, nil
+	}
+	balance, err := context.UTXOIndex.Balance(scriptPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	response := appmessage.NewGetBalanceByAddressResponseMessage(balance)
+	return response, nil
+}
@@ -0,0 +1,71 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+)
+
+// HandleGetBlockAcceptanceStatus handles the respectively named RPC command
+func HandleGetBlockAcceptanceStatus(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	getBlockAcceptanceStatusRequest := request.(*appmessage.GetBlockAcceptanceStatusRequestMessage)
+
+	blockHash, err := externalapi.NewDomainHashFromString(getBlockAcceptanceStatusRequest.BlockHash)
+	if err != nil {
+		errorMessage := &appmessage.GetBlockAcceptanceStatusResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Hash could not be parsed: %s", err)
+		return errorMessage, nil
+	}
+
+	errorMessage, err := validateBlockHasBody(context, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if errorMessage != nil {
+		return &appmessage.GetBlockAcceptanceStatusResponseMessage{Error: errorMessage}, nil
+	}
+
+	blockAcceptance, err := context.BlockAcceptance(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	virtualSelectedParent, err := context.Domain.Consensus().GetVirtualSelectedParent()
+	if err != nil {
+		return nil, err
+	}
+	isChainBlock, err := context.Domain.Consensus().IsInSelectedParentChainOf(blockHash, virtualSelectedParent)
+	if err != nil {
+		return nil, err
+	}
+	isFinalized, err := context.Domain.Consensus().IsFinalizedBlock(blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	acceptingBlockHash := ""
+	if blockAcceptance.AcceptingBlockHash != nil {
+		acceptingBlockHash = blockAcceptance.AcceptingBlockHash.String()
+	}
+	return appmessage.NewGetBlockAcceptanceStatusResponseMessage(acceptingBlockHash,
+		blockAcceptance.Confirmations, blockAcceptance.IsBlue, isChainBlock, isFinalized), nil
+}
+
+// validateBlockHasBody returns an RPC error if the given block isn't
+// a valid block with a body, which is required for it to be accepted
+func validateBlockHasBody(context *rpccontext.Context, blockHash *externalapi.DomainHash) (*appmessage.RPCError, error) {
+	blockInfo, err := context.Domain.Consensus().GetBlockInfo(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if !blockInfo.Exists {
+		return appmessage.RPCErrorf("Block %s not found", blockHash), nil
+	}
+	if blockInfo.BlockStatus == externalapi.StatusInvalid {
+		return appmessage.RPCErrorf("Block %s is invalid", blockHash), nil
+	}
+	if blockInfo.BlockStatus == externalapi.StatusHeaderOnly {
+		return appmessage.RPCErrorf("Block %s has no body", blockHash), nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,137 @@
+package rpchandlers_test
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/app/rpc/rpchandlers"
+	"github.com/kaspanet/kaspad/domain/consensus"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/testutils"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/infrastructure/config"
+)
+
+func TestHandleGetBlockTransactionVerboseData(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, params *dagconfig.Params) {
+		params.BlockCoinbaseMaturity = 0
+
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(params, false, "TestHandleGetBlockTransactionVerboseData")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		fakeContext := rpccontext.Context{
+			Config: &config.Config{Flags: &config.Flags{NetworkFlags: config.NetworkFlags{ActiveNetParams: params}}},
+			Domain: fakeDomain{tc},
+		}
+
+		getTransactionVerboseData := func(blockHash *externalapi.DomainHash) []*appmessage.TransactionVerboseData {
+			response, err := rpchandlers.HandleGetBlock(&fakeContext, nil,
+				appmessage.NewGetBlockRequestMessage(blockHash.String(), true))
+			if err != nil {
+				t.Fatalf("HandleGetBlock: %+v", err)
+			}
+			getBlockResponse := response.(*appmessage.GetBlockResponseMessage)
+			if getBlockResponse.Error != nil {
+				t.Fatalf("HandleGetBlock: %s", getBlockResponse.Error.Message)
+			}
+			return getBlockResponse.BlockVerboseData.TransactionVerboseData
+		}
+
+		// The coinbase of a block pays the miners of the blocks it merges, so only
+		// the coinbase of the second block pays to the test's OP_TRUE script
+		addBlockAndGetCoinbase(t, tc)
+		fundingTransaction := addBlockAndGetCoinbase(t, tc)
+
+		const fee = 1000
+		transaction := createTransactionWithFee(t, fundingTransaction, fee)
+		populatedTransaction := transaction.Clone()
+		err = tc.ValidateTransactionAndPopulateWithConsensusData(populatedTransaction)
+		if err != nil {
+			t.Fatalf("ValidateTransactionAndPopulateWithConsensusData: %+v", err)
+		}
+
+		// Create a DAG with the following structure, so that the
+		// transaction's block is merged by a block that has another
+		// parent as well:
+		//
+		//   ... <- transactionBlock <- mergingBlock <- nextBlock
+		//      \                      /
+		//       <---- siblingBlock <--
+		virtualInfo, err := tc.GetVirtualInfo()
+		if err != nil {
+			t.Fatalf("GetVirtualInfo: %+v", err)
+		}
+		transactionBlockHash, _, err := tc.AddBlock(virtualInfo.ParentHashes, nil,
+			[]*externalapi.DomainTransaction{transaction})
+		if err != nil {
+			t.Fatalf("AddBlock: %+v", err)
+		}
+
+		// The transaction wasn't accepted by a block yet,
+		// so its fee and spent outputs aren't known
+		transactionVerboseData := getTransactionVerboseData(transactionBlockHash)[1]
+		if transactionVerboseData.AcceptingBlockHash != "" || transactionVerboseData.Confirmations != 0 {
+			t.Fatalf("Expected the transaction not to be accepted, but got accepting block %s with %d confirmations",
+				transactionVerboseData.AcceptingBlockHash, transactionVerboseData.Confirmations)
+		}
+		if transactionVerboseData.Fee != 0 || transactionVerboseData.TransactionVerboseInputs[0].Value != 0 {
+			t.Fatalf("Expected no fee and input values for a transaction that wasn't accepted")
+		}
+
+		siblingBlockHash, _, err := tc.AddBlock(virtualInfo.ParentHashes, nil, nil)
+		if err != nil {
+			t.Fatalf("AddBlock: %+v", err)
+		}
+		mergingBlockHash, _, err := tc.AddBlock([]*externalapi.DomainHash{transactionBlockHash, siblingBlockHash}, nil, nil)
+		if err != nil {
+			t.Fatalf("AddBlock: %+v", err)
+		}
+		_, _, err = tc.AddBlock([]*externalapi.DomainHash{mergingBlockHash}, nil, nil)
+		if err != nil {
+			t.Fatalf("AddBlock: %+v", err)
+		}
+
+		transactionVerboseData = getTransactionVerboseData(transactionBlockHash)[1]
+		if transactionVerboseData.TxID != consensushashing.TransactionID(transaction).String() {
+			t.Fatalf("Expected the verbose data of transaction %s, but got %s",
+				consensushashing.TransactionID(transaction), transactionVerboseData.TxID)
+		}
+		if transactionVerboseData.AcceptingBlockHash != mergingBlockHash.String() {
+			t.Fatalf("Expected the transaction to be accepted by %s, but got %s",
+				mergingBlockHash, transactionVerboseData.AcceptingBlockHash)
+		}
+		const expectedConfirmations = 2
+		if transactionVerboseData.Confirmations != expectedConfirmations {
+			t.Fatalf("Expected %d confirmations, but got %d", expectedConfirmations, transactionVerboseData.Confirmations)
+		}
+		if transactionVerboseData.Fee != fee {
+			t.Fatalf("Expected a fee of %d, but got %d", fee, transactionVerboseData.Fee)
+		}
+		if transactionVerboseData.Mass != populatedTransaction.Mass {
+			t.Fatalf("Expected a mass of %d, but got %d", populatedTransaction.Mass, transactionVerboseData.Mass)
+		}
+
+		opTrueScriptPublicKey, _ := testutils.OpTrueScript()
+		_, opTrueAddress, err := txscript.ExtractScriptPubKeyAddress(opTrueScriptPublicKey, params)
+		if err != nil {
+			t.Fatalf("ExtractScriptPubKeyAddress: %+v", err)
+		}
+		input := transactionVerboseData.TransactionVerboseInputs[0]
+		if input.Value != fundingTransaction.Outputs[0].Value {
+			t.Fatalf("Expected an input value of %d, but got %d", fundingTransaction.Outputs[0].Value, input.Value)
+		}
+		if input.Address != opTrueAddress.EncodeAddress() {
+			t.Fatalf("Expected an input address of %s, but got %s", opTrueAddress, input.Address)
+		}
+		if transactionVerboseData.TransactionVerboseOutputs[0].ScriptPubKey.Asm == "" {
+			t.Fatalf("Expected the output's script public key to be disassembled")
+		}
+	})
+}
@@ -88,13 +88,20 @@ func HandleGetBlocks(context *rpccontext.Context, _ *router.Router, request appm
 	// Retrieve all block data in case BlockVerboseData was requested
 	if getBlocksRequest.IncludeBlockVerboseData {
 		response.BlockVerboseData = make([]*appmessage.BlockVerboseData, len(blockHashes))
+		var acceptanceResolver *rpccontext.BlockAcceptanceResolver
+		if getBlocksRequest.IncludeTransactionVerboseData {
+			acceptanceResolver, err = context.NewBlockAcceptanceResolver()
+			if err != nil {
+				return nil, err
+			}
+		}
 		for i, blockHash := range blockHashes {
 			blockHeader, err := context.Domain.Consensus().GetBlockHeader(blockHash)
 			if err != nil {
 				return nil, err
 			}
 			blockVerboseData, err := context.BuildBlockVerboseData(blockHeader, nil,
-				getBlocksRequest.IncludeTransactionVerboseData)
+				getBlocksRequest.IncludeTransactionVerboseData, acceptanceResolver)
 			if err != nil {
 				return nil, err
 			}
@@ -0,0 +1,60 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/pkg/errors"
+)
+
+// HandleGetDAGSubgraph handles the respectively named RPC command
+func HandleGetDAGSubgraph(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	getDAGSubgraphRequest := request.(*appmessage.GetDAGSubgraphRequestMessage)
+
+	var blocks []*appmessage.DAGSubgraphBlock
+	isTruncated := false
+	var err error
+	if getDAGSubgraphRequest.AnticoneOfHash != "" {
+		anticoneOfHash, parseErr := externalapi.NewDomainHashFromString(getDAGSubgraphRequest.AnticoneOfHash)
+		if parseErr != nil {
+			errorMessage := &appmessage.GetDAGSubgraphResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Could not parse anticoneOfHash: %s", parseErr)
+			return errorMessage, nil
+		}
+		var blockInfo *externalapi.BlockInfo
+		blockInfo, err = context.Domain.Consensus().GetBlockInfo(anticoneOfHash)
+		if err != nil {
+			return nil, err
+		}
+		if !blockInfo.Exists || blockInfo.BlockStatus == externalapi.StatusInvalid {
+			errorMessage := &appmessage.GetDAGSubgraphResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Block %s not found", anticoneOfHash)
+			return errorMessage, nil
+		}
+		blocks, err = context.BuildDAGSubgraphByAnticone(anticoneOfHash)
+	} else {
+		if getDAGSubgraphRequest.LowBlueScore > getDAGSubgraphRequest.HighBlueScore {
+			errorMessage := &appmessage.GetDAGSubgraphResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("lowBlueScore %d is higher than highBlueScore %d",
+				getDAGSubgraphRequest.LowBlueScore, getDAGSubgraphRequest.HighBlueScore)
+			return errorMessage, nil
+		}
+		blocks, isTruncated, err = context.BuildDAGSubgraphByBlueScore(getDAGSubgraphRequest.LowBlueScore,
+			getDAGSubgraphRequest.HighBlueScore)
+	}
+	if err != nil {
+		if errors.Is(err, rpccontext.ErrDAGSubgraphTooLarge) {
+			errorMessage := &appmessage.GetDAGSubgraphResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Could not build the DAG subgraph: %s", err)
+			return errorMessage, nil
+		}
+		return nil, err
+	}
+
+	dotGraph := ""
+	if getDAGSubgraphRequest.IncludeDotGraph {
+		dotGraph = rpccontext.DAGSubgraphToDot(blocks)
+	}
+	return appmessage.NewGetDAGSubgraphResponseMessage(blocks, dotGraph, isTruncated), nil
+}
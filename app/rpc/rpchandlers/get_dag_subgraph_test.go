@@ -0,0 +1,134 @@
+package rpchandlers_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/app/rpc/rpchandlers"
+	"github.com/kaspanet/kaspad/domain/consensus"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/testutils"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/infrastructure/config"
+)
+
+func TestHandleGetDAGSubgraph(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, params *dagconfig.Params) {
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(params, false, "TestHandleGetDAGSubgraph")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		fakeContext := rpccontext.Context{
+			Config: &config.Config{Flags: &config.Flags{NetworkFlags: config.NetworkFlags{ActiveNetParams: params}}},
+			Domain: fakeDomain{tc},
+		}
+
+		getDAGSubgraph := func(request *appmessage.GetDAGSubgraphRequestMessage) *appmessage.GetDAGSubgraphResponseMessage {
+			response, err := rpchandlers.HandleGetDAGSubgraph(&fakeContext, nil, request)
+			if err != nil {
+				t.Fatalf("HandleGetDAGSubgraph: %+v", err)
+			}
+			return response.(*appmessage.GetDAGSubgraphResponseMessage)
+		}
+
+		// Create a DAG with the following structure, in which sideBlock is
+		// in the anticone of K+2 chain blocks, and is therefore red:
+		//
+		//   genesis <- chain[0] <- ... <- chain[K+1] <- mergingBlock
+		//      ^                                            |
+		//      +---------------- sideBlock <----------------+
+		sideBlock, _, err := tc.AddBlock([]*externalapi.DomainHash{params.GenesisHash}, nil, nil)
+		if err != nil {
+			t.Fatalf("AddBlock: %+v", err)
+		}
+		chain := make([]*externalapi.DomainHash, 0, params.K+2)
+		tip := params.GenesisHash
+		for i := 0; i < int(params.K)+2; i++ {
+			tip, _, err = tc.AddBlock([]*externalapi.DomainHash{tip}, nil, nil)
+			if err != nil {
+				t.Fatalf("AddBlock: %+v", err)
+			}
+			chain = append(chain, tip)
+		}
+		mergingBlock, _, err := tc.AddBlock([]*externalapi.DomainHash{tip, sideBlock}, nil, nil)
+		if err != nil {
+			t.Fatalf("AddBlock: %+v", err)
+		}
+
+		response := getDAGSubgraph(appmessage.NewGetDAGSubgraphRequestMessage(0, 1_000_000, "", true))
+		if response.Error != nil {
+			t.Fatalf("Unexpected error: %s", response.Error.Message)
+		}
+		expectedBlockCount := len(chain) + 3
+		if len(response.Blocks) != expectedBlockCount {
+			t.Fatalf("Expected %d blocks but got %d", expectedBlockCount, len(response.Blocks))
+		}
+		if response.IsTruncated {
+			t.Fatalf("Expected a subgraph that starts at the genesis not to be truncated")
+		}
+		blocks := make(map[string]*appmessage.DAGSubgraphBlock)
+		for i, block := range response.Blocks {
+			if i > 0 && block.BlueScore < response.Blocks[i-1].BlueScore {
+				t.Fatalf("Expected the blocks to be ordered by blue score")
+			}
+			blocks[block.Hash] = block
+		}
+
+		side := blocks[sideBlock.String()]
+		if side.Color != appmessage.DAGSubgraphBlockColorRed || side.IsChainBlock {
+			t.Fatalf("Expected the side block to be a red non-chain block, but got: %+v", side)
+		}
+		for _, chainBlock := range append(chain, params.GenesisHash, mergingBlock) {
+			block := blocks[chainBlock.String()]
+			if block.Color != appmessage.DAGSubgraphBlockColorBlue || !block.IsChainBlock {
+				t.Fatalf("Expected %s to be a blue chain block, but got: %+v", chainBlock, block)
+			}
+		}
+		merging := blocks[mergingBlock.String()]
+		if merging.SelectedParentHash != tip.String() {
+			t.Fatalf("Expected the selected parent of the merging block to be %s but got %s",
+				tip, merging.SelectedParentHash)
+		}
+		if len(merging.MergeSetRedsHashes) != 1 || merging.MergeSetRedsHashes[0] != sideBlock.String() {
+			t.Fatalf("Expected the merging block to merge the side block as red, but got: %+v", merging)
+		}
+
+		expectedEdge := "\"" + sideBlock.String() + "\" -> \"" + params.GenesisHash.String() + "\""
+		if !strings.Contains(response.DotGraph, expectedEdge) {
+			t.Fatalf("Expected the DOT graph to contain the edge %s, but got:\n%s", expectedEdge, response.DotGraph)
+		}
+
+		// Blue score 1 contains just the side block and the first chain block
+		response = getDAGSubgraph(appmessage.NewGetDAGSubgraphRequestMessage(1, 1, "", false))
+		if len(response.Blocks) != 2 {
+			t.Fatalf("Expected 2 blocks with blue score 1 but got %d", len(response.Blocks))
+		}
+		if response.DotGraph != "" {
+			t.Fatalf("Expected no DOT graph when it's not requested")
+		}
+		if !response.IsTruncated {
+			t.Fatalf("Expected a subgraph whose traversal stopped at the genesis to be truncated")
+		}
+
+		response = getDAGSubgraph(appmessage.NewGetDAGSubgraphRequestMessage(0, 0, sideBlock.String(), false))
+		if len(response.Blocks) != len(chain)+1 {
+			t.Fatalf("Expected the side block and its anticone of %d blocks but got %d blocks",
+				len(chain), len(response.Blocks))
+		}
+		for _, block := range response.Blocks {
+			if block.Hash == params.GenesisHash.String() || block.Hash == mergingBlock.String() {
+				t.Fatalf("Block %s is not in the anticone of the side block", block.Hash)
+			}
+		}
+
+		response = getDAGSubgraph(appmessage.NewGetDAGSubgraphRequestMessage(2, 1, "", false))
+		if response.Error == nil {
+			t.Fatalf("Expected an error for an empty blue score range")
+		}
+	})
+}
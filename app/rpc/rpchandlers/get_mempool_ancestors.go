@@ -0,0 +1,39 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/transactionid"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+)
+
+// HandleGetMempoolAncestors handles the respectively named RPC command
+func HandleGetMempoolAncestors(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	getAncestorsRequest := request.(*appmessage.GetMempoolAncestorsRequestMessage)
+
+	transactionID, err := transactionid.FromString(getAncestorsRequest.TxID)
+	if err != nil {
+		errorMessage := &appmessage.GetMempoolAncestorsResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Transaction ID could not be parsed: %s", err)
+		return errorMessage, nil
+	}
+
+	ancestors, ok := context.Domain.MiningManager().TransactionAncestors(transactionID)
+	if !ok {
+		errorMessage := &appmessage.GetMempoolAncestorsResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Transaction %s was not found", transactionID)
+		return errorMessage, nil
+	}
+
+	var totalMass, totalFee uint64
+	for _, tx := range ancestors {
+		totalMass += tx.Mass
+		totalFee += tx.Fee
+	}
+	entries, err := mempoolEntries(context, ancestors)
+	if err != nil {
+		return nil, err
+	}
+
+	return appmessage.NewGetMempoolAncestorsResponseMessage(entries, totalMass, totalFee), nil
+}
@@ -0,0 +1,39 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/transactionid"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+)
+
+// HandleGetMempoolDescendants handles the respectively named RPC command
+func HandleGetMempoolDescendants(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	getDescendantsRequest := request.(*appmessage.GetMempoolDescendantsRequestMessage)
+
+	transactionID, err := transactionid.FromString(getDescendantsRequest.TxID)
+	if err != nil {
+		errorMessage := &appmessage.GetMempoolDescendantsResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Transaction ID could not be parsed: %s", err)
+		return errorMessage, nil
+	}
+
+	descendants, ok := context.Domain.MiningManager().TransactionDescendants(transactionID)
+	if !ok {
+		errorMessage := &appmessage.GetMempoolDescendantsResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Transaction %s was not found", transactionID)
+		return errorMessage, nil
+	}
+
+	var totalMass, totalFee uint64
+	for _, tx := range descendants {
+		totalMass += tx.Mass
+		totalFee += tx.Fee
+	}
+	entries, err := mempoolEntries(context, descendants)
+	if err != nil {
+		return nil, err
+	}
+
+	return appmessage.NewGetMempoolDescendantsResponseMessage(entries, totalMass, totalFee), nil
+}
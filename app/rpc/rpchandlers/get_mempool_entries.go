@@ -3,14 +3,64 @@ package rpchandlers
 import (
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/domain/utxoindex"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/kaspanet/kaspad/util"
 )
 
 // HandleGetMempoolEntries handles the respectively named RPC command
-func HandleGetMempoolEntries(context *rpccontext.Context, _ *router.Router, _ appmessage.Message) (appmessage.Message, error) {
+func HandleGetMempoolEntries(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	getMempoolEntriesRequest := request.(*appmessage.GetMempoolEntriesRequestMessage)
+
+	if getMempoolEntriesRequest.MinimumFeeRate < 0 {
+		errorMessage := &appmessage.GetMempoolEntriesResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("The minimum fee rate cannot be negative")
+		return errorMessage, nil
+	}
+
+	scriptPublicKeys := make(map[utxoindex.ScriptPublicKeyString]struct{}, len(getMempoolEntriesRequest.Addresses))
+	for _, addressString := range getMempoolEntriesRequest.Addresses {
+		address, err := util.DecodeAddress(addressString, context.Config.ActiveNetParams.Prefix)
+		if err != nil {
+			errorMessage := &appmessage.GetMempoolEntriesResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Could not decode address '%s': %s", addressString, err)
+			return errorMessage, nil
+		}
+		scriptPublicKey, err := txscript.PayToAddrScript(address)
+		if err != nil {
+			errorMessage := &appmessage.GetMempoolEntriesResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Could not create a scriptPublicKey for address '%s': %s", addressString, err)
+			return errorMessage, nil
+		}
+		scriptPublicKeys[utxoindex.ConvertScriptPublicKeyToString(scriptPublicKey)] = struct{}{}
+	}
 
 	transactions := context.Domain.MiningManager().AllTransactions()
+	filteredTransactions := make([]*externalapi.DomainTransaction, 0, len(transactions))
+	for _, tx := range transactions {
+		if feeRate(tx) < getMempoolEntriesRequest.MinimumFeeRate {
+			continue
+		}
+		if len(scriptPublicKeys) > 0 && !rpccontext.IsTransactionRelatedToScriptPublicKeys(tx, scriptPublicKeys) {
+			continue
+		}
+		filteredTransactions = append(filteredTransactions, tx)
+	}
+
+	entries, err := mempoolEntries(context, filteredTransactions)
+	if err != nil {
+		return nil, err
+	}
+
+	return appmessage.NewGetMempoolEntriesResponseMessage(entries), nil
+}
+
+func mempoolEntries(context *rpccontext.Context, transactions []*externalapi.DomainTransaction) (
+	[]*appmessage.MempoolEntry, error) {
+
 	entries := make([]*appmessage.MempoolEntry, 0, len(transactions))
 	for _, tx := range transactions {
 		transactionVerboseData, err := context.BuildTransactionVerboseData(
@@ -24,6 +74,13 @@ func HandleGetMempoolEntries(context *rpccontext.Context, _ *router.Router, _ ap
 			TransactionVerboseData: transactionVerboseData,
 		})
 	}
+	return entries, nil
+}
 
-	return appmessage.NewGetMempoolEntriesResponseMessage(entries), nil
+// feeRate returns the fee of the given mempool transaction in sompi per gram of mass
+func feeRate(tx *externalapi.DomainTransaction) float64 {
+	if tx.Mass == 0 {
+		return 0
+	}
+	return float64(tx.Fee) / float64(tx.Mass)
 }
@@ -0,0 +1,204 @@
+package rpchandlers_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/app/rpc/rpchandlers"
+	"github.com/kaspanet/kaspad/domain/consensus"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/model/testapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/testutils"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/domain/miningmanager"
+	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/kaspanet/kaspad/util"
+)
+
+type fakeDomainWithMiningManager struct {
+	fakeDomain
+	miningManager miningmanager.MiningManager
+}
+
+func (d fakeDomainWithMiningManager) MiningManager() miningmanager.MiningManager {
+	return d.miningManager
+}
+
+func TestHandleMempoolQueries(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, params *dagconfig.Params) {
+		params.BlockCoinbaseMaturity = 0
+
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(params, false, "TestHandleMempoolQueries")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		miningManager := miningmanager.NewFactory().NewMiningManager(tc, params.MaxMassAcceptedByBlock, true, time.Hour)
+		fakeContext := rpccontext.Context{
+			Config: &config.Config{Flags: &config.Flags{NetworkFlags: config.NetworkFlags{ActiveNetParams: params}}},
+			Domain: fakeDomainWithMiningManager{fakeDomain: fakeDomain{tc}, miningManager: miningManager},
+		}
+
+		// The coinbase of a block pays the miners of the blocks it merges, so only
+		// the coinbase of the second block pays to the test's OP_TRUE script
+		addBlockAndGetCoinbase(t, tc)
+		fundingTransaction := addBlockAndGetCoinbase(t, tc)
+
+		// Create a chain of transactions in which the last transaction
+		// pays a fee rate much higher than the others:
+		//
+		//   fundingTransaction <- parent <- child <- grandchild
+		parent := createTransactionWithFee(t, fundingTransaction, 10000)
+		child := createTransactionWithFee(t, parent, 10000)
+		grandchild := createTransactionWithFee(t, child, 1000000)
+		for _, tx := range []*externalapi.DomainTransaction{parent, child, grandchild} {
+			_, err := miningManager.ValidateAndInsertTransaction(tx, false)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+			}
+		}
+
+		// Ancestors and descendants
+		ancestorsResponse, err := rpchandlers.HandleGetMempoolAncestors(&fakeContext, nil,
+			appmessage.NewGetMempoolAncestorsRequestMessage(consensushashing.TransactionID(grandchild).String()))
+		if err != nil {
+			t.Fatalf("HandleGetMempoolAncestors: %+v", err)
+		}
+		ancestors := ancestorsResponse.(*appmessage.GetMempoolAncestorsResponseMessage)
+		assertMempoolEntries(t, ancestors.Entries, parent, child)
+		if ancestors.TotalMass != parent.Mass+child.Mass || ancestors.TotalFee != parent.Fee+child.Fee {
+			t.Fatalf("Unexpected ancestors total mass %d and total fee %d", ancestors.TotalMass, ancestors.TotalFee)
+		}
+
+		descendantsResponse, err := rpchandlers.HandleGetMempoolDescendants(&fakeContext, nil,
+			appmessage.NewGetMempoolDescendantsRequestMessage(consensushashing.TransactionID(parent).String()))
+		if err != nil {
+			t.Fatalf("HandleGetMempoolDescendants: %+v", err)
+		}
+		descendants := descendantsResponse.(*appmessage.GetMempoolDescendantsResponseMessage)
+		assertMempoolEntries(t, descendants.Entries, child, grandchild)
+		if descendants.TotalMass != child.Mass+grandchild.Mass || descendants.TotalFee != child.Fee+grandchild.Fee {
+			t.Fatalf("Unexpected descendants total mass %d and total fee %d", descendants.TotalMass, descendants.TotalFee)
+		}
+
+		notFoundResponse, err := rpchandlers.HandleGetMempoolAncestors(&fakeContext, nil,
+			appmessage.NewGetMempoolAncestorsRequestMessage(consensushashing.TransactionID(fundingTransaction).String()))
+		if err != nil {
+			t.Fatalf("HandleGetMempoolAncestors: %+v", err)
+		}
+		if notFoundResponse.(*appmessage.GetMempoolAncestorsResponseMessage).Error == nil {
+			t.Fatalf("Expected an error for a transaction that is not in the mempool")
+		}
+
+		// Stats
+		statsResponse, err := rpchandlers.HandleGetMempoolStats(&fakeContext, nil, appmessage.NewGetMempoolStatsRequestMessage())
+		if err != nil {
+			t.Fatalf("HandleGetMempoolStats: %+v", err)
+		}
+		stats := statsResponse.(*appmessage.GetMempoolStatsResponseMessage)
+		if stats.TransactionCount != 3 || stats.OrphanCount != 0 {
+			t.Fatalf("Expected 3 transactions and no orphans, but got %d and %d", stats.TransactionCount, stats.OrphanCount)
+		}
+		if stats.TotalMass != parent.Mass+child.Mass+grandchild.Mass ||
+			stats.TotalFee != parent.Fee+child.Fee+grandchild.Fee {
+
+			t.Fatalf("Unexpected total mass %d and total fee %d", stats.TotalMass, stats.TotalFee)
+		}
+		var histogramTransactionCount, histogramMass uint64
+		for i, bucket := range stats.FeeRateHistogram {
+			if i > 0 && bucket.MinimumFeeRate <= stats.FeeRateHistogram[i-1].MinimumFeeRate {
+				t.Fatalf("Expected the fee rate histogram to be ordered by ascending fee rate")
+			}
+			histogramTransactionCount += bucket.TransactionCount
+			histogramMass += bucket.TotalMass
+		}
+		if histogramTransactionCount != stats.TransactionCount || histogramMass != stats.TotalMass {
+			t.Fatalf("Expected the fee rate histogram to add up to %d transactions with mass %d, but got %d and %d",
+				stats.TransactionCount, stats.TotalMass, histogramTransactionCount, histogramMass)
+		}
+
+		// Filters
+		getMempoolEntries := func(addresses []string, minimumFeeRate float64) *appmessage.GetMempoolEntriesResponseMessage {
+			response, err := rpchandlers.HandleGetMempoolEntries(&fakeContext, nil,
+				appmessage.NewGetMempoolEntriesRequestMessage(addresses, minimumFeeRate))
+			if err != nil {
+				t.Fatalf("HandleGetMempoolEntries: %+v", err)
+			}
+			return response.(*appmessage.GetMempoolEntriesResponseMessage)
+		}
+
+		assertMempoolEntries(t, getMempoolEntries(nil, 0).Entries, parent, child, grandchild)
+
+		childFeeRate := float64(child.Fee) / float64(child.Mass)
+		grandchildFeeRate := float64(grandchild.Fee) / float64(grandchild.Mass)
+		assertMempoolEntries(t, getMempoolEntries(nil, (childFeeRate+grandchildFeeRate)/2).Entries, grandchild)
+
+		opTrueScriptPublicKey, _ := testutils.OpTrueScript()
+		_, opTrueAddress, err := txscript.ExtractScriptPubKeyAddress(opTrueScriptPublicKey, params)
+		if err != nil {
+			t.Fatalf("ExtractScriptPubKeyAddress: %+v", err)
+		}
+		assertMempoolEntries(t, getMempoolEntries([]string{opTrueAddress.String()}, 0).Entries, parent, child, grandchild)
+
+		otherAddress, err := util.NewAddressScriptHash([]byte{txscript.OpFalse}, params.Prefix)
+		if err != nil {
+			t.Fatalf("NewAddressScriptHash: %+v", err)
+		}
+		assertMempoolEntries(t, getMempoolEntries([]string{otherAddress.String()}, 0).Entries)
+
+		if getMempoolEntries([]string{"invalid address"}, 0).Error == nil {
+			t.Fatalf("Expected an error for an invalid address")
+		}
+	})
+}
+
+func assertMempoolEntries(t *testing.T, entries []*appmessage.MempoolEntry,
+	expectedTransactions ...*externalapi.DomainTransaction) {
+
+	if len(entries) != len(expectedTransactions) {
+		t.Fatalf("Expected %d mempool entries, but got %d", len(expectedTransactions), len(entries))
+	}
+	entryTransactionIDs := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		entryTransactionIDs[entry.TransactionVerboseData.TxID] = struct{}{}
+	}
+	for _, tx := range expectedTransactions {
+		transactionID := consensushashing.TransactionID(tx).String()
+		if _, ok := entryTransactionIDs[transactionID]; !ok {
+			t.Fatalf("Expected transaction %s to be in the mempool entries", transactionID)
+		}
+	}
+}
+
+func addBlockAndGetCoinbase(t *testing.T, tc testapi.TestConsensus) *externalapi.DomainTransaction {
+	virtualInfo, err := tc.GetVirtualInfo()
+	if err != nil {
+		t.Fatalf("GetVirtualInfo: %+v", err)
+	}
+	blockHash, _, err := tc.AddBlock(virtualInfo.ParentHashes, nil, nil)
+	if err != nil {
+		t.Fatalf("AddBlock: %+v", err)
+	}
+	block, err := tc.GetBlock(blockHash)
+	if err != nil {
+		t.Fatalf("GetBlock: %+v", err)
+	}
+	return block.Transactions[0]
+}
+
+func createTransactionWithFee(t *testing.T, txToSpend *externalapi.DomainTransaction,
+	fee uint64) *externalapi.DomainTransaction {
+
+	tx, err := testutils.CreateTransaction(txToSpend)
+	if err != nil {
+		t.Fatalf("CreateTransaction: %+v", err)
+	}
+	tx.Outputs[0].Value = txToSpend.Outputs[0].Value - fee
+	return tx
+}
@@ -0,0 +1,48 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+)
+
+// mempoolFeeRateHistogramBucketBounds are the lowest fee rates, in sompi
+// per gram of mass, of the buckets in the mempool fee rate histogram
+var mempoolFeeRateHistogramBucketBounds = []float64{0, 1, 2, 5, 10, 20, 50, 100, 200, 500, 1000}
+
+// HandleGetMempoolStats handles the respectively named RPC command
+func HandleGetMempoolStats(context *rpccontext.Context, _ *router.Router, _ appmessage.Message) (appmessage.Message, error) {
+	transactions := context.Domain.MiningManager().AllTransactions()
+	orphans := context.Domain.MiningManager().OrphanTransactions()
+
+	feeRateHistogram := make([]*appmessage.MempoolFeeRateBucket, len(mempoolFeeRateHistogramBucketBounds))
+	for i, minimumFeeRate := range mempoolFeeRateHistogramBucketBounds {
+		feeRateHistogram[i] = &appmessage.MempoolFeeRateBucket{MinimumFeeRate: minimumFeeRate}
+	}
+
+	var totalMass, totalFee uint64
+	for _, tx := range transactions {
+		totalMass += tx.Mass
+		totalFee += tx.Fee
+
+		bucket := feeRateHistogram[feeRateHistogramBucketIndex(feeRate(tx))]
+		bucket.TransactionCount++
+		bucket.TotalMass += tx.Mass
+	}
+
+	return appmessage.NewGetMempoolStatsResponseMessage(uint64(len(transactions)), uint64(len(orphans)),
+		totalMass, totalFee, feeRateHistogram), nil
+}
+
+// feeRateHistogramBucketIndex returns the index of the last bucket whose
+// lowest fee rate is not greater than the given fee rate
+func feeRateHistogramBucketIndex(feeRate float64) int {
+	index := 0
+	for i, minimumFeeRate := range mempoolFeeRateHistogramBucketBounds {
+		if feeRate < minimumFeeRate {
+			break
+		}
+		index = i
+	}
+	return index
+}
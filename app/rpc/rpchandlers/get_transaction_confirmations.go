@@ -0,0 +1,73 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/transactionid"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+)
+
+// HandleGetTransactionConfirmations handles the respectively named RPC command
+func HandleGetTransactionConfirmations(context *rpccontext.Context, _ *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	getTransactionConfirmationsRequest := request.(*appmessage.GetTransactionConfirmationsRequestMessage)
+
+	transactionID, err := transactionid.FromString(getTransactionConfirmationsRequest.TransactionID)
+	if err != nil {
+		errorMessage := &appmessage.GetTransactionConfirmationsResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Transaction ID could not be parsed: %s", err)
+		return errorMessage, nil
+	}
+	blockHash, err := externalapi.NewDomainHashFromString(getTransactionConfirmationsRequest.BlockHash)
+	if err != nil {
+		errorMessage := &appmessage.GetTransactionConfirmationsResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Hash could not be parsed: %s", err)
+		return errorMessage, nil
+	}
+
+	errorMessage, err := validateBlockHasBody(context, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if errorMessage != nil {
+		return &appmessage.GetTransactionConfirmationsResponseMessage{Error: errorMessage}, nil
+	}
+
+	block, err := context.Domain.Consensus().GetBlock(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	transactionIndex := -1
+	for i, transaction := range block.Transactions {
+		if consensushashing.TransactionID(transaction).Equal(transactionID) {
+			transactionIndex = i
+			break
+		}
+	}
+	if transactionIndex == -1 {
+		errorMessage := &appmessage.GetTransactionConfirmationsResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("Transaction %s was not found in block %s", transactionID, blockHash)
+		return errorMessage, nil
+	}
+
+	blockAcceptance, err := context.BlockAcceptance(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	// The acceptance data might not cover the transaction, for example if it
+	// was pruned, in which case the transaction is reported as not accepted
+	if blockAcceptance.AcceptingBlockHash == nil ||
+		transactionIndex >= len(blockAcceptance.TransactionAcceptanceData) ||
+		!blockAcceptance.TransactionAcceptanceData[transactionIndex].IsAccepted {
+
+		return appmessage.NewGetTransactionConfirmationsResponseMessage(false, "", 0, false), nil
+	}
+
+	isFinalized, err := context.Domain.Consensus().IsFinalizedBlock(blockAcceptance.AcceptingBlockHash)
+	if err != nil {
+		return nil, err
+	}
+	return appmessage.NewGetTransactionConfirmationsResponseMessage(true,
+		blockAcceptance.AcceptingBlockHash.String(), blockAcceptance.Confirmations, isFinalized), nil
+}
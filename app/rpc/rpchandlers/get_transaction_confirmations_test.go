@@ -0,0 +1,175 @@
+package rpchandlers_test
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/app/rpc/rpchandlers"
+	"github.com/kaspanet/kaspad/domain/consensus"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/testutils"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/infrastructure/config"
+)
+
+func TestHandleGetTransactionConfirmations(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, params *dagconfig.Params) {
+		params.BlockCoinbaseMaturity = 0
+
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(params, false, "TestHandleGetTransactionConfirmations")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		fakeContext := rpccontext.Context{
+			Config: &config.Config{Flags: &config.Flags{NetworkFlags: config.NetworkFlags{ActiveNetParams: params}}},
+			Domain: fakeDomain{tc},
+		}
+
+		getBlockAcceptanceStatus := func(blockHash *externalapi.DomainHash) *appmessage.GetBlockAcceptanceStatusResponseMessage {
+			response, err := rpchandlers.HandleGetBlockAcceptanceStatus(&fakeContext, nil,
+				appmessage.NewGetBlockAcceptanceStatusRequestMessage(blockHash.String()))
+			if err != nil {
+				t.Fatalf("HandleGetBlockAcceptanceStatus: %+v", err)
+			}
+			return response.(*appmessage.GetBlockAcceptanceStatusResponseMessage)
+		}
+		getTransactionConfirmations := func(transactionID *externalapi.DomainTransactionID,
+			blockHash *externalapi.DomainHash) *appmessage.GetTransactionConfirmationsResponseMessage {
+
+			response, err := rpchandlers.HandleGetTransactionConfirmations(&fakeContext, nil,
+				appmessage.NewGetTransactionConfirmationsRequestMessage(transactionID.String(), blockHash.String()))
+			if err != nil {
+				t.Fatalf("HandleGetTransactionConfirmations: %+v", err)
+			}
+			return response.(*appmessage.GetTransactionConfirmationsResponseMessage)
+		}
+
+		addBlockAndGetCoinbase(t, tc)
+		fundingTransaction := addBlockAndGetCoinbase(t, tc)
+		transaction := createTransactionWithFee(t, fundingTransaction, 1000)
+		transactionID := consensushashing.TransactionID(transaction)
+
+		virtualInfo, err := tc.GetVirtualInfo()
+		if err != nil {
+			t.Fatalf("GetVirtualInfo: %+v", err)
+		}
+		transactionBlockHash, _, err := tc.AddBlock(virtualInfo.ParentHashes, nil,
+			[]*externalapi.DomainTransaction{transaction})
+		if err != nil {
+			t.Fatalf("AddBlock: %+v", err)
+		}
+
+		// The transaction's block is the virtual's selected parent, so
+		// it's accepted by the virtual only
+		blockAcceptanceStatus := getBlockAcceptanceStatus(transactionBlockHash)
+		if blockAcceptanceStatus.Error != nil {
+			t.Fatalf("HandleGetBlockAcceptanceStatus: %s", blockAcceptanceStatus.Error.Message)
+		}
+		if blockAcceptanceStatus.AcceptingBlockHash != "" || blockAcceptanceStatus.Confirmations != 0 {
+			t.Fatalf("Expected the block not to be accepted, but got accepting block %s with %d confirmations",
+				blockAcceptanceStatus.AcceptingBlockHash, blockAcceptanceStatus.Confirmations)
+		}
+		if !blockAcceptanceStatus.IsBlue || !blockAcceptanceStatus.IsChainBlock || blockAcceptanceStatus.IsFinalized {
+			t.Fatalf("Expected the block to be a blue chain block that isn't finalized, but got: %+v",
+				blockAcceptanceStatus)
+		}
+		transactionConfirmations := getTransactionConfirmations(transactionID, transactionBlockHash)
+		if transactionConfirmations.Error != nil {
+			t.Fatalf("HandleGetTransactionConfirmations: %s", transactionConfirmations.Error.Message)
+		}
+		if transactionConfirmations.IsAccepted {
+			t.Fatalf("Expected the transaction not to be accepted")
+		}
+
+		// Create a DAG with the following structure, so that the
+		// transaction's block is merged by a block that has another
+		// parent as well:
+		//
+		//   ... <- transactionBlock <- mergingBlock <- nextBlock
+		//      \                      /
+		//       <---- siblingBlock <--
+		siblingBlockHash, _, err := tc.AddBlock(virtualInfo.ParentHashes, nil, nil)
+		if err != nil {
+			t.Fatalf("AddBlock: %+v", err)
+		}
+		mergingBlockHash, _, err := tc.AddBlock([]*externalapi.DomainHash{transactionBlockHash, siblingBlockHash}, nil, nil)
+		if err != nil {
+			t.Fatalf("AddBlock: %+v", err)
+		}
+		_, _, err = tc.AddBlock([]*externalapi.DomainHash{mergingBlockHash}, nil, nil)
+		if err != nil {
+			t.Fatalf("AddBlock: %+v", err)
+		}
+
+		const expectedConfirmations = 2
+		for _, blockHash := range []*externalapi.DomainHash{transactionBlockHash, siblingBlockHash} {
+			blockAcceptanceStatus := getBlockAcceptanceStatus(blockHash)
+			if blockAcceptanceStatus.Error != nil {
+				t.Fatalf("HandleGetBlockAcceptanceStatus: %s", blockAcceptanceStatus.Error.Message)
+			}
+			if blockAcceptanceStatus.AcceptingBlockHash != mergingBlockHash.String() {
+				t.Fatalf("Expected block %s to be accepted by %s, but got %s",
+					blockHash, mergingBlockHash, blockAcceptanceStatus.AcceptingBlockHash)
+			}
+			if blockAcceptanceStatus.Confirmations != expectedConfirmations {
+				t.Fatalf("Expected %d confirmations, but got %d",
+					expectedConfirmations, blockAcceptanceStatus.Confirmations)
+			}
+			if !blockAcceptanceStatus.IsBlue {
+				t.Fatalf("Expected block %s to be blue", blockHash)
+			}
+
+			// Only one of the merged blocks is the selected parent of mergingBlock
+			mergingBlockGHOSTDAGData, err := tc.GHOSTDAGDataStore().Get(tc.DatabaseContext(), mergingBlockHash)
+			if err != nil {
+				t.Fatalf("GHOSTDAGDataStore().Get: %+v", err)
+			}
+			expectedIsChainBlock := mergingBlockGHOSTDAGData.SelectedParent().Equal(blockHash)
+			if blockAcceptanceStatus.IsChainBlock != expectedIsChainBlock {
+				t.Fatalf("Expected IsChainBlock of block %s to be %t", blockHash, expectedIsChainBlock)
+			}
+		}
+
+		transactionConfirmations = getTransactionConfirmations(transactionID, transactionBlockHash)
+		if transactionConfirmations.Error != nil {
+			t.Fatalf("HandleGetTransactionConfirmations: %s", transactionConfirmations.Error.Message)
+		}
+		if !transactionConfirmations.IsAccepted {
+			t.Fatalf("Expected the transaction to be accepted")
+		}
+		if transactionConfirmations.AcceptingBlockHash != mergingBlockHash.String() {
+			t.Fatalf("Expected the transaction to be accepted by %s, but got %s",
+				mergingBlockHash, transactionConfirmations.AcceptingBlockHash)
+		}
+		if transactionConfirmations.Confirmations != expectedConfirmations {
+			t.Fatalf("Expected %d confirmations, but got %d",
+				expectedConfirmations, transactionConfirmations.Confirmations)
+		}
+		if transactionConfirmations.IsFinalized {
+			t.Fatalf("Expected the transaction not to be finalized")
+		}
+
+		// The genesis is the virtual's finality point until the DAG is deeper than the finality depth
+		genesisAcceptanceStatus := getBlockAcceptanceStatus(params.GenesisHash)
+		if genesisAcceptanceStatus.Error != nil {
+			t.Fatalf("HandleGetBlockAcceptanceStatus: %s", genesisAcceptanceStatus.Error.Message)
+		}
+		if !genesisAcceptanceStatus.IsFinalized || !genesisAcceptanceStatus.IsChainBlock {
+			t.Fatalf("Expected the genesis to be a finalized chain block, but got: %+v", genesisAcceptanceStatus)
+		}
+
+		transactionConfirmations = getTransactionConfirmations(transactionID, siblingBlockHash)
+		if transactionConfirmations.Error == nil {
+			t.Fatalf("Expected an error for a transaction that isn't in the given block")
+		}
+		blockAcceptanceStatus = getBlockAcceptanceStatus(&externalapi.DomainHash{})
+		if blockAcceptanceStatus.Error == nil {
+			t.Fatalf("Expected an error for a block that doesn't exist")
+		}
+	})
+}
@@ -1,11 +1,17 @@
 package rpchandlers
 
 import (
+	"encoding/binary"
+	"encoding/hex"
+
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/domain/utxoindex"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
 	"github.com/kaspanet/kaspad/util"
+	"github.com/pkg/errors"
 )
 
 // HandleGetUTXOsByAddresses handles the respectively named RPC command
@@ -18,8 +24,14 @@ func HandleGetUTXOsByAddresses(context *rpccontext.Context, _ *router.Router, re
 
 	getUTXOsByAddressesRequest := request.(*appmessage.GetUTXOsByAddressesRequestMessage)
 
-	allEntries := make([]*appmessage.UTXOsByAddressesEntry, 0)
-	for _, addressString := range getUTXOsByAddressesRequest.Addresses {
+	if getUTXOsByAddressesRequest.Limit == 0 && getUTXOsByAddressesRequest.Cursor != "" {
+		errorMessage := &appmessage.GetUTXOsByAddressesResponseMessage{}
+		errorMessage.Error = appmessage.RPCErrorf("A cursor cannot be used without a limit")
+		return errorMessage, nil
+	}
+
+	scriptPublicKeys := make([]*externalapi.ScriptPublicKey, len(getUTXOsByAddressesRequest.Addresses))
+	for i, addressString := range getUTXOsByAddressesRequest.Addresses {
 		address, err := util.DecodeAddress(addressString, context.Config.ActiveNetParams.Prefix)
 		if err != nil {
 			errorMessage := &appmessage.GetUTXOsByAddressesResponseMessage{}
@@ -32,14 +44,129 @@ func HandleGetUTXOsByAddresses(context *rpccontext.Context, _ *router.Router, re
 			errorMessage.Error = appmessage.RPCErrorf("Could not create a scriptPublicKey for address '%s': %s", addressString, err)
 			return errorMessage, nil
 		}
-		utxoOutpointEntryPairs, err := context.UTXOIndex.UTXOs(scriptPublicKey)
+		scriptPublicKeys[i] = scriptPublicKey
+	}
+
+	includeMempool := getUTXOsByAddressesRequest.IncludeMempool
+
+	if getUTXOsByAddressesRequest.Limit == 0 {
+		allEntries := make([]*appmessage.UTXOsByAddressesEntry, 0)
+		for i, addressString := range getUTXOsByAddressesRequest.Addresses {
+			utxoOutpointEntryPairs, err := context.UTXOIndex.UTXOs(scriptPublicKeys[i])
+			if err != nil {
+				return nil, err
+			}
+			if includeMempool {
+				for outpoint := range utxoOutpointEntryPairs {
+					outpoint := outpoint
+					if context.Domain.MiningManager().IsOutpointSpent(&outpoint) {
+						delete(utxoOutpointEntryPairs, outpoint)
+					}
+				}
+			}
+			entries := rpccontext.ConvertUTXOOutpointEntryPairsToUTXOsByAddressesEntries(addressString, utxoOutpointEntryPairs)
+			allEntries = append(allEntries, entries...)
+		}
+		if includeMempool {
+			allEntries = append(allEntries, unconfirmedUTXOsByAddressesEntries(context,
+				getUTXOsByAddressesRequest.Addresses, scriptPublicKeys)...)
+		}
+
+		response := appmessage.NewGetUTXOsByAddressesResponseMessage(allEntries, "")
+		return response, nil
+	}
+
+	fromAddressIndex := 0
+	var fromOutpoint *externalapi.DomainOutpoint
+	if getUTXOsByAddressesRequest.Cursor != "" {
+		var err error
+		fromAddressIndex, fromOutpoint, err = decodeUTXOsByAddressesCursor(getUTXOsByAddressesRequest.Cursor)
+		if err != nil || fromAddressIndex >= len(getUTXOsByAddressesRequest.Addresses) {
+			errorMessage := &appmessage.GetUTXOsByAddressesResponseMessage{}
+			errorMessage.Error = appmessage.RPCErrorf("Invalid cursor '%s'", getUTXOsByAddressesRequest.Cursor)
+			return errorMessage, nil
+		}
+	}
+
+	// Pages go over the UTXOs of the addresses one address after the other,
+	// so a cursor points to an outpoint of a specific address
+	entries := make([]*appmessage.UTXOsByAddressesEntry, 0)
+	remaining := int(getUTXOsByAddressesRequest.Limit)
+	nextCursor := ""
+	for i := fromAddressIndex; i < len(getUTXOsByAddressesRequest.Addresses); i++ {
+		addressString := getUTXOsByAddressesRequest.Addresses[i]
+		pairs, err := context.UTXOIndex.UTXOsPage(scriptPublicKeys[i], fromOutpoint, remaining)
 		if err != nil {
 			return nil, err
 		}
-		entries := rpccontext.ConvertUTXOOutpointEntryPairsToUTXOsByAddressesEntries(addressString, utxoOutpointEntryPairs)
-		allEntries = append(allEntries, entries...)
+		remaining -= len(pairs)
+		if remaining == 0 {
+			nextCursor = encodeUTXOsByAddressesCursor(i, pairs[len(pairs)-1].Outpoint)
+		}
+
+		// UTXOs spent in the mempool are excluded only after the cursor is set,
+		// so that they still count towards the limit of the page
+		if includeMempool {
+			pairs = context.ExcludeUTXOsSpentInMempool(pairs)
+		}
+		entries = append(entries, rpccontext.ConvertOutpointAndUTXOEntryPairsToUTXOsByAddressesEntries(addressString, pairs)...)
+
+		if remaining == 0 {
+			break
+		}
+		fromOutpoint = nil
+	}
+	if includeMempool && nextCursor == "" {
+		entries = append(entries, unconfirmedUTXOsByAddressesEntries(context,
+			getUTXOsByAddressesRequest.Addresses, scriptPublicKeys)...)
 	}
 
-	response := appmessage.NewGetUTXOsByAddressesResponseMessage(allEntries)
+	response := appmessage.NewGetUTXOsByAddressesResponseMessage(entries, nextCursor)
 	return response, nil
 }
+
+// unconfirmedUTXOsByAddressesEntries returns the outputs of the transactions in the
+// mempool that pay to the given addresses and are not spent in the mempool
+func unconfirmedUTXOsByAddressesEntries(context *rpccontext.Context, addresses []string,
+	scriptPublicKeys []*externalapi.ScriptPublicKey) []*appmessage.UTXOsByAddressesEntry {
+
+	mempoolUTXOs := context.MempoolUTXOs()
+	entries := make([]*appmessage.UTXOsByAddressesEntry, 0)
+	for i, addressString := range addresses {
+		utxoOutpointEntryPairs, ok := mempoolUTXOs[utxoindex.ConvertScriptPublicKeyToString(scriptPublicKeys[i])]
+		if !ok {
+			continue
+		}
+		entries = append(entries, rpccontext.ConvertUTXOOutpointEntryPairsToUTXOsByAddressesEntries(addressString, utxoOutpointEntryPairs)...)
+	}
+	rpccontext.MarkUTXOsByAddressesEntriesAsUnconfirmed(entries)
+	return entries
+}
+
+const utxosByAddressesCursorLength = 4 + externalapi.DomainHashSize + 4
+
+func encodeUTXOsByAddressesCursor(addressIndex int, outpoint *externalapi.DomainOutpoint) string {
+	cursor := make([]byte, utxosByAddressesCursorLength)
+	binary.LittleEndian.PutUint32(cursor[:4], uint32(addressIndex))
+	copy(cursor[4:4+externalapi.DomainHashSize], outpoint.TransactionID.ByteSlice())
+	binary.LittleEndian.PutUint32(cursor[4+externalapi.DomainHashSize:], outpoint.Index)
+	return hex.EncodeToString(cursor)
+}
+
+func decodeUTXOsByAddressesCursor(cursorString string) (addressIndex int, outpoint *externalapi.DomainOutpoint, err error) {
+	cursor, err := hex.DecodeString(cursorString)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(cursor) != utxosByAddressesCursorLength {
+		return 0, nil, errors.Errorf("unexpected cursor length %d", len(cursor))
+	}
+	addressIndex = int(binary.LittleEndian.Uint32(cursor[:4]))
+	var transactionIDBytes [externalapi.DomainHashSize]byte
+	copy(transactionIDBytes[:], cursor[4:4+externalapi.DomainHashSize])
+	outpoint = &externalapi.DomainOutpoint{
+		TransactionID: *externalapi.NewDomainTransactionIDFromByteArray(&transactionIDBytes),
+		Index:         binary.LittleEndian.Uint32(cursor[4+externalapi.DomainHashSize:]),
+	}
+	return addressIndex, outpoint, nil
+}
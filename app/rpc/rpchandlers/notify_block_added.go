@@ -4,15 +4,22 @@ import (
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/pkg/errors"
 )
 
 // HandleNotifyBlockAdded handles the respectively named RPC command
-func HandleNotifyBlockAdded(context *rpccontext.Context, router *router.Router, _ appmessage.Message) (appmessage.Message, error) {
-	listener, err := context.NotificationManager.Listener(router)
+func HandleNotifyBlockAdded(context *rpccontext.Context, router *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	notifyBlockAddedRequest := request.(*appmessage.NotifyBlockAddedRequestMessage)
+	err := context.NotificationManager.PropagateBlockAddedNotifications(router,
+		notifyBlockAddedRequest.StartStreamID, notifyBlockAddedRequest.StartSequenceNumber)
 	if err != nil {
+		if errors.Is(err, rpccontext.ErrNotificationsUnavailable) {
+			errorMessage := appmessage.NewNotifyBlockAddedResponseMessage()
+			errorMessage.Error = appmessage.RPCErrorf("Could not replay notifications: %s", err)
+			return errorMessage, nil
+		}
 		return nil, err
 	}
-	listener.PropagateBlockAddedNotifications()
 
 	response := appmessage.NewNotifyBlockAddedResponseMessage()
 	return response, nil
@@ -0,0 +1,27 @@
+package rpchandlers
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+)
+
+// HandleNotifyMempoolTransactions handles the respectively named RPC command
+func HandleNotifyMempoolTransactions(context *rpccontext.Context, router *router.Router, request appmessage.Message) (appmessage.Message, error) {
+	notifyMempoolTransactionsRequest := request.(*appmessage.NotifyMempoolTransactionsRequestMessage)
+	addresses, err := context.ConvertAddressStringsToUTXOsChangedNotificationAddresses(notifyMempoolTransactionsRequest.Addresses)
+	if err != nil {
+		errorMessage := appmessage.NewNotifyMempoolTransactionsResponseMessage()
+		errorMessage.Error = appmessage.RPCErrorf("Parsing error: %s", err)
+		return errorMessage, nil
+	}
+
+	listener, err := context.NotificationManager.Listener(router)
+	if err != nil {
+		return nil, err
+	}
+	listener.PropagateMempoolTransactionsNotifications(addresses)
+
+	response := appmessage.NewNotifyMempoolTransactionsResponseMessage()
+	return response, nil
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/pkg/errors"
 )
 
 // HandleNotifyUTXOsChanged handles the respectively named RPC command
@@ -22,11 +23,17 @@ func HandleNotifyUTXOsChanged(context *rpccontext.Context, router *router.Router
 		return errorMessage, nil
 	}
 
-	listener, err := context.NotificationManager.Listener(router)
+	err = context.NotificationManager.PropagateUTXOsChangedNotifications(router, addresses,
+		notifyUTXOsChangedRequest.IncludeMempool, notifyUTXOsChangedRequest.StartStreamID,
+		notifyUTXOsChangedRequest.StartSequenceNumber)
 	if err != nil {
+		if errors.Is(err, rpccontext.ErrNotificationsUnavailable) {
+			errorMessage := appmessage.NewNotifyUTXOsChangedResponseMessage()
+			errorMessage.Error = appmessage.RPCErrorf("Could not replay notifications: %s", err)
+			return errorMessage, nil
+		}
 		return nil, err
 	}
-	listener.PropagateUTXOsChangedNotifications(addresses)
 
 	response := appmessage.NewNotifyUTXOsChangedResponseMessage()
 	return response, nil
@@ -4,15 +4,24 @@ import (
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/app/rpc/rpccontext"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/pkg/errors"
 )
 
 // HandleNotifyVirtualSelectedParentChainChanged handles the respectively named RPC command
-func HandleNotifyVirtualSelectedParentChainChanged(context *rpccontext.Context, router *router.Router, _ appmessage.Message) (appmessage.Message, error) {
-	listener, err := context.NotificationManager.Listener(router)
+func HandleNotifyVirtualSelectedParentChainChanged(context *rpccontext.Context, router *router.Router,
+	request appmessage.Message) (appmessage.Message, error) {
+
+	notifyChainChangedRequest := request.(*appmessage.NotifyVirtualSelectedParentChainChangedRequestMessage)
+	err := context.NotificationManager.PropagateVirtualSelectedParentChainChangedNotifications(
+		router, notifyChainChangedRequest.StartStreamID, notifyChainChangedRequest.StartSequenceNumber)
 	if err != nil {
+		if errors.Is(err, rpccontext.ErrNotificationsUnavailable) {
+			errorMessage := appmessage.NewNotifyVirtualSelectedParentChainChangedResponseMessage()
+			errorMessage.Error = appmessage.RPCErrorf("Could not replay notifications: %s", err)
+			return errorMessage, nil
+		}
 		return nil, err
 	}
-	listener.PropagateVirtualSelectedParentChainChangedNotifications()
 
 	response := appmessage.NewNotifyVirtualSelectedParentChainChangedResponseMessage()
 	return response, nil
@@ -0,0 +1,373 @@
+package stratum
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/pow"
+	"github.com/pkg/errors"
+)
+
+const (
+	// extraNonceSize is the size in bytes of the nonce prefix the server
+	// assigns to every client, so that clients don't search overlapping
+	// nonce ranges
+	extraNonceSize = 2
+	nonceSize      = 8
+
+	maxRequestSize      = 64 * 1024
+	outgoingChannelSize = 64
+	writeTimeout        = 10 * time.Second
+
+	difficultyPasswordPrefix = "d="
+)
+
+// client is a single stratum connection. A client may authorize several
+// workers, and they all share the client's difficulty and nonce range
+type client struct {
+	server     *Server
+	connection net.Conn
+	extraNonce uint16
+
+	outgoing  chan interface{}
+	quit      chan struct{}
+	closeOnce sync.Once
+
+	lock         sync.Mutex
+	isSubscribed bool
+	workers      map[string]struct{}
+	difficulty   float64
+	target       *big.Int
+}
+
+func newClient(server *Server, connection net.Conn, extraNonce uint16) *client {
+	return &client{
+		server:     server,
+		connection: connection,
+		extraNonce: extraNonce,
+		outgoing:   make(chan interface{}, outgoingChannelSize),
+		quit:       make(chan struct{}),
+		workers:    make(map[string]struct{}),
+		difficulty: server.initialDifficulty,
+		target:     server.initialTarget,
+	}
+}
+
+func (c *client) String() string {
+	return c.connection.RemoteAddr().String()
+}
+
+func (c *client) start() {
+	spawn("stratum.client.writeLoop", c.writeLoop)
+	spawn("stratum.client.readLoop", func() {
+		defer c.close()
+		err := c.readLoop()
+		if err != nil {
+			log.Debugf("Stratum client %s disconnected: %s", c, err)
+		}
+	})
+}
+
+func (c *client) close() {
+	c.closeOnce.Do(func() {
+		close(c.quit)
+		_ = c.connection.Close()
+		c.server.removeClient(c)
+	})
+}
+
+func (c *client) readLoop() error {
+	scanner := bufio.NewScanner(c.connection)
+	scanner.Buffer(make([]byte, 0, 4096), maxRequestSize)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(strings.TrimSpace(string(line))) == 0 {
+			continue
+		}
+
+		var req request
+		err := json.Unmarshal(line, &req)
+		if err != nil {
+			return errors.Wrapf(err, "malformed request")
+		}
+
+		result, stratumErr := c.handleRequest(&req)
+		c.send(&response{ID: req.ID, Result: result, Error: stratumErr})
+
+		// Notifications that follow a subscription must be sent after
+		// the subscription response
+		if req.Method == methodSubscribe && stratumErr == nil {
+			c.sendInitialNotifications()
+		}
+	}
+	return scanner.Err()
+}
+
+func (c *client) writeLoop() {
+	for {
+		select {
+		case message := <-c.outgoing:
+			messageBytes, err := json.Marshal(message)
+			if err != nil {
+				log.Errorf("Could not serialize stratum message: %s", err)
+				continue
+			}
+			messageBytes = append(messageBytes, '\n')
+			err = c.connection.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if err == nil {
+				_, err = c.connection.Write(messageBytes)
+			}
+			if err != nil {
+				log.Debugf("Could not write to stratum client %s: %s", c, err)
+				c.close()
+				return
+			}
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// send queues the given message to be written to the client. Clients
+// that don't keep up with their messages are disconnected
+func (c *client) send(message interface{}) {
+	select {
+	case c.outgoing <- message:
+	case <-c.quit:
+	default:
+		log.Warnf("Stratum client %s is not reading its messages. Disconnecting", c)
+		c.close()
+	}
+}
+
+func (c *client) notifyJob(job *job, cleanJobs bool) {
+	c.lock.Lock()
+	isSubscribed := c.isSubscribed
+	c.lock.Unlock()
+	if !isSubscribed {
+		return
+	}
+	c.send(&notification{Method: methodNotify, Params: job.notifyParams(cleanJobs)})
+}
+
+func (c *client) handleRequest(req *request) (interface{}, *stratumError) {
+	switch req.Method {
+	case methodSubscribe:
+		return c.handleSubscribe()
+	case methodAuthorize:
+		return c.handleAuthorize(req.Params)
+	case methodSuggestDifficulty:
+		return c.handleSuggestDifficulty(req.Params)
+	case methodSubmit:
+		return c.handleSubmit(req.Params)
+	default:
+		return nil, newStratumError(errorCodeOther, fmt.Sprintf("unknown method %s", req.Method))
+	}
+}
+
+func (c *client) handleSubscribe() (interface{}, *stratumError) {
+	c.lock.Lock()
+	c.isSubscribed = true
+	c.lock.Unlock()
+
+	subscriptionID := fmt.Sprintf("%04x", c.extraNonce)
+	extraNonceBytes := []byte{byte(c.extraNonce >> 8), byte(c.extraNonce)}
+	result := []interface{}{
+		[][]string{{methodSetDifficulty, subscriptionID}, {methodNotify, subscriptionID}},
+		hex.EncodeToString(extraNonceBytes),
+		nonceSize - extraNonceSize,
+	}
+	return result, nil
+}
+
+func (c *client) sendInitialNotifications() {
+	c.lock.Lock()
+	difficulty := c.difficulty
+	c.lock.Unlock()
+
+	c.sendDifficulty(difficulty)
+	latestJob := c.server.jobManager.latest()
+	if latestJob != nil {
+		c.send(&notification{Method: methodNotify, Params: latestJob.notifyParams(true)})
+	}
+}
+
+func (c *client) handleAuthorize(params []json.RawMessage) (interface{}, *stratumError) {
+	if len(params) < 1 {
+		return nil, newStratumError(errorCodeOther, "missing worker name")
+	}
+	var workerName string
+	err := json.Unmarshal(params[0], &workerName)
+	if err != nil || workerName == "" {
+		return nil, newStratumError(errorCodeOther, "invalid worker name")
+	}
+
+	// Workers may request a difficulty using a password of the form d=<difficulty>
+	if len(params) >= 2 {
+		var password string
+		err := json.Unmarshal(params[1], &password)
+		if err == nil && strings.HasPrefix(password, difficultyPasswordPrefix) {
+			difficulty, err := strconv.ParseFloat(strings.TrimPrefix(password, difficultyPasswordPrefix), 64)
+			if err != nil || !isValidDifficulty(difficulty) {
+				return nil, newStratumError(errorCodeOther, fmt.Sprintf("invalid difficulty in password %s", password))
+			}
+			err = c.setDifficulty(difficulty)
+			if err != nil {
+				return nil, newStratumError(errorCodeOther, err.Error())
+			}
+		}
+	}
+
+	c.lock.Lock()
+	c.workers[workerName] = struct{}{}
+	c.lock.Unlock()
+
+	log.Infof("Stratum worker %s authorized from %s", workerName, c)
+	return true, nil
+}
+
+func (c *client) handleSuggestDifficulty(params []json.RawMessage) (interface{}, *stratumError) {
+	if len(params) < 1 {
+		return nil, newStratumError(errorCodeOther, "missing difficulty")
+	}
+	var difficulty float64
+	err := json.Unmarshal(params[0], &difficulty)
+	if err != nil || !isValidDifficulty(difficulty) {
+		return nil, newStratumError(errorCodeOther, "invalid difficulty")
+	}
+	err = c.setDifficulty(difficulty)
+	if err != nil {
+		return nil, newStratumError(errorCodeOther, err.Error())
+	}
+	return true, nil
+}
+
+func (c *client) setDifficulty(difficulty float64) error {
+	target, err := difficultyToTarget(c.server.powMax, difficulty)
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	c.difficulty = difficulty
+	c.target = target
+	isSubscribed := c.isSubscribed
+	c.lock.Unlock()
+
+	if isSubscribed {
+		c.sendDifficulty(difficulty)
+	}
+	return nil
+}
+
+func (c *client) sendDifficulty(difficulty float64) {
+	c.send(&notification{Method: methodSetDifficulty, Params: []interface{}{difficulty}})
+}
+
+func (c *client) handleSubmit(params []json.RawMessage) (interface{}, *stratumError) {
+	if len(params) < 3 {
+		return nil, newStratumError(errorCodeOther, "expected worker name, job ID and nonce")
+	}
+	var workerName, jobID, nonceString string
+	for i, destination := range []*string{&workerName, &jobID, &nonceString} {
+		err := json.Unmarshal(params[i], destination)
+		if err != nil {
+			return nil, newStratumError(errorCodeOther, fmt.Sprintf("parameter %d is not a string", i))
+		}
+	}
+
+	c.lock.Lock()
+	isSubscribed := c.isSubscribed
+	_, isAuthorized := c.workers[workerName]
+	target := c.target
+	c.lock.Unlock()
+	if !isSubscribed {
+		return nil, newStratumError(errorCodeNotSubscribed, "not subscribed")
+	}
+	if !isAuthorized {
+		return nil, newStratumError(errorCodeUnauthorizedWorker, fmt.Sprintf("worker %s is not authorized", workerName))
+	}
+
+	return c.validateShare(workerName, jobID, nonceString, target)
+}
+
+func (c *client) validateShare(workerName, jobID, nonceString string, target *big.Int) (interface{}, *stratumError) {
+	shareJob := c.server.jobManager.get(jobID)
+	if shareJob == nil {
+		c.server.stats.recordShare(workerName, shareStale, false)
+		return nil, newStratumError(errorCodeJobNotFound, fmt.Sprintf("job %s not found", jobID))
+	}
+
+	nonce, err := c.parseNonce(nonceString)
+	if err != nil {
+		c.server.stats.recordShare(workerName, shareInvalid, false)
+		return nil, newStratumError(errorCodeOther, err.Error())
+	}
+
+	header := shareJob.headerWithNonce(nonce)
+	meetsBlockTarget := pow.CheckProofOfWorkWithTarget(header, shareJob.blockTarget)
+	meetsShareTarget := meetsBlockTarget || pow.CheckProofOfWorkWithTarget(header, target)
+	if !meetsShareTarget {
+		c.server.stats.recordShare(workerName, shareLowDifficulty, false)
+		return nil, newStratumError(errorCodeLowDifficultyShare, "low difficulty share")
+	}
+
+	// Shares that find a block are never turned away because the job is full
+	switch shareJob.markNonceSubmitted(nonce) {
+	case nonceSubmissionDuplicate:
+		c.server.stats.recordShare(workerName, shareDuplicate, false)
+		return nil, newStratumError(errorCodeDuplicateShare, "duplicate share")
+	case nonceSubmissionJobFull:
+		if meetsBlockTarget {
+			break
+		}
+		c.server.stats.recordShare(workerName, shareStale, false)
+		return nil, newStratumError(errorCodeJobNotFound, fmt.Sprintf("job %s no longer accepts shares", jobID))
+	}
+
+	isBlock := false
+	if meetsBlockTarget {
+		block := *shareJob.block
+		block.Header = header.ToImmutable()
+		isBlock = c.server.submitBlock(&block, workerName)
+	}
+
+	log.Debugf("Accepted share from stratum worker %s for job %s (nonce %x)", workerName, jobID, nonce)
+	c.server.stats.recordShare(workerName, shareAccepted, isBlock)
+	return true, nil
+}
+
+// parseNonce parses a submitted nonce. Clients may either submit the whole
+// nonce, which must begin with their extra nonce, or only the part of the
+// nonce that follows their extra nonce
+func (c *client) parseNonce(nonceString string) (uint64, error) {
+	nonceString = strings.TrimPrefix(nonceString, "0x")
+
+	switch len(nonceString) {
+	case nonceSize * 2:
+		nonce, err := strconv.ParseUint(nonceString, 16, 64)
+		if err != nil {
+			return 0, errors.Errorf("invalid nonce %s", nonceString)
+		}
+		if uint16(nonce>>((nonceSize-extraNonceSize)*8)) != c.extraNonce {
+			return 0, errors.Errorf("nonce %s is outside of the assigned nonce range", nonceString)
+		}
+		return nonce, nil
+	case (nonceSize - extraNonceSize) * 2:
+		extraNonce2, err := strconv.ParseUint(nonceString, 16, 64)
+		if err != nil {
+			return 0, errors.Errorf("invalid nonce %s", nonceString)
+		}
+		return uint64(c.extraNonce)<<((nonceSize-extraNonceSize)*8) | extraNonce2, nil
+	default:
+		return 0, errors.Errorf("invalid nonce length %d", len(nonceString))
+	}
+}
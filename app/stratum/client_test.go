@@ -0,0 +1,233 @@
+package stratum
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"net"
+	"testing"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/model/pow"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/blockheader"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/util/difficulty"
+)
+
+type fakeBlockHandler struct {
+	addedBlocks []*externalapi.DomainBlock
+}
+
+func (f *fakeBlockHandler) AddBlock(block *externalapi.DomainBlock) error {
+	f.addedBlocks = append(f.addedBlocks, block)
+	return nil
+}
+
+func (f *fakeBlockHandler) IsIBDRunning() bool {
+	return false
+}
+
+func (f *fakeBlockHandler) ShouldMine() (bool, error) {
+	return true, nil
+}
+
+func newTestClient(t *testing.T, blockTarget *big.Int) (*client, *fakeBlockHandler, *job) {
+	blockHandler := &fakeBlockHandler{}
+	server := &Server{
+		powMax:            dagconfig.DevnetParams.PowMax,
+		initialDifficulty: 1,
+		initialTarget:     dagconfig.DevnetParams.PowMax,
+		blockHandler:      blockHandler,
+		stats:             newWorkerStatsStore(),
+		clients:           make(map[*client]struct{}),
+		extraNoncesInUse:  make(map[uint16]struct{}),
+	}
+	server.jobManager = newJobManager(nil, nil, nil, nil, false, server.broadcastJob)
+
+	header := blockheader.NewImmutableBlockHeader(0, []*externalapi.DomainHash{}, &externalapi.DomainHash{},
+		&externalapi.DomainHash{}, &externalapi.DomainHash{}, 1234, difficulty.BigToCompact(blockTarget), 0)
+	shareJob := server.jobManager.addJob(&externalapi.DomainBlock{Header: header}, nil)
+
+	connection, otherEnd := net.Pipe()
+	t.Cleanup(func() {
+		connection.Close()
+		otherEnd.Close()
+	})
+	c := newClient(server, connection, 0x1234)
+	c.isSubscribed = true
+	c.workers["worker"] = struct{}{}
+	return c, blockHandler, shareJob
+}
+
+func mustDifficultyToTarget(t *testing.T, powMax *big.Int, difficulty float64) *big.Int {
+	target, err := difficultyToTarget(powMax, difficulty)
+	if err != nil {
+		t.Fatalf("difficultyToTarget: %s", err)
+	}
+	return target
+}
+
+// findNonce returns the first nonce in the client's nonce range whose proof
+// of work satisfies the given target
+func findNonce(t *testing.T, c *client, shareJob *job, target *big.Int) uint64 {
+	base := uint64(c.extraNonce) << ((nonceSize - extraNonceSize) * 8)
+	for i := uint64(0); i < 1000; i++ {
+		if pow.CheckProofOfWorkWithTarget(shareJob.headerWithNonce(base+i), target) {
+			return base + i
+		}
+	}
+	t.Fatalf("findNonce: no nonce satisfies the target")
+	return 0
+}
+
+func TestValidateShare(t *testing.T) {
+	impossibleBlockTarget := big.NewInt(1)
+	c, blockHandler, shareJob := newTestClient(t, impossibleBlockTarget)
+	shareTarget := mustDifficultyToTarget(t, c.server.powMax, 4)
+	nonce := findNonce(t, c, shareJob, shareTarget)
+	nonceString := big.NewInt(0).SetUint64(nonce).Text(16)
+
+	_, stratumErr := c.validateShare("worker", "unknown", nonceString, shareTarget)
+	if stratumErr == nil || stratumErr.code != errorCodeJobNotFound {
+		t.Fatalf("TestValidateShare: expected a job not found error but got %v", stratumErr)
+	}
+
+	_, stratumErr = c.validateShare("worker", shareJob.id, nonceString, shareTarget)
+	if stratumErr != nil {
+		t.Fatalf("TestValidateShare: expected the share to be accepted but got %s", stratumErr)
+	}
+
+	_, stratumErr = c.validateShare("worker", shareJob.id, nonceString, shareTarget)
+	if stratumErr == nil || stratumErr.code != errorCodeDuplicateShare {
+		t.Fatalf("TestValidateShare: expected a duplicate share error but got %v", stratumErr)
+	}
+
+	// Nonces of invalid shares aren't recorded, so submitting one
+	// again isn't considered a duplicate
+	impossibleShareTarget := big.NewInt(1)
+	for i := 0; i < 2; i++ {
+		_, stratumErr = c.validateShare("worker", shareJob.id, "000000000001", impossibleShareTarget)
+		if stratumErr == nil || stratumErr.code != errorCodeLowDifficultyShare {
+			t.Fatalf("TestValidateShare: expected a low difficulty share error but got %v", stratumErr)
+		}
+	}
+
+	if len(blockHandler.addedBlocks) != 0 {
+		t.Fatalf("TestValidateShare: expected no blocks to be submitted but got %d", len(blockHandler.addedBlocks))
+	}
+
+	stats := c.server.WorkerStats()
+	if len(stats) != 1 {
+		t.Fatalf("TestValidateShare: expected stats for a single worker but got %d", len(stats))
+	}
+	if stats[0].AcceptedShares != 1 || stats[0].StaleShares != 1 || stats[0].DuplicateShares != 1 ||
+		stats[0].LowDifficultyShares != 2 || stats[0].RejectedShares() != 4 {
+		t.Fatalf("TestValidateShare: unexpected worker stats: %+v", stats[0])
+	}
+}
+
+func TestValidateShareFindsBlock(t *testing.T) {
+	blockTarget := mustDifficultyToTarget(t, dagconfig.DevnetParams.PowMax, 2)
+	c, blockHandler, shareJob := newTestClient(t, blockTarget)
+	nonce := findNonce(t, c, shareJob, shareJob.blockTarget)
+
+	// The share difficulty is higher than the block difficulty, but a
+	// solution for the block is still a valid share
+	impossibleShareTarget := big.NewInt(1)
+	_, stratumErr := c.validateShare("worker", shareJob.id, big.NewInt(0).SetUint64(nonce).Text(16), impossibleShareTarget)
+	if stratumErr != nil {
+		t.Fatalf("TestValidateShareFindsBlock: expected the share to be accepted but got %s", stratumErr)
+	}
+	if len(blockHandler.addedBlocks) != 1 {
+		t.Fatalf("TestValidateShareFindsBlock: expected a single block to be submitted but got %d",
+			len(blockHandler.addedBlocks))
+	}
+	if blockHandler.addedBlocks[0].Header.Nonce() != nonce {
+		t.Fatalf("TestValidateShareFindsBlock: expected the submitted block to have nonce %x but got %x",
+			nonce, blockHandler.addedBlocks[0].Header.Nonce())
+	}
+	if c.server.WorkerStats()[0].BlocksFound != 1 {
+		t.Fatalf("TestValidateShareFindsBlock: expected a single block found but got %d",
+			c.server.WorkerStats()[0].BlocksFound)
+	}
+}
+
+func TestParseNonce(t *testing.T) {
+	c := &client{extraNonce: 0x1234}
+	tests := []struct {
+		nonceString   string
+		expectedNonce uint64
+		expectsError  bool
+	}{
+		{nonceString: "1234000000000001", expectedNonce: 0x1234000000000001},
+		{nonceString: "0x1234abcdef012345", expectedNonce: 0x1234abcdef012345},
+		{nonceString: "abcdef012345", expectedNonce: 0x1234abcdef012345},
+		{nonceString: "4321000000000001", expectsError: true},
+		{nonceString: "1234", expectsError: true},
+		{nonceString: "zzzzzzzzzzzz", expectsError: true},
+	}
+
+	for _, test := range tests {
+		nonce, err := c.parseNonce(test.nonceString)
+		if test.expectsError {
+			if err == nil {
+				t.Errorf("TestParseNonce: expected an error for %s", test.nonceString)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("TestParseNonce: unexpected error for %s: %s", test.nonceString, err)
+			continue
+		}
+		if nonce != test.expectedNonce {
+			t.Errorf("TestParseNonce: expected %x for %s but got %x", test.expectedNonce, test.nonceString, nonce)
+		}
+	}
+}
+
+func TestDifficultyToTarget(t *testing.T) {
+	powMax := dagconfig.DevnetParams.PowMax
+	if mustDifficultyToTarget(t, powMax, 1).Cmp(powMax) != 0 {
+		t.Fatalf("TestDifficultyToTarget: expected difficulty 1 to map to powMax")
+	}
+	if mustDifficultyToTarget(t, powMax, 0.5).Cmp(powMax) != 0 {
+		t.Fatalf("TestDifficultyToTarget: expected targets to be capped at powMax")
+	}
+	expected := new(big.Int).Rsh(powMax, 10)
+	target := mustDifficultyToTarget(t, powMax, 1024)
+	difference := new(big.Int).Sub(target, expected)
+	if difference.CmpAbs(big.NewInt(1)) > 0 {
+		t.Fatalf("TestDifficultyToTarget: expected difficulty 1024 to map to %s but got %s", expected, target)
+	}
+	for _, invalidDifficulty := range []float64{0, -1, math.NaN(), math.Inf(1), math.Inf(-1)} {
+		_, err := difficultyToTarget(powMax, invalidDifficulty)
+		if err == nil {
+			t.Fatalf("TestDifficultyToTarget: expected an error for difficulty %f", invalidDifficulty)
+		}
+	}
+}
+
+func TestAuthorizeInvalidDifficulty(t *testing.T) {
+	c, _, _ := newTestClient(t, big.NewInt(1))
+	for _, password := range []string{"d=NaN", "d=nan", "d=Inf", "d=-Inf", "d=0", "d=-1", "d=x"} {
+		passwordJSON, err := json.Marshal(password)
+		if err != nil {
+			t.Fatalf("json.Marshal: %s", err)
+		}
+		_, stratumErr := c.handleAuthorize([]json.RawMessage{json.RawMessage(`"worker"`), passwordJSON})
+		if stratumErr == nil {
+			t.Fatalf("TestAuthorizeInvalidDifficulty: expected password %s to be rejected", password)
+		}
+		if c.difficulty != 1 || c.target.Cmp(c.server.initialTarget) != 0 {
+			t.Fatalf("TestAuthorizeInvalidDifficulty: password %s changed the difficulty to %f", password, c.difficulty)
+		}
+	}
+
+	_, stratumErr := c.handleAuthorize([]json.RawMessage{json.RawMessage(`"worker"`), json.RawMessage(`"d=4"`)})
+	if stratumErr != nil {
+		t.Fatalf("TestAuthorizeInvalidDifficulty: expected password d=4 to be accepted, but got: %s", stratumErr.message)
+	}
+	if c.difficulty != 4 {
+		t.Fatalf("TestAuthorizeInvalidDifficulty: expected difficulty 4, but got %f", c.difficulty)
+	}
+}
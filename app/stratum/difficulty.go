@@ -0,0 +1,33 @@
+package stratum
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// isValidDifficulty returns whether difficulty can be used as a share difficulty
+func isValidDifficulty(difficulty float64) bool {
+	return !math.IsNaN(difficulty) && !math.IsInf(difficulty, 0) && difficulty > 0
+}
+
+// difficultyToTarget converts a share difficulty into the target a share's
+// proof of work must not exceed. Difficulty is measured the same way block
+// difficulty is reported over RPC: relative to powMax, the easiest target
+// allowed by the network
+func difficultyToTarget(powMax *big.Int, difficulty float64) (*big.Int, error) {
+	if !isValidDifficulty(difficulty) {
+		return nil, errors.Errorf("invalid difficulty %f", difficulty)
+	}
+	powMaxFloat := new(big.Float).SetInt(powMax)
+	targetFloat := powMaxFloat.Quo(powMaxFloat, big.NewFloat(difficulty))
+	target, _ := targetFloat.Int(nil)
+	if target.Cmp(powMax) > 0 {
+		return new(big.Int).Set(powMax), nil
+	}
+	if target.Sign() <= 0 {
+		return big.NewInt(1), nil
+	}
+	return target, nil
+}
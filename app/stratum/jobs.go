@@ -0,0 +1,231 @@
+package stratum
+
+import (
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/util/difficulty"
+)
+
+const (
+	// maxJobs is the amount of recent jobs for which shares are still accepted
+	maxJobs = 16
+
+	// jobRefreshInterval is the interval after which a new job is handed out
+	// even if the virtual parents did not change, so that new mempool
+	// transactions make it into mined blocks
+	jobRefreshInterval = 30 * time.Second
+
+	// maxSubmittedNoncesPerJob bounds the amount of accepted shares that are
+	// remembered per job in order to detect duplicates. Shares submitted for
+	// a job after it is reached are rejected as stale
+	maxSubmittedNoncesPerJob = 1 << 16
+)
+
+// job is a unit of work handed out to stratum workers. It is derived from
+// a block template, and workers are expected to search for a nonce that
+// satisfies their share target
+type job struct {
+	id          string
+	block       *externalapi.DomainBlock
+	prePoWHash  *externalapi.DomainHash
+	timestamp   int64
+	blockTarget *big.Int
+
+	lock            sync.Mutex
+	submittedNonces map[uint64]struct{}
+}
+
+func newJob(id string, block *externalapi.DomainBlock) *job {
+	return &job{
+		id:              id,
+		block:           block,
+		prePoWHash:      prePoWHash(block.Header),
+		timestamp:       block.Header.TimeInMilliseconds(),
+		blockTarget:     difficulty.CompactToBig(block.Header.Bits()),
+		submittedNonces: make(map[uint64]struct{}),
+	}
+}
+
+// prePoWHash returns the hash of the given header with its timestamp and
+// nonce zeroed out, which is the part of the proof of work input that
+// doesn't change while mining
+func prePoWHash(header externalapi.BlockHeader) *externalapi.DomainHash {
+	mutableHeader := header.ToMutable()
+	mutableHeader.SetTimeInMilliseconds(0)
+	mutableHeader.SetNonce(0)
+	return consensushashing.HeaderHash(mutableHeader)
+}
+
+// notifyParams returns the parameters of the mining.notify message for this job:
+// [job id, pre-PoW hash (hex), timestamp (milliseconds), clean jobs]
+func (j *job) notifyParams(cleanJobs bool) []interface{} {
+	return []interface{}{j.id, j.prePoWHash.String(), j.timestamp, cleanJobs}
+}
+
+// nonceSubmissionResult is the result of recording a submitted nonce
+type nonceSubmissionResult int
+
+const (
+	nonceSubmissionNew nonceSubmissionResult = iota
+	nonceSubmissionDuplicate
+	nonceSubmissionJobFull
+)
+
+// markNonceSubmitted records that the given nonce was submitted for this job.
+// Only nonces of valid shares should be recorded, so that clients can't grow
+// the set of submitted nonces with junk
+func (j *job) markNonceSubmitted(nonce uint64) nonceSubmissionResult {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if _, ok := j.submittedNonces[nonce]; ok {
+		return nonceSubmissionDuplicate
+	}
+	if len(j.submittedNonces) >= maxSubmittedNoncesPerJob {
+		return nonceSubmissionJobFull
+	}
+	j.submittedNonces[nonce] = struct{}{}
+	return nonceSubmissionNew
+}
+
+func (j *job) headerWithNonce(nonce uint64) externalapi.MutableBlockHeader {
+	header := j.block.Header.ToMutable()
+	header.SetNonce(nonce)
+	return header
+}
+
+// templateSource provides the block templates jobs are derived from
+type templateSource interface {
+	GetBlockTemplate(coinbaseData *externalapi.DomainCoinbaseData) (*externalapi.DomainBlock, error)
+}
+
+// jobManager builds jobs out of block templates and keeps the most recent
+// ones so that shares submitted shortly after a new job was issued are
+// still accepted
+type jobManager struct {
+	consensus      externalapi.Consensus
+	templateSource templateSource
+	shouldMine     func() (bool, error)
+	coinbaseData   *externalapi.DomainCoinbaseData
+	allowUnsynced  bool
+	onNewJob       func(job *job, cleanJobs bool)
+
+	lock             sync.RWMutex
+	jobs             map[string]*job
+	jobIDs           []string
+	nextJobID        uint64
+	lastParentHashes []*externalapi.DomainHash
+	lastJobTime      time.Time
+	isSynced         bool
+}
+
+func newJobManager(consensus externalapi.Consensus, templateSource templateSource, shouldMine func() (bool, error),
+	coinbaseData *externalapi.DomainCoinbaseData, allowUnsynced bool, onNewJob func(job *job, cleanJobs bool)) *jobManager {
+
+	return &jobManager{
+		consensus:      consensus,
+		templateSource: templateSource,
+		shouldMine:     shouldMine,
+		coinbaseData:   coinbaseData,
+		allowUnsynced:  allowUnsynced,
+		onNewJob:       onNewJob,
+		jobs:           make(map[string]*job),
+		isSynced:       true,
+	}
+}
+
+// refresh issues a new job if the virtual parents changed since the last
+// job was issued, or if the last job is older than jobRefreshInterval
+func (m *jobManager) refresh() error {
+	virtualInfo, err := m.consensus.GetVirtualInfo()
+	if err != nil {
+		return err
+	}
+
+	m.lock.RLock()
+	parentsChanged := !externalapi.HashesEqual(virtualInfo.ParentHashes, m.lastParentHashes)
+	isJobOld := time.Since(m.lastJobTime) >= jobRefreshInterval
+	m.lock.RUnlock()
+	if !parentsChanged && !isJobOld {
+		return nil
+	}
+
+	isSynced, err := m.shouldMine()
+	if err != nil {
+		return err
+	}
+	m.logSyncStateChange(isSynced)
+	if !isSynced && !m.allowUnsynced {
+		return nil
+	}
+
+	template, err := m.templateSource.GetBlockTemplate(m.coinbaseData)
+	if err != nil {
+		return err
+	}
+
+	newJob := m.addJob(template, virtualInfo.ParentHashes)
+	log.Debugf("Issuing stratum job %s with parents %s", newJob.id, virtualInfo.ParentHashes)
+	m.onNewJob(newJob, parentsChanged)
+	return nil
+}
+
+func (m *jobManager) logSyncStateChange(isSynced bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if isSynced == m.isSynced {
+		return
+	}
+	m.isSynced = isSynced
+	if isSynced {
+		log.Infof("Kaspad is synced. Resuming to issue stratum jobs")
+		return
+	}
+	if m.allowUnsynced {
+		log.Warnf("Kaspad is not synced. Issuing stratum jobs anyway")
+		return
+	}
+	log.Warnf("Kaspad is not synced. Stratum jobs will not be issued until it is")
+}
+
+func (m *jobManager) addJob(template *externalapi.DomainBlock, parentHashes []*externalapi.DomainHash) *job {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	id := strconv.FormatUint(m.nextJobID, 16)
+	m.nextJobID++
+	newJob := newJob(id, template)
+
+	m.jobs[id] = newJob
+	m.jobIDs = append(m.jobIDs, id)
+	if len(m.jobIDs) > maxJobs {
+		delete(m.jobs, m.jobIDs[0])
+		m.jobIDs = m.jobIDs[1:]
+	}
+	m.lastParentHashes = parentHashes
+	m.lastJobTime = time.Now()
+	return newJob
+}
+
+// get returns the job with the given ID, or nil if it's unknown or too old
+func (m *jobManager) get(id string) *job {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return m.jobs[id]
+}
+
+// latest returns the most recently issued job, or nil if none was issued yet
+func (m *jobManager) latest() *job {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if len(m.jobIDs) == 0 {
+		return nil
+	}
+	return m.jobs[m.jobIDs[len(m.jobIDs)-1]]
+}
@@ -0,0 +1,65 @@
+package stratum
+
+import (
+	"encoding/json"
+)
+
+const (
+	methodSubscribe         = "mining.subscribe"
+	methodAuthorize         = "mining.authorize"
+	methodSubmit            = "mining.submit"
+	methodSuggestDifficulty = "mining.suggest_difficulty"
+	methodNotify            = "mining.notify"
+	methodSetDifficulty     = "mining.set_difficulty"
+)
+
+// Stratum error codes, as used by most stratum implementations
+const (
+	errorCodeOther              = 20
+	errorCodeJobNotFound        = 21
+	errorCodeDuplicateShare     = 22
+	errorCodeLowDifficultyShare = 23
+	errorCodeUnauthorizedWorker = 24
+	errorCodeNotSubscribed      = 25
+)
+
+// request is a stratum request sent by a miner
+type request struct {
+	ID     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// response is a stratum response to a miner's request
+type response struct {
+	ID     json.RawMessage `json:"id"`
+	Result interface{}     `json:"result"`
+	Error  *stratumError   `json:"error"`
+}
+
+// notification is a stratum message initiated by the server
+type notification struct {
+	ID     interface{}   `json:"id"`
+	Method string        `json:"method"`
+	Params []interface{} `json:"params"`
+}
+
+// stratumError is an error returned to a miner. It is serialized as
+// the conventional [code, message, traceback] triplet
+type stratumError struct {
+	code    int
+	message string
+}
+
+func newStratumError(code int, message string) *stratumError {
+	return &stratumError{code: code, message: message}
+}
+
+func (e *stratumError) Error() string {
+	return e.message
+}
+
+// MarshalJSON implements json.Marshaler
+func (e *stratumError) MarshalJSON() ([]byte, error) {
+	return json.Marshal([]interface{}{e.code, e.message, nil})
+}
@@ -0,0 +1,262 @@
+package stratum
+
+import (
+	"math/big"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/protocol/protocolerrors"
+	"github.com/kaspanet/kaspad/domain"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/ruleerrors"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/pkg/errors"
+)
+
+const (
+	templatePollInterval = 500 * time.Millisecond
+	statsLogInterval     = 10 * time.Minute
+	maxClients           = 1 << (extraNonceSize * 8)
+)
+
+// BlockHandler is the interface the stratum server uses to submit the
+// blocks found by its workers. It is implemented by protocol.Manager
+type BlockHandler interface {
+	AddBlock(block *externalapi.DomainBlock) error
+	IsIBDRunning() bool
+	ShouldMine() (bool, error)
+}
+
+// Server is a stratum mining server. It hands out jobs derived from the
+// node's block templates, validates the shares submitted by workers against
+// their share difficulty, and submits shares that satisfy the block
+// difficulty as blocks
+type Server struct {
+	listenAddress     string
+	powMax            *big.Int
+	initialDifficulty float64
+	initialTarget     *big.Int
+	blockHandler      BlockHandler
+	jobManager        *jobManager
+	stats             *workerStatsStore
+
+	listener net.Listener
+	quit     chan struct{}
+
+	clientsLock        sync.Mutex
+	clients            map[*client]struct{}
+	extraNoncesInUse   map[uint16]struct{}
+	nextExtraNonce     uint16
+	isShuttingDownLock sync.Mutex
+	isShuttingDown     bool
+}
+
+// NewServer creates a new stratum Server
+func NewServer(cfg *config.Config, domain domain.Domain, blockHandler BlockHandler) (*Server, error) {
+	scriptPublicKey, err := txscript.PayToAddrScript(cfg.StratumPayAddress)
+	if err != nil {
+		return nil, err
+	}
+	coinbaseData := &externalapi.DomainCoinbaseData{ScriptPublicKey: scriptPublicKey}
+	initialTarget, err := difficultyToTarget(cfg.ActiveNetParams.PowMax, cfg.StratumDifficulty)
+	if err != nil {
+		return nil, err
+	}
+
+	server := &Server{
+		listenAddress:     cfg.StratumListen,
+		powMax:            cfg.ActiveNetParams.PowMax,
+		initialDifficulty: cfg.StratumDifficulty,
+		initialTarget:     initialTarget,
+		blockHandler:      blockHandler,
+		stats:             newWorkerStatsStore(),
+		quit:              make(chan struct{}),
+		clients:           make(map[*client]struct{}),
+		extraNoncesInUse:  make(map[uint16]struct{}),
+	}
+	server.jobManager = newJobManager(domain.Consensus(), domain.MiningManager(), blockHandler.ShouldMine,
+		coinbaseData, cfg.StratumAllowUnsynced, server.broadcastJob)
+
+	return server, nil
+}
+
+// Start starts listening for stratum connections and issuing jobs
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.listenAddress)
+	if err != nil {
+		return errors.Wrapf(err, "error listening on %s", s.listenAddress)
+	}
+	s.listener = listener
+	log.Infof("Stratum server listening on %s", listener.Addr())
+
+	spawn("stratum.Server.acceptLoop", s.acceptLoop)
+	spawn("stratum.Server.jobsLoop", s.jobsLoop)
+	spawn("stratum.Server.statsLoop", s.statsLoop)
+	return nil
+}
+
+// Stop stops the stratum server and disconnects all its clients
+func (s *Server) Stop() error {
+	s.isShuttingDownLock.Lock()
+	s.isShuttingDown = true
+	s.isShuttingDownLock.Unlock()
+
+	close(s.quit)
+	err := s.listener.Close()
+
+	s.clientsLock.Lock()
+	clients := make([]*client, 0, len(s.clients))
+	for c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.clientsLock.Unlock()
+	for _, c := range clients {
+		c.close()
+	}
+
+	s.logStats()
+	return err
+}
+
+// WorkerStats returns the share statistics of all the workers that
+// submitted shares since the server started
+func (s *Server) WorkerStats() []*WorkerStats {
+	return s.stats.all()
+}
+
+func (s *Server) acceptLoop() {
+	for {
+		connection, err := s.listener.Accept()
+		if err != nil {
+			s.isShuttingDownLock.Lock()
+			isShuttingDown := s.isShuttingDown
+			s.isShuttingDownLock.Unlock()
+			if isShuttingDown {
+				return
+			}
+			log.Errorf("Error accepting stratum connection: %s", err)
+			continue
+		}
+
+		c, err := s.addClient(connection)
+		if err != nil {
+			log.Warnf("Rejecting stratum connection from %s: %s", connection.RemoteAddr(), err)
+			_ = connection.Close()
+			continue
+		}
+		log.Debugf("Stratum client connected from %s", c)
+		c.start()
+	}
+}
+
+func (s *Server) addClient(connection net.Conn) (*client, error) {
+	s.clientsLock.Lock()
+	defer s.clientsLock.Unlock()
+
+	if len(s.clients) >= maxClients {
+		return nil, errors.Errorf("the maximum of %d stratum clients has been reached", maxClients)
+	}
+	for {
+		_, inUse := s.extraNoncesInUse[s.nextExtraNonce]
+		if !inUse {
+			break
+		}
+		s.nextExtraNonce++
+	}
+	extraNonce := s.nextExtraNonce
+	s.nextExtraNonce++
+
+	c := newClient(s, connection, extraNonce)
+	s.clients[c] = struct{}{}
+	s.extraNoncesInUse[extraNonce] = struct{}{}
+	return c, nil
+}
+
+func (s *Server) removeClient(c *client) {
+	s.clientsLock.Lock()
+	defer s.clientsLock.Unlock()
+
+	delete(s.clients, c)
+	delete(s.extraNoncesInUse, c.extraNonce)
+}
+
+func (s *Server) broadcastJob(job *job, cleanJobs bool) {
+	s.clientsLock.Lock()
+	clients := make([]*client, 0, len(s.clients))
+	for c := range s.clients {
+		clients = append(clients, c)
+	}
+	s.clientsLock.Unlock()
+
+	for _, c := range clients {
+		c.notifyJob(job, cleanJobs)
+	}
+}
+
+func (s *Server) jobsLoop() {
+	ticker := time.NewTicker(templatePollInterval)
+	defer ticker.Stop()
+	for {
+		err := s.jobManager.refresh()
+		if err != nil {
+			log.Errorf("Error issuing a stratum job: %s", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+func (s *Server) statsLoop() {
+	ticker := time.NewTicker(statsLogInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.logStats()
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+func (s *Server) logStats() {
+	for _, workerStats := range s.stats.all() {
+		log.Infof("Stratum worker %s: %d accepted shares, %d rejected shares (%d stale, %d duplicate, "+
+			"%d low difficulty, %d invalid), %d blocks found",
+			workerStats.Name, workerStats.AcceptedShares, workerStats.RejectedShares(), workerStats.StaleShares,
+			workerStats.DuplicateShares, workerStats.LowDifficultyShares, workerStats.InvalidShares,
+			workerStats.BlocksFound)
+	}
+}
+
+// submitBlock submits a block found by a worker through the normal block
+// path. It returns whether the block was accepted
+func (s *Server) submitBlock(block *externalapi.DomainBlock, workerName string) bool {
+	blockHash := consensushashing.BlockHash(block)
+	if s.blockHandler.IsIBDRunning() {
+		log.Warnf("Block %s found by stratum worker %s was not submitted because IBD is running",
+			blockHash, workerName)
+		return false
+	}
+
+	err := s.blockHandler.AddBlock(block)
+	if err != nil {
+		isProtocolOrRuleError := errors.As(err, &ruleerrors.RuleError{}) || errors.As(err, &protocolerrors.ProtocolError{})
+		if isProtocolOrRuleError {
+			log.Warnf("Block %s found by stratum worker %s was rejected: %s", blockHash, workerName, err)
+		} else {
+			log.Errorf("Error submitting block %s found by stratum worker %s: %+v", blockHash, workerName, err)
+		}
+		return false
+	}
+
+	log.Infof("Accepted block %s found by stratum worker %s", blockHash, workerName)
+	return true
+}
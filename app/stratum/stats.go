@@ -0,0 +1,89 @@
+package stratum
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// WorkerStats holds the share statistics of a single stratum worker
+type WorkerStats struct {
+	Name                string
+	AcceptedShares      uint64
+	StaleShares         uint64
+	DuplicateShares     uint64
+	LowDifficultyShares uint64
+	InvalidShares       uint64
+	BlocksFound         uint64
+	LastShareTime       time.Time
+}
+
+// RejectedShares returns the total amount of shares rejected for any reason
+func (ws *WorkerStats) RejectedShares() uint64 {
+	return ws.StaleShares + ws.DuplicateShares + ws.LowDifficultyShares + ws.InvalidShares
+}
+
+type shareResult int
+
+const (
+	shareAccepted shareResult = iota
+	shareStale
+	shareDuplicate
+	shareLowDifficulty
+	shareInvalid
+)
+
+// workerStatsStore keeps the statistics of every worker that
+// authorized since the server started
+type workerStatsStore struct {
+	lock  sync.Mutex
+	stats map[string]*WorkerStats
+}
+
+func newWorkerStatsStore() *workerStatsStore {
+	return &workerStatsStore{stats: make(map[string]*WorkerStats)}
+}
+
+func (s *workerStatsStore) recordShare(workerName string, result shareResult, isBlock bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	stats, ok := s.stats[workerName]
+	if !ok {
+		stats = &WorkerStats{Name: workerName}
+		s.stats[workerName] = stats
+	}
+
+	stats.LastShareTime = time.Now()
+	switch result {
+	case shareAccepted:
+		stats.AcceptedShares++
+	case shareStale:
+		stats.StaleShares++
+	case shareDuplicate:
+		stats.DuplicateShares++
+	case shareLowDifficulty:
+		stats.LowDifficultyShares++
+	case shareInvalid:
+		stats.InvalidShares++
+	}
+	if isBlock {
+		stats.BlocksFound++
+	}
+}
+
+// all returns a copy of the statistics of all workers, sorted by name
+func (s *workerStatsStore) all() []*WorkerStats {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	allStats := make([]*WorkerStats, 0, len(s.stats))
+	for _, stats := range s.stats {
+		statsCopy := *stats
+		allStats = append(allStats, &statsCopy)
+	}
+	sort.Slice(allStats, func(i, j int) bool {
+		return allStats[i].Name < allStats[j].Name
+	})
+	return allStats
+}
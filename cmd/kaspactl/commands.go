@@ -24,15 +24,24 @@ var commandTypes = []reflect.Type{
 	reflect.TypeOf(protowire.KaspadMessage_GetVirtualSelectedParentBlueScoreRequest{}),
 	reflect.TypeOf(protowire.KaspadMessage_GetVirtualSelectedParentChainFromBlockRequest{}),
 	reflect.TypeOf(protowire.KaspadMessage_ResolveFinalityConflictRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetDAGSubgraphRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetBlockAcceptanceStatusRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetTransactionConfirmationsRequest{}),
 
 	reflect.TypeOf(protowire.KaspadMessage_GetBlockTemplateRequest{}),
 	reflect.TypeOf(protowire.KaspadMessage_SubmitBlockRequest{}),
 
 	reflect.TypeOf(protowire.KaspadMessage_GetMempoolEntryRequest{}),
 	reflect.TypeOf(protowire.KaspadMessage_GetMempoolEntriesRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetMempoolAncestorsRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetMempoolDescendantsRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetMempoolStatsRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_DumpMempoolRequest{}),
 	reflect.TypeOf(protowire.KaspadMessage_SubmitTransactionRequest{}),
 
 	reflect.TypeOf(protowire.KaspadMessage_GetUtxosByAddressesRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetBalanceByAddressRequest{}),
+	reflect.TypeOf(protowire.KaspadMessage_GetBalancesByAddressesRequest{}),
 
 	reflect.TypeOf(protowire.KaspadMessage_BanRequest{}),
 	reflect.TypeOf(protowire.KaspadMessage_UnbanRequest{}),
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"strings"
+)
+
+const keyTab = '\t'
+
+// completer provides tab completion of command names and request
+// fields for the interactive shell
+type completer struct {
+	commands     map[string]*interactiveCommand
+	commandNames []string
+}
+
+func newCompleter(commands map[string]*interactiveCommand) *completer {
+	return &completer{
+		commands:     commands,
+		commandNames: sortedCommandNames(commands),
+	}
+}
+
+// autoComplete implements the terminal.Terminal AutoCompleteCallback
+func (c *completer) autoComplete(line string, pos int, key rune) (newLine string, newPos int, ok bool) {
+	if key != keyTab {
+		return "", 0, false
+	}
+
+	beforeCursor, afterCursor := line[:pos], line[pos:]
+	words := strings.Split(beforeCursor, " ")
+	previousWords, currentWord := words[:len(words)-1], words[len(words)-1]
+
+	candidates, suffix := c.candidates(previousWords)
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, currentWord) {
+			matches = append(matches, candidate)
+		}
+	}
+	if len(matches) == 0 {
+		return "", 0, false
+	}
+
+	completion := commonPrefix(matches)
+	if len(matches) == 1 && !strings.HasSuffix(completion, "=") {
+		completion += suffix
+	}
+	if completion == currentWord {
+		return "", 0, false
+	}
+
+	newBeforeCursor := beforeCursor[:len(beforeCursor)-len(currentWord)] + completion
+	return newBeforeCursor + afterCursor, len(newBeforeCursor), true
+}
+
+// candidates returns the possible completions of the word following
+// previousWords, alongside the suffix to append once a single candidate
+// is left
+func (c *completer) candidates(previousWords []string) (candidates []string, suffix string) {
+	words := nonEmptyWords(previousWords)
+	if len(words) == 0 {
+		return append(builtinCommandNames(), c.commandNames...), " "
+	}
+
+	switch words[0] {
+	case helpCommandName:
+		if len(words) == 1 {
+			return c.commandNames, ""
+		}
+		return nil, ""
+	case subscribeCommandName:
+		if len(words) == 1 {
+			return c.notifyCommandNames(), " "
+		}
+		return c.fieldCandidates(words[1], words[2:]), ""
+	default:
+		return c.fieldCandidates(words[0], words[1:]), ""
+	}
+}
+
+func (c *completer) notifyCommandNames() []string {
+	var names []string
+	for _, name := range c.commandNames {
+		if c.commands[name].isNotify() {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// fieldCandidates returns `field=` completions for all the fields of the
+// given command that were not yet set in arguments
+func (c *completer) fieldCandidates(commandName string, arguments []string) []string {
+	command, ok := c.commands[commandName]
+	if !ok {
+		return nil
+	}
+
+	alreadySet := make(map[string]struct{})
+	for _, argument := range arguments {
+		separatorIndex := strings.Index(argument, "=")
+		if separatorIndex > 0 {
+			alreadySet[argument[:separatorIndex]] = struct{}{}
+		}
+	}
+
+	var candidates []string
+	fields := command.fields()
+	for i := 0; i < fields.Len(); i++ {
+		fieldName := fields.Get(i).JSONName()
+		if _, ok := alreadySet[fieldName]; ok {
+			continue
+		}
+		candidates = append(candidates, fieldName+"=")
+	}
+	return candidates
+}
+
+func nonEmptyWords(words []string) []string {
+	var nonEmpty []string
+	for _, word := range words {
+		if word != "" {
+			nonEmpty = append(nonEmpty, word)
+		}
+	}
+	return nonEmpty
+}
+
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, value := range values[1:] {
+		for !strings.HasPrefix(value, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
@@ -16,6 +16,7 @@ type configFlags struct {
 	Timeout              uint64 `short:"t" long:"timeout" description:"Timeout for the request (in seconds)"`
 	RequestJSON          string `short:"j" long:"json" description:"The request in JSON format"`
 	ListCommands         bool   `short:"l" long:"list-commands" description:"List all commands and exit"`
+	Interactive          bool   `short:"i" long:"interactive" description:"Start an interactive shell with command completion and notification streaming"`
 	CommandAndParameters []string
 	config.NetworkFlags
 }
@@ -26,7 +27,7 @@ func parseConfig() (*configFlags, error) {
 		Timeout:   defaultTimeout,
 	}
 	parser := flags.NewParser(cfg, flags.HelpFlag)
-	parser.Usage = "kaspactl [OPTIONS] [COMMAND] [COMMAND PARAMETERS].\n\nCommand can be supplied only if --json is not used." +
+	parser.Usage = "kaspactl [OPTIONS] [COMMAND] [COMMAND PARAMETERS].\n\nCommand can be supplied only if --json and --interactive are not used." +
 		"\n\nUse `kaspactl --list-commands` to get a list of all commands and their parameters"
 	remainingArgs, err := parser.Parse()
 	if err != nil {
@@ -43,6 +44,12 @@ func parseConfig() (*configFlags, error) {
 	}
 
 	cfg.CommandAndParameters = remainingArgs
+	if cfg.Interactive {
+		if len(cfg.CommandAndParameters) > 0 || cfg.RequestJSON != "" {
+			return nil, errors.New("Neither --json nor a command may be specified together with --interactive")
+		}
+		return cfg, nil
+	}
 	if len(cfg.CommandAndParameters) == 0 && cfg.RequestJSON == "" ||
 		len(cfg.CommandAndParameters) > 0 && cfg.RequestJSON != "" {
 
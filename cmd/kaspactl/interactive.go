@@ -0,0 +1,389 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver/protowire"
+	"github.com/kaspanet/kaspad/infrastructure/network/rpcclient/grpcclient"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh/terminal"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	interactivePrompt = "kaspactl> "
+
+	helpCommandName      = "help"
+	subscribeCommandName = "subscribe"
+	exitCommandName      = "exit"
+)
+
+func builtinCommandNames() []string {
+	return []string{helpCommandName, subscribeCommandName, exitCommandName}
+}
+
+type lineReader interface {
+	readLine() (string, error)
+}
+
+// terminalLineReader reads lines from an interactive terminal, with
+// history and tab completion
+type terminalLineReader struct {
+	fd       int
+	terminal *terminal.Terminal
+}
+
+func (r *terminalLineReader) readLine() (string, error) {
+	// The terminal is only put into raw mode while reading a line, so
+	// that Ctrl-C raises an interrupt signal while streaming notifications
+	oldState, err := terminal.MakeRaw(r.fd)
+	if err != nil {
+		return "", errors.WithStack(err)
+	}
+	defer terminal.Restore(r.fd, oldState)
+
+	return r.terminal.ReadLine()
+}
+
+// scannerLineReader reads lines from a non-interactive input, such as a pipe
+type scannerLineReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *scannerLineReader) readLine() (string, error) {
+	if !r.scanner.Scan() {
+		if r.scanner.Err() != nil {
+			return "", r.scanner.Err()
+		}
+		return "", io.EOF
+	}
+	return r.scanner.Text(), nil
+}
+
+type shell struct {
+	cfg        *configFlags
+	rpcAddress string
+	client     *grpcclient.GRPCClient
+	commands   map[string]*interactiveCommand
+}
+
+func runInteractiveShell(cfg *configFlags, rpcAddress string) error {
+	client, err := grpcclient.Connect(rpcAddress)
+	if err != nil {
+		return errors.Wrapf(err, "error connecting to the RPC server")
+	}
+
+	s := &shell{
+		cfg:        cfg,
+		rpcAddress: rpcAddress,
+		client:     client,
+		commands:   interactiveCommands(),
+	}
+	defer func() {
+		_ = s.client.Disconnect()
+	}()
+
+	reader := s.newLineReader()
+	for {
+		line, err := reader.readLine()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if line == exitCommandName {
+			return nil
+		}
+
+		err = s.execute(line)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", err)
+		}
+	}
+}
+
+func (s *shell) newLineReader() lineReader {
+	stdinFD := int(os.Stdin.Fd())
+	if !terminal.IsTerminal(stdinFD) {
+		return &scannerLineReader{scanner: bufio.NewScanner(os.Stdin)}
+	}
+
+	stdio := struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}
+	term := terminal.NewTerminal(stdio, interactivePrompt)
+	term.AutoCompleteCallback = newCompleter(s.commands).autoComplete
+	fmt.Println("Type `help` to list all commands. Use Tab to complete command and field names")
+	return &terminalLineReader{fd: stdinFD, terminal: term}
+}
+
+func (s *shell) execute(line string) error {
+	if strings.HasPrefix(line, "{") {
+		message := &protowire.KaspadMessage{}
+		err := protojson.Unmarshal([]byte(line), message)
+		if err != nil {
+			return errors.Wrapf(err, "error parsing the request")
+		}
+		return s.postAndPrint(message)
+	}
+
+	words, err := splitCommandLine(line)
+	if err != nil {
+		return err
+	}
+	commandName, args := words[0], words[1:]
+
+	switch commandName {
+	case helpCommandName:
+		return s.help(args)
+	case subscribeCommandName:
+		return s.subscribe(args)
+	}
+
+	command, ok := s.commands[commandName]
+	if !ok {
+		return errors.Errorf("unknown command: %s. Use `help` to list all commands", commandName)
+	}
+	if command.isNotify() {
+		return errors.Errorf("use `%s %s` to stream notifications", subscribeCommandName, commandName)
+	}
+	message, err := buildInteractiveRequest(command, args)
+	if err != nil {
+		return err
+	}
+	return s.postAndPrint(message)
+}
+
+func (s *shell) help(args []string) error {
+	if len(args) > 0 {
+		command, ok := s.commands[args[0]]
+		if !ok {
+			return errors.Errorf("unknown command: %s", args[0])
+		}
+		fmt.Printf("\t%s\n", command.help())
+		return nil
+	}
+
+	fmt.Printf("Built-in commands:\n")
+	fmt.Printf("\t%s [COMMAND]\n", helpCommandName)
+	fmt.Printf("\t%s [NOTIFY COMMAND] [PARAMETERS] -- streams notifications until Ctrl-C is pressed\n", subscribeCommandName)
+	fmt.Printf("\t%s\n", exitCommandName)
+	fmt.Printf("RPC commands (parameters are given either by order or as field=value):\n")
+	for _, name := range sortedCommandNames(s.commands) {
+		fmt.Printf("\t%s\n", s.commands[name].help())
+	}
+	fmt.Printf("A raw request may also be given in JSON format, e.g. {\"getBlockDagInfoRequest\":{}}\n")
+	return nil
+}
+
+func (s *shell) postAndPrint(message *protowire.KaspadMessage) error {
+	response, err := s.post(message)
+	if err != nil {
+		return err
+	}
+	fmt.Println(formatMessage(response))
+	return nil
+}
+
+// post sends the given message and waits for its response. If the RPC
+// server fails to respond in time the shell reconnects, so that a late
+// response isn't mistaken for the response of the next request
+func (s *shell) post(message *protowire.KaspadMessage) (*protowire.KaspadMessage, error) {
+	type postResult struct {
+		response *protowire.KaspadMessage
+		err      error
+	}
+	resultChan := make(chan postResult, 1)
+	client := s.client
+	go func() {
+		response, err := client.Post(message)
+		resultChan <- postResult{response: response, err: err}
+	}()
+
+	timeout := time.Duration(s.cfg.Timeout) * time.Second
+	select {
+	case result := <-resultChan:
+		if result.err != nil {
+			reconnectErr := s.reconnect()
+			if reconnectErr != nil {
+				return nil, reconnectErr
+			}
+			return nil, errors.Wrapf(result.err, "error posting the request to the RPC server")
+		}
+		return result.response, nil
+	case <-time.After(timeout):
+		err := s.reconnect()
+		if err != nil {
+			return nil, err
+		}
+		return nil, errors.Errorf("timeout of %s has been exceeded", timeout)
+	}
+}
+
+func (s *shell) reconnect() error {
+	_ = s.client.Disconnect()
+	client, err := grpcclient.Connect(s.rpcAddress)
+	if err != nil {
+		return errors.Wrapf(err, "error reconnecting to the RPC server")
+	}
+	s.client = client
+	return nil
+}
+
+// subscribe sends a Notify request over a dedicated connection and prints
+// every notification that arrives until the user presses Ctrl-C. The
+// connection is closed afterwards, which cancels the subscription
+func (s *shell) subscribe(args []string) error {
+	if len(args) == 0 {
+		return errors.Errorf("usage: %s [NOTIFY COMMAND] [PARAMETERS]", subscribeCommandName)
+	}
+	command, ok := s.commands[args[0]]
+	if !ok || !command.isNotify() {
+		return errors.Errorf("'%s' is not a notification command", args[0])
+	}
+	message, err := buildInteractiveRequest(command, args[1:])
+	if err != nil {
+		return err
+	}
+
+	client, err := grpcclient.Connect(s.rpcAddress)
+	if err != nil {
+		return errors.Wrapf(err, "error connecting to the RPC server")
+	}
+	defer func() {
+		_ = client.Disconnect()
+	}()
+
+	response, err := client.Post(message)
+	if err != nil {
+		return errors.Wrapf(err, "error posting the request to the RPC server")
+	}
+	fmt.Println(formatMessage(response))
+	err = responseError(response)
+	if err != nil {
+		return err
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	notificationChan := make(chan *protowire.KaspadMessage)
+	errChan := make(chan error, 1)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		for {
+			notification, err := client.ReceiveMessage()
+			if err != nil {
+				errChan <- err
+				return
+			}
+			select {
+			case notificationChan <- notification:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	fmt.Println("Streaming notifications. Press Ctrl-C to stop")
+	for {
+		select {
+		case notification := <-notificationChan:
+			fmt.Println(formatMessage(notification))
+		case err := <-errChan:
+			return errors.Wrapf(err, "error receiving notifications from the RPC server")
+		case <-interrupt:
+			fmt.Println()
+			return nil
+		}
+	}
+}
+
+// responseError returns the RPC error carried by the given response, if any
+func responseError(response *protowire.KaspadMessage) error {
+	reflectedResponse := response.ProtoReflect()
+	payloadField := reflectedResponse.WhichOneof(reflectedResponse.Descriptor().Oneofs().ByName("payload"))
+	if payloadField == nil || payloadField.Kind() != protoreflect.MessageKind {
+		return nil
+	}
+	payload := reflectedResponse.Get(payloadField).Message()
+	errorField := payload.Descriptor().Fields().ByName("error")
+	if errorField == nil || !payload.Has(errorField) {
+		return nil
+	}
+	rpcError := payload.Get(errorField).Message().Interface().(*protowire.RPCError)
+	return errors.Errorf("RPC error: %s", rpcError.Message)
+}
+
+// splitCommandLine splits the given line into whitespace-separated words.
+// Whitespace inside quotes, braces or brackets does not split words, so that
+// JSON values may be given as parameters. Quotes surrounding a whole word are
+// removed
+func splitCommandLine(line string) ([]string, error) {
+	var words []string
+	current := &strings.Builder{}
+	var quote, wordQuote rune
+	depth := 0
+
+	flush := func() {
+		word := current.String()
+		isQuotedWord := wordQuote != 0 && len(word) >= 2 && rune(word[len(word)-1]) == wordQuote
+		if isQuotedWord {
+			word = word[1 : len(word)-1]
+		}
+		if word != "" || isQuotedWord {
+			words = append(words, word)
+		}
+		current.Reset()
+		wordQuote = 0
+	}
+
+	for _, char := range line {
+		switch {
+		case quote != 0:
+			current.WriteRune(char)
+			if char == quote {
+				quote = 0
+			}
+		case char == '"' || char == '\'':
+			if current.Len() == 0 && depth == 0 {
+				wordQuote = char
+			}
+			quote = char
+			current.WriteRune(char)
+		case char == '{' || char == '[':
+			depth++
+			current.WriteRune(char)
+		case char == '}' || char == ']':
+			depth--
+			current.WriteRune(char)
+		case (char == ' ' || char == '\t') && depth == 0:
+			flush()
+		default:
+			current.WriteRune(char)
+		}
+	}
+	if quote != 0 {
+		return nil, errors.Errorf("unterminated quote in: %s", line)
+	}
+	if depth != 0 {
+		return nil, errors.Errorf("unbalanced braces or brackets in: %s", line)
+	}
+	flush()
+	return words, nil
+}
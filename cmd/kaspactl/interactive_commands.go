@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver/protowire"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	requestMessageSuffix = "RequestMessage"
+	notifyCommandPrefix  = "Notify"
+)
+
+// interactiveCommand describes a request that can be issued from the
+// interactive shell. Unlike commandDescription, it is built from the
+// protowire message descriptors rather than from the generated Go types,
+// so every request in messages.proto is available
+type interactiveCommand struct {
+	name         string
+	payloadField protoreflect.FieldDescriptor
+}
+
+func (ic *interactiveCommand) fields() protoreflect.FieldDescriptors {
+	return ic.payloadField.Message().Fields()
+}
+
+func (ic *interactiveCommand) isNotify() bool {
+	return strings.HasPrefix(ic.name, notifyCommandPrefix)
+}
+
+func (ic *interactiveCommand) help() string {
+	sb := &strings.Builder{}
+	sb.WriteString(ic.name)
+	fields := ic.fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		fieldType := field.Kind().String()
+		if field.Kind() == protoreflect.MessageKind {
+			fieldType = string(field.Message().Name())
+		}
+		if field.IsList() {
+			fieldType = "[]" + fieldType
+		}
+		_, _ = fmt.Fprintf(sb, " [%s:%s]", field.JSONName(), fieldType)
+	}
+	return sb.String()
+}
+
+// interactiveCommands returns all the request messages that can be sent in a
+// KaspadMessage, keyed by command name
+func interactiveCommands() map[string]*interactiveCommand {
+	payload := (&protowire.KaspadMessage{}).ProtoReflect().Descriptor().Oneofs().ByName("payload")
+	payloadFields := payload.Fields()
+
+	commands := make(map[string]*interactiveCommand)
+	for i := 0; i < payloadFields.Len(); i++ {
+		payloadField := payloadFields.Get(i)
+		if payloadField.Kind() != protoreflect.MessageKind {
+			continue
+		}
+		messageName := string(payloadField.Message().Name())
+		if !strings.HasSuffix(messageName, requestMessageSuffix) {
+			continue
+		}
+		name := strings.TrimSuffix(messageName, requestMessageSuffix)
+		commands[name] = &interactiveCommand{
+			name:         name,
+			payloadField: payloadField,
+		}
+	}
+	return commands
+}
+
+func sortedCommandNames(commands map[string]*interactiveCommand) []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// buildInteractiveRequest builds a KaspadMessage out of the given command and
+// arguments. Arguments are either all positional, in which case they are
+// matched to the request fields by order, or all in `field=value` form
+func buildInteractiveRequest(command *interactiveCommand, args []string) (*protowire.KaspadMessage, error) {
+	fields := command.fields()
+	values := make(map[string]json.RawMessage)
+
+	if len(args) > 0 && isNamedArgument(fields, args[0]) {
+		for _, arg := range args {
+			if !isNamedArgument(fields, arg) {
+				return nil, errors.Errorf("argument '%s' is not in the form field=value", arg)
+			}
+			separatorIndex := strings.Index(arg, "=")
+			fieldName, valueString := arg[:separatorIndex], arg[separatorIndex+1:]
+			field := fields.ByJSONName(fieldName)
+			value, err := fieldValueToJSON(field, valueString)
+			if err != nil {
+				return nil, err
+			}
+			values[field.JSONName()] = value
+		}
+	} else {
+		if len(args) > fields.Len() {
+			return nil, errors.Errorf("command '%s' expects at most %d parameters but got %d",
+				command.name, fields.Len(), len(args))
+		}
+		for i, arg := range args {
+			field := fields.Get(i)
+			value, err := fieldValueToJSON(field, arg)
+			if err != nil {
+				return nil, err
+			}
+			values[field.JSONName()] = value
+		}
+	}
+
+	requestJSON, err := json.Marshal(map[string]interface{}{command.payloadField.JSONName(): values})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	message := &protowire.KaspadMessage{}
+	err = protojson.Unmarshal(requestJSON, message)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error parsing the parameters of '%s'", command.name)
+	}
+	return message, nil
+}
+
+func isNamedArgument(fields protoreflect.FieldDescriptors, arg string) bool {
+	separatorIndex := strings.Index(arg, "=")
+	if separatorIndex <= 0 {
+		return false
+	}
+	return fields.ByJSONName(arg[:separatorIndex]) != nil
+}
+
+// fieldValueToJSON converts a value as typed in the shell into its JSON
+// representation, so that protojson can take care of validating it
+func fieldValueToJSON(field protoreflect.FieldDescriptor, valueString string) (json.RawMessage, error) {
+	if !field.IsList() {
+		return scalarValueToJSON(field, valueString)
+	}
+
+	if strings.HasPrefix(valueString, "[") {
+		return json.RawMessage(valueString), nil
+	}
+	var elements []json.RawMessage
+	if valueString != "" {
+		for _, elementString := range strings.Split(valueString, ",") {
+			element, err := scalarValueToJSON(field, elementString)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, element)
+		}
+	}
+	listJSON, err := json.Marshal(elements)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return listJSON, nil
+}
+
+func scalarValueToJSON(field protoreflect.FieldDescriptor, valueString string) (json.RawMessage, error) {
+	switch field.Kind() {
+	case protoreflect.StringKind, protoreflect.BytesKind, protoreflect.EnumKind:
+		valueJSON, err := json.Marshal(valueString)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		return valueJSON, nil
+	default:
+		if !json.Valid([]byte(valueString)) {
+			return nil, errors.Errorf("value '%s' of field '%s' is not a valid %s",
+				valueString, field.JSONName(), field.Kind())
+		}
+		return json.RawMessage(valueString), nil
+	}
+}
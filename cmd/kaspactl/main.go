@@ -27,6 +27,13 @@ func main() {
 	if err != nil {
 		printErrorAndExit(fmt.Sprintf("error parsing RPC server address: %s", err))
 	}
+	if cfg.Interactive {
+		err := runInteractiveShell(cfg, rpcAddress)
+		if err != nil {
+			printErrorAndExit(err.Error())
+		}
+		return
+	}
 	client, err := grpcclient.Connect(rpcAddress)
 	if err != nil {
 		printErrorAndExit(fmt.Sprintf("error connecting to the RPC server: %s", err))
@@ -91,6 +98,10 @@ func prettifyResponse(response string) string {
 		printErrorAndExit(fmt.Sprintf("error parsing the response from the RPC server: %s", err))
 	}
 
+	return formatMessage(kaspadMessage)
+}
+
+func formatMessage(kaspadMessage *protowire.KaspadMessage) string {
 	marshalOptions := &protojson.MarshalOptions{}
 	marshalOptions.Indent = "    "
 	marshalOptions.EmitUnpopulated = true
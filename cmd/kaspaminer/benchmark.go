@@ -0,0 +1,45 @@
+package main
+
+import (
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/model/pow"
+)
+
+// runBenchmark measures the raw proof of work hashing speed of the given
+// amount of workers, without connecting to a node. The workers hash the
+// given header against an unreachable target, exactly as they would while
+// mining
+func runBenchmark(header externalapi.BlockHeader, numberOfWorkers int, duration time.Duration) {
+	counters := newHashCounters(numberOfWorkers)
+	impossibleTarget := big.NewInt(-1)
+	var shouldStop uint32
+
+	wg := sync.WaitGroup{}
+	wg.Add(numberOfWorkers)
+	for i := 0; i < numberOfWorkers; i++ {
+		workerIndex := i
+		spawn("benchmarkWorker", func() {
+			defer wg.Done()
+			rangeStart, rangeSize := nonceRange(workerIndex, numberOfWorkers)
+			headerForMining := header.ToMutable()
+			for offset := uint64(0); atomic.LoadUint32(&shouldStop) == 0; offset = (offset + 1) % rangeSize {
+				headerForMining.SetNonce(rangeStart + offset)
+				pow.CheckProofOfWorkWithTarget(headerForMining, impossibleTarget)
+				counters.add(workerIndex)
+			}
+		})
+	}
+
+	log.Infof("Benchmarking proof of work hashing with %d workers for %s", numberOfWorkers, duration)
+	start := time.Now()
+	time.Sleep(duration)
+	atomic.StoreUint32(&shouldStop, 1)
+	wg.Wait()
+
+	logHashRates(counters.sample(), time.Since(start))
+}
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kaspanet/kaspad/infrastructure/config"
 
@@ -20,6 +22,7 @@ const (
 	defaultLogFilename          = "kaspaminer.log"
 	defaultErrLogFilename       = "kaspaminer_err.log"
 	defaultTargetBlockRateRatio = 2.0
+	defaultBenchmarkDuration    = 10 * time.Second
 )
 
 var (
@@ -31,19 +34,24 @@ var (
 )
 
 type configFlags struct {
-	ShowVersion           bool     `short:"V" long:"version" description:"Display version information and exit"`
-	RPCServer             string   `short:"s" long:"rpcserver" description:"RPC server to connect to"`
-	MiningAddr            string   `long:"miningaddr" description:"Address to mine to"`
-	NumberOfBlocks        uint64   `short:"n" long:"numblocks" description:"Number of blocks to mine. If omitted, will mine until the process is interrupted."`
-	MineWhenNotSynced     bool     `long:"mine-when-not-synced" description:"Mine even if the node is not synced with the rest of the network."`
-	Profile               string   `long:"profile" description:"Enable HTTP profiling on given port -- NOTE port must be between 1024 and 65536"`
-	TargetBlocksPerSecond *float64 `long:"target-blocks-per-second" description:"Sets a maximum block rate. 0 means no limit (The default one is 2 * target network block rate)"`
+	ShowVersion           bool          `short:"V" long:"version" description:"Display version information and exit"`
+	RPCServer             string        `short:"s" long:"rpcserver" description:"RPC server to connect to"`
+	MiningAddr            string        `long:"miningaddr" description:"Address to mine to"`
+	NumberOfBlocks        uint64        `short:"n" long:"numblocks" description:"Number of blocks to mine. If omitted, will mine until the process is interrupted."`
+	MineWhenNotSynced     bool          `long:"mine-when-not-synced" description:"Mine even if the node is not synced with the rest of the network."`
+	Profile               string        `long:"profile" description:"Enable HTTP profiling on given port -- NOTE port must be between 1024 and 65536"`
+	TargetBlocksPerSecond *float64      `long:"target-blocks-per-second" description:"Sets a maximum block rate. 0 means no limit (The default one is 2 * target network block rate)"`
+	Workers               int           `long:"workers" description:"Number of mining goroutines. The nonce space is split between them (The default is the number of CPUs)"`
+	Benchmark             bool          `long:"benchmark" description:"Measure the proof of work hashing speed without connecting to a node, and exit"`
+	BenchmarkDuration     time.Duration `long:"benchmark-duration" description:"How long to run the benchmark for"`
 	config.NetworkFlags
 }
 
 func parseConfig() (*configFlags, error) {
 	cfg := &configFlags{
-		RPCServer: defaultRPCServer,
+		RPCServer:         defaultRPCServer,
+		Workers:           runtime.NumCPU(),
+		BenchmarkDuration: defaultBenchmarkDuration,
 	}
 	parser := flags.NewParser(cfg, flags.PrintErrors|flags.HelpFlag)
 	_, err := parser.Parse()
@@ -77,7 +85,15 @@ func parseConfig() (*configFlags, error) {
 		}
 	}
 
-	if cfg.MiningAddr == "" {
+	if cfg.Workers < 1 {
+		return nil, errors.New("--workers must be at least 1")
+	}
+
+	if cfg.BenchmarkDuration <= 0 {
+		return nil, errors.New("--benchmark-duration must be positive")
+	}
+
+	if cfg.MiningAddr == "" && !cfg.Benchmark {
 		return nil, errors.New("--miningaddr is required")
 	}
 
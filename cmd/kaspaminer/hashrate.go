@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const logHashRateInterval = 10 * time.Second
+
+// workerHashCounter counts the hashes tried by a single worker. It's padded
+// to the size of a cache line so that workers don't slow each other down by
+// updating neighbouring counters
+type workerHashCounter struct {
+	hashesTried uint64
+	_           [56]byte
+}
+
+// hashCounters holds a hash counter for each worker
+type hashCounters []workerHashCounter
+
+func newHashCounters(numberOfWorkers int) hashCounters {
+	return make(hashCounters, numberOfWorkers)
+}
+
+func (c hashCounters) add(workerIndex int) {
+	atomic.AddUint64(&c[workerIndex].hashesTried, 1)
+}
+
+// sample returns the amount of hashes each worker tried since the previous
+// sample was taken
+func (c hashCounters) sample() []uint64 {
+	hashesTried := make([]uint64, len(c))
+	for i := range c {
+		hashesTried[i] = atomic.SwapUint64(&c[i].hashesTried, 0)
+	}
+	return hashesTried
+}
+
+func logHashRate(counters hashCounters) {
+	spawn("logHashRate", func() {
+		lastCheck := time.Now()
+		for range time.Tick(logHashRateInterval) {
+			hashesTried := counters.sample()
+			currentTime := time.Now()
+			logHashRates(hashesTried, currentTime.Sub(lastCheck))
+			lastCheck = currentTime
+		}
+	})
+}
+
+// logHashRates logs the total hash rate, and the hash rate of every worker
+// if there is more than one
+func logHashRates(hashesTried []uint64, duration time.Duration) {
+	hashRate := func(hashes uint64) float64 {
+		kiloHashesTried := float64(hashes) / 1000.0
+		return kiloHashesTried / duration.Seconds()
+	}
+
+	totalHashesTried := uint64(0)
+	workerHashRates := make([]string, len(hashesTried))
+	for i, workerHashesTried := range hashesTried {
+		totalHashesTried += workerHashesTried
+		workerHashRates[i] = fmt.Sprintf("%d: %.2f", i, hashRate(workerHashesTried))
+	}
+
+	log.Infof("Current hash rate is %.2f Khash/s", hashRate(totalHashesTried))
+	if len(hashesTried) > 1 {
+		log.Infof("Hash rate per worker (Khash/s): %s", strings.Join(workerHashRates, ", "))
+	}
+}
@@ -36,11 +36,16 @@ func main() {
 		profiling.Start(cfg.Profile, log)
 	}
 
+	if cfg.Benchmark {
+		runBenchmark(cfg.NetParams().GenesisBlock.Header, cfg.Workers, cfg.BenchmarkDuration)
+		return
+	}
+
 	client, err := newMinerClient(cfg)
 	if err != nil {
 		panic(errors.Wrap(err, "error connecting to the RPC server"))
 	}
-	defer client.safeRPCClient().Disconnect()
+	defer client.Close()
 
 	miningAddr, err := util.DecodeAddress(cfg.MiningAddr, cfg.ActiveNetParams.Prefix)
 	if err != nil {
@@ -49,7 +54,8 @@ func main() {
 
 	doneChan := make(chan struct{})
 	spawn("mineLoop", func() {
-		err = mineLoop(client, cfg.NumberOfBlocks, *cfg.TargetBlocksPerSecond, cfg.MineWhenNotSynced, miningAddr)
+		err = mineLoop(client, cfg.NumberOfBlocks, *cfg.TargetBlocksPerSecond, cfg.MineWhenNotSynced, miningAddr,
+			cfg.Workers)
 		if err != nil {
 			panic(errors.Wrap(err, "error in mine loop"))
 		}
@@ -2,8 +2,9 @@ package main
 
 import (
 	nativeerrors "errors"
+	"math"
+	"math/big"
 	"math/rand"
-	"sync/atomic"
 	"time"
 
 	"github.com/kaspanet/kaspad/cmd/kaspaminer/templatemanager"
@@ -16,17 +17,14 @@ import (
 
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/kaspanet/kaspad/infrastructure/network/rpcclient"
 
 	"github.com/kaspanet/kaspad/util"
 	"github.com/pkg/errors"
 )
 
-var hashesTried uint64
-
-const logHashRateInterval = 10 * time.Second
-
 func mineLoop(client *minerClient, numberOfBlocks uint64, targetBlocksPerSecond float64, mineWhenNotSynced bool,
-	miningAddr util.Address) error {
+	miningAddr util.Address, numberOfWorkers int) error {
 	rand.Seed(time.Now().UnixNano()) // Seed the global concurrent-safe random source.
 
 	errChan := make(chan error)
@@ -42,6 +40,15 @@ func mineLoop(client *minerClient, numberOfBlocks uint64, targetBlocksPerSecond
 		templatesLoop(client, miningAddr, errChan)
 	})
 
+	counters := newHashCounters(numberOfWorkers)
+	minedBlockChan := make(chan *externalapi.DomainBlock)
+	for i := 0; i < numberOfWorkers; i++ {
+		workerIndex := i
+		spawn("mineWorker", func() {
+			mineWorker(workerIndex, numberOfWorkers, counters, mineWhenNotSynced, minedBlockChan)
+		})
+	}
+
 	spawn("blocksLoop", func() {
 		const windowSize = 10
 		var expectedDurationForWindow time.Duration
@@ -56,7 +63,7 @@ func mineLoop(client *minerClient, numberOfBlocks uint64, targetBlocksPerSecond
 		sleepTime := 0 * time.Second
 
 		for {
-			foundBlockChan <- mineNextBlock(mineWhenNotSynced)
+			foundBlockChan <- <-minedBlockChan
 
 			if hasBlockRateTarget {
 				blockInWindowIndex++
@@ -88,7 +95,7 @@ func mineLoop(client *minerClient, numberOfBlocks uint64, targetBlocksPerSecond
 		doneChan <- struct{}{}
 	})
 
-	logHashRate()
+	logHashRate(counters)
 
 	select {
 	case err := <-errChan:
@@ -98,31 +105,14 @@ func mineLoop(client *minerClient, numberOfBlocks uint64, targetBlocksPerSecond
 	}
 }
 
-func logHashRate() {
-	spawn("logHashRate", func() {
-		lastCheck := time.Now()
-		for range time.Tick(logHashRateInterval) {
-			currentHashesTried := atomic.LoadUint64(&hashesTried)
-			currentTime := time.Now()
-			kiloHashesTried := float64(currentHashesTried) / 1000.0
-			hashRate := kiloHashesTried / currentTime.Sub(lastCheck).Seconds()
-			log.Infof("Current hash rate is %.2f Khash/s", hashRate)
-			lastCheck = currentTime
-			// subtract from hashesTried the hashes we already sampled
-			atomic.AddUint64(&hashesTried, -currentHashesTried)
-		}
-	})
-}
-
 func handleFoundBlock(client *minerClient, block *externalapi.DomainBlock) error {
 	blockHash := consensushashing.BlockHash(block)
-	log.Infof("Submitting block %s to %s", blockHash, client.safeRPCClient().Address())
+	log.Infof("Submitting block %s to %s", blockHash, client.Address())
 
-	rejectReason, err := client.safeRPCClient().SubmitBlock(block)
+	rejectReason, err := client.SubmitBlock(block)
 	if err != nil {
-		if nativeerrors.Is(err, router.ErrTimeout) {
-			log.Warnf("Got timeout while submitting block %s to %s: %s", blockHash, client.safeRPCClient().Address(), err)
-			client.reconnect()
+		if nativeerrors.Is(err, router.ErrTimeout) || nativeerrors.Is(err, rpcclient.ErrConnectionLost) {
+			log.Warnf("Could not submit block %s to %s: %s", blockHash, client.Address(), err)
 			return nil
 		}
 		if rejectReason == appmessage.RejectReasonIsInIBD {
@@ -131,29 +121,52 @@ func handleFoundBlock(client *minerClient, block *externalapi.DomainBlock) error
 			time.Sleep(waitTime)
 			return nil
 		}
-		return errors.Wrapf(err, "Error submitting block %s to %s", blockHash, client.safeRPCClient().Address())
+		return errors.Wrapf(err, "Error submitting block %s to %s", blockHash, client.Address())
 	}
 	return nil
 }
 
-func mineNextBlock(mineWhenNotSynced bool) *externalapi.DomainBlock {
-	nonce := rand.Uint64() // Use the global concurrent-safe random source.
+// nonceRange returns the part of the nonce space that belongs to the given
+// worker, so that no two workers ever try the same nonce
+func nonceRange(workerIndex int, numberOfWorkers int) (rangeStart uint64, rangeSize uint64) {
+	rangeSize = math.MaxUint64 / uint64(numberOfWorkers)
+	return uint64(workerIndex) * rangeSize, rangeSize
+}
+
+// mineWorker searches the worker's part of the nonce space for a block and
+// sends every block it finds to foundBlockChan.
+// The template is only fetched again once templatemanager reports a new
+// version, so that workers don't contend over the template lock on every hash
+func mineWorker(workerIndex int, numberOfWorkers int, counters hashCounters, mineWhenNotSynced bool,
+	foundBlockChan chan<- *externalapi.DomainBlock) {
+
+	rangeStart, rangeSize := nonceRange(workerIndex, numberOfWorkers)
+	offset := rand.Uint64() % rangeSize // Use the global concurrent-safe random source.
+
+	var block *externalapi.DomainBlock
+	var headerForMining externalapi.MutableBlockHeader
+	var targetDifficulty *big.Int
+	var templateVersion uint64
 	for {
-		nonce++
-		// For each nonce we try to build a block from the most up to date
-		// block template.
-		// In the rare case where the nonce space is exhausted for a specific
-		// block, it'll keep looping the nonce until a new block template
-		// is discovered.
-		block := getBlockForMining(mineWhenNotSynced)
-		targetDifficulty := difficulty.CompactToBig(block.Header.Bits())
-		headerForMining := block.Header.ToMutable()
-		headerForMining.SetNonce(nonce)
-		atomic.AddUint64(&hashesTried, 1)
+		if block == nil || templatemanager.Version() != templateVersion {
+			templateVersion = templatemanager.Version()
+			block = getBlockForMining(mineWhenNotSynced)
+			targetDifficulty = difficulty.CompactToBig(block.Header.Bits())
+			headerForMining = block.Header.ToMutable()
+		}
+
+		// In the rare case where the worker's nonce range is exhausted for a
+		// specific block, it'll keep looping the range until a new block
+		// template is discovered.
+		offset = (offset + 1) % rangeSize
+		headerForMining.SetNonce(rangeStart + offset)
+		counters.add(workerIndex)
 		if pow.CheckProofOfWorkWithTarget(headerForMining, targetDifficulty) {
 			block.Header = headerForMining.ToImmutable()
-			log.Infof("Found block %s with parents %s", consensushashing.BlockHash(block), block.Header.ParentHashes())
-			return block
+			log.Infof("Worker %d found block %s with parents %s",
+				workerIndex, consensushashing.BlockHash(block), block.Header.ParentHashes())
+			foundBlockChan <- block
+			block = nil
 		}
 	}
 }
@@ -190,14 +203,13 @@ func getBlockForMining(mineWhenNotSynced bool) *externalapi.DomainBlock {
 
 func templatesLoop(client *minerClient, miningAddr util.Address, errChan chan error) {
 	getBlockTemplate := func() {
-		template, err := client.safeRPCClient().GetBlockTemplate(miningAddr.String())
-		if nativeerrors.Is(err, router.ErrTimeout) {
-			log.Warnf("Got timeout while requesting block template from %s: %s", client.safeRPCClient().Address(), err)
-			client.reconnect()
+		template, err := client.GetBlockTemplate(miningAddr.String())
+		if nativeerrors.Is(err, router.ErrTimeout) || nativeerrors.Is(err, rpcclient.ErrConnectionLost) {
+			log.Warnf("Could not request a block template from %s: %s", client.Address(), err)
 			return
 		}
 		if err != nil {
-			errChan <- errors.Wrapf(err, "Error getting block template from %s", client.safeRPCClient().Address())
+			errChan <- errors.Wrapf(err, "Error getting block template from %s", client.Address())
 			return
 		}
 		templatemanager.Set(template)
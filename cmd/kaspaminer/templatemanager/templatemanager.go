@@ -4,12 +4,18 @@ import (
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"sync"
+	"sync/atomic"
 )
 
 var currentTemplate *externalapi.DomainBlock
 var isSynced bool
 var lock = &sync.Mutex{}
 
+// version is incremented every time the template is set. It's accessed
+// atomically, so that workers may cheaply check whether their template is
+// outdated without taking the lock
+var version uint64
+
 // Get returns the template to work on
 func Get() (*externalapi.DomainBlock, bool) {
 	lock.Lock()
@@ -29,4 +35,11 @@ func Set(template *appmessage.GetBlockTemplateResponseMessage) {
 	defer lock.Unlock()
 	currentTemplate = block
 	isSynced = template.IsSynced
+	atomic.AddUint64(&version, 1)
+}
+
+// Version returns a number that changes every time the template is set.
+// A version read before calling Get is never newer than the returned template
+func Version() uint64 {
+	return atomic.LoadUint64(&version)
 }
@@ -12,7 +12,7 @@ func balance(conf *balanceConfig) error {
 	if err != nil {
 		return err
 	}
-	getUTXOsByAddressesResponse, err := client.GetUTXOsByAddresses([]string{conf.Address})
+	getUTXOsByAddressesResponse, err := client.GetUTXOsByAddressesIncludingMempool([]string{conf.Address})
 	if err != nil {
 		return err
 	}
@@ -24,7 +24,8 @@ func balance(conf *balanceConfig) error {
 
 	var availableBalance, pendingBalance uint64
 	for _, entry := range getUTXOsByAddressesResponse.Entries {
-		if isUTXOSpendable(entry, virtualSelectedParentBlueScore, conf.ActiveNetParams.BlockCoinbaseMaturity) {
+		if !entry.IsUnconfirmed &&
+			isUTXOSpendable(entry, virtualSelectedParentBlueScore, conf.ActiveNetParams.BlockCoinbaseMaturity) {
 			availableBalance += entry.UTXOEntry.Amount
 		} else {
 			pendingBalance += entry.UTXOEntry.Amount
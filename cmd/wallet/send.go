@@ -87,7 +87,9 @@ func parsePrivateKey(privateKeyHex string) (*secp256k1.SchnorrKeyPair, *secp256k
 }
 
 func fetchSpendableUTXOs(conf *sendConfig, client *rpcclient.RPCClient, address string) ([]*appmessage.UTXOsByAddressesEntry, error) {
-	getUTXOsByAddressesResponse, err := client.GetUTXOsByAddresses([]string{address})
+	// The mempool is included so that UTXOs that were already spent by
+	// pending transactions aren't spent again
+	getUTXOsByAddressesResponse, err := client.GetUTXOsByAddressesIncludingMempool([]string{address})
 	if err != nil {
 		return nil, err
 	}
@@ -11,9 +11,19 @@ import (
 )
 
 type consensus struct {
-	lock            *sync.Mutex
+	// lock is held for reading by methods that only query the committed
+	// state, and for writing while changes are committed to it. writeLock
+	// is held by anything that stages or commits changes, so that they're
+	// made one at a time. Since staging doesn't require lock, queries can
+	// run while a block is being validated
+	lock            *stateLock
+	writeLock       *sync.Mutex
 	databaseContext model.DBManager
 
+	// committedState queries the same stores as this consensus, without
+	// seeing what this consensus staged
+	committedState *consensus
+
 	blockProcessor        model.BlockProcessor
 	blockBuilder          model.BlockBuilder
 	consensusStateManager model.ConsensusStateManager
@@ -53,8 +63,8 @@ type consensus struct {
 func (s *consensus) BuildBlock(coinbaseData *externalapi.DomainCoinbaseData,
 	transactions []*externalapi.DomainTransaction) (*externalapi.DomainBlock, error) {
 
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
 
 	return s.blockBuilder.BuildBlock(coinbaseData, transactions)
 }
@@ -62,42 +72,57 @@ func (s *consensus) BuildBlock(coinbaseData *externalapi.DomainCoinbaseData,
 // ValidateAndInsertBlock validates the given block and, if valid, applies it
 // to the current state
 func (s *consensus) ValidateAndInsertBlock(block *externalapi.DomainBlock) (*externalapi.BlockInsertionResult, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
 
 	return s.blockProcessor.ValidateAndInsertBlock(block)
 }
 
+// ValidateAndInsertBlocks validates the given topologically ordered blocks and
+// inserts them to the current state, resolving the virtual only once for all
+// of them when possible. It returns an insertion result for every block that
+// was inserted. If a block fails validation, the blocks that precede it remain
+// inserted, and the returned error is the one ValidateAndInsertBlock would have
+// returned for it
+func (s *consensus) ValidateAndInsertBlocks(blocks []*externalapi.DomainBlock) ([]*externalapi.BlockInsertionResult, error) {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+
+	return s.blockProcessor.ValidateAndInsertBlocks(blocks)
+}
+
 // ValidateTransactionAndPopulateWithConsensusData validates the given transaction
 // and populates it with any missing consensus data
 func (s *consensus) ValidateTransactionAndPopulateWithConsensusData(transaction *externalapi.DomainTransaction) error {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	err := s.transactionValidator.ValidateTransactionInIsolation(transaction)
+	state := s.committedState
+	err := state.transactionValidator.ValidateTransactionInIsolation(transaction)
 	if err != nil {
 		return err
 	}
 
-	err = s.consensusStateManager.PopulateTransactionWithUTXOEntries(transaction)
+	err = state.consensusStateManager.PopulateTransactionWithUTXOEntries(transaction)
 	if err != nil {
 		return err
 	}
 
-	virtualSelectedParentMedianTime, err := s.pastMedianTimeManager.PastMedianTime(model.VirtualBlockHash)
+	virtualSelectedParentMedianTime, err := state.pastMedianTimeManager.PastMedianTime(model.VirtualBlockHash)
 	if err != nil {
 		return err
 	}
 
-	return s.transactionValidator.ValidateTransactionInContextAndPopulateMassAndFee(transaction,
+	return state.transactionValidator.ValidateTransactionInContextAndPopulateMassAndFee(transaction,
 		model.VirtualBlockHash, virtualSelectedParentMedianTime)
 }
 
 func (s *consensus) GetBlock(blockHash *externalapi.DomainHash) (*externalapi.DomainBlock, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	block, err := s.blockStore.Block(s.databaseContext, blockHash)
+	state := s.committedState
+	block, err := state.blockStore.Block(state.databaseContext, blockHash)
 	if err != nil {
 		if errors.Is(err, database.ErrNotFound) {
 			return nil, errors.Wrapf(err, "block %s does not exist", blockHash)
@@ -108,10 +133,11 @@ func (s *consensus) GetBlock(blockHash *externalapi.DomainHash) (*externalapi.Do
 }
 
 func (s *consensus) GetBlockHeader(blockHash *externalapi.DomainHash) (externalapi.BlockHeader, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	blockHeader, err := s.blockHeaderStore.BlockHeader(s.databaseContext, blockHash)
+	state := s.committedState
+	blockHeader, err := state.blockHeaderStore.BlockHeader(state.databaseContext, blockHash)
 	if err != nil {
 		if errors.Is(err, database.ErrNotFound) {
 			return nil, errors.Wrapf(err, "block header %s does not exist", blockHash)
@@ -122,12 +148,13 @@ func (s *consensus) GetBlockHeader(blockHash *externalapi.DomainHash) (externala
 }
 
 func (s *consensus) GetBlockInfo(blockHash *externalapi.DomainHash) (*externalapi.BlockInfo, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
+	state := s.committedState
 	blockInfo := &externalapi.BlockInfo{}
 
-	exists, err := s.blockStatusStore.Exists(s.databaseContext, blockHash)
+	exists, err := state.blockStatusStore.Exists(state.databaseContext, blockHash)
 	if err != nil {
 		return nil, err
 	}
@@ -136,7 +163,7 @@ func (s *consensus) GetBlockInfo(blockHash *externalapi.DomainHash) (*externalap
 		return blockInfo, nil
 	}
 
-	blockStatus, err := s.blockStatusStore.Get(s.databaseContext, blockHash)
+	blockStatus, err := state.blockStatusStore.Get(state.databaseContext, blockHash)
 	if err != nil {
 		return nil, err
 	}
@@ -147,7 +174,7 @@ func (s *consensus) GetBlockInfo(blockHash *externalapi.DomainHash) (*externalap
 		return blockInfo, nil
 	}
 
-	ghostdagData, err := s.ghostdagDataStore.Get(s.databaseContext, blockHash)
+	ghostdagData, err := state.ghostdagDataStore.Get(state.databaseContext, blockHash)
 	if err != nil {
 		return nil, err
 	}
@@ -157,8 +184,34 @@ func (s *consensus) GetBlockInfo(blockHash *externalapi.DomainHash) (*externalap
 	return blockInfo, nil
 }
 
+func (s *consensus) GetBlockGHOSTDAGInfo(blockHash *externalapi.DomainHash) (*externalapi.BlockGHOSTDAGInfo, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	state := s.committedState
+	err := state.validateBlockHashExists(blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	ghostdagData, err := state.ghostdagDataStore.Get(state.databaseContext, blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return &externalapi.BlockGHOSTDAGInfo{
+		SelectedParent: ghostdagData.SelectedParent(),
+		MergeSetBlues:  ghostdagData.MergeSetBlues(),
+		MergeSetReds:   ghostdagData.MergeSetReds(),
+	}, nil
+}
+
 func (s *consensus) GetBlockChildren(blockHash *externalapi.DomainHash) ([]*externalapi.DomainHash, error) {
-	blockRelation, err := s.blockRelationStore.BlockRelation(s.databaseContext, blockHash)
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	state := s.committedState
+	blockRelation, err := state.blockRelationStore.BlockRelation(state.databaseContext, blockHash)
 	if err != nil {
 		return nil, err
 	}
@@ -167,54 +220,58 @@ func (s *consensus) GetBlockChildren(blockHash *externalapi.DomainHash) ([]*exte
 }
 
 func (s *consensus) GetBlockAcceptanceData(blockHash *externalapi.DomainHash) (externalapi.AcceptanceData, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	err := s.validateBlockHashExists(blockHash)
+	state := s.committedState
+	err := state.validateBlockHashExists(blockHash)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.acceptanceDataStore.Get(s.databaseContext, blockHash)
+	return state.acceptanceDataStore.Get(state.databaseContext, blockHash)
 }
 
 func (s *consensus) GetHashesBetween(lowHash, highHash *externalapi.DomainHash,
 	maxBlueScoreDifference uint64) ([]*externalapi.DomainHash, error) {
 
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	err := s.validateBlockHashExists(lowHash)
+	state := s.committedState
+	err := state.validateBlockHashExists(lowHash)
 	if err != nil {
 		return nil, err
 	}
-	err = s.validateBlockHashExists(highHash)
+	err = state.validateBlockHashExists(highHash)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.syncManager.GetHashesBetween(lowHash, highHash, maxBlueScoreDifference)
+	return state.syncManager.GetHashesBetween(lowHash, highHash, maxBlueScoreDifference)
 }
 
 func (s *consensus) GetMissingBlockBodyHashes(highHash *externalapi.DomainHash) ([]*externalapi.DomainHash, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	err := s.validateBlockHashExists(highHash)
+	state := s.committedState
+	err := state.validateBlockHashExists(highHash)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.syncManager.GetMissingBlockBodyHashes(highHash)
+	return state.syncManager.GetMissingBlockBodyHashes(highHash)
 }
 
 func (s *consensus) GetPruningPointUTXOs(expectedPruningPointHash *externalapi.DomainHash,
 	fromOutpoint *externalapi.DomainOutpoint, limit int) ([]*externalapi.OutpointAndUTXOEntryPair, error) {
 
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	pruningPointHash, err := s.pruningStore.PruningPoint(s.databaseContext)
+	state := s.committedState
+	pruningPointHash, err := state.pruningStore.PruningPoint(state.databaseContext)
 	if err != nil {
 		return nil, err
 	}
@@ -225,7 +282,18 @@ func (s *consensus) GetPruningPointUTXOs(expectedPruningPointHash *externalapi.D
 			pruningPointHash)
 	}
 
-	pruningPointUTXOs, err := s.pruningStore.PruningPointUTXOs(s.databaseContext, fromOutpoint, limit)
+	// The pruning point UTXO set is updated after the new pruning point is
+	// committed, so until the update is finished it's only partially written
+	isUpdatingPruningPointUTXOSet, err := state.pruningStore.HadStartedUpdatingPruningPointUTXOSet(state.databaseContext)
+	if err != nil {
+		return nil, err
+	}
+	if isUpdatingPruningPointUTXOSet {
+		return nil, errors.Wrapf(ruleerrors.ErrWrongPruningPointHash, "the UTXO set of pruning point %s "+
+			"is still being updated", pruningPointHash)
+	}
+
+	pruningPointUTXOs, err := state.pruningStore.PruningPointUTXOs(state.databaseContext, fromOutpoint, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -235,10 +303,11 @@ func (s *consensus) GetPruningPointUTXOs(expectedPruningPointHash *externalapi.D
 func (s *consensus) GetVirtualUTXOs(expectedVirtualParents []*externalapi.DomainHash,
 	fromOutpoint *externalapi.DomainOutpoint, limit int) ([]*externalapi.OutpointAndUTXOEntryPair, error) {
 
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	virtualParents, err := s.dagTopologyManager.Parents(model.VirtualBlockHash)
+	state := s.committedState
+	virtualParents, err := state.dagTopologyManager.Parents(model.VirtualBlockHash)
 	if err != nil {
 		return nil, err
 	}
@@ -249,7 +318,7 @@ func (s *consensus) GetVirtualUTXOs(expectedVirtualParents []*externalapi.Domain
 			virtualParents)
 	}
 
-	virtualUTXOs, err := s.consensusStateStore.VirtualUTXOs(s.databaseContext, fromOutpoint, limit)
+	virtualUTXOs, err := state.consensusStateStore.VirtualUTXOs(state.databaseContext, fromOutpoint, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -257,27 +326,33 @@ func (s *consensus) GetVirtualUTXOs(expectedVirtualParents []*externalapi.Domain
 }
 
 func (s *consensus) PruningPoint() (*externalapi.DomainHash, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	return s.pruningStore.PruningPoint(s.databaseContext)
+	state := s.committedState
+	return state.pruningStore.PruningPoint(state.databaseContext)
 }
 
 func (s *consensus) ClearImportedPruningPointData() error {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
 
 	return s.pruningManager.ClearImportedPruningPointData()
 }
 
 func (s *consensus) AppendImportedPruningPointUTXOs(outpointAndUTXOEntryPairs []*externalapi.OutpointAndUTXOEntryPair) error {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
 
 	return s.pruningManager.AppendImportedPruningPointUTXOs(outpointAndUTXOEntryPairs)
 }
 
 func (s *consensus) ValidateAndInsertImportedPruningPoint(newPruningPoint *externalapi.DomainBlock) error {
+	s.writeLock.Lock()
+	defer s.writeLock.Unlock()
+
+	// The virtual UTXO set is replaced by many separate writes, so queries
+	// must wait for the whole import rather than only for its commits
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
@@ -285,10 +360,11 @@ func (s *consensus) ValidateAndInsertImportedPruningPoint(newPruningPoint *exter
 }
 
 func (s *consensus) GetVirtualSelectedParent() (*externalapi.DomainHash, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	virtualGHOSTDAGData, err := s.ghostdagDataStore.Get(s.databaseContext, model.VirtualBlockHash)
+	state := s.committedState
+	virtualGHOSTDAGData, err := state.ghostdagDataStore.Get(state.databaseContext, model.VirtualBlockHash)
 	if err != nil {
 		return nil, err
 	}
@@ -296,29 +372,31 @@ func (s *consensus) GetVirtualSelectedParent() (*externalapi.DomainHash, error)
 }
 
 func (s *consensus) Tips() ([]*externalapi.DomainHash, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	return s.consensusStateStore.Tips(s.databaseContext)
+	state := s.committedState
+	return state.consensusStateStore.Tips(state.databaseContext)
 }
 
 func (s *consensus) GetVirtualInfo() (*externalapi.VirtualInfo, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	blockRelations, err := s.blockRelationStore.BlockRelation(s.databaseContext, model.VirtualBlockHash)
+	state := s.committedState
+	blockRelations, err := state.blockRelationStore.BlockRelation(state.databaseContext, model.VirtualBlockHash)
 	if err != nil {
 		return nil, err
 	}
-	bits, err := s.difficultyManager.RequiredDifficulty(model.VirtualBlockHash)
+	bits, err := state.difficultyManager.RequiredDifficulty(model.VirtualBlockHash)
 	if err != nil {
 		return nil, err
 	}
-	pastMedianTime, err := s.pastMedianTimeManager.PastMedianTime(model.VirtualBlockHash)
+	pastMedianTime, err := state.pastMedianTimeManager.PastMedianTime(model.VirtualBlockHash)
 	if err != nil {
 		return nil, err
 	}
-	virtualGHOSTDAGData, err := s.ghostdagDataStore.Get(s.databaseContext, model.VirtualBlockHash)
+	virtualGHOSTDAGData, err := state.ghostdagDataStore.Get(state.databaseContext, model.VirtualBlockHash)
 	if err != nil {
 		return nil, err
 	}
@@ -328,79 +406,86 @@ func (s *consensus) GetVirtualInfo() (*externalapi.VirtualInfo, error) {
 		Bits:           bits,
 		PastMedianTime: pastMedianTime,
 		BlueScore:      virtualGHOSTDAGData.BlueScore(),
+		MergeSetBlues:  virtualGHOSTDAGData.MergeSetBlues(),
 	}, nil
 }
 
 func (s *consensus) CreateBlockLocator(lowHash, highHash *externalapi.DomainHash, limit uint32) (externalapi.BlockLocator, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	err := s.validateBlockHashExists(lowHash)
+	state := s.committedState
+	err := state.validateBlockHashExists(lowHash)
 	if err != nil {
 		return nil, err
 	}
-	err = s.validateBlockHashExists(highHash)
+	err = state.validateBlockHashExists(highHash)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.syncManager.CreateBlockLocator(lowHash, highHash, limit)
+	return state.syncManager.CreateBlockLocator(lowHash, highHash, limit)
 }
 
 func (s *consensus) CreateFullHeadersSelectedChainBlockLocator() (externalapi.BlockLocator, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	lowHash, err := s.pruningStore.PruningPoint(s.databaseContext)
+	state := s.committedState
+	lowHash, err := state.pruningStore.PruningPoint(state.databaseContext)
 	if err != nil {
 		return nil, err
 	}
 
-	highHash, err := s.headersSelectedTipStore.HeadersSelectedTip(s.databaseContext)
+	highHash, err := state.headersSelectedTipStore.HeadersSelectedTip(state.databaseContext)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.syncManager.CreateHeadersSelectedChainBlockLocator(lowHash, highHash)
+	return state.syncManager.CreateHeadersSelectedChainBlockLocator(lowHash, highHash)
 }
 
 func (s *consensus) CreateHeadersSelectedChainBlockLocator(lowHash,
 	highHash *externalapi.DomainHash) (externalapi.BlockLocator, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	return s.syncManager.CreateHeadersSelectedChainBlockLocator(lowHash, highHash)
+	state := s.committedState
+	return state.syncManager.CreateHeadersSelectedChainBlockLocator(lowHash, highHash)
 }
 
 func (s *consensus) GetSyncInfo() (*externalapi.SyncInfo, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	return s.syncManager.GetSyncInfo()
+	state := s.committedState
+	return state.syncManager.GetSyncInfo()
 }
 
 func (s *consensus) IsValidPruningPoint(blockHash *externalapi.DomainHash) (bool, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	err := s.validateBlockHashExists(blockHash)
+	state := s.committedState
+	err := state.validateBlockHashExists(blockHash)
 	if err != nil {
 		return false, err
 	}
 
-	return s.pruningManager.IsValidPruningPoint(blockHash)
+	return state.pruningManager.IsValidPruningPoint(blockHash)
 }
 
 func (s *consensus) GetVirtualSelectedParentChainFromBlock(blockHash *externalapi.DomainHash) (*externalapi.SelectedChainPath, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	err := s.validateBlockHashExists(blockHash)
+	state := s.committedState
+	err := state.validateBlockHashExists(blockHash)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.consensusStateManager.GetVirtualSelectedParentChainFromBlock(blockHash)
+	return state.consensusStateManager.GetVirtualSelectedParentChainFromBlock(blockHash)
 }
 
 func (s *consensus) validateBlockHashExists(blockHash *externalapi.DomainHash) error {
@@ -415,36 +500,77 @@ func (s *consensus) validateBlockHashExists(blockHash *externalapi.DomainHash) e
 }
 
 func (s *consensus) IsInSelectedParentChainOf(blockHashA *externalapi.DomainHash, blockHashB *externalapi.DomainHash) (bool, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	state := s.committedState
+	err := state.validateBlockHashExists(blockHashA)
+	if err != nil {
+		return false, err
+	}
+	err = state.validateBlockHashExists(blockHashB)
+	if err != nil {
+		return false, err
+	}
 
-	err := s.validateBlockHashExists(blockHashA)
+	return state.dagTopologyManager.IsInSelectedParentChainOf(blockHashA, blockHashB)
+}
+
+func (s *consensus) IsAncestorOf(blockHashA *externalapi.DomainHash, blockHashB *externalapi.DomainHash) (bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	state := s.committedState
+	err := state.validateBlockHashExists(blockHashA)
 	if err != nil {
 		return false, err
 	}
-	err = s.validateBlockHashExists(blockHashB)
+	err = state.validateBlockHashExists(blockHashB)
 	if err != nil {
 		return false, err
 	}
 
-	return s.dagTopologyManager.IsInSelectedParentChainOf(blockHashA, blockHashB)
+	return state.dagTopologyManager.IsAncestorOf(blockHashA, blockHashB)
 }
 
 func (s *consensus) GetHeadersSelectedTip() (*externalapi.DomainHash, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	return s.headersSelectedTipStore.HeadersSelectedTip(s.databaseContext)
+	state := s.committedState
+	return state.headersSelectedTipStore.HeadersSelectedTip(state.databaseContext)
 }
 
 func (s *consensus) Anticone(blockHash *externalapi.DomainHash) ([]*externalapi.DomainHash, error) {
-	s.lock.Lock()
-	defer s.lock.Unlock()
+	s.lock.RLock()
+	defer s.lock.RUnlock()
 
-	err := s.validateBlockHashExists(blockHash)
+	state := s.committedState
+	err := state.validateBlockHashExists(blockHash)
 	if err != nil {
 		return nil, err
 	}
 
-	return s.dagTraversalManager.Anticone(blockHash)
+	return state.dagTraversalManager.Anticone(blockHash)
+}
+
+func (s *consensus) IsFinalizedBlock(blockHash *externalapi.DomainHash) (bool, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	state := s.committedState
+	err := state.validateBlockHashExists(blockHash)
+	if err != nil {
+		return false, err
+	}
+
+	virtualFinalityPoint, err := state.finalityManager.VirtualFinalityPoint()
+	if err != nil {
+		return false, err
+	}
+	// The finality point itself is finalized
+	if blockHash.Equal(virtualFinalityPoint) {
+		return true, nil
+	}
+	return state.dagTopologyManager.IsAncestorOf(blockHash, virtualFinalityPoint)
 }
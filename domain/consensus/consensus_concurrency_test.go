@@ -0,0 +1,260 @@
+package consensus
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/testutils"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/pkg/errors"
+)
+
+func TestConsensus_QueriesRunWhileBlockIsValidated(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, params *dagconfig.Params) {
+		factory := NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(params, false, "TestConsensus_QueriesRunWhileBlockIsValidated")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+		consensus := tc.(*testConsensus)
+
+		block, _, err := consensus.BuildBlockWithParents([]*externalapi.DomainHash{params.GenesisHash}, nil, nil)
+		if err != nil {
+			t.Fatalf("BuildBlockWithParents: %+v", err)
+		}
+		blockHash := consensushashing.BlockHash(block)
+
+		// Act as a writer in the middle of validating block: hold the write
+		// lock and stage some of the block's data without committing it
+		consensus.writeLock.Lock()
+		consensus.BlockStore().Stage(blockHash, block)
+		consensus.BlockHeaderStore().Stage(blockHash, block.Header)
+		consensus.BlockStatusStore().Stage(blockHash, externalapi.StatusHeaderOnly)
+		consensus.ConsensusStateStore().StageTips([]*externalapi.DomainHash{blockHash})
+
+		queryDone := make(chan struct{})
+		go func() {
+			defer close(queryDone)
+			err := queryConsensus(consensus, params, params.GenesisHash)
+			if err != nil {
+				t.Errorf("Query failed: %+v", err)
+				return
+			}
+
+			blockInfo, err := consensus.GetBlockInfo(blockHash)
+			if err != nil {
+				t.Errorf("GetBlockInfo: %+v", err)
+				return
+			}
+			if blockInfo.Exists {
+				t.Errorf("A query saw block %s before it was committed", blockHash)
+			}
+			tips, err := consensus.Tips()
+			if err != nil {
+				t.Errorf("Tips: %+v", err)
+				return
+			}
+			if !externalapi.HashesEqual(tips, []*externalapi.DomainHash{params.GenesisHash}) {
+				t.Errorf("A query saw the staged tips %s", tips)
+			}
+			syncInfo, err := consensus.GetSyncInfo()
+			if err != nil {
+				t.Errorf("GetSyncInfo: %+v", err)
+				return
+			}
+			if syncInfo.BlockCount != 1 || syncInfo.HeaderCount != 1 {
+				t.Errorf("A query counted the staged block: got %d blocks and %d headers",
+					syncInfo.BlockCount, syncInfo.HeaderCount)
+			}
+		}()
+		select {
+		case <-queryDone:
+		case <-time.After(10 * time.Second):
+			t.Fatalf("A query waited for the validation of a block")
+		}
+
+		consensus.DiscardAllStores()
+		consensus.writeLock.Unlock()
+
+		_, err = consensus.ValidateAndInsertBlock(block)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertBlock: %+v", err)
+		}
+		blockInfo, err := consensus.GetBlockInfo(blockHash)
+		if err != nil {
+			t.Fatalf("GetBlockInfo: %+v", err)
+		}
+		if !blockInfo.Exists {
+			t.Fatalf("Block %s wasn't found after it was committed", blockHash)
+		}
+	})
+}
+
+func TestConsensus_ConcurrentReadsAndInsertions(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, params *dagconfig.Params) {
+		factory := NewFactory()
+		consensus, teardown, err := factory.NewTestConsensus(params, false, "TestConsensus_ConcurrentReadsAndInsertions")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		const numberOfBlocks = 60
+		const numberOfReaders = 8
+
+		// insertedHashes holds every block that was inserted so far, so
+		// that the readers could query blocks that are known to exist
+		insertedHashesLock := sync.RWMutex{}
+		insertedHashes := []*externalapi.DomainHash{params.GenesisHash}
+		randomInsertedHash := func(random *rand.Rand) *externalapi.DomainHash {
+			insertedHashesLock.RLock()
+			defer insertedHashesLock.RUnlock()
+
+			return insertedHashes[random.Intn(len(insertedHashes))]
+		}
+
+		insertionDone := make(chan struct{})
+		go func() {
+			defer close(insertionDone)
+			random := rand.New(rand.NewSource(0))
+			for i := 0; i < numberOfBlocks; i++ {
+				tips, err := consensus.Tips()
+				if err != nil {
+					t.Errorf("Tips: %+v", err)
+					return
+				}
+				parentHashes := tips
+				// Every few blocks, create a side block with a random parent,
+				// so that the DAG is wider than a single chain
+				if i%3 == 0 {
+					parentHashes = []*externalapi.DomainHash{randomInsertedHash(random)}
+				}
+				blockHash, _, err := consensus.AddBlock(parentHashes, nil, nil)
+				if err != nil {
+					t.Errorf("AddBlock: %+v", err)
+					return
+				}
+
+				insertedHashesLock.Lock()
+				insertedHashes = append(insertedHashes, blockHash)
+				insertedHashesLock.Unlock()
+			}
+		}()
+
+		readersWaitGroup := sync.WaitGroup{}
+		readersWaitGroup.Add(numberOfReaders)
+		for i := 0; i < numberOfReaders; i++ {
+			random := rand.New(rand.NewSource(int64(i + 1)))
+			go func() {
+				defer readersWaitGroup.Done()
+				for {
+					select {
+					case <-insertionDone:
+						return
+					default:
+					}
+					err := queryConsensus(consensus, params, randomInsertedHash(random))
+					if err != nil {
+						t.Errorf("Query failed: %+v", err)
+						return
+					}
+				}
+			}()
+		}
+
+		<-insertionDone
+		readersWaitGroup.Wait()
+		if t.Failed() {
+			t.FailNow()
+		}
+
+		// Query a fresh consensus over the same database, so that the
+		// readers have to concurrently populate its empty caches
+		freshConsensus, err := factory.NewConsensus(params, consensus.Database(), false)
+		if err != nil {
+			t.Fatalf("NewConsensus: %+v", err)
+		}
+		readersWaitGroup.Add(numberOfReaders)
+		for i := 0; i < numberOfReaders; i++ {
+			offset := i
+			go func() {
+				defer readersWaitGroup.Done()
+				for j := range insertedHashes {
+					blockHash := insertedHashes[(j+offset)%len(insertedHashes)]
+					err := queryConsensus(freshConsensus, params, blockHash)
+					if err != nil {
+						t.Errorf("Query failed: %+v", err)
+						return
+					}
+				}
+			}()
+		}
+		readersWaitGroup.Wait()
+	})
+}
+
+// queryConsensus calls the read-only methods of the consensus that RPC handlers commonly use
+func queryConsensus(consensus externalapi.Consensus, params *dagconfig.Params, blockHash *externalapi.DomainHash) error {
+	blockInfo, err := consensus.GetBlockInfo(blockHash)
+	if err != nil {
+		return err
+	}
+	if !blockInfo.Exists {
+		return errors.Errorf("block %s was inserted but does not exist", blockHash)
+	}
+	_, err = consensus.GetBlock(blockHash)
+	if err != nil {
+		return err
+	}
+	_, err = consensus.GetBlockHeader(blockHash)
+	if err != nil {
+		return err
+	}
+	_, err = consensus.GetBlockChildren(blockHash)
+	if err != nil {
+		return err
+	}
+	_, err = consensus.Anticone(blockHash)
+	if err != nil {
+		return err
+	}
+	_, err = consensus.IsInSelectedParentChainOf(params.GenesisHash, blockHash)
+	if err != nil {
+		return err
+	}
+	_, err = consensus.GetHashesBetween(params.GenesisHash, blockHash, 1000)
+	if err != nil {
+		return err
+	}
+	_, err = consensus.GetVirtualInfo()
+	if err != nil {
+		return err
+	}
+	_, err = consensus.GetVirtualSelectedParent()
+	if err != nil {
+		return err
+	}
+	_, err = consensus.GetVirtualSelectedParentChainFromBlock(params.GenesisHash)
+	if err != nil {
+		return err
+	}
+	_, err = consensus.Tips()
+	if err != nil {
+		return err
+	}
+	_, err = consensus.GetHeadersSelectedTip()
+	if err != nil {
+		return err
+	}
+	_, err = consensus.GetSyncInfo()
+	if err != nil {
+		return err
+	}
+	_, err = consensus.PruningPoint()
+	return err
+}
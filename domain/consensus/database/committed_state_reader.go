@@ -0,0 +1,55 @@
+package database
+
+import (
+	"github.com/kaspanet/kaspad/domain/consensus/model"
+	"github.com/pkg/errors"
+)
+
+// errCommittedStateReaderIsReadOnly is returned when writing through a committedStateReader
+var errCommittedStateReaderIsReadOnly = errors.New("the committed state reader is read-only")
+
+// committedStateReader is a model.DBManager that only reads the committed
+// state of the database. Stores don't show their staged changes to reads
+// that go through it, so it can be used while another goroutine stages
+// changes to the same stores
+type committedStateReader struct {
+	dbManager model.DBManager
+}
+
+// NewCommittedStateReader returns a read-only model.DBManager over the
+// committed state of the given dbManager
+func NewCommittedStateReader(dbManager model.DBManager) model.DBManager {
+	return &committedStateReader{dbManager: dbManager}
+}
+
+func (csr *committedStateReader) Get(key model.DBKey) ([]byte, error) {
+	return csr.dbManager.Get(key)
+}
+
+func (csr *committedStateReader) Has(key model.DBKey) (bool, error) {
+	return csr.dbManager.Has(key)
+}
+
+func (csr *committedStateReader) Cursor(bucket model.DBBucket) (model.DBCursor, error) {
+	return csr.dbManager.Cursor(bucket)
+}
+
+func (csr *committedStateReader) Put(model.DBKey, []byte) error {
+	return errors.WithStack(errCommittedStateReaderIsReadOnly)
+}
+
+func (csr *committedStateReader) Delete(model.DBKey) error {
+	return errors.WithStack(errCommittedStateReaderIsReadOnly)
+}
+
+func (csr *committedStateReader) Begin() (model.DBTransaction, error) {
+	return nil, errors.WithStack(errCommittedStateReaderIsReadOnly)
+}
+
+// IsStagingVisible returns whether reads through dbContext should see the
+// changes that stores staged but didn't commit yet. It's false only for
+// readers created by NewCommittedStateReader
+func IsStagingVisible(dbContext model.DBReader) bool {
+	_, isCommittedStateReader := dbContext.(*committedStateReader)
+	return !isCommittedStateReader
+}
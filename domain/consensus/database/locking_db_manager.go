@@ -0,0 +1,94 @@
+package database
+
+import (
+	"sync"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model"
+)
+
+// lockingDBManager is a model.DBManager that holds a lock while it writes
+// to the database. Direct writes hold it for the duration of the write,
+// and transactions hold it from Begin until they're committed or rolled back
+type lockingDBManager struct {
+	dbManager model.DBManager
+	writeLock sync.Locker
+}
+
+// NewLockingDBManager returns a model.DBManager that holds writeLock whenever
+// it writes to the given dbManager
+func NewLockingDBManager(dbManager model.DBManager, writeLock sync.Locker) model.DBManager {
+	return &lockingDBManager{
+		dbManager: dbManager,
+		writeLock: writeLock,
+	}
+}
+
+func (ldm *lockingDBManager) Get(key model.DBKey) ([]byte, error) {
+	return ldm.dbManager.Get(key)
+}
+
+func (ldm *lockingDBManager) Has(key model.DBKey) (bool, error) {
+	return ldm.dbManager.Has(key)
+}
+
+func (ldm *lockingDBManager) Cursor(bucket model.DBBucket) (model.DBCursor, error) {
+	return ldm.dbManager.Cursor(bucket)
+}
+
+func (ldm *lockingDBManager) Put(key model.DBKey, value []byte) error {
+	ldm.writeLock.Lock()
+	defer ldm.writeLock.Unlock()
+
+	return ldm.dbManager.Put(key, value)
+}
+
+func (ldm *lockingDBManager) Delete(key model.DBKey) error {
+	ldm.writeLock.Lock()
+	defer ldm.writeLock.Unlock()
+
+	return ldm.dbManager.Delete(key)
+}
+
+func (ldm *lockingDBManager) Begin() (model.DBTransaction, error) {
+	ldm.writeLock.Lock()
+	transaction, err := ldm.dbManager.Begin()
+	if err != nil {
+		ldm.writeLock.Unlock()
+		return nil, err
+	}
+	return &lockingDBTransaction{
+		DBTransaction: transaction,
+		writeLock:     ldm.writeLock,
+	}, nil
+}
+
+// lockingDBTransaction is a model.DBTransaction that releases the lock
+// taken by lockingDBManager.Begin once it's committed or rolled back
+type lockingDBTransaction struct {
+	model.DBTransaction
+	writeLock  sync.Locker
+	isReleased bool
+}
+
+func (ldt *lockingDBTransaction) Commit() error {
+	defer ldt.release()
+	return ldt.DBTransaction.Commit()
+}
+
+func (ldt *lockingDBTransaction) Rollback() error {
+	defer ldt.release()
+	return ldt.DBTransaction.Rollback()
+}
+
+func (ldt *lockingDBTransaction) RollbackUnlessClosed() error {
+	defer ldt.release()
+	return ldt.DBTransaction.RollbackUnlessClosed()
+}
+
+func (ldt *lockingDBTransaction) release() {
+	if ldt.isReleased {
+		return
+	}
+	ldt.isReleased = true
+	ldt.writeLock.Unlock()
+}
@@ -68,8 +68,10 @@ func (ads *acceptanceDataStore) Commit(dbTx model.DBTransaction) error {
 
 // Get gets the acceptanceData associated with the given blockHash
 func (ads *acceptanceDataStore) Get(dbContext model.DBReader, blockHash *externalapi.DomainHash) (externalapi.AcceptanceData, error) {
-	if acceptanceData, ok := ads.staging[*blockHash]; ok {
-		return acceptanceData.Clone(), nil
+	if database.IsStagingVisible(dbContext) {
+		if acceptanceData, ok := ads.staging[*blockHash]; ok {
+			return acceptanceData.Clone(), nil
+		}
 	}
 
 	if acceptanceData, ok := ads.cache.Get(blockHash); ok {
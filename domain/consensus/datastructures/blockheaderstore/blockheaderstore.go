@@ -102,8 +102,10 @@ func (bhs *blockHeaderStore) Commit(dbTx model.DBTransaction) error {
 
 // BlockHeader gets the block header associated with the given blockHash
 func (bhs *blockHeaderStore) BlockHeader(dbContext model.DBReader, blockHash *externalapi.DomainHash) (externalapi.BlockHeader, error) {
-	if header, ok := bhs.staging[*blockHash]; ok {
-		return header, nil
+	if database.IsStagingVisible(dbContext) {
+		if header, ok := bhs.staging[*blockHash]; ok {
+			return header, nil
+		}
 	}
 
 	if header, ok := bhs.cache.Get(blockHash); ok {
@@ -125,8 +127,10 @@ func (bhs *blockHeaderStore) BlockHeader(dbContext model.DBReader, blockHash *ex
 
 // HasBlock returns whether a block header with a given hash exists in the store.
 func (bhs *blockHeaderStore) HasBlockHeader(dbContext model.DBReader, blockHash *externalapi.DomainHash) (bool, error) {
-	if _, ok := bhs.staging[*blockHash]; ok {
-		return true, nil
+	if database.IsStagingVisible(dbContext) {
+		if _, ok := bhs.staging[*blockHash]; ok {
+			return true, nil
+		}
 	}
 
 	if bhs.cache.Has(blockHash) {
@@ -181,7 +185,10 @@ func (bhs *blockHeaderStore) deserializeHeader(headerBytes []byte) (externalapi.
 	return serialization.DbBlockHeaderToDomainBlockHeader(dbBlockHeader)
 }
 
-func (bhs *blockHeaderStore) Count() uint64 {
+func (bhs *blockHeaderStore) Count(dbContext model.DBReader) uint64 {
+	if !database.IsStagingVisible(dbContext) {
+		return bhs.count
+	}
 	return bhs.count + uint64(len(bhs.staging)) - uint64(len(bhs.toDelete))
 }
 
@@ -195,7 +202,7 @@ func (bhs *blockHeaderStore) deserializeHeaderCount(countBytes []byte) (uint64,
 }
 
 func (bhs *blockHeaderStore) commitCount(dbTx model.DBTransaction) error {
-	count := bhs.Count()
+	count := bhs.Count(dbTx)
 	countBytes, err := bhs.serializeHeaderCount(count)
 	if err != nil {
 		return err
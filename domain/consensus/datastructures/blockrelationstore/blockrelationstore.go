@@ -55,8 +55,10 @@ func (brs *blockRelationStore) Commit(dbTx model.DBTransaction) error {
 }
 
 func (brs *blockRelationStore) BlockRelation(dbContext model.DBReader, blockHash *externalapi.DomainHash) (*model.BlockRelations, error) {
-	if blockRelations, ok := brs.staging[*blockHash]; ok {
-		return blockRelations.Clone(), nil
+	if database.IsStagingVisible(dbContext) {
+		if blockRelations, ok := brs.staging[*blockHash]; ok {
+			return blockRelations.Clone(), nil
+		}
 	}
 
 	if blockRelations, ok := brs.cache.Get(blockHash); ok {
@@ -77,8 +79,10 @@ func (brs *blockRelationStore) BlockRelation(dbContext model.DBReader, blockHash
 }
 
 func (brs *blockRelationStore) Has(dbContext model.DBReader, blockHash *externalapi.DomainHash) (bool, error) {
-	if _, ok := brs.staging[*blockHash]; ok {
-		return true, nil
+	if database.IsStagingVisible(dbContext) {
+		if _, ok := brs.staging[*blockHash]; ok {
+			return true, nil
+		}
 	}
 
 	if brs.cache.Has(blockHash) {
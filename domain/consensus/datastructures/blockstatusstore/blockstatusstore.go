@@ -57,8 +57,10 @@ func (bss *blockStatusStore) Commit(dbTx model.DBTransaction) error {
 
 // Get gets the blockStatus associated with the given blockHash
 func (bss *blockStatusStore) Get(dbContext model.DBReader, blockHash *externalapi.DomainHash) (externalapi.BlockStatus, error) {
-	if status, ok := bss.staging[*blockHash]; ok {
-		return status, nil
+	if database.IsStagingVisible(dbContext) {
+		if status, ok := bss.staging[*blockHash]; ok {
+			return status, nil
+		}
 	}
 
 	if status, ok := bss.cache.Get(blockHash); ok {
@@ -80,8 +82,10 @@ func (bss *blockStatusStore) Get(dbContext model.DBReader, blockHash *externalap
 
 // Exists returns true if the blockStatus for the given blockHash exists
 func (bss *blockStatusStore) Exists(dbContext model.DBReader, blockHash *externalapi.DomainHash) (bool, error) {
-	if _, ok := bss.staging[*blockHash]; ok {
-		return true, nil
+	if database.IsStagingVisible(dbContext) {
+		if _, ok := bss.staging[*blockHash]; ok {
+			return true, nil
+		}
 	}
 
 	if bss.cache.Has(blockHash) {
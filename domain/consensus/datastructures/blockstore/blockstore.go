@@ -103,8 +103,10 @@ func (bs *blockStore) Commit(dbTx model.DBTransaction) error {
 
 // Block gets the block associated with the given blockHash
 func (bs *blockStore) Block(dbContext model.DBReader, blockHash *externalapi.DomainHash) (*externalapi.DomainBlock, error) {
-	if block, ok := bs.staging[*blockHash]; ok {
-		return block.Clone(), nil
+	if database.IsStagingVisible(dbContext) {
+		if block, ok := bs.staging[*blockHash]; ok {
+			return block.Clone(), nil
+		}
 	}
 
 	if block, ok := bs.cache.Get(blockHash); ok {
@@ -126,8 +128,10 @@ func (bs *blockStore) Block(dbContext model.DBReader, blockHash *externalapi.Dom
 
 // HasBlock returns whether a block with a given hash exists in the store.
 func (bs *blockStore) HasBlock(dbContext model.DBReader, blockHash *externalapi.DomainHash) (bool, error) {
-	if _, ok := bs.staging[*blockHash]; ok {
-		return true, nil
+	if database.IsStagingVisible(dbContext) {
+		if _, ok := bs.staging[*blockHash]; ok {
+			return true, nil
+		}
 	}
 
 	if bs.cache.Has(blockHash) {
@@ -182,7 +186,10 @@ func (bs *blockStore) hashAsKey(hash *externalapi.DomainHash) model.DBKey {
 	return bucket.Key(hash.ByteSlice())
 }
 
-func (bs *blockStore) Count() uint64 {
+func (bs *blockStore) Count(dbContext model.DBReader) uint64 {
+	if !database.IsStagingVisible(dbContext) {
+		return bs.count
+	}
 	return bs.count + uint64(len(bs.staging)) - uint64(len(bs.toDelete))
 }
 
@@ -196,7 +203,7 @@ func (bs *blockStore) deserializeBlockCount(countBytes []byte) (uint64, error) {
 }
 
 func (bs *blockStore) commitCount(dbTx model.DBTransaction) error {
-	count := bs.Count()
+	count := bs.Count(dbTx)
 	countBytes, err := bs.serializeBlockCount(count)
 	if err != nil {
 		return err
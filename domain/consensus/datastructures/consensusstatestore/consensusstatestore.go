@@ -1,6 +1,8 @@
 package consensusstatestore
 
 import (
+	"sync"
+
 	"github.com/kaspanet/kaspad/domain/consensus/model"
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/utxolrucache"
@@ -13,7 +15,9 @@ type consensusStateStore struct {
 
 	virtualUTXOSetCache *utxolrucache.LRUCache
 
-	tipsCache []*externalapi.DomainHash
+	// tipsCacheLock guards tipsCache, which may be populated by concurrent readers
+	tipsCacheLock sync.RWMutex
+	tipsCache     []*externalapi.DomainHash
 }
 
 // New instantiates a new ConsensusStateStore
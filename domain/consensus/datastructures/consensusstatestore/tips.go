@@ -11,12 +11,12 @@ import (
 var tipsKey = database.MakeBucket(nil).Key([]byte("tips"))
 
 func (css *consensusStateStore) Tips(dbContext model.DBReader) ([]*externalapi.DomainHash, error) {
-	if css.tipsStaging != nil {
+	if database.IsStagingVisible(dbContext) && css.tipsStaging != nil {
 		return externalapi.CloneHashes(css.tipsStaging), nil
 	}
 
-	if css.tipsCache != nil {
-		return externalapi.CloneHashes(css.tipsCache), nil
+	if tipsCache := css.cachedTips(); tipsCache != nil {
+		return externalapi.CloneHashes(tipsCache), nil
 	}
 
 	tipsBytes, err := dbContext.Get(tipsKey)
@@ -28,10 +28,24 @@ func (css *consensusStateStore) Tips(dbContext model.DBReader) ([]*externalapi.D
 	if err != nil {
 		return nil, err
 	}
-	css.tipsCache = tips
+	css.setCachedTips(tips)
 	return externalapi.CloneHashes(tips), nil
 }
 
+func (css *consensusStateStore) cachedTips() []*externalapi.DomainHash {
+	css.tipsCacheLock.RLock()
+	defer css.tipsCacheLock.RUnlock()
+
+	return css.tipsCache
+}
+
+func (css *consensusStateStore) setCachedTips(tips []*externalapi.DomainHash) {
+	css.tipsCacheLock.Lock()
+	defer css.tipsCacheLock.Unlock()
+
+	css.tipsCache = tips
+}
+
 func (css *consensusStateStore) StageTips(tipHashes []*externalapi.DomainHash) {
 	css.tipsStaging = externalapi.CloneHashes(tipHashes)
 }
@@ -49,7 +63,7 @@ func (css *consensusStateStore) commitTips(dbTx model.DBTransaction) error {
 	if err != nil {
 		return err
 	}
-	css.tipsCache = css.tipsStaging
+	css.setCachedTips(css.tipsStaging)
 
 	// Note: we don't discard the staging here since that's
 	// being done at the end of Commit()
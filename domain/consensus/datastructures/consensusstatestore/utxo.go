@@ -95,7 +95,7 @@ func (css *consensusStateStore) utxoByOutpointFromStagedVirtualUTXODiff(dbContex
 	outpoint *externalapi.DomainOutpoint) (
 	externalapi.UTXOEntry, error) {
 
-	if css.virtualUTXODiffStaging != nil {
+	if database.IsStagingVisible(dbContext) && css.virtualUTXODiffStaging != nil {
 		if css.virtualUTXODiffStaging.ToRemove().Contains(outpoint) {
 			return nil, errors.Errorf("outpoint was not found")
 		}
@@ -134,7 +134,7 @@ func (css *consensusStateStore) HasUTXOByOutpoint(dbContext model.DBReader, outp
 func (css *consensusStateStore) hasUTXOByOutpointFromStagedVirtualUTXODiff(dbContext model.DBReader,
 	outpoint *externalapi.DomainOutpoint) (bool, error) {
 
-	if css.virtualUTXODiffStaging != nil {
+	if database.IsStagingVisible(dbContext) && css.virtualUTXODiffStaging != nil {
 		if css.virtualUTXODiffStaging.ToRemove().Contains(outpoint) {
 			return false, nil
 		}
@@ -196,7 +196,7 @@ func (css *consensusStateStore) VirtualUTXOSetIterator(dbContext model.DBReader)
 	}
 
 	mainIterator := newCursorUTXOSetIterator(cursor)
-	if css.virtualUTXODiffStaging != nil {
+	if database.IsStagingVisible(dbContext) && css.virtualUTXODiffStaging != nil {
 		return utxo.IteratorWithDiff(mainIterator, css.virtualUTXODiffStaging)
 	}
 
@@ -30,8 +30,10 @@ func (fs *finalityStore) StageFinalityPoint(blockHash *externalapi.DomainHash, f
 
 func (fs *finalityStore) FinalityPoint(
 	dbContext model.DBReader, blockHash *externalapi.DomainHash) (*externalapi.DomainHash, error) {
-	if finalityPointHash, ok := fs.staging[*blockHash]; ok {
-		return finalityPointHash, nil
+	if database.IsStagingVisible(dbContext) {
+		if finalityPointHash, ok := fs.staging[*blockHash]; ok {
+			return finalityPointHash, nil
+		}
 	}
 
 	if finalityPointHash, ok := fs.cache.Get(blockHash); ok {
@@ -57,8 +57,10 @@ func (gds *ghostdagDataStore) Commit(dbTx model.DBTransaction) error {
 
 // Get gets the blockGHOSTDAGData associated with the given blockHash
 func (gds *ghostdagDataStore) Get(dbContext model.DBReader, blockHash *externalapi.DomainHash) (*model.BlockGHOSTDAGData, error) {
-	if blockGHOSTDAGData, ok := gds.staging[*blockHash]; ok {
-		return blockGHOSTDAGData, nil
+	if database.IsStagingVisible(dbContext) {
+		if blockGHOSTDAGData, ok := gds.staging[*blockHash]; ok {
+			return blockGHOSTDAGData, nil
+		}
 	}
 
 	if blockGHOSTDAGData, ok := gds.cache.Get(blockHash); ok {
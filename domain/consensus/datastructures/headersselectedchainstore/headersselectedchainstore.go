@@ -2,6 +2,8 @@ package headersselectedchainstore
 
 import (
 	"encoding/binary"
+	"sync"
+
 	"github.com/kaspanet/kaspad/domain/consensus/database"
 	"github.com/kaspanet/kaspad/domain/consensus/database/binaryserialization"
 	"github.com/kaspanet/kaspad/domain/consensus/model"
@@ -16,13 +18,17 @@ var bucketChainBlockIndexByHash = database.MakeBucket([]byte("chain-block-index-
 var highestChainBlockIndexKey = database.MakeBucket(nil).Key([]byte("highest-chain-block-index"))
 
 type headersSelectedChainStore struct {
-	stagingAddedByHash          map[externalapi.DomainHash]uint64
-	stagingRemovedByHash        map[externalapi.DomainHash]struct{}
-	stagingAddedByIndex         map[uint64]*externalapi.DomainHash
-	stagingRemovedByIndex       map[uint64]struct{}
-	cacheByIndex                *lrucacheuint64tohash.LRUCache
-	cacheByHash                 *lrucache.LRUCache
-	cacheHighestChainBlockIndex uint64
+	stagingAddedByHash    map[externalapi.DomainHash]uint64
+	stagingRemovedByHash  map[externalapi.DomainHash]struct{}
+	stagingAddedByIndex   map[uint64]*externalapi.DomainHash
+	stagingRemovedByIndex map[uint64]struct{}
+	cacheByIndex          *lrucacheuint64tohash.LRUCache
+	cacheByHash           *lrucache.LRUCache
+
+	// cacheHighestChainBlockIndexLock guards cacheHighestChainBlockIndex,
+	// which may be populated by concurrent readers
+	cacheHighestChainBlockIndexLock sync.RWMutex
+	cacheHighestChainBlockIndex     uint64
 }
 
 // New instantiates a new HeadersSelectedChainStore
@@ -37,12 +43,18 @@ func New(cacheSize int, preallocate bool) model.HeadersSelectedChainStore {
 	}
 }
 
-// Stage stages the given chain changes
+// Stage stages the given chain changes on top of any already staged chain changes
 func (hscs *headersSelectedChainStore) Stage(dbContext model.DBReader,
 	chainChanges *externalapi.SelectedChainPath) error {
 
-	if hscs.IsStaged() {
-		return errors.Errorf("can't stage when there's already staged data")
+	currentIndex := uint64(0)
+	highestChainBlockIndex, exists, err := hscs.highestChainBlockIndex(dbContext)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		currentIndex = highestChainBlockIndex - uint64(len(chainChanges.Removed)) + 1
 	}
 
 	for _, blockHash := range chainChanges.Removed {
@@ -51,20 +63,12 @@ func (hscs *headersSelectedChainStore) Stage(dbContext model.DBReader,
 			return err
 		}
 
+		delete(hscs.stagingAddedByIndex, index)
+		delete(hscs.stagingAddedByHash, *blockHash)
 		hscs.stagingRemovedByIndex[index] = struct{}{}
 		hscs.stagingRemovedByHash[*blockHash] = struct{}{}
 	}
 
-	currentIndex := uint64(0)
-	highestChainBlockIndex, exists, err := hscs.highestChainBlockIndex(dbContext)
-	if err != nil {
-		return err
-	}
-
-	if exists {
-		currentIndex = highestChainBlockIndex - uint64(len(chainChanges.Removed)) + 1
-	}
-
 	for _, blockHash := range chainChanges.Added {
 		hscs.stagingAddedByIndex[currentIndex] = blockHash
 		hscs.stagingAddedByHash[*blockHash] = currentIndex
@@ -136,7 +140,7 @@ func (hscs *headersSelectedChainStore) Commit(dbTx model.DBTransaction) error {
 		return err
 	}
 
-	hscs.cacheHighestChainBlockIndex = highestIndex
+	hscs.setCachedHighestChainBlockIndex(highestIndex)
 
 	hscs.Discard()
 	return nil
@@ -144,12 +148,13 @@ func (hscs *headersSelectedChainStore) Commit(dbTx model.DBTransaction) error {
 
 // Get gets the chain block index for the given blockHash
 func (hscs *headersSelectedChainStore) GetIndexByHash(dbContext model.DBReader, blockHash *externalapi.DomainHash) (uint64, error) {
-	if index, ok := hscs.stagingAddedByHash[*blockHash]; ok {
-		return index, nil
-	}
-
-	if _, ok := hscs.stagingRemovedByHash[*blockHash]; ok {
-		return 0, errors.Wrapf(database.ErrNotFound, "couldn't find block %s", blockHash)
+	if database.IsStagingVisible(dbContext) {
+		if index, ok := hscs.stagingAddedByHash[*blockHash]; ok {
+			return index, nil
+		}
+		if _, ok := hscs.stagingRemovedByHash[*blockHash]; ok {
+			return 0, errors.Wrapf(database.ErrNotFound, "couldn't find block %s", blockHash)
+		}
 	}
 
 	if index, ok := hscs.cacheByHash.Get(blockHash); ok {
@@ -167,12 +172,13 @@ func (hscs *headersSelectedChainStore) GetIndexByHash(dbContext model.DBReader,
 }
 
 func (hscs *headersSelectedChainStore) GetHashByIndex(dbContext model.DBReader, index uint64) (*externalapi.DomainHash, error) {
-	if blockHash, ok := hscs.stagingAddedByIndex[index]; ok {
-		return blockHash, nil
-	}
-
-	if _, ok := hscs.stagingRemovedByIndex[index]; ok {
-		return nil, errors.Wrapf(database.ErrNotFound, "couldn't find chain block with index %d", index)
+	if database.IsStagingVisible(dbContext) {
+		if blockHash, ok := hscs.stagingAddedByIndex[index]; ok {
+			return blockHash, nil
+		}
+		if _, ok := hscs.stagingRemovedByIndex[index]; ok {
+			return nil, errors.Wrapf(database.ErrNotFound, "couldn't find chain block with index %d", index)
+		}
 	}
 
 	if blockHash, ok := hscs.cacheByIndex.Get(index); ok {
@@ -211,8 +217,20 @@ func (hscs *headersSelectedChainStore) indexAsKey(index uint64) model.DBKey {
 }
 
 func (hscs *headersSelectedChainStore) highestChainBlockIndex(dbContext model.DBReader) (uint64, bool, error) {
-	if hscs.cacheHighestChainBlockIndex != 0 {
-		return hscs.cacheHighestChainBlockIndex, true, nil
+	// The chain changes are staged one on top of the other, so the highest
+	// staged chain block is always the highest chain block
+	if database.IsStagingVisible(dbContext) && len(hscs.stagingAddedByIndex) > 0 {
+		highestStagedIndex := uint64(0)
+		for index := range hscs.stagingAddedByIndex {
+			if index > highestStagedIndex {
+				highestStagedIndex = index
+			}
+		}
+		return highestStagedIndex, true, nil
+	}
+
+	if cachedIndex := hscs.cachedHighestChainBlockIndex(); cachedIndex != 0 {
+		return cachedIndex, true, nil
 	}
 
 	indexBytes, err := dbContext.Get(highestChainBlockIndexKey)
@@ -224,6 +242,20 @@ func (hscs *headersSelectedChainStore) highestChainBlockIndex(dbContext model.DB
 	}
 
 	index := hscs.deserializeIndex(indexBytes)
-	hscs.cacheHighestChainBlockIndex = index
+	hscs.setCachedHighestChainBlockIndex(index)
 	return index, true, nil
 }
+
+func (hscs *headersSelectedChainStore) cachedHighestChainBlockIndex() uint64 {
+	hscs.cacheHighestChainBlockIndexLock.RLock()
+	defer hscs.cacheHighestChainBlockIndexLock.RUnlock()
+
+	return hscs.cacheHighestChainBlockIndex
+}
+
+func (hscs *headersSelectedChainStore) setCachedHighestChainBlockIndex(index uint64) {
+	hscs.cacheHighestChainBlockIndexLock.Lock()
+	defer hscs.cacheHighestChainBlockIndexLock.Unlock()
+
+	hscs.cacheHighestChainBlockIndex = index
+}
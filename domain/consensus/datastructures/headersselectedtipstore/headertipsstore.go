@@ -1,6 +1,8 @@
 package headersselectedtipstore
 
 import (
+	"sync"
+
 	"github.com/golang/protobuf/proto"
 	"github.com/kaspanet/kaspad/domain/consensus/database"
 	"github.com/kaspanet/kaspad/domain/consensus/database/serialization"
@@ -12,7 +14,10 @@ var headerSelectedTipKey = database.MakeBucket(nil).Key([]byte("headers-selected
 
 type headerSelectedTipStore struct {
 	staging *externalapi.DomainHash
-	cache   *externalapi.DomainHash
+
+	// cacheLock guards cache, which may be populated by concurrent readers
+	cacheLock sync.RWMutex
+	cache     *externalapi.DomainHash
 }
 
 // New instantiates a new HeaderSelectedTipStore
@@ -21,11 +26,11 @@ func New() model.HeaderSelectedTipStore {
 }
 
 func (hts *headerSelectedTipStore) Has(dbContext model.DBReader) (bool, error) {
-	if hts.staging != nil {
+	if database.IsStagingVisible(dbContext) && hts.staging != nil {
 		return true, nil
 	}
 
-	if hts.cache != nil {
+	if hts.cachedSelectedTip() != nil {
 		return true, nil
 	}
 
@@ -49,7 +54,7 @@ func (hts *headerSelectedTipStore) Commit(dbTx model.DBTransaction) error {
 	if err != nil {
 		return err
 	}
-	hts.cache = hts.staging
+	hts.setCachedSelectedTip(hts.staging)
 
 	hts.Discard()
 	return nil
@@ -64,12 +69,12 @@ func (hts *headerSelectedTipStore) IsStaged() bool {
 }
 
 func (hts *headerSelectedTipStore) HeadersSelectedTip(dbContext model.DBReader) (*externalapi.DomainHash, error) {
-	if hts.staging != nil {
+	if database.IsStagingVisible(dbContext) && hts.staging != nil {
 		return hts.staging, nil
 	}
 
-	if hts.cache != nil {
-		return hts.cache, nil
+	if cachedSelectedTip := hts.cachedSelectedTip(); cachedSelectedTip != nil {
+		return cachedSelectedTip, nil
 	}
 
 	selectedTipBytes, err := dbContext.Get(headerSelectedTipKey)
@@ -81,8 +86,22 @@ func (hts *headerSelectedTipStore) HeadersSelectedTip(dbContext model.DBReader)
 	if err != nil {
 		return nil, err
 	}
+	hts.setCachedSelectedTip(selectedTip)
+	return selectedTip, nil
+}
+
+func (hts *headerSelectedTipStore) cachedSelectedTip() *externalapi.DomainHash {
+	hts.cacheLock.RLock()
+	defer hts.cacheLock.RUnlock()
+
+	return hts.cache
+}
+
+func (hts *headerSelectedTipStore) setCachedSelectedTip(selectedTip *externalapi.DomainHash) {
+	hts.cacheLock.Lock()
+	defer hts.cacheLock.Unlock()
+
 	hts.cache = selectedTip
-	return hts.cache, nil
 }
 
 func (hts *headerSelectedTipStore) serializeHeadersSelectedTip(selectedTip *externalapi.DomainHash) ([]byte, error) {
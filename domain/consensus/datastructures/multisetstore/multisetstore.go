@@ -68,8 +68,10 @@ func (ms *multisetStore) Commit(dbTx model.DBTransaction) error {
 
 // Get gets the multiset associated with the given blockHash
 func (ms *multisetStore) Get(dbContext model.DBReader, blockHash *externalapi.DomainHash) (model.Multiset, error) {
-	if multiset, ok := ms.staging[*blockHash]; ok {
-		return multiset.Clone(), nil
+	if database.IsStagingVisible(dbContext) {
+		if multiset, ok := ms.staging[*blockHash]; ok {
+			return multiset.Clone(), nil
+		}
 	}
 
 	if multiset, ok := ms.cache.Get(blockHash); ok {
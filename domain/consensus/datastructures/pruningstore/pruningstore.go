@@ -1,6 +1,8 @@
 package pruningstore
 
 import (
+	"sync"
+
 	"github.com/golang/protobuf/proto"
 	"github.com/kaspanet/kaspad/domain/consensus/database"
 	"github.com/kaspanet/kaspad/domain/consensus/database/serialization"
@@ -16,9 +18,13 @@ var updatingPruningPointUTXOSetKey = database.MakeBucket(nil).Key([]byte("updati
 // pruningStore represents a store for the current pruning state
 type pruningStore struct {
 	pruningPointStaging          *externalapi.DomainHash
-	pruningPointCache            *externalapi.DomainHash
 	pruningPointCandidateStaging *externalapi.DomainHash
-	pruningPointCandidateCache   *externalapi.DomainHash
+
+	// cacheLock guards pruningPointCache and pruningPointCandidateCache,
+	// which may be populated by concurrent readers
+	cacheLock                  sync.RWMutex
+	pruningPointCache          *externalapi.DomainHash
+	pruningPointCandidateCache *externalapi.DomainHash
 
 	startUpdatingPruningPointUTXOSetStaging bool
 }
@@ -33,12 +39,12 @@ func (ps *pruningStore) StagePruningPointCandidate(candidate *externalapi.Domain
 }
 
 func (ps *pruningStore) PruningPointCandidate(dbContext model.DBReader) (*externalapi.DomainHash, error) {
-	if ps.pruningPointCandidateStaging != nil {
+	if database.IsStagingVisible(dbContext) && ps.pruningPointCandidateStaging != nil {
 		return ps.pruningPointCandidateStaging, nil
 	}
 
-	if ps.pruningPointCandidateCache != nil {
-		return ps.pruningPointCandidateCache, nil
+	if cached := ps.cachedPruningPointCandidate(); cached != nil {
+		return cached, nil
 	}
 
 	candidateBytes, err := dbContext.Get(pruningBlockHashKey)
@@ -50,16 +56,16 @@ func (ps *pruningStore) PruningPointCandidate(dbContext model.DBReader) (*extern
 	if err != nil {
 		return nil, err
 	}
-	ps.pruningPointCandidateCache = candidate
+	ps.setCachedPruningPointCandidate(candidate)
 	return candidate, nil
 }
 
 func (ps *pruningStore) HasPruningPointCandidate(dbContext model.DBReader) (bool, error) {
-	if ps.pruningPointCandidateStaging != nil {
+	if database.IsStagingVisible(dbContext) && ps.pruningPointCandidateStaging != nil {
 		return true, nil
 	}
 
-	if ps.pruningPointCandidateCache != nil {
+	if ps.cachedPruningPointCandidate() != nil {
 		return true, nil
 	}
 
@@ -90,7 +96,7 @@ func (ps *pruningStore) Commit(dbTx model.DBTransaction) error {
 		if err != nil {
 			return err
 		}
-		ps.pruningPointCache = ps.pruningPointStaging
+		ps.setCachedPruningPoint(ps.pruningPointStaging)
 	}
 
 	if ps.pruningPointCandidateStaging != nil {
@@ -102,7 +108,7 @@ func (ps *pruningStore) Commit(dbTx model.DBTransaction) error {
 		if err != nil {
 			return err
 		}
-		ps.pruningPointCandidateCache = ps.pruningPointCandidateStaging
+		ps.setCachedPruningPointCandidate(ps.pruningPointCandidateStaging)
 	}
 
 	if ps.startUpdatingPruningPointUTXOSetStaging {
@@ -162,12 +168,12 @@ func (ps *pruningStore) UpdatePruningPointUTXOSet(dbContext model.DBWriter,
 
 // PruningPoint gets the current pruning point
 func (ps *pruningStore) PruningPoint(dbContext model.DBReader) (*externalapi.DomainHash, error) {
-	if ps.pruningPointStaging != nil {
+	if database.IsStagingVisible(dbContext) && ps.pruningPointStaging != nil {
 		return ps.pruningPointStaging, nil
 	}
 
-	if ps.pruningPointCache != nil {
-		return ps.pruningPointCache, nil
+	if cached := ps.cachedPruningPoint(); cached != nil {
+		return cached, nil
 	}
 
 	pruningPointBytes, err := dbContext.Get(pruningBlockHashKey)
@@ -179,10 +185,38 @@ func (ps *pruningStore) PruningPoint(dbContext model.DBReader) (*externalapi.Dom
 	if err != nil {
 		return nil, err
 	}
-	ps.pruningPointCache = pruningPoint
+	ps.setCachedPruningPoint(pruningPoint)
 	return pruningPoint, nil
 }
 
+func (ps *pruningStore) cachedPruningPoint() *externalapi.DomainHash {
+	ps.cacheLock.RLock()
+	defer ps.cacheLock.RUnlock()
+
+	return ps.pruningPointCache
+}
+
+func (ps *pruningStore) setCachedPruningPoint(pruningPoint *externalapi.DomainHash) {
+	ps.cacheLock.Lock()
+	defer ps.cacheLock.Unlock()
+
+	ps.pruningPointCache = pruningPoint
+}
+
+func (ps *pruningStore) cachedPruningPointCandidate() *externalapi.DomainHash {
+	ps.cacheLock.RLock()
+	defer ps.cacheLock.RUnlock()
+
+	return ps.pruningPointCandidateCache
+}
+
+func (ps *pruningStore) setCachedPruningPointCandidate(candidate *externalapi.DomainHash) {
+	ps.cacheLock.Lock()
+	defer ps.cacheLock.Unlock()
+
+	ps.pruningPointCandidateCache = candidate
+}
+
 func (ps *pruningStore) serializeHash(hash *externalapi.DomainHash) ([]byte, error) {
 	return proto.Marshal(serialization.DomainHashToDbHash(hash))
 }
@@ -198,11 +232,11 @@ func (ps *pruningStore) deserializePruningPoint(pruningPointBytes []byte) (*exte
 }
 
 func (ps *pruningStore) HasPruningPoint(dbContext model.DBReader) (bool, error) {
-	if ps.pruningPointStaging != nil {
+	if database.IsStagingVisible(dbContext) && ps.pruningPointStaging != nil {
 		return true, nil
 	}
 
-	if ps.pruningPointCache != nil {
+	if ps.cachedPruningPoint() != nil {
 		return true, nil
 	}
 
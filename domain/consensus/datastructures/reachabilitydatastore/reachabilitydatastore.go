@@ -1,6 +1,8 @@
 package reachabilitydatastore
 
 import (
+	"sync"
+
 	"github.com/golang/protobuf/proto"
 	"github.com/kaspanet/kaspad/domain/consensus/database"
 	"github.com/kaspanet/kaspad/domain/consensus/database/serialization"
@@ -17,7 +19,11 @@ type reachabilityDataStore struct {
 	reachabilityDataStaging        map[externalapi.DomainHash]model.ReachabilityData
 	reachabilityReindexRootStaging *externalapi.DomainHash
 	reachabilityDataCache          *lrucache.LRUCache
-	reachabilityReindexRootCache   *externalapi.DomainHash
+
+	// reachabilityReindexRootCacheLock guards reachabilityReindexRootCache,
+	// which may be populated by concurrent readers
+	reachabilityReindexRootCacheLock sync.RWMutex
+	reachabilityReindexRootCache     *externalapi.DomainHash
 }
 
 // New instantiates a new ReachabilityDataStore
@@ -59,7 +65,7 @@ func (rds *reachabilityDataStore) Commit(dbTx model.DBTransaction) error {
 		if err != nil {
 			return err
 		}
-		rds.reachabilityReindexRootCache = rds.reachabilityReindexRootStaging
+		rds.setCachedReachabilityReindexRoot(rds.reachabilityReindexRootStaging)
 	}
 	for hash, reachabilityData := range rds.reachabilityDataStaging {
 		reachabilityDataBytes, err := rds.serializeReachabilityData(reachabilityData)
@@ -81,8 +87,10 @@ func (rds *reachabilityDataStore) Commit(dbTx model.DBTransaction) error {
 func (rds *reachabilityDataStore) ReachabilityData(dbContext model.DBReader,
 	blockHash *externalapi.DomainHash) (model.ReachabilityData, error) {
 
-	if reachabilityData, ok := rds.reachabilityDataStaging[*blockHash]; ok {
-		return reachabilityData, nil
+	if database.IsStagingVisible(dbContext) {
+		if reachabilityData, ok := rds.reachabilityDataStaging[*blockHash]; ok {
+			return reachabilityData, nil
+		}
 	}
 
 	if reachabilityData, ok := rds.reachabilityDataCache.Get(blockHash); ok {
@@ -103,8 +111,10 @@ func (rds *reachabilityDataStore) ReachabilityData(dbContext model.DBReader,
 }
 
 func (rds *reachabilityDataStore) HasReachabilityData(dbContext model.DBReader, blockHash *externalapi.DomainHash) (bool, error) {
-	if _, ok := rds.reachabilityDataStaging[*blockHash]; ok {
-		return true, nil
+	if database.IsStagingVisible(dbContext) {
+		if _, ok := rds.reachabilityDataStaging[*blockHash]; ok {
+			return true, nil
+		}
 	}
 
 	if rds.reachabilityDataCache.Has(blockHash) {
@@ -116,12 +126,12 @@ func (rds *reachabilityDataStore) HasReachabilityData(dbContext model.DBReader,
 
 // ReachabilityReindexRoot returns the current reachability reindex root
 func (rds *reachabilityDataStore) ReachabilityReindexRoot(dbContext model.DBReader) (*externalapi.DomainHash, error) {
-	if rds.reachabilityReindexRootStaging != nil {
+	if database.IsStagingVisible(dbContext) && rds.reachabilityReindexRootStaging != nil {
 		return rds.reachabilityReindexRootStaging, nil
 	}
 
-	if rds.reachabilityReindexRootCache != nil {
-		return rds.reachabilityReindexRootCache, nil
+	if reachabilityReindexRootCache := rds.cachedReachabilityReindexRoot(); reachabilityReindexRootCache != nil {
+		return reachabilityReindexRootCache, nil
 	}
 
 	reachabilityReindexRootBytes, err := dbContext.Get(reachabilityReindexRootKey)
@@ -133,10 +143,24 @@ func (rds *reachabilityDataStore) ReachabilityReindexRoot(dbContext model.DBRead
 	if err != nil {
 		return nil, err
 	}
-	rds.reachabilityReindexRootCache = reachabilityReindexRoot
+	rds.setCachedReachabilityReindexRoot(reachabilityReindexRoot)
 	return reachabilityReindexRoot, nil
 }
 
+func (rds *reachabilityDataStore) cachedReachabilityReindexRoot() *externalapi.DomainHash {
+	rds.reachabilityReindexRootCacheLock.RLock()
+	defer rds.reachabilityReindexRootCacheLock.RUnlock()
+
+	return rds.reachabilityReindexRootCache
+}
+
+func (rds *reachabilityDataStore) setCachedReachabilityReindexRoot(reachabilityReindexRoot *externalapi.DomainHash) {
+	rds.reachabilityReindexRootCacheLock.Lock()
+	defer rds.reachabilityReindexRootCacheLock.Unlock()
+
+	rds.reachabilityReindexRootCache = reachabilityReindexRoot
+}
+
 func (rds *reachabilityDataStore) reachabilityDataBlockHashAsKey(hash *externalapi.DomainHash) model.DBKey {
 	return reachabilityDataBucket.Key(hash.ByteSlice())
 }
@@ -108,8 +108,10 @@ func (uds *utxoDiffStore) Commit(dbTx model.DBTransaction) error {
 
 // UTXODiff gets the utxoDiff associated with the given blockHash
 func (uds *utxoDiffStore) UTXODiff(dbContext model.DBReader, blockHash *externalapi.DomainHash) (externalapi.UTXODiff, error) {
-	if utxoDiff, ok := uds.utxoDiffStaging[*blockHash]; ok {
-		return utxoDiff, nil
+	if database.IsStagingVisible(dbContext) {
+		if utxoDiff, ok := uds.utxoDiffStaging[*blockHash]; ok {
+			return utxoDiff, nil
+		}
 	}
 
 	if utxoDiff, ok := uds.utxoDiffCache.Get(blockHash); ok {
@@ -131,8 +133,10 @@ func (uds *utxoDiffStore) UTXODiff(dbContext model.DBReader, blockHash *external
 
 // UTXODiffChild gets the utxoDiff child associated with the given blockHash
 func (uds *utxoDiffStore) UTXODiffChild(dbContext model.DBReader, blockHash *externalapi.DomainHash) (*externalapi.DomainHash, error) {
-	if utxoDiffChild, ok := uds.utxoDiffChildStaging[*blockHash]; ok {
-		return utxoDiffChild, nil
+	if database.IsStagingVisible(dbContext) {
+		if utxoDiffChild, ok := uds.utxoDiffChildStaging[*blockHash]; ok {
+			return utxoDiffChild, nil
+		}
 	}
 
 	if utxoDiffChild, ok := uds.utxoDiffChildCache.Get(blockHash); ok {
@@ -154,8 +158,10 @@ func (uds *utxoDiffStore) UTXODiffChild(dbContext model.DBReader, blockHash *ext
 
 // HasUTXODiffChild returns true if the given blockHash has a UTXODiffChild
 func (uds *utxoDiffStore) HasUTXODiffChild(dbContext model.DBReader, blockHash *externalapi.DomainHash) (bool, error) {
-	if _, ok := uds.utxoDiffChildStaging[*blockHash]; ok {
-		return true, nil
+	if database.IsStagingVisible(dbContext) {
+		if _, ok := uds.utxoDiffChildStaging[*blockHash]; ok {
+			return true, nil
+		}
 	}
 
 	if uds.utxoDiffChildCache.Has(blockHash) {
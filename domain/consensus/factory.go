@@ -127,49 +127,114 @@ func (f *factory) NewConsensus(dagParams *dagconfig.Params, db infrastructuredat
 	finalityStore := finalitystore.New(200, preallocateCaches)
 	headersSelectedChainStore := headersselectedchainstore.New(pruningWindowSizeForCaches, preallocateCaches)
 
-	// Processes
+	stateLock := &stateLock{}
+	c := &consensus{
+		lock:            stateLock,
+		writeLock:       &sync.Mutex{},
+		databaseContext: consensusdatabase.NewLockingDBManager(dbManager, stateLock),
+
+		acceptanceDataStore:       acceptanceDataStore,
+		blockStore:                blockStore,
+		blockHeaderStore:          blockHeaderStore,
+		pruningStore:              pruningStore,
+		ghostdagDataStore:         ghostdagDataStore,
+		blockStatusStore:          blockStatusStore,
+		blockRelationStore:        blockRelationStore,
+		consensusStateStore:       consensusStateStore,
+		headersSelectedTipStore:   headersSelectedTipStore,
+		multisetStore:             multisetStore,
+		reachabilityDataStore:     reachabilityDataStore,
+		utxoDiffStore:             utxoDiffStore,
+		finalityStore:             finalityStore,
+		headersSelectedChainStore: headersSelectedChainStore,
+	}
+	err = f.initProcesses(c, dagParams, isArchivalNode)
+	if err != nil {
+		return nil, err
+	}
+
+	// The committed state shares the stores of c, but reads them without
+	// seeing what c staged, so that queries could run while c validates blocks
+	committedState := *c
+	committedState.databaseContext = consensusdatabase.NewCommittedStateReader(dbManager)
+	err = f.initProcesses(&committedState, dagParams, isArchivalNode)
+	if err != nil {
+		return nil, err
+	}
+	c.committedState = &committedState
+
+	genesisInfo, err := c.GetBlockInfo(dagParams.GenesisHash)
+	if err != nil {
+		return nil, err
+	}
+
+	if !genesisInfo.Exists {
+		_, err = c.ValidateAndInsertBlock(dagParams.GenesisBlock)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = c.consensusStateManager.RecoverUTXOIfRequired()
+	if err != nil {
+		return nil, err
+	}
+	err = c.pruningManager.ClearImportedPruningPointData()
+	if err != nil {
+		return nil, err
+	}
+	err = c.pruningManager.UpdatePruningPointUTXOSetIfRequired()
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// initProcesses creates the processes of c over its stores and databaseContext
+func (f *factory) initProcesses(c *consensus, dagParams *dagconfig.Params, isArchivalNode bool) error {
 	reachabilityManager := reachabilitymanager.New(
-		dbManager,
-		ghostdagDataStore,
-		reachabilityDataStore)
+		c.databaseContext,
+		c.ghostdagDataStore,
+		c.reachabilityDataStore)
 	dagTopologyManager := dagtopologymanager.New(
-		dbManager,
+		c.databaseContext,
 		reachabilityManager,
-		blockRelationStore,
-		ghostdagDataStore)
+		c.blockRelationStore,
+		c.ghostdagDataStore)
 	ghostdagManager := f.ghostdagConstructor(
-		dbManager,
+		c.databaseContext,
 		dagTopologyManager,
-		ghostdagDataStore,
-		blockHeaderStore,
+		c.ghostdagDataStore,
+		c.blockHeaderStore,
 		dagParams.K)
 	dagTraversalManager := dagtraversalmanager.New(
-		dbManager,
+		c.databaseContext,
 		dagTopologyManager,
-		ghostdagDataStore,
-		reachabilityDataStore,
+		c.ghostdagDataStore,
+		c.reachabilityDataStore,
 		ghostdagManager,
-		consensusStateStore)
+		c.consensusStateStore)
 	pastMedianTimeManager := f.pastMedianTimeConsructor(
 		dagParams.TimestampDeviationTolerance,
-		dbManager,
+		c.databaseContext,
 		dagTraversalManager,
-		blockHeaderStore,
-		ghostdagDataStore)
+		c.blockHeaderStore,
+		c.ghostdagDataStore)
 	transactionValidator := transactionvalidator.New(dagParams.BlockCoinbaseMaturity,
 		dagParams.EnableNonNativeSubnetworks,
 		dagParams.MassPerTxByte,
 		dagParams.MassPerScriptPubKeyByte,
 		dagParams.MassPerSigOp,
 		dagParams.MaxCoinbasePayloadLength,
-		dbManager,
+		c.databaseContext,
 		pastMedianTimeManager,
-		ghostdagDataStore)
+		c.ghostdagDataStore)
 	difficultyManager := f.difficultyConstructor(
-		dbManager,
+		c.databaseContext,
 		ghostdagManager,
-		ghostdagDataStore,
-		blockHeaderStore,
+		c.ghostdagDataStore,
+		c.blockHeaderStore,
 		dagTopologyManager,
 		dagTraversalManager,
 		dagParams.PowMax,
@@ -178,28 +243,28 @@ func (f *factory) NewConsensus(dagParams *dagconfig.Params, db infrastructuredat
 		dagParams.TargetTimePerBlock,
 		dagParams.GenesisHash)
 	coinbaseManager := coinbasemanager.New(
-		dbManager,
+		c.databaseContext,
 		dagParams.SubsidyReductionInterval,
 		dagParams.BaseSubsidy,
 		dagParams.CoinbasePayloadScriptPublicKeyMaxLength,
-		ghostdagDataStore,
-		acceptanceDataStore)
-	headerTipsManager := headersselectedtipmanager.New(dbManager, dagTopologyManager, dagTraversalManager,
-		ghostdagManager, headersSelectedTipStore, headersSelectedChainStore)
+		c.ghostdagDataStore,
+		c.acceptanceDataStore)
+	headerTipsManager := headersselectedtipmanager.New(c.databaseContext, dagTopologyManager, dagTraversalManager,
+		ghostdagManager, c.headersSelectedTipStore, c.headersSelectedChainStore)
 	genesisHash := dagParams.GenesisHash
 	finalityManager := finalitymanager.New(
-		dbManager,
+		c.databaseContext,
 		dagTopologyManager,
-		finalityStore,
-		ghostdagDataStore,
+		c.finalityStore,
+		c.ghostdagDataStore,
 		genesisHash,
 		dagParams.FinalityDepth())
 	mergeDepthManager := mergedepthmanager.New(
-		dbManager,
+		c.databaseContext,
 		dagTopologyManager,
 		dagTraversalManager,
 		finalityManager,
-		ghostdagDataStore)
+		c.ghostdagDataStore)
 	blockValidator := blockvalidator.New(
 		dagParams.PowMax,
 		dagParams.SkipProofOfWork,
@@ -211,7 +276,7 @@ func (f *factory) NewConsensus(dagParams *dagconfig.Params, db infrastructuredat
 		dagParams.TimestampDeviationTolerance,
 		dagParams.TargetTimePerBlock,
 
-		dbManager,
+		c.databaseContext,
 		difficultyManager,
 		pastMedianTimeManager,
 		transactionValidator,
@@ -222,16 +287,16 @@ func (f *factory) NewConsensus(dagParams *dagconfig.Params, db infrastructuredat
 		mergeDepthManager,
 		reachabilityManager,
 
-		pruningStore,
-		blockStore,
-		ghostdagDataStore,
-		blockHeaderStore,
-		blockStatusStore,
-		reachabilityDataStore,
-		consensusStateStore,
+		c.pruningStore,
+		c.blockStore,
+		c.ghostdagDataStore,
+		c.blockHeaderStore,
+		c.blockStatusStore,
+		c.reachabilityDataStore,
+		c.consensusStateStore,
 	)
 	consensusStateManager, err := consensusstatemanager.New(
-		dbManager,
+		c.databaseContext,
 		dagParams.PruningDepth(),
 		dagParams.MaxMassAcceptedByBlock,
 		dagParams.MaxBlockParents,
@@ -249,58 +314,58 @@ func (f *factory) NewConsensus(dagParams *dagconfig.Params, db infrastructuredat
 		mergeDepthManager,
 		finalityManager,
 
-		blockStatusStore,
-		ghostdagDataStore,
-		consensusStateStore,
-		multisetStore,
-		blockStore,
-		utxoDiffStore,
-		blockRelationStore,
-		acceptanceDataStore,
-		blockHeaderStore,
-		headersSelectedTipStore,
-		pruningStore)
+		c.blockStatusStore,
+		c.ghostdagDataStore,
+		c.consensusStateStore,
+		c.multisetStore,
+		c.blockStore,
+		c.utxoDiffStore,
+		c.blockRelationStore,
+		c.acceptanceDataStore,
+		c.blockHeaderStore,
+		c.headersSelectedTipStore,
+		c.pruningStore)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	pruningManager := pruningmanager.New(
-		dbManager,
+		c.databaseContext,
 		dagTraversalManager,
 		dagTopologyManager,
 		consensusStateManager,
-		consensusStateStore,
-		ghostdagDataStore,
-		pruningStore,
-		blockStatusStore,
-		headersSelectedTipStore,
-		multisetStore,
-		acceptanceDataStore,
-		blockStore,
-		blockHeaderStore,
-		utxoDiffStore,
+		c.consensusStateStore,
+		c.ghostdagDataStore,
+		c.pruningStore,
+		c.blockStatusStore,
+		c.headersSelectedTipStore,
+		c.multisetStore,
+		c.acceptanceDataStore,
+		c.blockStore,
+		c.blockHeaderStore,
+		c.utxoDiffStore,
 		isArchivalNode,
 		genesisHash,
 		dagParams.FinalityDepth(),
 		dagParams.PruningDepth())
 
 	syncManager := syncmanager.New(
-		dbManager,
+		c.databaseContext,
 		genesisHash,
 		dagTraversalManager,
 		dagTopologyManager,
 		ghostdagManager,
 		pruningManager,
 
-		ghostdagDataStore,
-		blockStatusStore,
-		blockHeaderStore,
-		blockStore,
-		pruningStore,
-		headersSelectedChainStore)
+		c.ghostdagDataStore,
+		c.blockStatusStore,
+		c.blockHeaderStore,
+		c.blockStore,
+		c.pruningStore,
+		c.headersSelectedChainStore)
 
 	blockBuilder := blockbuilder.New(
-		dbManager,
+		c.databaseContext,
 		difficultyManager,
 		pastMedianTimeManager,
 		coinbaseManager,
@@ -308,16 +373,16 @@ func (f *factory) NewConsensus(dagParams *dagconfig.Params, db infrastructuredat
 		ghostdagManager,
 		transactionValidator,
 
-		acceptanceDataStore,
-		blockRelationStore,
-		multisetStore,
-		ghostdagDataStore,
+		c.acceptanceDataStore,
+		c.blockRelationStore,
+		c.multisetStore,
+		c.ghostdagDataStore,
 	)
 
 	blockProcessor := blockprocessor.New(
 		genesisHash,
 		dagParams.TargetTimePerBlock,
-		dbManager,
+		c.databaseContext,
 		consensusStateManager,
 		pruningManager,
 		blockValidator,
@@ -330,85 +395,40 @@ func (f *factory) NewConsensus(dagParams *dagconfig.Params, db infrastructuredat
 		headerTipsManager,
 		syncManager,
 
-		acceptanceDataStore,
-		blockStore,
-		blockStatusStore,
-		blockRelationStore,
-		multisetStore,
-		ghostdagDataStore,
-		consensusStateStore,
-		pruningStore,
-		reachabilityDataStore,
-		utxoDiffStore,
-		blockHeaderStore,
-		headersSelectedTipStore,
-		finalityStore,
-		headersSelectedChainStore)
-
-	c := &consensus{
-		lock:            &sync.Mutex{},
-		databaseContext: dbManager,
-
-		blockProcessor:        blockProcessor,
-		blockBuilder:          blockBuilder,
-		consensusStateManager: consensusStateManager,
-		transactionValidator:  transactionValidator,
-		syncManager:           syncManager,
-		pastMedianTimeManager: pastMedianTimeManager,
-		blockValidator:        blockValidator,
-		coinbaseManager:       coinbaseManager,
-		dagTopologyManager:    dagTopologyManager,
-		dagTraversalManager:   dagTraversalManager,
-		difficultyManager:     difficultyManager,
-		ghostdagManager:       ghostdagManager,
-		headerTipsManager:     headerTipsManager,
-		mergeDepthManager:     mergeDepthManager,
-		pruningManager:        pruningManager,
-		reachabilityManager:   reachabilityManager,
-		finalityManager:       finalityManager,
-
-		acceptanceDataStore:       acceptanceDataStore,
-		blockStore:                blockStore,
-		blockHeaderStore:          blockHeaderStore,
-		pruningStore:              pruningStore,
-		ghostdagDataStore:         ghostdagDataStore,
-		blockStatusStore:          blockStatusStore,
-		blockRelationStore:        blockRelationStore,
-		consensusStateStore:       consensusStateStore,
-		headersSelectedTipStore:   headersSelectedTipStore,
-		multisetStore:             multisetStore,
-		reachabilityDataStore:     reachabilityDataStore,
-		utxoDiffStore:             utxoDiffStore,
-		finalityStore:             finalityStore,
-		headersSelectedChainStore: headersSelectedChainStore,
-	}
-
-	genesisInfo, err := c.GetBlockInfo(genesisHash)
-	if err != nil {
-		return nil, err
-	}
-
-	if !genesisInfo.Exists {
-		_, err = c.ValidateAndInsertBlock(dagParams.GenesisBlock)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	err = consensusStateManager.RecoverUTXOIfRequired()
-	if err != nil {
-		return nil, err
-	}
-	err = pruningManager.ClearImportedPruningPointData()
-	if err != nil {
-		return nil, err
-	}
-	err = pruningManager.UpdatePruningPointUTXOSetIfRequired()
-	if err != nil {
-		return nil, err
-	}
-
-	return c, nil
+		c.acceptanceDataStore,
+		c.blockStore,
+		c.blockStatusStore,
+		c.blockRelationStore,
+		c.multisetStore,
+		c.ghostdagDataStore,
+		c.consensusStateStore,
+		c.pruningStore,
+		c.reachabilityDataStore,
+		c.utxoDiffStore,
+		c.blockHeaderStore,
+		c.headersSelectedTipStore,
+		c.finalityStore,
+		c.headersSelectedChainStore)
+
+	c.blockProcessor = blockProcessor
+	c.blockBuilder = blockBuilder
+	c.consensusStateManager = consensusStateManager
+	c.transactionValidator = transactionValidator
+	c.syncManager = syncManager
+	c.pastMedianTimeManager = pastMedianTimeManager
+	c.blockValidator = blockValidator
+	c.coinbaseManager = coinbaseManager
+	c.dagTopologyManager = dagTopologyManager
+	c.dagTraversalManager = dagTraversalManager
+	c.difficultyManager = difficultyManager
+	c.ghostdagManager = ghostdagManager
+	c.headerTipsManager = headerTipsManager
+	c.mergeDepthManager = mergeDepthManager
+	c.pruningManager = pruningManager
+	c.reachabilityManager = reachabilityManager
+	c.finalityManager = finalityManager
+
+	return nil
 }
 
 func (f *factory) NewTestConsensus(dagParams *dagconfig.Params, isArchivalNode bool, testName string) (
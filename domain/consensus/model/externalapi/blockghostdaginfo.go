@@ -0,0 +1,8 @@
+package externalapi
+
+// BlockGHOSTDAGInfo represents the GHOSTDAG data of a block needed by external components
+type BlockGHOSTDAGInfo struct {
+	SelectedParent *DomainHash
+	MergeSetBlues  []*DomainHash
+	MergeSetReds   []*DomainHash
+}
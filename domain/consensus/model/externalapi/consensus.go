@@ -4,11 +4,13 @@ package externalapi
 type Consensus interface {
 	BuildBlock(coinbaseData *DomainCoinbaseData, transactions []*DomainTransaction) (*DomainBlock, error)
 	ValidateAndInsertBlock(block *DomainBlock) (*BlockInsertionResult, error)
+	ValidateAndInsertBlocks(blocks []*DomainBlock) ([]*BlockInsertionResult, error)
 	ValidateTransactionAndPopulateWithConsensusData(transaction *DomainTransaction) error
 
 	GetBlock(blockHash *DomainHash) (*DomainBlock, error)
 	GetBlockHeader(blockHash *DomainHash) (BlockHeader, error)
 	GetBlockInfo(blockHash *DomainHash) (*BlockInfo, error)
+	GetBlockGHOSTDAGInfo(blockHash *DomainHash) (*BlockGHOSTDAGInfo, error)
 	GetBlockChildren(blockHash *DomainHash) ([]*DomainHash, error)
 	GetBlockAcceptanceData(blockHash *DomainHash) (AcceptanceData, error)
 
@@ -30,6 +32,8 @@ type Consensus interface {
 	IsValidPruningPoint(blockHash *DomainHash) (bool, error)
 	GetVirtualSelectedParentChainFromBlock(blockHash *DomainHash) (*SelectedChainPath, error)
 	IsInSelectedParentChainOf(blockHashA *DomainHash, blockHashB *DomainHash) (bool, error)
+	IsAncestorOf(blockHashA *DomainHash, blockHashB *DomainHash) (bool, error)
 	GetHeadersSelectedTip() (*DomainHash, error)
 	Anticone(blockHash *DomainHash) ([]*DomainHash, error)
+	IsFinalizedBlock(blockHash *DomainHash) (bool, error)
 }
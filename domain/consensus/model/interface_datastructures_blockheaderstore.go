@@ -11,5 +11,5 @@ type BlockHeaderStore interface {
 	HasBlockHeader(dbContext DBReader, blockHash *externalapi.DomainHash) (bool, error)
 	BlockHeaders(dbContext DBReader, blockHashes []*externalapi.DomainHash) ([]externalapi.BlockHeader, error)
 	Delete(blockHash *externalapi.DomainHash)
-	Count() uint64
+	Count(dbContext DBReader) uint64
 }
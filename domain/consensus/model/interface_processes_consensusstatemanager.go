@@ -5,6 +5,8 @@ import "github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 // ConsensusStateManager manages the node's consensus state
 type ConsensusStateManager interface {
 	AddBlock(blockHash *externalapi.DomainHash) (*externalapi.SelectedChainPath, externalapi.UTXODiff, error)
+	AddBlockWithoutUpdatingVirtual(blockHash *externalapi.DomainHash) error
+	UpdateVirtualAfterAddingBlocks(blockHashes []*externalapi.DomainHash) (*externalapi.SelectedChainPath, externalapi.UTXODiff, bool, error)
 	PopulateTransactionWithUTXOEntries(transaction *externalapi.DomainTransaction) error
 	ImportPruningPoint(newPruningPoint *externalapi.DomainBlock) error
 	RestorePastUTXOSetIterator(blockHash *externalapi.DomainHash) (externalapi.ReadOnlyUTXOSetIterator, error)
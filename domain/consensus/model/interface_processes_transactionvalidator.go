@@ -10,4 +10,7 @@ type TransactionValidator interface {
 	ValidateTransactionInIsolation(transaction *externalapi.DomainTransaction) error
 	ValidateTransactionInContextAndPopulateMassAndFee(tx *externalapi.DomainTransaction,
 		povBlockHash *externalapi.DomainHash, selectedParentMedianTime int64) error
+	ValidateTransactionInContextIgnoringScriptsAndPopulateMassAndFee(tx *externalapi.DomainTransaction,
+		povBlockHash *externalapi.DomainHash, selectedParentMedianTime int64) error
+	ValidateTransactionsScripts(txs []*externalapi.DomainTransaction) error
 }
@@ -141,6 +141,15 @@ func (bp *blockProcessor) ValidateAndInsertBlock(block *externalapi.DomainBlock)
 	return bp.validateAndInsertBlock(block, false)
 }
 
+// ValidateAndInsertBlocks validates the given topologically ordered blocks and,
+// if valid, applies them to the current state
+func (bp *blockProcessor) ValidateAndInsertBlocks(blocks []*externalapi.DomainBlock) ([]*externalapi.BlockInsertionResult, error) {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "ValidateAndInsertBlocks")
+	defer onEnd()
+
+	return bp.validateAndInsertBlocks(blocks)
+}
+
 func (bp *blockProcessor) ValidateAndInsertImportedPruningPoint(newPruningPoint *externalapi.DomainBlock) error {
 	onEnd := logger.LogAndMeasureExecutionTime(log, "ValidateAndInsertImportedPruningPoint")
 	defer onEnd()
@@ -135,8 +135,8 @@ func (bp *blockProcessor) validateAndInsertBlock(block *externalapi.DomainBlock,
 			logClosureErr = err
 			return fmt.Sprintf("Failed to get virtual GHOSTDAG data: %s", err)
 		}
-		headerCount := bp.blockHeaderStore.Count()
-		blockCount := bp.blockStore.Count()
+		headerCount := bp.blockHeaderStore.Count(bp.databaseContext)
+		blockCount := bp.blockStore.Count(bp.databaseContext)
 		return fmt.Sprintf("New virtual's blue score: %d. Block count: %d. Header count: %d",
 			virtualGhostDAGData.BlueScore(), blockCount, headerCount)
 	}))
@@ -307,6 +307,7 @@ func (bp *blockProcessor) commitAllChanges() error {
 	if err != nil {
 		return err
 	}
+	defer dbTx.RollbackUnlessClosed()
 
 	for _, store := range bp.stores {
 		err = store.Commit(dbTx)
@@ -0,0 +1,184 @@
+package blockprocessor
+
+import (
+	"github.com/kaspanet/kaspad/domain/consensus/model"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/ruleerrors"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/utxo"
+	"github.com/pkg/errors"
+)
+
+// validateAndInsertBlocks first attempts to insert all the given blocks at once: every block is
+// validated and staged on top of the ones that precede it, the virtual is resolved only once
+// after all of them, and everything is committed in a single database transaction.
+//
+// Whenever inserting the blocks at once cannot be guaranteed to have the same outcome as
+// inserting them one by one - most notably, when one of them fails validation - all the staged
+// changes are discarded and the blocks are inserted one by one instead. That way, the blocks
+// preceding an invalid block are inserted, and the invalid block is rejected with the exact same
+// error (and stored with the exact same status) as it would have been by ValidateAndInsertBlock.
+//
+// Note that when the blocks are inserted at once, blocks that would have been the virtual selected
+// parent only temporarily (had they been inserted one by one) might remain with
+// StatusUTXOPendingVerification, the same as any other block that was never a candidate to be
+// the virtual selected parent.
+func (bp *blockProcessor) validateAndInsertBlocks(blocks []*externalapi.DomainBlock) (
+	[]*externalapi.BlockInsertionResult, error) {
+
+	blockInsertionResults, wereInserted, err := bp.validateAndInsertBlocksAtOnce(blocks)
+	if err != nil {
+		bp.discardAllChanges()
+		return nil, err
+	}
+	if wereInserted {
+		return blockInsertionResults, nil
+	}
+	bp.discardAllChanges()
+
+	log.Debugf("Could not insert %d blocks at once. Inserting them one by one", len(blocks))
+	blockInsertionResults = make([]*externalapi.BlockInsertionResult, 0, len(blocks))
+	for _, block := range blocks {
+		blockInsertionResult, err := bp.validateAndInsertBlock(block, false)
+		if err != nil {
+			return blockInsertionResults, err
+		}
+		blockInsertionResults = append(blockInsertionResults, blockInsertionResult)
+	}
+	return blockInsertionResults, nil
+}
+
+// validateAndInsertBlocksAtOnce returns wereInserted=false without an error if the
+// blocks cannot be inserted at once. In that case, the caller is expected to discard
+// all the staged changes
+func (bp *blockProcessor) validateAndInsertBlocksAtOnce(blocks []*externalapi.DomainBlock) (
+	blockInsertionResults []*externalapi.BlockInsertionResult, wereInserted bool, err error) {
+
+	if len(blocks) == 0 {
+		return []*externalapi.BlockInsertionResult{}, true, nil
+	}
+	for _, block := range blocks {
+		if consensushashing.BlockHash(block).Equal(bp.genesisHash) {
+			log.Debugf("The genesis cannot be inserted along with other blocks")
+			return nil, false, nil
+		}
+	}
+
+	oldPruningPoint, err := bp.pruningStore.PruningPoint(bp.databaseContext)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var blockWithBodyHashes []*externalapi.DomainHash
+	for _, block := range blocks {
+		blockHash := consensushashing.BlockHash(block)
+		_, err := bp.validateBlockWithoutMarkingInvalid(block, false)
+		if err != nil {
+			if errors.As(err, &ruleerrors.RuleError{}) {
+				log.Debugf("Block %s failed validation: %s", blockHash, err)
+				return nil, false, nil
+			}
+			return nil, false, err
+		}
+
+		err = bp.setBlockStatusAfterBlockValidation(block, false)
+		if err != nil {
+			return nil, false, err
+		}
+
+		oldHeadersSelectedTip, err := bp.headersSelectedTipStore.HeadersSelectedTip(bp.databaseContext)
+		if err != nil {
+			return nil, false, err
+		}
+
+		err = bp.headerTipsManager.AddHeaderTip(blockHash)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if !isHeaderOnlyBlock(block) {
+			err = bp.consensusStateManager.AddBlockWithoutUpdatingVirtual(blockHash)
+			if err != nil {
+				return nil, false, err
+			}
+			blockWithBodyHashes = append(blockWithBodyHashes, blockHash)
+		}
+
+		err = bp.updateReachabilityReindexRoot(oldHeadersSelectedTip)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	var selectedParentChainChanges *externalapi.SelectedChainPath
+	var virtualUTXODiff externalapi.UTXODiff
+	if len(blockWithBodyHashes) > 0 {
+		var wasVirtualUpdated bool
+		selectedParentChainChanges, virtualUTXODiff, wasVirtualUpdated, err =
+			bp.consensusStateManager.UpdateVirtualAfterAddingBlocks(blockWithBodyHashes)
+		if err != nil {
+			return nil, false, err
+		}
+		if !wasVirtualUpdated {
+			return nil, false, nil
+		}
+
+		err = bp.pruningManager.UpdatePruningPointByVirtual()
+		if err != nil {
+			return nil, false, err
+		}
+
+		// When inserting the blocks one by one, the blocks that follow a pruning point
+		// movement are validated against the new pruning point
+		newPruningPoint, err := bp.pruningStore.PruningPoint(bp.databaseContext)
+		if err != nil {
+			return nil, false, err
+		}
+		if !newPruningPoint.Equal(oldPruningPoint) {
+			log.Debugf("The pruning point moved from %s to %s", oldPruningPoint, newPruningPoint)
+			return nil, false, nil
+		}
+	}
+
+	err = bp.commitAllChanges()
+	if err != nil {
+		return nil, false, err
+	}
+
+	err = bp.pruningManager.UpdatePruningPointUTXOSetIfRequired()
+	if err != nil {
+		return nil, false, err
+	}
+
+	virtualParents, err := bp.dagTopologyManager.Parents(model.VirtualBlockHash)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// The virtual changes are attributed to the last block with a body, since the virtual
+	// was updated only after it. The blocks with a body that precede it get empty changes
+	var lastBlockWithBodyHash *externalapi.DomainHash
+	if len(blockWithBodyHashes) > 0 {
+		lastBlockWithBodyHash = blockWithBodyHashes[len(blockWithBodyHashes)-1]
+	}
+	blockInsertionResults = make([]*externalapi.BlockInsertionResult, len(blocks))
+	for i, block := range blocks {
+		blockInsertionResult := &externalapi.BlockInsertionResult{VirtualParents: virtualParents}
+		if !isHeaderOnlyBlock(block) {
+			if consensushashing.BlockHash(block).Equal(lastBlockWithBodyHash) {
+				blockInsertionResult.VirtualSelectedParentChainChanges = selectedParentChainChanges
+				blockInsertionResult.VirtualUTXODiff = virtualUTXODiff
+			} else {
+				blockInsertionResult.VirtualSelectedParentChainChanges = &externalapi.SelectedChainPath{}
+				blockInsertionResult.VirtualUTXODiff = utxo.NewUTXODiff()
+			}
+		}
+		blockInsertionResults[i] = blockInsertionResult
+
+		bp.blockLogger.LogBlock(block)
+	}
+
+	log.Debugf("%d blocks were validated and inserted at once", len(blocks))
+
+	return blockInsertionResults, true, nil
+}
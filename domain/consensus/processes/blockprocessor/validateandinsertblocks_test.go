@@ -0,0 +1,334 @@
+package blockprocessor_test
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/domain/consensus"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/model/testapi"
+	"github.com/kaspanet/kaspad/domain/consensus/ruleerrors"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/blockheader"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/constants"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/merkle"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/testutils"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/pkg/errors"
+)
+
+func TestValidateAndInsertBlocks(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, params *dagconfig.Params) {
+		factory := consensus.NewFactory()
+		tcSource, teardownSource, err := factory.NewTestConsensus(params, false, "TestValidateAndInsertBlocks_Source")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardownSource(false)
+		tcSerial, teardownSerial, err := factory.NewTestConsensus(params, false, "TestValidateAndInsertBlocks_Serial")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardownSerial(false)
+		tcBatch, teardownBatch, err := factory.NewTestConsensus(params, false, "TestValidateAndInsertBlocks_Batch")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardownBatch(false)
+
+		var blocks []*externalapi.DomainBlock
+		addBlock := func(parentHashes ...*externalapi.DomainHash) *externalapi.DomainHash {
+			blockHash, _, err := tcSource.AddBlock(parentHashes, nil, nil)
+			if err != nil {
+				t.Fatalf("AddBlock: %+v", err)
+			}
+			block, err := tcSource.GetBlock(blockHash)
+			if err != nil {
+				t.Fatalf("GetBlock: %+v", err)
+			}
+			blocks = append(blocks, block)
+			return blockHash
+		}
+
+		// Build a chain that occasionally merges side blocks
+		tipHash := params.GenesisHash
+		for i := 0; i < 20; i++ {
+			if i%4 == 3 {
+				sideBlockHash := addBlock(tipHash)
+				tipHash = addBlock(tipHash)
+				tipHash = addBlock(tipHash, sideBlockHash)
+				continue
+			}
+			tipHash = addBlock(tipHash)
+		}
+		mainChainBlocks := blocks
+
+		for _, block := range mainChainBlocks {
+			_, err := tcSerial.ValidateAndInsertBlock(block)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertBlock: %+v", err)
+			}
+		}
+		const batchSize = 7
+		for offset := 0; offset < len(mainChainBlocks); offset += batchSize {
+			end := offset + batchSize
+			if end > len(mainChainBlocks) {
+				end = len(mainChainBlocks)
+			}
+			batch := mainChainBlocks[offset:end]
+			blockInsertionResults, err := tcBatch.ValidateAndInsertBlocks(batch)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertBlocks: %+v", err)
+			}
+			if len(blockInsertionResults) != len(batch) {
+				t.Fatalf("Expected %d insertion results but got %d", len(batch), len(blockInsertionResults))
+			}
+
+			// The virtual is resolved only after the last block, so all the chain
+			// changes of the batch, which contains several chain blocks, are attributed to it
+			lastChainChanges := blockInsertionResults[len(batch)-1].VirtualSelectedParentChainChanges
+			if len(batch) == batchSize && len(lastChainChanges.Added) < 2 {
+				t.Fatalf("Expected the last block of the batch to add several blocks to the "+
+					"selected parent chain, but it added %d", len(lastChainChanges.Added))
+			}
+		}
+		compareConsensusStates(t, tcSerial, tcBatch, mainChainBlocks)
+
+		// Build a longer competing chain, whose insertion re-organizes the virtual
+		// selected parent chain, so the blocks have to be inserted one by one
+		competingTipHash := params.GenesisHash
+		for i := 0; i < len(mainChainBlocks)+5; i++ {
+			competingTipHash = addBlock(competingTipHash)
+		}
+		competingChainBlocks := blocks[len(mainChainBlocks):]
+
+		for _, block := range competingChainBlocks {
+			_, err := tcSerial.ValidateAndInsertBlock(block)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertBlock: %+v", err)
+			}
+		}
+		blockInsertionResults, err := tcBatch.ValidateAndInsertBlocks(competingChainBlocks)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertBlocks: %+v", err)
+		}
+		if len(blockInsertionResults) != len(competingChainBlocks) {
+			t.Fatalf("Expected %d insertion results but got %d",
+				len(competingChainBlocks), len(blockInsertionResults))
+		}
+		virtualSelectedParent, err := tcBatch.GetVirtualSelectedParent()
+		if err != nil {
+			t.Fatalf("GetVirtualSelectedParent: %+v", err)
+		}
+		if !virtualSelectedParent.Equal(competingTipHash) {
+			t.Fatalf("Expected the virtual selected parent to be %s but got %s", competingTipHash, virtualSelectedParent)
+		}
+		compareConsensusStates(t, tcSerial, tcBatch, blocks)
+	})
+}
+
+func TestValidateAndInsertBlocksWithInvalidBlock(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, params *dagconfig.Params) {
+		factory := consensus.NewFactory()
+		tcSource, teardownSource, err := factory.NewTestConsensus(params, false,
+			"TestValidateAndInsertBlocksWithInvalidBlock_Source")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardownSource(false)
+		tcSerial, teardownSerial, err := factory.NewTestConsensus(params, false,
+			"TestValidateAndInsertBlocksWithInvalidBlock_Serial")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardownSerial(false)
+		tcBatch, teardownBatch, err := factory.NewTestConsensus(params, false,
+			"TestValidateAndInsertBlocksWithInvalidBlock_Batch")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardownBatch(false)
+
+		var blocks []*externalapi.DomainBlock
+		tipHash := params.GenesisHash
+		for i := 0; i < 6; i++ {
+			tipHash, _, err = tcSource.AddBlock([]*externalapi.DomainHash{tipHash}, nil, nil)
+			if err != nil {
+				t.Fatalf("AddBlock: %+v", err)
+			}
+			block, err := tcSource.GetBlock(tipHash)
+			if err != nil {
+				t.Fatalf("GetBlock: %+v", err)
+			}
+			blocks = append(blocks, block)
+		}
+
+		const invalidBlockIndex = 3
+		invalidBlock, _, err := tcSource.BuildBlockWithParents(
+			[]*externalapi.DomainHash{consensushashing.BlockHash(blocks[invalidBlockIndex-1])}, nil, nil)
+		if err != nil {
+			t.Fatalf("BuildBlockWithParents: %+v", err)
+		}
+		invalidBlock.Transactions[0].Version = constants.MaxTransactionVersion + 1 // This should invalidate the block
+		invalidBlock.Header = blockheader.NewImmutableBlockHeader(
+			invalidBlock.Header.Version(),
+			invalidBlock.Header.ParentHashes(),
+			merkle.CalculateHashMerkleRoot(invalidBlock.Transactions),
+			invalidBlock.Header.AcceptedIDMerkleRoot(),
+			invalidBlock.Header.UTXOCommitment(),
+			invalidBlock.Header.TimeInMilliseconds(),
+			invalidBlock.Header.Bits(),
+			invalidBlock.Header.Nonce(),
+		)
+		invalidBlockHash := consensushashing.BlockHash(invalidBlock)
+		batch := append(append(append([]*externalapi.DomainBlock{}, blocks[:invalidBlockIndex]...), invalidBlock),
+			blocks[invalidBlockIndex:]...)
+
+		var expectedErr error
+		for _, block := range batch {
+			_, expectedErr = tcSerial.ValidateAndInsertBlock(block)
+			if expectedErr != nil {
+				break
+			}
+		}
+		if !errors.As(expectedErr, &ruleerrors.RuleError{}) {
+			t.Fatalf("Expected the invalid block to be rejected with a rule error, but got: %+v", expectedErr)
+		}
+
+		blockInsertionResults, err := tcBatch.ValidateAndInsertBlocks(batch)
+		if err == nil {
+			t.Fatalf("ValidateAndInsertBlocks: expected an error")
+		}
+		if err.Error() != expectedErr.Error() {
+			t.Fatalf("Expected the error %s, but got: %s", expectedErr, err)
+		}
+		if len(blockInsertionResults) != invalidBlockIndex {
+			t.Fatalf("Expected %d insertion results but got %d", invalidBlockIndex, len(blockInsertionResults))
+		}
+
+		for i, block := range batch {
+			blockHash := consensushashing.BlockHash(block)
+			blockInfo, err := tcBatch.GetBlockInfo(blockHash)
+			if err != nil {
+				t.Fatalf("GetBlockInfo: %+v", err)
+			}
+			switch {
+			case i < invalidBlockIndex:
+				if blockInfo.BlockStatus != externalapi.StatusUTXOValid {
+					t.Fatalf("Expected block %s, which precedes the invalid block, to be inserted "+
+						"as valid, but its status is %s", blockHash, blockInfo.BlockStatus)
+				}
+			case blockHash.Equal(invalidBlockHash):
+				if blockInfo.BlockStatus != externalapi.StatusInvalid {
+					t.Fatalf("Expected the invalid block to have status %s but got %s",
+						externalapi.StatusInvalid, blockInfo.BlockStatus)
+				}
+			default:
+				if blockInfo.Exists {
+					t.Fatalf("Expected block %s, which follows the invalid block, not to be inserted", blockHash)
+				}
+			}
+		}
+		compareConsensusStates(t, tcSerial, tcBatch, blocks[:invalidBlockIndex])
+
+		// The rest of the blocks are inserted once they are sent without the invalid block
+		blockInsertionResults, err = tcBatch.ValidateAndInsertBlocks(blocks[invalidBlockIndex:])
+		if err != nil {
+			t.Fatalf("ValidateAndInsertBlocks: %+v", err)
+		}
+		if len(blockInsertionResults) != len(blocks)-invalidBlockIndex {
+			t.Fatalf("Expected %d insertion results but got %d", len(blocks)-invalidBlockIndex, len(blockInsertionResults))
+		}
+	})
+}
+
+// compareConsensusStates makes sure that the virtual, and the past UTXO sets of the
+// given blocks, are the same in a consensus that inserted the blocks one by one and
+// in a consensus that inserted them in batches
+func compareConsensusStates(t *testing.T, tcSerial testapi.TestConsensus, tcBatch testapi.TestConsensus,
+	blocks []*externalapi.DomainBlock) {
+
+	serialVirtualInfo, err := tcSerial.GetVirtualInfo()
+	if err != nil {
+		t.Fatalf("GetVirtualInfo: %+v", err)
+	}
+	batchVirtualInfo, err := tcBatch.GetVirtualInfo()
+	if err != nil {
+		t.Fatalf("GetVirtualInfo: %+v", err)
+	}
+	if !externalapi.HashesEqual(serialVirtualInfo.ParentHashes, batchVirtualInfo.ParentHashes) ||
+		serialVirtualInfo.BlueScore != batchVirtualInfo.BlueScore ||
+		serialVirtualInfo.Bits != batchVirtualInfo.Bits ||
+		serialVirtualInfo.PastMedianTime != batchVirtualInfo.PastMedianTime {
+		t.Fatalf("Expected the virtual to be %+v but got %+v", serialVirtualInfo, batchVirtualInfo)
+	}
+
+	serialVirtualUTXOs, err := tcSerial.GetVirtualUTXOs(serialVirtualInfo.ParentHashes, nil, 1_000_000)
+	if err != nil {
+		t.Fatalf("GetVirtualUTXOs: %+v", err)
+	}
+	batchVirtualUTXOs, err := tcBatch.GetVirtualUTXOs(batchVirtualInfo.ParentHashes, nil, 1_000_000)
+	if err != nil {
+		t.Fatalf("GetVirtualUTXOs: %+v", err)
+	}
+	if len(serialVirtualUTXOs) != len(batchVirtualUTXOs) {
+		t.Fatalf("Expected %d virtual UTXOs but got %d", len(serialVirtualUTXOs), len(batchVirtualUTXOs))
+	}
+	for i, serialPair := range serialVirtualUTXOs {
+		batchPair := batchVirtualUTXOs[i]
+		if !serialPair.Outpoint.Equal(batchPair.Outpoint) || !serialPair.UTXOEntry.Equal(batchPair.UTXOEntry) {
+			t.Fatalf("Expected virtual UTXO %s but got %s", serialPair.Outpoint, batchPair.Outpoint)
+		}
+	}
+
+	for _, block := range blocks {
+		blockHash := consensushashing.BlockHash(block)
+		batchBlockInfo, err := tcBatch.GetBlockInfo(blockHash)
+		if err != nil {
+			t.Fatalf("GetBlockInfo: %+v", err)
+		}
+		if batchBlockInfo.BlockStatus != externalapi.StatusUTXOValid {
+			continue
+		}
+		serialBlockInfo, err := tcSerial.GetBlockInfo(blockHash)
+		if err != nil {
+			t.Fatalf("GetBlockInfo: %+v", err)
+		}
+		if serialBlockInfo.BlockStatus != externalapi.StatusUTXOValid {
+			t.Fatalf("Block %s is valid only in the batch consensus, in which its status is %s",
+				blockHash, serialBlockInfo.BlockStatus)
+		}
+
+		serialPastUTXOs := collectPastUTXOs(t, tcSerial, blockHash)
+		batchPastUTXOs := collectPastUTXOs(t, tcBatch, blockHash)
+		if len(serialPastUTXOs) != len(batchPastUTXOs) {
+			t.Fatalf("Expected block %s to have %d past UTXOs but got %d",
+				blockHash, len(serialPastUTXOs), len(batchPastUTXOs))
+		}
+		for outpoint, serialEntry := range serialPastUTXOs {
+			batchEntry, ok := batchPastUTXOs[outpoint]
+			if !ok || !serialEntry.Equal(batchEntry) {
+				t.Fatalf("The past UTXO %s of block %s is different in the batch consensus", outpoint, blockHash)
+			}
+		}
+	}
+}
+
+func collectPastUTXOs(t *testing.T, tc testapi.TestConsensus,
+	blockHash *externalapi.DomainHash) map[externalapi.DomainOutpoint]externalapi.UTXOEntry {
+
+	iterator, err := tc.ConsensusStateManager().RestorePastUTXOSetIterator(blockHash)
+	if err != nil {
+		t.Fatalf("RestorePastUTXOSetIterator: %+v", err)
+	}
+	defer iterator.Close()
+
+	pastUTXOs := make(map[externalapi.DomainOutpoint]externalapi.UTXOEntry)
+	for ok := iterator.First(); ok; ok = iterator.Next() {
+		outpoint, entry, err := iterator.Get()
+		if err != nil {
+			t.Fatalf("Get: %+v", err)
+		}
+		pastUTXOs[*outpoint] = entry
+	}
+	return pastUTXOs
+}
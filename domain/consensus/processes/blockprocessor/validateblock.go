@@ -13,17 +13,37 @@ func (bp *blockProcessor) validateBlockAndDiscardChanges(block *externalapi.Doma
 }
 
 func (bp *blockProcessor) validateBlock(block *externalapi.DomainBlock, isPruningPoint bool) error {
+	shouldBeMarkedInvalid, err := bp.validateBlockWithoutMarkingInvalid(block, isPruningPoint)
+	if shouldBeMarkedInvalid {
+		// Discard all changes so we save only the block status
+		bp.discardAllChanges()
+		hash := consensushashing.BlockHash(block)
+		bp.blockStatusStore.Stage(hash, externalapi.StatusInvalid)
+		commitErr := bp.commitAllChanges()
+		if commitErr != nil {
+			return commitErr
+		}
+	}
+	return err
+}
+
+// validateBlockWithoutMarkingInvalid validates the given block without storing
+// anything in the database. shouldBeMarkedInvalid is true if the validation
+// failed in a way that requires the block to be stored with StatusInvalid
+func (bp *blockProcessor) validateBlockWithoutMarkingInvalid(block *externalapi.DomainBlock, isPruningPoint bool) (
+	shouldBeMarkedInvalid bool, err error) {
+
 	blockHash := consensushashing.HeaderHash(block.Header)
 	log.Debugf("Validating block %s", blockHash)
 
-	err := bp.checkBlockStatus(block)
+	err = bp.checkBlockStatus(block)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	hasValidatedHeader, err := bp.hasValidatedHeader(blockHash)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	if !hasValidatedHeader {
@@ -38,18 +58,17 @@ func (bp *blockProcessor) validateBlock(block *externalapi.DomainBlock, isPrunin
 	// This is to prevent spamming attacks.
 	err = bp.validatePreProofOfWork(block)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	if !hasValidatedHeader {
 		err = bp.blockValidator.ValidatePruningPointViolationAndProofOfWorkAndDifficulty(blockHash)
 		if err != nil {
-			return err
+			return false, err
 		}
 	}
 
-	// If in-context validations fail, discard all changes and store the
-	// block with StatusInvalid.
+	// If in-context validations fail, the block should be stored with StatusInvalid.
 	err = bp.validatePostProofOfWork(block, isPruningPoint)
 	if err != nil {
 		if errors.As(err, &ruleerrors.RuleError{}) {
@@ -63,20 +82,11 @@ func (bp *blockProcessor) validateBlock(block *externalapi.DomainBlock, isPrunin
 			// transactions that fits the merkle root.
 			// ErrPrunedBlock - ErrPrunedBlock is an error that rejects a block body and
 			// not the block as a whole, so we shouldn't mark it as invalid.
-			if !errors.As(err, &ruleerrors.ErrMissingParents{}) &&
+			shouldBeMarkedInvalid = !errors.As(err, &ruleerrors.ErrMissingParents{}) &&
 				!errors.Is(err, ruleerrors.ErrBadMerkleRoot) &&
-				!errors.Is(err, ruleerrors.ErrPrunedBlock) {
-				// Discard all changes so we save only the block status
-				bp.discardAllChanges()
-				hash := consensushashing.BlockHash(block)
-				bp.blockStatusStore.Stage(hash, externalapi.StatusInvalid)
-				commitErr := bp.commitAllChanges()
-				if commitErr != nil {
-					return commitErr
-				}
-			}
+				!errors.Is(err, ruleerrors.ErrPrunedBlock)
 		}
-		return err
+		return shouldBeMarkedInvalid, err
 	}
-	return nil
+	return false, nil
 }
@@ -0,0 +1,132 @@
+package consensusstatemanager
+
+import (
+	"github.com/kaspanet/kaspad/domain/consensus/model"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+)
+
+// AddBlockWithoutUpdatingVirtual adds the given block to the DAG tips without
+// resolving its status or updating the virtual. UpdateVirtualAfterAddingBlocks
+// is expected to be called once all the blocks of a batch were added
+func (csm *consensusStateManager) AddBlockWithoutUpdatingVirtual(blockHash *externalapi.DomainHash) error {
+	log.Debugf("Adding block %s to the DAG tips without updating the virtual", blockHash)
+	_, err := csm.addTip(blockHash)
+	return err
+}
+
+// UpdateVirtualAfterAddingBlocks resolves the status of the next virtual selected
+// parent out of the given blocks, which were added with AddBlockWithoutUpdatingVirtual,
+// and updates the virtual only once for all of them.
+// This is only done if the result is guaranteed to be the same as adding the
+// blocks one by one with AddBlock. Otherwise, wasUpdated is false, and the caller
+// is expected to discard all staged changes and add the blocks one by one.
+func (csm *consensusStateManager) UpdateVirtualAfterAddingBlocks(blockHashes []*externalapi.DomainHash) (
+	selectedParentChainChanges *externalapi.SelectedChainPath, virtualUTXODiff externalapi.UTXODiff,
+	wasUpdated bool, err error) {
+
+	onEnd := logger.LogAndMeasureExecutionTime(log, "csm.UpdateVirtualAfterAddingBlocks")
+	defer onEnd()
+
+	virtualGHOSTDAGData, err := csm.ghostdagDataStore.Get(csm.databaseContext, model.VirtualBlockHash)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	oldVirtualSelectedParent := virtualGHOSTDAGData.SelectedParent()
+
+	candidates := append([]*externalapi.DomainHash{oldVirtualSelectedParent}, blockHashes...)
+	nextVirtualSelectedParent, err := csm.ghostdagManager.ChooseSelectedParent(candidates...)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	log.Debugf("The next virtual selected parent is: %s", nextVirtualSelectedParent)
+
+	if !nextVirtualSelectedParent.Equal(oldVirtualSelectedParent) {
+		wasResolved, err := csm.resolveNextVirtualSelectedParentStatus(oldVirtualSelectedParent, nextVirtualSelectedParent)
+		if err != nil {
+			return nil, nil, false, err
+		}
+		if !wasResolved {
+			return nil, nil, false, nil
+		}
+	}
+
+	tips, err := csm.consensusStateStore.Tips(csm.databaseContext)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	log.Debugf("Updating the virtual with the new tips")
+	lastBlockHash := blockHashes[len(blockHashes)-1]
+	selectedParentChainChanges, virtualUTXODiff, err = csm.updateVirtual(lastBlockHash, tips)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	return selectedParentChainChanges, virtualUTXODiff, true, nil
+}
+
+// resolveNextVirtualSelectedParentStatus resolves the status of the next virtual
+// selected parent, along with the unverified blocks in its selected parent chain.
+// It returns false if the status cannot be resolved the same way AddBlock would
+// have resolved it, or if the block turns out not to be UTXO valid
+func (csm *consensusStateManager) resolveNextVirtualSelectedParentStatus(
+	oldVirtualSelectedParent *externalapi.DomainHash, nextVirtualSelectedParent *externalapi.DomainHash) (bool, error) {
+
+	// When the next virtual selected parent doesn't extend the current virtual selected parent
+	// chain, AddBlock might have resolved the statuses of competing chains along the way
+	isExtendingChain, err := csm.dagTopologyManager.IsInSelectedParentChainOf(
+		oldVirtualSelectedParent, nextVirtualSelectedParent)
+	if err != nil {
+		return false, err
+	}
+	if !isExtendingChain {
+		log.Debugf("Block %s does not extend the selected parent chain of %s",
+			nextVirtualSelectedParent, oldVirtualSelectedParent)
+		return false, nil
+	}
+
+	isViolatingFinality, _, err := csm.isViolatingFinality(nextVirtualSelectedParent)
+	if err != nil {
+		return false, err
+	}
+	if isViolatingFinality {
+		log.Debugf("Block %s violates finality", nextVirtualSelectedParent)
+		return false, nil
+	}
+
+	unverifiedBlocks, err := csm.getUnverifiedChainBlocks(nextVirtualSelectedParent)
+	if err != nil {
+		return false, err
+	}
+
+	blockStatus, err := csm.resolveBlockStatus(nextVirtualSelectedParent)
+	if err != nil {
+		return false, err
+	}
+	log.Debugf("Block %s resolved to status `%s`", nextVirtualSelectedParent, blockStatus)
+	if blockStatus != externalapi.StatusUTXOValid {
+		return false, nil
+	}
+
+	// Every unverified block was the selected tip at the time its status was resolved,
+	// so its UTXO diff was staged relative to the virtual. Now that a later block
+	// is the selected tip, the UTXO diffs of the earlier ones are made relative to it
+	nextVirtualSelectedParentUTXODiff, err := csm.utxoDiffStore.UTXODiff(csm.databaseContext, nextVirtualSelectedParent)
+	if err != nil {
+		return false, err
+	}
+	for _, unverifiedBlock := range unverifiedBlocks[1:] {
+		unverifiedBlockUTXODiff, err := csm.utxoDiffStore.UTXODiff(csm.databaseContext, unverifiedBlock)
+		if err != nil {
+			return false, err
+		}
+		utxoDiff, err := nextVirtualSelectedParentUTXODiff.DiffFrom(unverifiedBlockUTXODiff)
+		if err != nil {
+			return false, err
+		}
+		csm.stageDiff(unverifiedBlock, utxoDiff, nextVirtualSelectedParent)
+	}
+
+	return true, nil
+}
@@ -145,6 +145,7 @@ func (csm *consensusStateManager) applyImportedPruningPointUTXOSet() error {
 	if err != nil {
 		return err
 	}
+	defer dbTx.RollbackUnlessClosed()
 
 	for _, store := range csm.stores {
 		err = store.Commit(dbTx)
@@ -67,6 +67,13 @@ func (csm *consensusStateManager) validateBlockTransactionsAgainstPastUTXO(block
 	}
 	log.Tracef("The past median time of %s is %d", blockHash, selectedParentMedianTime)
 
+	// The transactions are validated in two passes: first, everything but their scripts is
+	// validated one transaction after another, and then the scripts of all the transactions
+	// that passed the first pass are validated concurrently. An error from the first pass
+	// is reported only if none of the scripts before it failed, so that the reported error
+	// is the same as if every transaction were fully validated in order
+	var validationErr error
+	transactionsToValidateScriptsOf := make([]*externalapi.DomainTransaction, 0, len(block.Transactions))
 	for i, transaction := range block.Transactions {
 		transactionID := consensushashing.TransactionID(transaction)
 		log.Tracef("Validating transaction %s in block %s against "+
@@ -79,18 +86,37 @@ func (csm *consensusStateManager) validateBlockTransactionsAgainstPastUTXO(block
 		log.Tracef("Populating transaction %s with UTXO entries", transactionID)
 		err = csm.populateTransactionWithUTXOEntriesFromVirtualOrDiff(transaction, pastUTXODiff)
 		if err != nil {
-			return err
+			if !errors.As(err, &(ruleerrors.RuleError{})) {
+				return err
+			}
+			validationErr = err
+			break
 		}
 
 		log.Tracef("Validating transaction %s and populating it with mass and fee", transactionID)
-		err = csm.transactionValidator.ValidateTransactionInContextAndPopulateMassAndFee(
+		err = csm.transactionValidator.ValidateTransactionInContextIgnoringScriptsAndPopulateMassAndFee(
 			transaction, blockHash, selectedParentMedianTime)
 		if err != nil {
-			return err
+			if !errors.As(err, &(ruleerrors.RuleError{})) {
+				return err
+			}
+			validationErr = err
+			break
 		}
-		log.Tracef("Validation against the block's past UTXO "+
-			"passed for transaction %s in block %s", transactionID, blockHash)
+		transactionsToValidateScriptsOf = append(transactionsToValidateScriptsOf, transaction)
+	}
+
+	log.Tracef("Validating the scripts of %d transactions in block %s",
+		len(transactionsToValidateScriptsOf), blockHash)
+	err = csm.transactionValidator.ValidateTransactionsScripts(transactionsToValidateScriptsOf)
+	if err != nil {
+		return err
+	}
+	if validationErr != nil {
+		return validationErr
 	}
+	log.Tracef("Validation against the block's past UTXO "+
+		"passed for all transactions in block %s", blockHash)
 	return nil
 }
 
@@ -444,6 +444,6 @@ func (b *blockHeadersStore) Delete(blockHash *externalapi.DomainHash) {
 	delete(b.dagMap, *blockHash)
 }
 
-func (b *blockHeadersStore) Count() uint64 {
+func (b *blockHeadersStore) Count(dbContext model.DBReader) uint64 {
 	return uint64(len(b.dagMap))
 }
@@ -15,9 +15,9 @@ func (sm *syncManager) syncInfo() (*externalapi.SyncInfo, error) {
 }
 
 func (sm *syncManager) getHeaderCount() uint64 {
-	return sm.blockHeaderStore.Count()
+	return sm.blockHeaderStore.Count(sm.databaseContext)
 }
 
 func (sm *syncManager) getBlockCount() uint64 {
-	return sm.blockStore.Count()
+	return sm.blockStore.Count(sm.databaseContext)
 }
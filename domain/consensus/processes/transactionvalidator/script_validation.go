@@ -0,0 +1,148 @@
+package transactionvalidator
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/ruleerrors"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/pkg/errors"
+)
+
+// scriptValidationJob is a single transaction input whose script is pending validation
+type scriptValidationJob struct {
+	tx         *externalapi.DomainTransaction
+	inputIndex int
+}
+
+// ValidateTransactionsScripts validates the scripts of all the inputs of the given
+// transactions, which are expected to be populated with their UTXO entries.
+// The inputs are validated concurrently, but the returned error is always the
+// one that validating the inputs one after another, in order, would return.
+func (v *transactionValidator) ValidateTransactionsScripts(txs []*externalapi.DomainTransaction) error {
+	return v.validateScripts(txs)
+}
+
+func (v *transactionValidator) validateTransactionScripts(tx *externalapi.DomainTransaction) error {
+	return v.validateScripts([]*externalapi.DomainTransaction{tx})
+}
+
+func (v *transactionValidator) validateScripts(txs []*externalapi.DomainTransaction) error {
+	var jobs []scriptValidationJob
+	for _, tx := range txs {
+		for i, input := range tx.Inputs {
+			if input.UTXOEntry == nil {
+				continue
+			}
+			jobs = append(jobs, scriptValidationJob{tx: tx, inputIndex: i})
+		}
+	}
+
+	jobErrors := v.runScriptValidationJobs(jobs)
+
+	// Go over the inputs in order, so that the reported error is that of the
+	// first failing input regardless of the order in which the jobs ran
+	jobIndex := 0
+	for _, tx := range txs {
+		var missingOutpoints []*externalapi.DomainOutpoint
+		for _, input := range tx.Inputs {
+			if input.UTXOEntry == nil {
+				missingOutpoints = append(missingOutpoints, &input.PreviousOutpoint)
+				continue
+			}
+			if jobErrors[jobIndex] != nil {
+				return jobErrors[jobIndex]
+			}
+			jobIndex++
+		}
+		if len(missingOutpoints) > 0 {
+			return ruleerrors.NewErrMissingTxOut(missingOutpoints)
+		}
+	}
+	return nil
+}
+
+// runScriptValidationJobs validates the given jobs using up to v.scriptValidationWorkers
+// goroutines, and returns the error of each job by its index.
+// Once a job fails, jobs with a higher index are no longer validated, since
+// their result would never be reported
+func (v *transactionValidator) runScriptValidationJobs(jobs []scriptValidationJob) []error {
+	jobErrors := make([]error, len(jobs))
+
+	numberOfWorkers := v.scriptValidationWorkers
+	if numberOfWorkers > len(jobs) {
+		numberOfWorkers = len(jobs)
+	}
+	if numberOfWorkers <= 1 {
+		for i, job := range jobs {
+			jobErrors[i] = v.validateInputScript(job.tx, job.inputIndex)
+			if jobErrors[i] != nil {
+				break
+			}
+		}
+		return jobErrors
+	}
+
+	nextJobIndex := int64(-1)
+	lowestFailedJobIndex := int64(math.MaxInt64)
+	waitGroup := sync.WaitGroup{}
+	waitGroup.Add(numberOfWorkers)
+	for i := 0; i < numberOfWorkers; i++ {
+		go func() {
+			defer waitGroup.Done()
+			for {
+				jobIndex := atomic.AddInt64(&nextJobIndex, 1)
+				if jobIndex >= int64(len(jobs)) || jobIndex > atomic.LoadInt64(&lowestFailedJobIndex) {
+					return
+				}
+				job := jobs[jobIndex]
+				err := v.validateInputScript(job.tx, job.inputIndex)
+				if err == nil {
+					continue
+				}
+				jobErrors[jobIndex] = err
+				for {
+					lowestFailedJobIndexValue := atomic.LoadInt64(&lowestFailedJobIndex)
+					if jobIndex >= lowestFailedJobIndexValue ||
+						atomic.CompareAndSwapInt64(&lowestFailedJobIndex, lowestFailedJobIndexValue, jobIndex) {
+						break
+					}
+				}
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	return jobErrors
+}
+
+func (v *transactionValidator) validateInputScript(tx *externalapi.DomainTransaction, inputIndex int) error {
+	input := tx.Inputs[inputIndex]
+	sigScript := input.SignatureScript
+	scriptPubKey := input.UTXOEntry.ScriptPublicKey()
+
+	// Create a new script engine for the script pair.
+	vm, err := txscript.NewEngine(scriptPubKey, tx,
+		inputIndex, txscript.ScriptNoFlags, v.sigCache)
+	if err != nil {
+		return errors.Wrapf(ruleerrors.ErrScriptMalformed, "failed to parse input "+
+			"%d which references output %s - "+
+			"%s (input script bytes %x, prev "+
+			"output script bytes %x)",
+			inputIndex,
+			input.PreviousOutpoint, err, sigScript, scriptPubKey)
+	}
+
+	// Execute the script pair.
+	if err := vm.Execute(); err != nil {
+		return errors.Wrapf(ruleerrors.ErrScriptValidation, "failed to validate input "+
+			"%d which references output %s - "+
+			"%s (input script bytes %x, prev output "+
+			"script bytes %x)",
+			inputIndex,
+			input.PreviousOutpoint, err, sigScript, scriptPubKey)
+	}
+	return nil
+}
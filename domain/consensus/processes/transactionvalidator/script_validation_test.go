@@ -0,0 +1,173 @@
+package transactionvalidator_test
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"github.com/kaspanet/go-secp256k1"
+	"github.com/kaspanet/kaspad/domain/consensus"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/model/testapi"
+	"github.com/kaspanet/kaspad/domain/consensus/ruleerrors"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/constants"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/subnetworks"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/testutils"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/utxo"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/util"
+	"github.com/pkg/errors"
+)
+
+func TestValidateTransactionsScripts(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, params *dagconfig.Params) {
+		factory := consensus.NewFactory()
+		tc, tearDown, err := factory.NewTestConsensus(params, false, "TestValidateTransactionsScripts")
+		if err != nil {
+			t.Fatalf("Failed create a NewTestConsensus: %s", err)
+		}
+		defer tearDown(false)
+
+		for _, numberOfWorkers := range []int{1, 8} {
+			tc.TransactionValidator().SetScriptValidationWorkers(numberOfWorkers)
+
+			txs := createSignedTransactions(t, params, 3, 10)
+			err = tc.TransactionValidator().ValidateTransactionsScripts(txs)
+			if err != nil {
+				t.Fatalf("ValidateTransactionsScripts (%d workers): %+v", numberOfWorkers, err)
+			}
+
+			// Break the signatures of several inputs. The reported error must always be that
+			// of the first of them, regardless of the order in which the inputs are validated
+			txs[1].Inputs[7].SignatureScript = txs[1].Inputs[6].SignatureScript
+			txs[1].Inputs[4].SignatureScript = txs[1].Inputs[3].SignatureScript
+			txs[2].Inputs[0].SignatureScript = txs[2].Inputs[1].SignatureScript
+			expectedErr := tc.TransactionValidator().ValidateTransactionsScripts(txs[1:2])
+			for i := 0; i < 10; i++ {
+				err = tc.TransactionValidator().ValidateTransactionsScripts(txs)
+				if !errors.Is(err, ruleerrors.ErrScriptValidation) {
+					t.Fatalf("Expected ErrScriptValidation (%d workers), but got: %+v", numberOfWorkers, err)
+				}
+				if err.Error() != expectedErr.Error() {
+					t.Fatalf("Expected the error of input 4 (%d workers), but got: %s", numberOfWorkers, err)
+				}
+			}
+
+			// A script error is reported before the missing outpoints of the same transaction,
+			// but missing outpoints are reported before script errors of later transactions
+			txs[1].Inputs[2].UTXOEntry = nil
+			err = tc.TransactionValidator().ValidateTransactionsScripts(txs)
+			if !errors.Is(err, ruleerrors.ErrScriptValidation) {
+				t.Fatalf("Expected ErrScriptValidation (%d workers), but got: %+v", numberOfWorkers, err)
+			}
+			txs[0].Inputs[5].UTXOEntry = nil
+			err = tc.TransactionValidator().ValidateTransactionsScripts(txs)
+			if !errors.As(err, &ruleerrors.ErrMissingTxOut{}) {
+				t.Fatalf("Expected ErrMissingTxOut (%d workers), but got: %+v", numberOfWorkers, err)
+			}
+		}
+	})
+}
+
+func BenchmarkValidateTransactionsScripts(b *testing.B) {
+	params := &dagconfig.DevnetParams
+	factory := consensus.NewFactory()
+	tc, tearDown, err := factory.NewTestConsensus(params, false, "BenchmarkValidateTransactionsScripts")
+	if err != nil {
+		b.Fatalf("Failed create a NewTestConsensus: %s", err)
+	}
+	defer tearDown(false)
+
+	// Disable the signature cache, otherwise every iteration but the first
+	// would hit the cache instead of verifying the signatures
+	tc.TransactionValidator().SetSigCache(nil)
+
+	benchmarks := []struct {
+		name                 string
+		numberOfTransactions int
+		inputsPerTransaction int
+	}{
+		{name: "SingleTransaction", numberOfTransactions: 1, inputsPerTransaction: 100},
+		{name: "Block", numberOfTransactions: 100, inputsPerTransaction: 2},
+	}
+	for _, benchmark := range benchmarks {
+		txs := createSignedTransactions(b, params, benchmark.numberOfTransactions, benchmark.inputsPerTransaction)
+		for _, numberOfWorkers := range []int{1, runtime.NumCPU()} {
+			b.Run(fmt.Sprintf("%s/Workers%d", benchmark.name, numberOfWorkers), func(b *testing.B) {
+				benchmarkValidateTransactionsScripts(b, tc.TransactionValidator(), txs, numberOfWorkers)
+			})
+		}
+	}
+}
+
+func benchmarkValidateTransactionsScripts(b *testing.B, transactionValidator testapi.TestTransactionValidator,
+	txs []*externalapi.DomainTransaction, numberOfWorkers int) {
+
+	transactionValidator.SetScriptValidationWorkers(numberOfWorkers)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		err := transactionValidator.ValidateTransactionsScripts(txs)
+		if err != nil {
+			b.Fatalf("ValidateTransactionsScripts: %+v", err)
+		}
+	}
+}
+
+// createSignedTransactions creates transactions whose inputs are populated with UTXO entries
+// and are signed by a P2PK key, so that validating each input requires a signature check
+func createSignedTransactions(tb testing.TB, params *dagconfig.Params,
+	numberOfTransactions int, inputsPerTransaction int) []*externalapi.DomainTransaction {
+
+	privateKey, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		tb.Fatalf("Failed to generate a private key: %v", err)
+	}
+	publicKey, err := privateKey.SchnorrPublicKey()
+	if err != nil {
+		tb.Fatalf("Failed to generate a public key: %v", err)
+	}
+	publicKeySerialized, err := publicKey.Serialize()
+	if err != nil {
+		tb.Fatalf("Failed to serialize public key: %v", err)
+	}
+	addr, err := util.NewAddressPubKeyHashFromPublicKey(publicKeySerialized[:], params.Prefix)
+	if err != nil {
+		tb.Fatalf("Failed to generate p2pkh address: %v", err)
+	}
+	scriptPublicKey, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		tb.Fatalf("PayToAddrScript: unexpected error: %v", err)
+	}
+
+	txs := make([]*externalapi.DomainTransaction, numberOfTransactions)
+	for i := range txs {
+		tx := &externalapi.DomainTransaction{
+			Version: constants.MaxTransactionVersion,
+			Inputs:  make([]*externalapi.DomainTransactionInput, inputsPerTransaction),
+			Outputs: []*externalapi.DomainTransactionOutput{{
+				Value:           100_000_000,
+				ScriptPublicKey: scriptPublicKey,
+			}},
+			SubnetworkID: subnetworks.SubnetworkIDNative,
+		}
+		for j := range tx.Inputs {
+			tx.Inputs[j] = &externalapi.DomainTransactionInput{
+				PreviousOutpoint: externalapi.DomainOutpoint{
+					TransactionID: *externalapi.NewDomainTransactionIDFromByteArray(&[32]byte{byte(i)}),
+					Index:         uint32(j),
+				},
+				Sequence:  constants.MaxTxInSequenceNum,
+				UTXOEntry: utxo.NewUTXOEntry(100_000_000, scriptPublicKey, false, 0),
+			}
+		}
+		for j, input := range tx.Inputs {
+			input.SignatureScript, err = txscript.SignatureScript(tx, j, scriptPublicKey, txscript.SigHashAll, privateKey)
+			if err != nil {
+				tb.Fatalf("Failed to create a sigScript: %v", err)
+			}
+		}
+		txs[i] = tx
+	}
+	return txs
+}
@@ -22,3 +22,7 @@ func (tbv *testTransactionValidator) SigCache() *txscript.SigCache {
 func (tbv *testTransactionValidator) SetSigCache(sigCache *txscript.SigCache) {
 	tbv.sigCache = sigCache
 }
+
+func (tbv *testTransactionValidator) SetScriptValidationWorkers(scriptValidationWorkers int) {
+	tbv.scriptValidationWorkers = scriptValidationWorkers
+}
@@ -5,7 +5,6 @@ import (
 	"github.com/kaspanet/kaspad/domain/consensus/ruleerrors"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/constants"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/transactionhelper"
-	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
 	"github.com/pkg/errors"
 )
 
@@ -16,39 +15,66 @@ import (
 func (v *transactionValidator) ValidateTransactionInContextAndPopulateMassAndFee(tx *externalapi.DomainTransaction,
 	povBlockHash *externalapi.DomainHash, selectedParentMedianTime int64) error {
 
-	err := v.checkTransactionCoinbaseMaturity(povBlockHash, tx)
+	err := v.validateTransactionInContextIgnoringScripts(tx, povBlockHash, selectedParentMedianTime)
 	if err != nil {
 		return err
 	}
 
-	totalSompiIn, err := v.checkTransactionInputAmounts(tx)
+	err = v.validateTransactionScripts(tx)
 	if err != nil {
 		return err
 	}
 
-	totalSompiOut, err := v.checkTransactionOutputAmounts(tx, totalSompiIn)
+	tx.Mass, err = v.transactionMass(tx)
 	if err != nil {
 		return err
 	}
 
-	tx.Fee = totalSompiIn - totalSompiOut
+	return nil
+}
+
+// ValidateTransactionInContextIgnoringScriptsAndPopulateMassAndFee validates the transaction against its
+// referenced UTXO, without validating its scripts, and populates its mass and fee fields.
+// The scripts are expected to be validated separately using ValidateTransactionsScripts.
+//
+// Note: if the function fails, there's no guarantee that the transaction mass and fee fields will remain unaffected.
+func (v *transactionValidator) ValidateTransactionInContextIgnoringScriptsAndPopulateMassAndFee(
+	tx *externalapi.DomainTransaction, povBlockHash *externalapi.DomainHash, selectedParentMedianTime int64) error {
 
-	err = v.checkTransactionSequenceLock(povBlockHash, tx, selectedParentMedianTime)
+	err := v.validateTransactionInContextIgnoringScripts(tx, povBlockHash, selectedParentMedianTime)
 	if err != nil {
 		return err
 	}
 
-	err = v.validateTransactionScripts(tx)
+	tx.Mass, err = v.transactionMass(tx)
 	if err != nil {
 		return err
 	}
 
-	tx.Mass, err = v.transactionMass(tx)
+	return nil
+}
+
+func (v *transactionValidator) validateTransactionInContextIgnoringScripts(tx *externalapi.DomainTransaction,
+	povBlockHash *externalapi.DomainHash, selectedParentMedianTime int64) error {
+
+	err := v.checkTransactionCoinbaseMaturity(povBlockHash, tx)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	totalSompiIn, err := v.checkTransactionInputAmounts(tx)
+	if err != nil {
+		return err
+	}
+
+	totalSompiOut, err := v.checkTransactionOutputAmounts(tx, totalSompiIn)
+	if err != nil {
+		return err
+	}
+
+	tx.Fee = totalSompiIn - totalSompiOut
+
+	return v.checkTransactionSequenceLock(povBlockHash, tx, selectedParentMedianTime)
 }
 
 func (v *transactionValidator) checkTransactionCoinbaseMaturity(
@@ -175,46 +201,6 @@ func (v *transactionValidator) checkTransactionSequenceLock(povBlockHash *extern
 	return nil
 }
 
-func (v *transactionValidator) validateTransactionScripts(tx *externalapi.DomainTransaction) error {
-
-	var missingOutpoints []*externalapi.DomainOutpoint
-	for i, input := range tx.Inputs {
-		// Create a new script engine for the script pair.
-		sigScript := input.SignatureScript
-		utxoEntry := input.UTXOEntry
-		if utxoEntry == nil {
-			missingOutpoints = append(missingOutpoints, &input.PreviousOutpoint)
-			continue
-		}
-
-		scriptPubKey := utxoEntry.ScriptPublicKey()
-		vm, err := txscript.NewEngine(scriptPubKey, tx,
-			i, txscript.ScriptNoFlags, v.sigCache)
-		if err != nil {
-			return errors.Wrapf(ruleerrors.ErrScriptMalformed, "failed to parse input "+
-				"%d which references output %s - "+
-				"%s (input script bytes %x, prev "+
-				"output script bytes %x)",
-				i,
-				input.PreviousOutpoint, err, sigScript, scriptPubKey)
-		}
-
-		// Execute the script pair.
-		if err := vm.Execute(); err != nil {
-			return errors.Wrapf(ruleerrors.ErrScriptValidation, "failed to validate input "+
-				"%d which references output %s - "+
-				"%s (input script bytes %x, prev output "+
-				"script bytes %x)",
-				i,
-				input.PreviousOutpoint, err, sigScript, scriptPubKey)
-		}
-	}
-	if len(missingOutpoints) > 0 {
-		return ruleerrors.NewErrMissingTxOut(missingOutpoints)
-	}
-	return nil
-}
-
 func (v *transactionValidator) calcTxSequenceLockFromReferencedUTXOEntries(
 	povBlockHash *externalapi.DomainHash, tx *externalapi.DomainTransaction) (*sequenceLock, error) {
 
@@ -1,7 +1,10 @@
 package transactionvalidator
 
 import (
+	"runtime"
+
 	"github.com/kaspanet/kaspad/domain/consensus/model"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txmass"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
 )
 
@@ -15,11 +18,10 @@ type transactionValidator struct {
 	pastMedianTimeManager      model.PastMedianTimeManager
 	ghostdagDataStore          model.GHOSTDAGDataStore
 	enableNonNativeSubnetworks bool
-	massPerTxByte              uint64
-	massPerScriptPubKeyByte    uint64
-	massPerSigOp               uint64
+	txMassCalculator           *txmass.Calculator
 	maxCoinbasePayloadLength   uint64
 	sigCache                   *txscript.SigCache
+	scriptValidationWorkers    int
 }
 
 // New instantiates a new TransactionValidator
@@ -35,13 +37,12 @@ func New(blockCoinbaseMaturity uint64,
 	return &transactionValidator{
 		blockCoinbaseMaturity:      blockCoinbaseMaturity,
 		enableNonNativeSubnetworks: enableNonNativeSubnetworks,
-		massPerTxByte:              massPerTxByte,
-		massPerScriptPubKeyByte:    massPerScriptPubKeyByte,
-		massPerSigOp:               massPerSigOp,
+		txMassCalculator:           txmass.NewCalculator(massPerTxByte, massPerScriptPubKeyByte, massPerSigOp),
 		maxCoinbasePayloadLength:   maxCoinbasePayloadLength,
 		databaseContext:            databaseContext,
 		pastMedianTimeManager:      pastMedianTimeManager,
 		ghostdagDataStore:          ghostdagDataStore,
 		sigCache:                   txscript.NewSigCache(sigCacheSize),
+		scriptValidationWorkers:    runtime.NumCPU(),
 	}
 }
@@ -0,0 +1,29 @@
+package consensus
+
+import "sync"
+
+// stateLock guards the committed consensus state. Queries hold it for
+// reading, and the writer holds it for writing while it commits.
+//
+// Unlike sync.RWMutex, the writer may take the write lock again while
+// holding it, so that operations which hold it throughout could still
+// commit through a locking database manager. Only the goroutine that
+// holds consensus.writeLock may call Lock and Unlock
+type stateLock struct {
+	sync.RWMutex
+	writeDepth int
+}
+
+func (sl *stateLock) Lock() {
+	if sl.writeDepth == 0 {
+		sl.RWMutex.Lock()
+	}
+	sl.writeDepth++
+}
+
+func (sl *stateLock) Unlock() {
+	sl.writeDepth--
+	if sl.writeDepth == 0 {
+		sl.RWMutex.Unlock()
+	}
+}
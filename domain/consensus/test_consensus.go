@@ -32,8 +32,8 @@ func (tc *testConsensus) BuildBlockWithParents(parentHashes []*externalapi.Domai
 	*externalapi.DomainBlock, externalapi.UTXODiff, error) {
 
 	// Require write lock because BuildBlockWithParents stages temporary data
-	tc.lock.Lock()
-	defer tc.lock.Unlock()
+	tc.writeLock.Lock()
+	defer tc.writeLock.Unlock()
 
 	block, diff, err := tc.testBlockBuilder.BuildBlockWithParents(parentHashes, coinbaseData, transactions)
 	if err != nil {
@@ -47,8 +47,8 @@ func (tc *testConsensus) AddBlock(parentHashes []*externalapi.DomainHash, coinba
 	transactions []*externalapi.DomainTransaction) (*externalapi.DomainHash, *externalapi.BlockInsertionResult, error) {
 
 	// Require write lock because BuildBlockWithParents stages temporary data
-	tc.lock.Lock()
-	defer tc.lock.Unlock()
+	tc.writeLock.Lock()
+	defer tc.writeLock.Unlock()
 
 	block, _, err := tc.testBlockBuilder.BuildBlockWithParents(parentHashes, coinbaseData, transactions)
 	if err != nil {
@@ -67,8 +67,8 @@ func (tc *testConsensus) AddUTXOInvalidHeader(parentHashes []*externalapi.Domain
 	*externalapi.BlockInsertionResult, error) {
 
 	// Require write lock because BuildBlockWithParents stages temporary data
-	tc.lock.Lock()
-	defer tc.lock.Unlock()
+	tc.writeLock.Lock()
+	defer tc.writeLock.Unlock()
 
 	header, err := tc.testBlockBuilder.BuildUTXOInvalidHeader(parentHashes)
 	if err != nil {
@@ -90,8 +90,8 @@ func (tc *testConsensus) AddUTXOInvalidBlock(parentHashes []*externalapi.DomainH
 	*externalapi.BlockInsertionResult, error) {
 
 	// Require write lock because BuildBlockWithParents stages temporary data
-	tc.lock.Lock()
-	defer tc.lock.Unlock()
+	tc.writeLock.Lock()
+	defer tc.writeLock.Unlock()
 
 	block, err := tc.testBlockBuilder.BuildUTXOInvalidBlock(parentHashes)
 	if err != nil {
@@ -182,16 +182,16 @@ func (tc *testConsensus) MineJSON(r io.Reader, blockType testapi.MineJSONBlockTy
 
 func (tc *testConsensus) BuildUTXOInvalidBlock(parentHashes []*externalapi.DomainHash) (*externalapi.DomainBlock, error) {
 	// Require write lock because BuildBlockWithParents stages temporary data
-	tc.lock.Lock()
-	defer tc.lock.Unlock()
+	tc.writeLock.Lock()
+	defer tc.writeLock.Unlock()
 
 	return tc.testBlockBuilder.BuildUTXOInvalidBlock(parentHashes)
 }
 
 func (tc *testConsensus) BuildHeaderWithParents(parentHashes []*externalapi.DomainHash) (externalapi.BlockHeader, error) {
 	// Require write lock because BuildUTXOInvalidHeader stages temporary data
-	tc.lock.Lock()
-	defer tc.lock.Unlock()
+	tc.writeLock.Lock()
+	defer tc.writeLock.Unlock()
 
 	return tc.testBlockBuilder.BuildUTXOInvalidHeader(parentHashes)
 }
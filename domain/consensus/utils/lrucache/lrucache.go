@@ -1,12 +1,16 @@
 package lrucache
 
 import (
+	"sync"
+
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 )
 
 // LRUCache is a least-recently-used cache for any type
 // that's able to be indexed by DomainHash
+// It is safe for concurrent access.
 type LRUCache struct {
+	lock     sync.RWMutex
 	cache    map[externalapi.DomainHash]interface{}
 	capacity int
 }
@@ -27,6 +31,9 @@ func New(capacity int, preallocate bool) *LRUCache {
 
 // Add adds an entry to the LRUCache
 func (c *LRUCache) Add(key *externalapi.DomainHash, value interface{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
 	c.cache[*key] = value
 
 	if len(c.cache) > c.capacity {
@@ -36,6 +43,9 @@ func (c *LRUCache) Add(key *externalapi.DomainHash, value interface{}) {
 
 // Get returns the entry for the given key, or (nil, false) otherwise
 func (c *LRUCache) Get(key *externalapi.DomainHash) (interface{}, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
 	value, ok := c.cache[*key]
 	if !ok {
 		return nil, false
@@ -45,6 +55,9 @@ func (c *LRUCache) Get(key *externalapi.DomainHash) (interface{}, bool) {
 
 // Has returns whether the LRUCache contains the given key
 func (c *LRUCache) Has(key *externalapi.DomainHash) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
 	_, ok := c.cache[*key]
 	return ok
 }
@@ -52,6 +65,13 @@ func (c *LRUCache) Has(key *externalapi.DomainHash) bool {
 // Remove removes the entry for the the given key. Does nothing if
 // the entry does not exist
 func (c *LRUCache) Remove(key *externalapi.DomainHash) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.remove(key)
+}
+
+func (c *LRUCache) remove(key *externalapi.DomainHash) {
 	delete(c.cache, *key)
 }
 
@@ -61,5 +81,5 @@ func (c *LRUCache) evictRandom() {
 		keyToEvict = key
 		break
 	}
-	c.Remove(&keyToEvict)
+	c.remove(&keyToEvict)
 }
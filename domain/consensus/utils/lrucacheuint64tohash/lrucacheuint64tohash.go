@@ -1,10 +1,16 @@
 package lrucacheuint64tohash
 
-import "github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+import (
+	"sync"
+
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+)
 
 // LRUCache is a least-recently-used cache from
 // uint64 to DomainHash
+// It is safe for concurrent access.
 type LRUCache struct {
+	lock     sync.RWMutex
 	cache    map[uint64]*externalapi.DomainHash
 	capacity int
 }
@@ -25,6 +31,9 @@ func New(capacity int, preallocate bool) *LRUCache {
 
 // Add adds an entry to the LRUCache
 func (c *LRUCache) Add(key uint64, value *externalapi.DomainHash) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
 	c.cache[key] = value
 
 	if len(c.cache) > c.capacity {
@@ -34,6 +43,9 @@ func (c *LRUCache) Add(key uint64, value *externalapi.DomainHash) {
 
 // Get returns the entry for the given key, or (nil, false) otherwise
 func (c *LRUCache) Get(key uint64) (*externalapi.DomainHash, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
 	value, ok := c.cache[key]
 	if !ok {
 		return nil, false
@@ -43,6 +55,9 @@ func (c *LRUCache) Get(key uint64) (*externalapi.DomainHash, bool) {
 
 // Has returns whether the LRUCache contains the given key
 func (c *LRUCache) Has(key uint64) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
 	_, ok := c.cache[key]
 	return ok
 }
@@ -50,6 +65,13 @@ func (c *LRUCache) Has(key uint64) bool {
 // Remove removes the entry for the the given key. Does nothing if
 // the entry does not exist
 func (c *LRUCache) Remove(key uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.remove(key)
+}
+
+func (c *LRUCache) remove(key uint64) {
 	delete(c.cache, key)
 }
 
@@ -59,5 +81,5 @@ func (c *LRUCache) evictRandom() {
 		keyToEvict = key
 		break
 	}
-	c.Remove(keyToEvict)
+	c.remove(keyToEvict)
 }
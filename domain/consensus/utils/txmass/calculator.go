@@ -0,0 +1,57 @@
+package txmass
+
+import (
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/estimatedsize"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/transactionhelper"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
+)
+
+// Calculator exposes methods to calculate the mass of a transaction
+type Calculator struct {
+	massPerTxByte           uint64
+	massPerScriptPubKeyByte uint64
+	massPerSigOp            uint64
+}
+
+// NewCalculator creates a new instance of Calculator
+func NewCalculator(massPerTxByte, massPerScriptPubKeyByte, massPerSigOp uint64) *Calculator {
+	return &Calculator{
+		massPerTxByte:           massPerTxByte,
+		massPerScriptPubKeyByte: massPerScriptPubKeyByte,
+		massPerSigOp:            massPerSigOp,
+	}
+}
+
+// CalculateTransactionMass returns the mass of the given transaction.
+// The UTXOEntry of every input of the transaction must be populated
+func (c *Calculator) CalculateTransactionMass(transaction *externalapi.DomainTransaction) uint64 {
+	if transactionhelper.IsCoinBase(transaction) {
+		return 0
+	}
+
+	sigOpsCount := uint64(0)
+	for _, input := range transaction.Inputs {
+		// Count the precise number of signature operations in the
+		// referenced public key script.
+		scriptPublicKey := input.UTXOEntry.ScriptPublicKey()
+		isP2SH := txscript.IsPayToScriptHash(scriptPublicKey)
+		sigOpsCount += uint64(txscript.GetPreciseSigOpCount(input.SignatureScript, scriptPublicKey, isP2SH))
+	}
+
+	return c.calculateTransactionMassStandalonePart(transaction) + sigOpsCount*c.massPerSigOp
+}
+
+// calculateTransactionMassStandalonePart returns the part of the mass of the
+// given transaction that doesn't depend on the UTXOs it spends
+func (c *Calculator) calculateTransactionMassStandalonePart(transaction *externalapi.DomainTransaction) uint64 {
+	size := estimatedsize.TransactionEstimatedSerializedSize(transaction)
+
+	totalScriptPubKeySize := uint64(0)
+	for _, output := range transaction.Outputs {
+		totalScriptPubKeySize += 2 //output.ScriptPublicKey.Version (uint16)
+		totalScriptPubKeySize += uint64(len(output.ScriptPublicKey.Script))
+	}
+
+	return size*c.massPerTxByte + totalScriptPubKeySize*c.massPerScriptPubKeyByte
+}
@@ -334,9 +334,10 @@ func calcSignatureHash(prevScriptPublicKey []parsedOpcode, scriptVersion uint16,
 
 		// All but current output get zeroed out.
 		for i := 0; i < idx; i++ {
+			// The script public key is shared with the original transaction,
+			// so it's replaced rather than modified in place
 			txCopy.Outputs[i].Value = 0
-			txCopy.Outputs[i].ScriptPublicKey.Script = nil
-			txCopy.Outputs[i].ScriptPublicKey.Version = 0
+			txCopy.Outputs[i].ScriptPublicKey = &externalapi.ScriptPublicKey{Script: nil, Version: 0}
 		}
 
 		// Sequence on all other inputs is 0, too.
@@ -1,12 +1,16 @@
 package utxolrucache
 
 import (
+	"sync"
+
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 )
 
 // LRUCache is a least-recently-used cache for UTXO entries
 // indexed by DomainOutpoint
+// It is safe for concurrent access.
 type LRUCache struct {
+	lock     sync.RWMutex
 	cache    map[externalapi.DomainOutpoint]externalapi.UTXOEntry
 	capacity int
 }
@@ -27,6 +31,9 @@ func New(capacity int, preallocate bool) *LRUCache {
 
 // Add adds an entry to the LRUCache
 func (c *LRUCache) Add(key *externalapi.DomainOutpoint, value externalapi.UTXOEntry) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
 	c.cache[*key] = value
 
 	if len(c.cache) > c.capacity {
@@ -36,6 +43,9 @@ func (c *LRUCache) Add(key *externalapi.DomainOutpoint, value externalapi.UTXOEn
 
 // Get returns the entry for the given key, or (nil, false) otherwise
 func (c *LRUCache) Get(key *externalapi.DomainOutpoint) (externalapi.UTXOEntry, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
 	value, ok := c.cache[*key]
 	if !ok {
 		return nil, false
@@ -45,6 +55,9 @@ func (c *LRUCache) Get(key *externalapi.DomainOutpoint) (externalapi.UTXOEntry,
 
 // Has returns whether the LRUCache contains the given key
 func (c *LRUCache) Has(key *externalapi.DomainOutpoint) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
 	_, ok := c.cache[*key]
 	return ok
 }
@@ -52,11 +65,21 @@ func (c *LRUCache) Has(key *externalapi.DomainOutpoint) bool {
 // Remove removes the entry for the the given key. Does nothing if
 // the entry does not exist
 func (c *LRUCache) Remove(key *externalapi.DomainOutpoint) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.remove(key)
+}
+
+func (c *LRUCache) remove(key *externalapi.DomainOutpoint) {
 	delete(c.cache, *key)
 }
 
 // Clear clears the cache
 func (c *LRUCache) Clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
 	keys := make([]externalapi.DomainOutpoint, len(c.cache))
 	for outpoint := range c.cache {
 		keys = append(keys, outpoint)
@@ -73,5 +96,5 @@ func (c *LRUCache) evictRandom() {
 		keyToEvict = key
 		break
 	}
-	c.Remove(&keyToEvict)
+	c.remove(&keyToEvict)
 }
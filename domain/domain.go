@@ -1,6 +1,8 @@
 package domain
 
 import (
+	"time"
+
 	"github.com/kaspanet/kaspad/domain/consensus"
 	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
 	"github.com/kaspanet/kaspad/domain/dagconfig"
@@ -28,7 +30,9 @@ func (d domain) MiningManager() miningmanager.MiningManager {
 }
 
 // New instantiates a new instance of a Domain object
-func New(dagParams *dagconfig.Params, db infrastructuredatabase.Database, isArchivalNode bool) (Domain, error) {
+func New(dagParams *dagconfig.Params, db infrastructuredatabase.Database, isArchivalNode bool,
+	mempoolExpiry time.Duration) (Domain, error) {
+
 	consensusFactory := consensus.NewFactory()
 	consensusInstance, err := consensusFactory.NewConsensus(dagParams, db, isArchivalNode)
 	if err != nil {
@@ -37,7 +41,7 @@ func New(dagParams *dagconfig.Params, db infrastructuredatabase.Database, isArch
 
 	miningManagerFactory := miningmanager.NewFactory()
 	miningManager := miningManagerFactory.NewMiningManager(consensusInstance, dagParams.MaxMassAcceptedByBlock,
-		dagParams.RelayNonStdTxs)
+		dagParams.RelayNonStdTxs, mempoolExpiry)
 
 	return &domain{
 		consensus:     consensusInstance,
@@ -36,6 +36,11 @@ const (
 	// orphanExpireScanInterval is the minimum amount of time in between
 	// scans of the orphan pool to evict expired transactions.
 	orphanExpireScanInterval = time.Minute * 5
+
+	// transactionExpireScanInterval is the minimum amount of time in between
+	// scans of the mempool to evict transactions that are older than
+	// the maximum transaction age.
+	transactionExpireScanInterval = time.Minute
 )
 
 // policy houses the policy (configuration parameters) which is used to
@@ -63,6 +68,11 @@ type policy struct {
 	// MinRelayTxFee defines the minimum transaction fee in KAS/kB to be
 	// considered a non-zero fee.
 	MinRelayTxFee util.Amount
+
+	// MaxTransactionAge is the maximum amount of time a transaction is
+	// allowed to stay in the mempool before it expires and is evicted,
+	// along with its chained transactions, during the next scan.
+	MaxTransactionAge time.Duration
 }
 
 // mempool is used as a source of transactions that need to be mined into blocks
@@ -88,19 +98,33 @@ type mempool struct {
 	// to on an unconditional timer.
 	nextExpireScan mstime.Time
 
+	// nextTransactionExpireScan is the time after which the mempool will be
+	// scanned in order to evict expired transactions. The scan only runs
+	// when a transaction or a block is processed by the mempool.
+	nextTransactionExpireScan mstime.Time
+
+	// removedTransactions holds the transactions that were removed from the
+	// mempool and were not yet passed to onTransactionsRemovedHandler. It's
+	// passed to the handler only after the mempool lock is released
+	removedTransactions          []*miningmanagermodel.RemovedTransaction
+	onTransactionsRemovedHandler miningmanagermodel.OnTransactionsRemovedHandler
+
 	mtx    sync.RWMutex
 	policy policy
 }
 
 // New returns a new memory pool for validating and storing standalone
 // transactions until they are mined into a block.
-func New(consensus consensusexternalapi.Consensus, acceptNonStd bool) miningmanagermodel.Mempool {
+func New(consensus consensusexternalapi.Consensus, acceptNonStd bool,
+	maxTransactionAge time.Duration) miningmanagermodel.Mempool {
+
 	policy := policy{
-		MaxTxVersion:    constants.MaxTransactionVersion,
-		AcceptNonStd:    acceptNonStd,
-		MaxOrphanTxs:    5,
-		MaxOrphanTxSize: 100000,
-		MinRelayTxFee:   1000, // 1 sompi per byte
+		MaxTxVersion:      constants.MaxTransactionVersion,
+		AcceptNonStd:      acceptNonStd,
+		MaxOrphanTxs:      5,
+		MaxOrphanTxSize:   100000,
+		MinRelayTxFee:     1000, // 1 sompi per byte
+		MaxTransactionAge: maxTransactionAge,
 	}
 	return &mempool{
 		mtx:                                  sync.RWMutex{},
@@ -113,6 +137,7 @@ func New(consensus consensusexternalapi.Consensus, acceptNonStd bool) miningmana
 		mempoolUTXOSet:                       newMempoolUTXOSet(),
 		consensus:                            consensus,
 		nextExpireScan:                       mstime.Now().Add(orphanExpireScanInterval),
+		nextTransactionExpireScan:            mstime.Now().Add(transactionExpireScanInterval),
 	}
 }
 
@@ -145,6 +170,18 @@ func (mp *mempool) AllTransactions() []*consensusexternalapi.DomainTransaction {
 	return transactions
 }
 
+func (mp *mempool) OrphanTransactions() []*consensusexternalapi.DomainTransaction {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	transactions := make([]*consensusexternalapi.DomainTransaction, 0, len(mp.orphans))
+	for _, orphan := range mp.orphans {
+		transactions = append(transactions, orphan.tx)
+	}
+
+	return transactions
+}
+
 // txDescriptor is a descriptor containing a transaction in the mempool along with
 // additional metadata.
 type txDescriptor struct {
@@ -154,6 +191,9 @@ type txDescriptor struct {
 	// one that is accepted to pool, but cannot be mined in next block because it
 	// depends on outputs of accepted, but still not mined transaction
 	depCount int
+
+	// expiration is the time after which the transaction is evicted from the mempool
+	expiration mstime.Time
 }
 
 // orphanTx is normal transaction that references an ancestor transaction
@@ -372,11 +412,13 @@ func (mp *mempool) haveTransaction(txID *consensusexternalapi.DomainTransactionI
 	return mp.isTransactionInPool(txID) || mp.isOrphanInPool(txID)
 }
 
-// removeTransactionsFromPool removes given transactions from the mempool, and move their chained mempool
-// transactions (if any) to the main pool.
+// removeTransactionsFromPool removes given transactions from the mempool for the given reason, and move
+// their chained mempool transactions (if any) to the main pool.
 //
 // This function MUST be called with the mempool lock held (for writes).
-func (mp *mempool) removeTransactionsFromPool(txs []*consensusexternalapi.DomainTransaction) error {
+func (mp *mempool) removeTransactionsFromPool(txs []*consensusexternalapi.DomainTransaction,
+	reason miningmanagermodel.TransactionRemovalReason) error {
+
 	for _, tx := range txs[transactionhelper.CoinbaseTransactionIndex+1:] {
 		txID := consensushashing.TransactionID(tx)
 
@@ -386,7 +428,7 @@ func (mp *mempool) removeTransactionsFromPool(txs []*consensusexternalapi.Domain
 			continue
 		}
 
-		err := mp.cleanTransactionFromSets(mempoolTx.DomainTransaction)
+		err := mp.cleanTransactionFromSets(mempoolTx.DomainTransaction, reason)
 		if err != nil {
 			return err
 		}
@@ -396,16 +438,19 @@ func (mp *mempool) removeTransactionsFromPool(txs []*consensusexternalapi.Domain
 	return nil
 }
 
-// removeTransactionAndItsChainedTransactions removes a transaction and all of its chained transaction from the mempool.
+// removeTransactionAndItsChainedTransactions removes a transaction and all of its chained transaction from the
+// mempool for the given reason.
 //
 // This function MUST be called with the mempool lock held (for writes).
-func (mp *mempool) removeTransactionAndItsChainedTransactions(tx *consensusexternalapi.DomainTransaction) error {
+func (mp *mempool) removeTransactionAndItsChainedTransactions(tx *consensusexternalapi.DomainTransaction,
+	reason miningmanagermodel.TransactionRemovalReason) error {
+
 	txID := consensushashing.TransactionID(tx)
 	// Remove any transactions which rely on this one.
 	for i := uint32(0); i < uint32(len(tx.Outputs)); i++ {
 		prevOut := consensusexternalapi.DomainOutpoint{TransactionID: *txID, Index: i}
 		if txRedeemer, exists := mp.mempoolUTXOSet.poolTransactionBySpendingOutpoint(prevOut); exists {
-			err := mp.removeTransactionAndItsChainedTransactions(txRedeemer)
+			err := mp.removeTransactionAndItsChainedTransactions(txRedeemer, reason)
 			if err != nil {
 				return err
 			}
@@ -416,7 +461,7 @@ func (mp *mempool) removeTransactionAndItsChainedTransactions(tx *consensusexter
 		mp.removeChainTransaction(tx)
 	}
 
-	err := mp.cleanTransactionFromSets(tx)
+	err := mp.cleanTransactionFromSets(tx, reason)
 	if err != nil {
 		return err
 	}
@@ -424,11 +469,14 @@ func (mp *mempool) removeTransactionAndItsChainedTransactions(tx *consensusexter
 	return nil
 }
 
-// cleanTransactionFromSets removes the transaction from all mempool related transaction sets.
+// cleanTransactionFromSets removes the transaction from all mempool related transaction sets,
+// and records its removal for the given reason.
 // It assumes that any chained transaction is already cleaned from the mempool.
 //
 // This function MUST be called with the mempool lock held (for writes).
-func (mp *mempool) cleanTransactionFromSets(tx *consensusexternalapi.DomainTransaction) error {
+func (mp *mempool) cleanTransactionFromSets(tx *consensusexternalapi.DomainTransaction,
+	reason miningmanagermodel.TransactionRemovalReason) error {
+
 	err := mp.mempoolUTXOSet.removeTx(tx)
 	if err != nil {
 		return err
@@ -438,6 +486,13 @@ func (mp *mempool) cleanTransactionFromSets(tx *consensusexternalapi.DomainTrans
 	delete(mp.pool, *txID)
 	delete(mp.chainedTransactions, *txID)
 
+	if mp.onTransactionsRemovedHandler != nil {
+		mp.removedTransactions = append(mp.removedTransactions, &miningmanagermodel.RemovedTransaction{
+			Transaction: tx,
+			Reason:      reason,
+		})
+	}
+
 	return mp.removeTransactionFromOrderedTransactionsByFeeRate(tx)
 }
 
@@ -493,7 +548,8 @@ func (mp *mempool) removeDoubleSpends(tx *consensusexternalapi.DomainTransaction
 	for _, txIn := range tx.Inputs {
 		if txRedeemer, ok := mp.mempoolUTXOSet.poolTransactionBySpendingOutpoint(txIn.PreviousOutpoint); ok {
 			if !consensushashing.TransactionID(txRedeemer).Equal(txID) {
-				err := mp.removeTransactionAndItsChainedTransactions(txRedeemer)
+				err := mp.removeTransactionAndItsChainedTransactions(txRedeemer,
+					miningmanagermodel.TransactionRemovalReasonConflicted)
 				if err != nil {
 					return err
 				}
@@ -514,6 +570,7 @@ func (mp *mempool) addTransaction(tx *consensusexternalapi.DomainTransaction, pa
 	txDescriptor := &txDescriptor{
 		DomainTransaction: tx,
 		depCount:          len(parentsInPool),
+		expiration:        mstime.Now().Add(mp.policy.MaxTransactionAge),
 	}
 	txID := *consensushashing.TransactionID(tx)
 
@@ -588,6 +645,48 @@ func (mp *mempool) removeTransactionFromOrderedTransactionsByFeeRate(tx *consens
 	return nil
 }
 
+// expireTransactions evicts the transactions whose expiration time has passed,
+// along with their chained transactions, when it's time to scan the mempool.
+// This is done for efficiency so the scan only happens periodically instead
+// of on every transaction processed by the mempool.
+//
+// This function MUST be called with the mempool lock held (for writes).
+func (mp *mempool) expireTransactions() error {
+	now := mstime.Now()
+	if !now.After(mp.nextTransactionExpireScan) {
+		return nil
+	}
+	mp.nextTransactionExpireScan = now.Add(transactionExpireScanInterval)
+
+	var expiredTransactions []*consensusexternalapi.DomainTransaction
+	for _, txDescs := range []map[consensusexternalapi.DomainTransactionID]*txDescriptor{mp.pool, mp.chainedTransactions} {
+		for _, txDesc := range txDescs {
+			if now.After(txDesc.expiration) {
+				expiredTransactions = append(expiredTransactions, txDesc.DomainTransaction)
+			}
+		}
+	}
+	if len(expiredTransactions) == 0 {
+		return nil
+	}
+
+	originalCount := len(mp.pool) + len(mp.chainedTransactions)
+	for _, tx := range expiredTransactions {
+		// The transaction might have already been removed
+		// as a chained transaction of another expired transaction
+		if !mp.isTransactionInPool(consensushashing.TransactionID(tx)) {
+			continue
+		}
+		err := mp.removeTransactionAndItsChainedTransactions(tx, miningmanagermodel.TransactionRemovalReasonExpired)
+		if err != nil {
+			return err
+		}
+	}
+	count := len(mp.pool) + len(mp.chainedTransactions)
+	log.Debugf("Expired %d transactions (remaining: %d)", originalCount-count, count)
+	return nil
+}
+
 func (mp *mempool) enforceTransactionLimit() error {
 	const limit = 1_000_000
 	if len(mp.pool)+len(mp.chainedTransactions) > limit {
@@ -597,7 +696,7 @@ func (mp *mempool) enforceTransactionLimit() error {
 			limit,
 			consensushashing.TransactionID(txToRemove),
 		)
-		return mp.removeTransactionAndItsChainedTransactions(txToRemove)
+		return mp.removeTransactionAndItsChainedTransactions(txToRemove, miningmanagermodel.TransactionRemovalReasonEvicted)
 	}
 	return nil
 }
@@ -647,6 +746,38 @@ func (mp *mempool) fetchTxDesc(txID *consensusexternalapi.DomainTransactionID) (
 func (mp *mempool) maybeAcceptTransaction(tx *consensusexternalapi.DomainTransaction, rejectDupOrphans bool) (
 	[]*consensusexternalapi.DomainOutpoint, *txDescriptor, error) {
 
+	missingOutpoints, parentsInPool, err := mp.checkTransactionAcceptance(tx, rejectDupOrphans)
+	if err != nil || len(missingOutpoints) > 0 {
+		return missingOutpoints, nil, err
+	}
+
+	// Add to transaction pool.
+	txDesc, err := mp.addTransaction(tx, parentsInPool)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	log.Debugf("Accepted transaction %s (pool size: %d)", consensushashing.TransactionID(tx),
+		len(mp.pool))
+
+	err = mp.enforceTransactionLimit()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return nil, txDesc, nil
+}
+
+// checkTransactionAcceptance runs all the checks of maybeAcceptTransaction without
+// adding the transaction to the pool. It returns the outpoints the transaction spends
+// that are missing, if it's an orphan, and otherwise the outpoints it spends from
+// transactions in the pool.
+//
+// This function MUST be called with the mempool lock held (for reads).
+func (mp *mempool) checkTransactionAcceptance(tx *consensusexternalapi.DomainTransaction, rejectDupOrphans bool) (
+	missingOutpoints []*consensusexternalapi.DomainOutpoint, parentsInPool []consensusexternalapi.DomainOutpoint,
+	err error) {
+
 	txID := consensushashing.TransactionID(tx)
 
 	// Don't accept the transaction if it already exists in the pool. This
@@ -686,7 +817,7 @@ func (mp *mempool) maybeAcceptTransaction(tx *consensusexternalapi.DomainTransac
 	// at this point. There is a more in-depth check that happens later
 	// after fetching the referenced transaction inputs from the DAG
 	// which examines the actual spend data and prevents double spends.
-	err := mp.checkPoolDoubleSpend(tx)
+	err = mp.checkPoolDoubleSpend(tx)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -701,14 +832,14 @@ func (mp *mempool) maybeAcceptTransaction(tx *consensusexternalapi.DomainTransac
 	// don't exist or are already spent. Adding orphans to the orphan pool
 	// is not handled by this function, and the caller should use
 	// maybeAddOrphan if this behavior is desired.
-	parentsInPool := mp.mempoolUTXOSet.populateUTXOEntries(tx)
+	parentsInPool = mp.mempoolUTXOSet.populateUTXOEntries(tx)
 
 	// This will populate the missing UTXOEntries.
 	err = mp.consensus.ValidateTransactionAndPopulateWithConsensusData(tx)
-	missingOutpoints := ruleerrors.ErrMissingTxOut{}
+	missingTxOutErr := ruleerrors.ErrMissingTxOut{}
 	if err != nil {
-		if errors.As(err, &missingOutpoints) {
-			return missingOutpoints.MissingOutpoints, nil, nil
+		if errors.As(err, &missingTxOutErr) {
+			return missingTxOutErr.MissingOutpoints, nil, nil
 		}
 		if errors.As(err, &ruleerrors.RuleError{}) {
 			return nil, nil, newRuleError(err)
@@ -744,21 +875,7 @@ func (mp *mempool) maybeAcceptTransaction(tx *consensusexternalapi.DomainTransac
 			minFee)
 		return nil, nil, txRuleError(RejectInsufficientFee, str)
 	}
-	// Add to transaction pool.
-	txDesc, err := mp.addTransaction(tx, parentsInPool)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	log.Debugf("Accepted transaction %s (pool size: %d)", txID,
-		len(mp.pool))
-
-	err = mp.enforceTransactionLimit()
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return nil, txDesc, nil
+	return nil, parentsInPool, nil
 }
 
 // processOrphans determines if there are any orphans which depend on the passed
@@ -860,18 +977,40 @@ func (mp *mempool) processOrphans(acceptedTx *consensusexternalapi.DomainTransac
 // with any additional orphan transaactions that were added as a result of
 // the passed one being accepted.
 //
+// It returns the transactions that were accepted to the mempool: the passed
+// transaction (unless it was added to the orphan pool) followed by the orphans
+// that were accepted as a result.
+//
 // This function is safe for concurrent access.
-func (mp *mempool) ValidateAndInsertTransaction(tx *consensusexternalapi.DomainTransaction, allowOrphan bool) error {
+func (mp *mempool) ValidateAndInsertTransaction(tx *consensusexternalapi.DomainTransaction, allowOrphan bool) (
+	[]*consensusexternalapi.DomainTransaction, error) {
+
+	acceptedTxs, err := mp.validateAndInsertTransaction(tx, allowOrphan)
+	mp.notifyTransactionsRemoved()
+	if err != nil {
+		return nil, err
+	}
+	return acceptedTxs, nil
+}
+
+func (mp *mempool) validateAndInsertTransaction(tx *consensusexternalapi.DomainTransaction, allowOrphan bool) (
+	[]*consensusexternalapi.DomainTransaction, error) {
+
 	log.Tracef("Processing transaction %s", consensushashing.TransactionID(tx))
 
 	// Protect concurrent access.
 	mp.mtx.Lock()
 	defer mp.mtx.Unlock()
 
+	err := mp.expireTransactions()
+	if err != nil {
+		return nil, err
+	}
+
 	// Potentially accept the transaction to the memory pool.
 	missingParents, txD, err := mp.maybeAcceptTransaction(tx, true)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if len(missingParents) == 0 {
@@ -880,36 +1019,67 @@ func (mp *mempool) ValidateAndInsertTransaction(tx *consensusexternalapi.DomainT
 		// are now available) and repeat for those accepted
 		// transactions until there are no more.
 		newTxs := mp.processOrphans(tx)
-		acceptedTxs := make([]*txDescriptor, len(newTxs)+1)
+		acceptedTxs := make([]*consensusexternalapi.DomainTransaction, len(newTxs)+1)
 
 		// Add the parent transaction first so remote nodes
 		// do not add orphans.
-		acceptedTxs[0] = txD
-		copy(acceptedTxs[1:], newTxs)
+		acceptedTxs[0] = txD.DomainTransaction
+		for i, newTx := range newTxs {
+			acceptedTxs[i+1] = newTx.DomainTransaction
+		}
 
-		return nil
+		return acceptedTxs, nil
 	}
 
 	// The transaction is an orphan (has inputs missing). Reject
 	// it if the flag to allow orphans is not set.
 	if !allowOrphan {
-		// Only use the first missing parent transaction in
-		// the error message.
-		//
-		// NOTE: RejectDuplicate is really not an accurate
-		// reject code here, but it matches the reference
-		// implementation and there isn't a better choice due
-		// to the limited number of reject codes. Missing
-		// inputs is assumed to mean they are already spent
-		// which is not really always the case.
-		str := fmt.Sprintf("orphan transaction %s references "+
-			"outputs of unknown or fully-spent "+
-			"transaction %s", consensushashing.TransactionID(tx), missingParents[0])
-		return txRuleError(RejectDuplicate, str)
+		return nil, orphanRejectedError(tx, missingParents)
 	}
 
 	// Potentially add the orphan transaction to the orphan pool.
-	return mp.maybeAddOrphan(tx)
+	return nil, mp.maybeAddOrphan(tx)
+}
+
+// ValidateTransaction checks whether the given transaction would be accepted
+// to the mempool by ValidateAndInsertTransaction, without inserting it.
+// Orphan transactions are rejected.
+//
+// This function is safe for concurrent access.
+func (mp *mempool) ValidateTransaction(tx *consensusexternalapi.DomainTransaction) error {
+	log.Tracef("Validating transaction %s", consensushashing.TransactionID(tx))
+
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	missingParents, _, err := mp.checkTransactionAcceptance(tx, true)
+	if err != nil {
+		return err
+	}
+	if len(missingParents) > 0 {
+		return orphanRejectedError(tx, missingParents)
+	}
+	return nil
+}
+
+// orphanRejectedError returns the error for an orphan transaction
+// that is rejected since orphans are not allowed
+func orphanRejectedError(tx *consensusexternalapi.DomainTransaction,
+	missingParents []*consensusexternalapi.DomainOutpoint) error {
+
+	// Only use the first missing parent transaction in
+	// the error message.
+	//
+	// NOTE: RejectDuplicate is really not an accurate
+	// reject code here, but it matches the reference
+	// implementation and there isn't a better choice due
+	// to the limited number of reject codes. Missing
+	// inputs is assumed to mean they are already spent
+	// which is not really always the case.
+	str := fmt.Sprintf("orphan transaction %s references "+
+		"outputs of unknown or fully-spent "+
+		"transaction %s", consensushashing.TransactionID(tx), missingParents[0])
+	return txRuleError(RejectDuplicate, str)
 }
 
 // Count returns the number of transactions in the main pool. It does not
@@ -958,6 +1128,15 @@ func (mp *mempool) BlockCandidateTransactions() []*consensusexternalapi.DomainTr
 // from the mempool transactions that double spend a
 // transaction that is already in the DAG
 func (mp *mempool) HandleNewBlockTransactions(txs []*consensusexternalapi.DomainTransaction) ([]*consensusexternalapi.DomainTransaction, error) {
+	acceptedTxs, err := mp.handleNewBlockTransactions(txs)
+	mp.notifyTransactionsRemoved()
+	if err != nil {
+		return nil, err
+	}
+	return acceptedTxs, nil
+}
+
+func (mp *mempool) handleNewBlockTransactions(txs []*consensusexternalapi.DomainTransaction) ([]*consensusexternalapi.DomainTransaction, error) {
 	// Protect concurrent access.
 	mp.mtx.Lock()
 	defer mp.mtx.Unlock()
@@ -969,10 +1148,14 @@ func (mp *mempool) HandleNewBlockTransactions(txs []*consensusexternalapi.Domain
 	// no longer an orphan. Transactions which depend on a confirmed
 	// transaction are NOT removed recursively because they are still
 	// valid.
-	err := mp.removeTransactionsFromPool(txs)
+	err := mp.removeTransactionsFromPool(txs, miningmanagermodel.TransactionRemovalReasonMined)
 	if err != nil {
 		return nil, errors.Wrapf(err, "Failed removing txs from pool")
 	}
+	err = mp.expireTransactions()
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed expiring txs from pool")
+	}
 	acceptedTxs := make([]*consensusexternalapi.DomainTransaction, 0)
 	for _, tx := range txs[transactionhelper.CoinbaseTransactionIndex+1:] {
 		err := mp.removeDoubleSpends(tx)
@@ -989,10 +1172,152 @@ func (mp *mempool) HandleNewBlockTransactions(txs []*consensusexternalapi.Domain
 	return acceptedTxs, nil
 }
 
-func (mp *mempool) RemoveTransactions(txs []*consensusexternalapi.DomainTransaction) error {
+// UnspentOutputs returns the outputs of the transactions in the mempool
+// that are not spent by other transactions in the mempool.
+//
+// This function is safe for concurrent access.
+func (mp *mempool) UnspentOutputs() []*consensusexternalapi.OutpointAndUTXOEntryPair {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	return mp.mempoolUTXOSet.unspentOutputs()
+}
+
+// IsOutpointSpent returns whether the given outpoint is spent by a
+// transaction in the mempool.
+//
+// This function is safe for concurrent access.
+func (mp *mempool) IsOutpointSpent(outpoint *consensusexternalapi.DomainOutpoint) bool {
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	_, exists := mp.mempoolUTXOSet.poolTransactionBySpendingOutpoint(*outpoint)
+	return exists
+}
+
+// TransactionAncestors returns the transactions in the mempool that the
+// transaction with the given ID spends from, directly or indirectly.
+// It returns false if the transaction is not in the mempool.
+//
+// This function is safe for concurrent access.
+func (mp *mempool) TransactionAncestors(transactionID *consensusexternalapi.DomainTransactionID) (
+	[]*consensusexternalapi.DomainTransaction, bool) {
+
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	txDesc, exists := mp.fetchTxDesc(transactionID)
+	if !exists {
+		return nil, false
+	}
+
+	ancestors := make([]*consensusexternalapi.DomainTransaction, 0)
+	visited := make(map[consensusexternalapi.DomainTransactionID]struct{})
+	queue := []*consensusexternalapi.DomainTransaction{txDesc.DomainTransaction}
+	for len(queue) > 0 {
+		tx := queue[0]
+		queue = queue[1:]
+		for _, txIn := range tx.Inputs {
+			parentID := txIn.PreviousOutpoint.TransactionID
+			if _, ok := visited[parentID]; ok {
+				continue
+			}
+			parentDesc, exists := mp.fetchTxDesc(&parentID)
+			if !exists {
+				continue
+			}
+			visited[parentID] = struct{}{}
+			ancestors = append(ancestors, parentDesc.DomainTransaction)
+			queue = append(queue, parentDesc.DomainTransaction)
+		}
+	}
+	return ancestors, true
+}
+
+// TransactionDescendants returns the transactions in the mempool that spend
+// from the transaction with the given ID, directly or indirectly.
+// It returns false if the transaction is not in the mempool.
+//
+// This function is safe for concurrent access.
+func (mp *mempool) TransactionDescendants(transactionID *consensusexternalapi.DomainTransactionID) (
+	[]*consensusexternalapi.DomainTransaction, bool) {
+
+	mp.mtx.RLock()
+	defer mp.mtx.RUnlock()
+
+	txDesc, exists := mp.fetchTxDesc(transactionID)
+	if !exists {
+		return nil, false
+	}
+
+	descendants := make([]*consensusexternalapi.DomainTransaction, 0)
+	visited := make(map[consensusexternalapi.DomainTransactionID]struct{})
+	queue := []*consensusexternalapi.DomainTransaction{txDesc.DomainTransaction}
+	for len(queue) > 0 {
+		tx := queue[0]
+		queue = queue[1:]
+		outpoint := consensusexternalapi.DomainOutpoint{TransactionID: *consensushashing.TransactionID(tx)}
+		for i := range tx.Outputs {
+			outpoint.Index = uint32(i)
+			child, exists := mp.mempoolUTXOSet.poolTransactionBySpendingOutpoint(outpoint)
+			if !exists {
+				continue
+			}
+			childID := *consensushashing.TransactionID(child)
+			if _, ok := visited[childID]; ok {
+				continue
+			}
+			visited[childID] = struct{}{}
+			descendants = append(descendants, child)
+			queue = append(queue, child)
+		}
+	}
+	return descendants, true
+}
+
+func (mp *mempool) RemoveTransactions(txs []*consensusexternalapi.DomainTransaction,
+	reason miningmanagermodel.TransactionRemovalReason) error {
+
+	err := mp.removeTransactions(txs, reason)
+	mp.notifyTransactionsRemoved()
+	return err
+}
+
+func (mp *mempool) removeTransactions(txs []*consensusexternalapi.DomainTransaction,
+	reason miningmanagermodel.TransactionRemovalReason) error {
+
 	// Protect concurrent access.
 	mp.mtx.Lock()
 	defer mp.mtx.Unlock()
 
-	return mp.removeTransactionsFromPool(txs)
+	return mp.removeTransactionsFromPool(txs, reason)
+}
+
+// SetOnTransactionsRemovedHandler sets the handler that's called with the
+// transactions that are removed from the mempool
+//
+// This function is safe for concurrent access.
+func (mp *mempool) SetOnTransactionsRemovedHandler(handler miningmanagermodel.OnTransactionsRemovedHandler) {
+	mp.mtx.Lock()
+	defer mp.mtx.Unlock()
+
+	mp.onTransactionsRemovedHandler = handler
+}
+
+// notifyTransactionsRemoved passes the transactions that were removed from
+// the mempool since it was last called to onTransactionsRemovedHandler.
+//
+// This function MUST be called without the mempool lock held, so that the
+// handler could access the mempool.
+func (mp *mempool) notifyTransactionsRemoved() {
+	mp.mtx.Lock()
+	removedTransactions := mp.removedTransactions
+	mp.removedTransactions = nil
+	handler := mp.onTransactionsRemovedHandler
+	mp.mtx.Unlock()
+
+	if handler == nil || len(removedTransactions) == 0 {
+		return
+	}
+	handler(removedTransactions)
 }
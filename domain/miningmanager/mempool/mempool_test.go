@@ -0,0 +1,239 @@
+package mempool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/domain/consensus"
+	consensusexternalapi "github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/model/testapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/testutils"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	miningmanagermodel "github.com/kaspanet/kaspad/domain/miningmanager/model"
+	"github.com/kaspanet/kaspad/util/mstime"
+	"github.com/pkg/errors"
+)
+
+func TestExpireTransactions(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, params *dagconfig.Params) {
+		params.BlockCoinbaseMaturity = 0
+
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(params, false, "TestExpireTransactions")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		mp := New(tc, true, time.Hour).(*mempool)
+
+		// The coinbase of a block pays the miners of the blocks it merges, so only
+		// the coinbases of the blocks after the first one pay to the test's OP_TRUE script
+		addBlockAndGetCoinbase(t, tc)
+		firstFundingTransaction := addBlockAndGetCoinbase(t, tc)
+		secondFundingTransaction := addBlockAndGetCoinbase(t, tc)
+
+		parent := createTransactionWithFee(t, firstFundingTransaction)
+		child := createTransactionWithFee(t, parent)
+		for _, tx := range []*consensusexternalapi.DomainTransaction{parent, child} {
+			_, err := mp.ValidateAndInsertTransaction(tx, false)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+			}
+		}
+
+		// Make the parent expire, but don't let the next scan happen yet
+		parentDesc, _ := mp.fetchTxDesc(consensushashing.TransactionID(parent))
+		parentDesc.expiration = mstime.Now().Add(-time.Second)
+		unrelated := createTransactionWithFee(t, secondFundingTransaction)
+		_, err = mp.ValidateAndInsertTransaction(unrelated, false)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+		}
+		if len(mp.AllTransactions()) != 3 {
+			t.Fatalf("Expected no transaction to expire before the next scan, but got %d transactions",
+				len(mp.AllTransactions()))
+		}
+
+		// Once it's time to scan the mempool, the expired parent
+		// is evicted along with its chained transaction
+		mp.nextTransactionExpireScan = mstime.Now().Add(-time.Second)
+		_, err = mp.HandleNewBlockTransactions([]*consensusexternalapi.DomainTransaction{firstFundingTransaction})
+		if err != nil {
+			t.Fatalf("HandleNewBlockTransactions: %+v", err)
+		}
+		for _, tx := range []*consensusexternalapi.DomainTransaction{parent, child} {
+			if _, ok := mp.GetTransaction(consensushashing.TransactionID(tx)); ok {
+				t.Fatalf("Expected transaction %s to expire", consensushashing.TransactionID(tx))
+			}
+			if mp.IsOutpointSpent(&tx.Inputs[0].PreviousOutpoint) {
+				t.Fatalf("Expected the outpoint spent by transaction %s to be unspent in the mempool",
+					consensushashing.TransactionID(tx))
+			}
+		}
+		if _, ok := mp.GetTransaction(consensushashing.TransactionID(unrelated)); !ok {
+			t.Fatalf("Expected transaction %s not to expire", consensushashing.TransactionID(unrelated))
+		}
+	})
+}
+
+func TestOnTransactionsRemovedHandler(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, params *dagconfig.Params) {
+		params.BlockCoinbaseMaturity = 0
+
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(params, false, "TestOnTransactionsRemovedHandler")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		mp := New(tc, true, time.Hour).(*mempool)
+		var removedTransactions []*miningmanagermodel.RemovedTransaction
+		mp.SetOnTransactionsRemovedHandler(func(removed []*miningmanagermodel.RemovedTransaction) {
+			removedTransactions = append(removedTransactions, removed...)
+		})
+
+		addBlockAndGetCoinbase(t, tc)
+		firstFundingTransaction := addBlockAndGetCoinbase(t, tc)
+		secondFundingTransaction := addBlockAndGetCoinbase(t, tc)
+
+		parent := createTransactionWithFee(t, firstFundingTransaction)
+		child := createTransactionWithFee(t, parent)
+		doubleSpent := createTransactionWithFee(t, secondFundingTransaction)
+		for _, tx := range []*consensusexternalapi.DomainTransaction{parent, child, doubleSpent} {
+			_, err := mp.ValidateAndInsertTransaction(tx, false)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+			}
+		}
+		if len(removedTransactions) != 0 {
+			t.Fatalf("Expected no transactions to be removed, but got %d", len(removedTransactions))
+		}
+
+		// A block that includes the parent along with a transaction that double
+		// spends doubleSpent removes both from the mempool, but keeps the child
+		doubleSpender := createTransactionWithFee(t, secondFundingTransaction)
+		doubleSpender.Outputs[0].Value--
+		_, err = mp.HandleNewBlockTransactions(
+			[]*consensusexternalapi.DomainTransaction{secondFundingTransaction, parent, doubleSpender})
+		if err != nil {
+			t.Fatalf("HandleNewBlockTransactions: %+v", err)
+		}
+		checkRemovedTransactions(t, removedTransactions, map[consensusexternalapi.DomainTransactionID]miningmanagermodel.TransactionRemovalReason{
+			*consensushashing.TransactionID(parent):      miningmanagermodel.TransactionRemovalReasonMined,
+			*consensushashing.TransactionID(doubleSpent): miningmanagermodel.TransactionRemovalReasonConflicted,
+		})
+
+		removedTransactions = nil
+		childDesc, _ := mp.fetchTxDesc(consensushashing.TransactionID(child))
+		childDesc.expiration = mstime.Now().Add(-time.Second)
+		mp.nextTransactionExpireScan = mstime.Now().Add(-time.Second)
+		_, err = mp.HandleNewBlockTransactions([]*consensusexternalapi.DomainTransaction{secondFundingTransaction})
+		if err != nil {
+			t.Fatalf("HandleNewBlockTransactions: %+v", err)
+		}
+		checkRemovedTransactions(t, removedTransactions, map[consensusexternalapi.DomainTransactionID]miningmanagermodel.TransactionRemovalReason{
+			*consensushashing.TransactionID(child): miningmanagermodel.TransactionRemovalReasonExpired,
+		})
+	})
+}
+
+func TestValidateTransaction(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, params *dagconfig.Params) {
+		params.BlockCoinbaseMaturity = 0
+
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(params, false, "TestValidateTransaction")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		mp := New(tc, true, time.Hour).(*mempool)
+
+		addBlockAndGetCoinbase(t, tc)
+		fundingTransaction := addBlockAndGetCoinbase(t, tc)
+
+		parent := createTransactionWithFee(t, fundingTransaction)
+		err = mp.ValidateTransaction(parent)
+		if err != nil {
+			t.Fatalf("ValidateTransaction: %+v", err)
+		}
+		if _, ok := mp.GetTransaction(consensushashing.TransactionID(parent)); ok {
+			t.Fatalf("Expected ValidateTransaction not to insert the transaction")
+		}
+
+		// The child is an orphan as long as the parent isn't in the mempool
+		child := createTransactionWithFee(t, parent)
+		err = mp.ValidateTransaction(child)
+		if !errors.As(err, &RuleError{}) {
+			t.Fatalf("Expected ValidateTransaction to reject an orphan with a RuleError, but got %+v", err)
+		}
+		if len(mp.OrphanTransactions()) != 0 {
+			t.Fatalf("Expected ValidateTransaction not to add the orphan to the orphan pool")
+		}
+
+		_, err = mp.ValidateAndInsertTransaction(parent, false)
+		if err != nil {
+			t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+		}
+		err = mp.ValidateTransaction(child)
+		if err != nil {
+			t.Fatalf("ValidateTransaction: %+v", err)
+		}
+		err = mp.ValidateTransaction(parent)
+		if !errors.As(err, &RuleError{}) {
+			t.Fatalf("Expected ValidateTransaction to reject a transaction that is in the mempool "+
+				"with a RuleError, but got %+v", err)
+		}
+	})
+}
+
+func checkRemovedTransactions(t *testing.T, removedTransactions []*miningmanagermodel.RemovedTransaction,
+	expectedReasons map[consensusexternalapi.DomainTransactionID]miningmanagermodel.TransactionRemovalReason) {
+
+	if len(removedTransactions) != len(expectedReasons) {
+		t.Fatalf("Expected %d transactions to be removed, but got %d", len(expectedReasons), len(removedTransactions))
+	}
+	for _, removedTransaction := range removedTransactions {
+		transactionID := consensushashing.TransactionID(removedTransaction.Transaction)
+		expectedReason, ok := expectedReasons[*transactionID]
+		if !ok {
+			t.Fatalf("Transaction %s was unexpectedly removed", transactionID)
+		}
+		if removedTransaction.Reason != expectedReason {
+			t.Fatalf("Expected transaction %s to be removed because it was %s, but it was %s",
+				transactionID, expectedReason, removedTransaction.Reason)
+		}
+	}
+}
+
+func addBlockAndGetCoinbase(t *testing.T, tc testapi.TestConsensus) *consensusexternalapi.DomainTransaction {
+	virtualInfo, err := tc.GetVirtualInfo()
+	if err != nil {
+		t.Fatalf("GetVirtualInfo: %+v", err)
+	}
+	blockHash, _, err := tc.AddBlock(virtualInfo.ParentHashes, nil, nil)
+	if err != nil {
+		t.Fatalf("AddBlock: %+v", err)
+	}
+	block, err := tc.GetBlock(blockHash)
+	if err != nil {
+		t.Fatalf("GetBlock: %+v", err)
+	}
+	return block.Transactions[0]
+}
+
+func createTransactionWithFee(t *testing.T,
+	txToSpend *consensusexternalapi.DomainTransaction) *consensusexternalapi.DomainTransaction {
+
+	const fee = 10000
+	tx, err := testutils.CreateTransaction(txToSpend)
+	if err != nil {
+		t.Fatalf("CreateTransaction: %+v", err)
+	}
+	tx.Outputs[0].Value = txToSpend.Outputs[0].Value - fee
+	return tx
+}
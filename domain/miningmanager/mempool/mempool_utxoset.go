@@ -101,3 +101,20 @@ func (mpus *mempoolUTXOSet) poolTransactionBySpendingOutpoint(outpoint consensus
 	tx, exists := mpus.transactionByPreviousOutpoint[outpoint]
 	return tx, exists
 }
+
+// unspentOutputs returns the outputs of the pool transactions that are
+// not spent by other pool transactions
+func (mpus *mempoolUTXOSet) unspentOutputs() []*consensusexternalapi.OutpointAndUTXOEntryPair {
+	unspentOutputs := make([]*consensusexternalapi.OutpointAndUTXOEntryPair, 0, len(mpus.poolUnspentOutputs))
+	for outpoint, utxoEntry := range mpus.poolUnspentOutputs {
+		if _, isSpent := mpus.transactionByPreviousOutpoint[outpoint]; isSpent {
+			continue
+		}
+		outpoint := outpoint
+		unspentOutputs = append(unspentOutputs, &consensusexternalapi.OutpointAndUTXOEntryPair{
+			Outpoint:  &outpoint,
+			UTXOEntry: utxoEntry,
+		})
+	}
+	return unspentOutputs
+}
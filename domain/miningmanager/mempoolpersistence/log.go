@@ -0,0 +1,7 @@
+package mempoolpersistence
+
+import (
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+)
+
+var log = logger.RegisterSubSystem("TXMP")
@@ -0,0 +1,292 @@
+// Package mempoolpersistence saves the transactions of the mempool to a file,
+// and loads them back into the mempool, so that they survive restarts.
+package mempoolpersistence
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/kaspanet/kaspad/domain/consensus/database/serialization"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/miningmanager"
+	"github.com/kaspanet/kaspad/domain/miningmanager/mempool"
+	"github.com/kaspanet/kaspad/util/mstime"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	dumpFileName = "mempool.dat"
+
+	// dumpVersion is the version of the dump file format.
+	// Files of any other version are ignored
+	dumpVersion = 1
+
+	// maxDumpAge is the age after which a dump file is considered stale
+	// and is not loaded. Most of its transactions are expected to have
+	// been either accepted to the DAG or double spent by then
+	maxDumpAge = 24 * time.Hour
+)
+
+// dumpLock prevents concurrent dumps from writing the same temporary file
+var dumpLock sync.Mutex
+
+// DumpFilePath returns the path of the mempool dump file within the given data directory
+func DumpFilePath(dataDir string) string {
+	return filepath.Join(dataDir, dumpFileName)
+}
+
+// Dump writes all the transactions in the mempool, including orphans, to the file at the given path.
+// It returns the number of transactions that were written.
+//
+// The file is replaced atomically, so a dump that fails midway never corrupts a previous dump.
+func Dump(miningManager miningmanager.MiningManager, path string) (int, error) {
+	dumpLock.Lock()
+	defer dumpLock.Unlock()
+
+	transactions := sortTransactionsByDependencies(miningManager.AllTransactions())
+	orphans := miningManager.OrphanTransactions()
+
+	buffer := &bytes.Buffer{}
+	err := writeHeader(buffer, mstime.Now(), len(transactions)+len(orphans))
+	if err != nil {
+		return 0, err
+	}
+	for _, transaction := range transactions {
+		err := writeTransaction(buffer, transaction, false)
+		if err != nil {
+			return 0, err
+		}
+	}
+	for _, orphan := range orphans {
+		err := writeTransaction(buffer, orphan, true)
+		if err != nil {
+			return 0, err
+		}
+	}
+	err = binary.Write(buffer, binary.LittleEndian, crc32.ChecksumIEEE(buffer.Bytes()))
+	if err != nil {
+		return 0, err
+	}
+
+	err = writeFileAtomically(path, buffer.Bytes())
+	if err != nil {
+		return 0, err
+	}
+	return len(transactions) + len(orphans), nil
+}
+
+// Load reads the transactions in the file at the given path and inserts them into the mempool.
+// Every transaction is validated again, and transactions that are no longer valid are dropped.
+//
+// A missing file is ignored. A corrupted file is moved aside to path.corrupt, and a stale file
+// is removed, so that neither of them prevents the node from starting.
+func Load(miningManager miningmanager.MiningManager, path string) error {
+	fileBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	dumpTime, entries, err := parseDump(fileBytes)
+	if err != nil {
+		log.Warnf("Ignoring the corrupted mempool dump at '%s': %s", path, err)
+		return os.Rename(path, path+".corrupt")
+	}
+	if age := mstime.Since(dumpTime); age > maxDumpAge {
+		log.Infof("Ignoring the mempool dump at '%s' since it's stale (created %s ago)", path, age)
+		return os.Remove(path)
+	}
+
+	acceptedCount := 0
+	for _, entry := range entries {
+		_, err := miningManager.ValidateAndInsertTransaction(entry.transaction, entry.isOrphan)
+		if err != nil {
+			if !errors.As(err, &mempool.RuleError{}) {
+				return err
+			}
+			log.Debugf("Dropping transaction %s from the mempool dump: %s",
+				consensushashing.TransactionID(entry.transaction), err)
+			continue
+		}
+		acceptedCount++
+	}
+	log.Infof("Loaded %d out of %d transactions from the mempool dump at '%s'", acceptedCount, len(entries), path)
+	return nil
+}
+
+type dumpEntry struct {
+	transaction *externalapi.DomainTransaction
+	isOrphan    bool
+}
+
+func writeHeader(writer io.Writer, dumpTime mstime.Time, transactionCount int) error {
+	err := binary.Write(writer, binary.LittleEndian, uint32(dumpVersion))
+	if err != nil {
+		return err
+	}
+	err = binary.Write(writer, binary.LittleEndian, dumpTime.UnixMilliseconds())
+	if err != nil {
+		return err
+	}
+	return binary.Write(writer, binary.LittleEndian, uint32(transactionCount))
+}
+
+func writeTransaction(writer io.Writer, transaction *externalapi.DomainTransaction, isOrphan bool) error {
+	transactionBytes, err := proto.Marshal(serialization.DomainTransactionToDbTransaction(transaction))
+	if err != nil {
+		return err
+	}
+	err = binary.Write(writer, binary.LittleEndian, isOrphan)
+	if err != nil {
+		return err
+	}
+	err = binary.Write(writer, binary.LittleEndian, uint32(len(transactionBytes)))
+	if err != nil {
+		return err
+	}
+	_, err = writer.Write(transactionBytes)
+	return err
+}
+
+func parseDump(fileBytes []byte) (dumpTime mstime.Time, entries []*dumpEntry, err error) {
+	const checksumSize = 4
+	if len(fileBytes) < checksumSize {
+		return mstime.Time{}, nil, errors.New("the file is too short")
+	}
+	content := fileBytes[:len(fileBytes)-checksumSize]
+	checksum := binary.LittleEndian.Uint32(fileBytes[len(fileBytes)-checksumSize:])
+	if crc32.ChecksumIEEE(content) != checksum {
+		return mstime.Time{}, nil, errors.New("checksum mismatch")
+	}
+
+	reader := bytes.NewReader(content)
+	var version uint32
+	err = binary.Read(reader, binary.LittleEndian, &version)
+	if err != nil {
+		return mstime.Time{}, nil, err
+	}
+	if version != dumpVersion {
+		return mstime.Time{}, nil, errors.Errorf("unknown version %d", version)
+	}
+	var dumpTimeMilliseconds int64
+	err = binary.Read(reader, binary.LittleEndian, &dumpTimeMilliseconds)
+	if err != nil {
+		return mstime.Time{}, nil, err
+	}
+	var transactionCount uint32
+	err = binary.Read(reader, binary.LittleEndian, &transactionCount)
+	if err != nil {
+		return mstime.Time{}, nil, err
+	}
+
+	entries = make([]*dumpEntry, 0, transactionCount)
+	for i := uint32(0); i < transactionCount; i++ {
+		entry, err := readTransaction(reader)
+		if err != nil {
+			return mstime.Time{}, nil, err
+		}
+		entries = append(entries, entry)
+	}
+	if reader.Len() != 0 {
+		return mstime.Time{}, nil, errors.Errorf("unexpected %d bytes after the last transaction", reader.Len())
+	}
+	return mstime.UnixMilliseconds(dumpTimeMilliseconds), entries, nil
+}
+
+func readTransaction(reader *bytes.Reader) (*dumpEntry, error) {
+	var isOrphan bool
+	err := binary.Read(reader, binary.LittleEndian, &isOrphan)
+	if err != nil {
+		return nil, err
+	}
+	var transactionLength uint32
+	err = binary.Read(reader, binary.LittleEndian, &transactionLength)
+	if err != nil {
+		return nil, err
+	}
+	if int(transactionLength) > reader.Len() {
+		return nil, errors.Errorf("transaction length %d exceeds the remaining %d bytes",
+			transactionLength, reader.Len())
+	}
+	transactionBytes := make([]byte, transactionLength)
+	_, err = io.ReadFull(reader, transactionBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	dbTransaction := &serialization.DbTransaction{}
+	err = proto.Unmarshal(transactionBytes, dbTransaction)
+	if err != nil {
+		return nil, err
+	}
+	transaction, err := serialization.DbTransactionToDomainTransaction(dbTransaction)
+	if err != nil {
+		return nil, err
+	}
+	return &dumpEntry{transaction: transaction, isOrphan: isOrphan}, nil
+}
+
+func writeFileAtomically(path string, content []byte) error {
+	temporaryPath := path + ".tmp"
+	file, err := os.Create(temporaryPath)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(content)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	err = file.Sync()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	err = file.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(temporaryPath, path)
+}
+
+// sortTransactionsByDependencies returns the given transactions ordered such that
+// every transaction comes after the transactions it spends from, so that chained
+// transactions can be inserted back into the mempool in order
+func sortTransactionsByDependencies(transactions []*externalapi.DomainTransaction) []*externalapi.DomainTransaction {
+	transactionsByID := make(map[externalapi.DomainTransactionID]*externalapi.DomainTransaction, len(transactions))
+	for _, transaction := range transactions {
+		transactionsByID[*consensushashing.TransactionID(transaction)] = transaction
+	}
+
+	sorted := make([]*externalapi.DomainTransaction, 0, len(transactions))
+	visited := make(map[externalapi.DomainTransactionID]struct{}, len(transactions))
+	var visit func(transactionID externalapi.DomainTransactionID)
+	visit = func(transactionID externalapi.DomainTransactionID) {
+		if _, ok := visited[transactionID]; ok {
+			return
+		}
+		visited[transactionID] = struct{}{}
+		transaction := transactionsByID[transactionID]
+		for _, input := range transaction.Inputs {
+			if _, ok := transactionsByID[input.PreviousOutpoint.TransactionID]; ok {
+				visit(input.PreviousOutpoint.TransactionID)
+			}
+		}
+		sorted = append(sorted, transaction)
+	}
+	for _, transaction := range transactions {
+		visit(*consensushashing.TransactionID(transaction))
+	}
+	return sorted
+}
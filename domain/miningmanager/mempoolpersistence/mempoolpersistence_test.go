@@ -0,0 +1,201 @@
+package mempoolpersistence
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/domain/consensus"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/model/testapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/testutils"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/domain/miningmanager"
+)
+
+func TestDumpAndLoad(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, params *dagconfig.Params) {
+		params.BlockCoinbaseMaturity = 0
+
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(params, false, "TestDumpAndLoad")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		dataDir, err := ioutil.TempDir("", "TestDumpAndLoad")
+		if err != nil {
+			t.Fatalf("TempDir: %+v", err)
+		}
+		defer os.RemoveAll(dataDir)
+		path := DumpFilePath(dataDir)
+
+		miningManager := miningmanager.NewFactory().NewMiningManager(tc, params.MaxMassAcceptedByBlock, true, time.Hour)
+
+		// The coinbase of a block pays the miners of the blocks it merges, so only
+		// the coinbase of the second block pays to the test's OP_TRUE script
+		addBlockAndGetCoinbase(t, tc)
+		fundingTransaction := addBlockAndGetCoinbase(t, tc)
+		transaction := createTransactionWithFee(t, fundingTransaction)
+		chainedTransaction := createTransactionWithFee(t, transaction)
+		orphanTransaction := createTransactionWithFee(t, createTransactionWithFee(t, chainedTransaction))
+
+		// Insert the chained transaction first, so that the dump has to reorder it after its parent
+		for _, tx := range []*externalapi.DomainTransaction{chainedTransaction, transaction, orphanTransaction} {
+			_, err := miningManager.ValidateAndInsertTransaction(tx, true)
+			if err != nil {
+				t.Fatalf("ValidateAndInsertTransaction: %+v", err)
+			}
+		}
+		if len(miningManager.AllTransactions()) != 2 || len(miningManager.OrphanTransactions()) != 1 {
+			t.Fatalf("Expected 2 transactions and 1 orphan in the mempool, but got %d and %d",
+				len(miningManager.AllTransactions()), len(miningManager.OrphanTransactions()))
+		}
+
+		transactionCount, err := Dump(miningManager, path)
+		if err != nil {
+			t.Fatalf("Dump: %+v", err)
+		}
+		if transactionCount != 3 {
+			t.Fatalf("Expected to dump 3 transactions, but dumped %d", transactionCount)
+		}
+
+		loadedMiningManager := miningmanager.NewFactory().NewMiningManager(tc, params.MaxMassAcceptedByBlock, true, time.Hour)
+		err = Load(loadedMiningManager, path)
+		if err != nil {
+			t.Fatalf("Load: %+v", err)
+		}
+		for _, tx := range []*externalapi.DomainTransaction{transaction, chainedTransaction} {
+			if _, ok := loadedMiningManager.GetTransaction(consensushashing.TransactionID(tx)); !ok {
+				t.Fatalf("Expected transaction %s to be loaded into the mempool", consensushashing.TransactionID(tx))
+			}
+		}
+		orphans := loadedMiningManager.OrphanTransactions()
+		if len(orphans) != 1 || !consensushashing.TransactionID(orphans[0]).Equal(consensushashing.TransactionID(orphanTransaction)) {
+			t.Fatalf("Expected the orphan transaction to be loaded into the orphan pool")
+		}
+
+		// Transactions that were double spent by the DAG since the dump was made are dropped
+		// while loading, and the orphan that depends on them remains an orphan
+		virtualInfo, err := tc.GetVirtualInfo()
+		if err != nil {
+			t.Fatalf("GetVirtualInfo: %+v", err)
+		}
+		doubleSpendingTransaction := createTransactionWithFee(t, fundingTransaction)
+		doubleSpendingTransaction.Outputs[0].Value--
+		_, _, err = tc.AddBlock(virtualInfo.ParentHashes, nil,
+			[]*externalapi.DomainTransaction{doubleSpendingTransaction})
+		if err != nil {
+			t.Fatalf("AddBlock: %+v", err)
+		}
+		reloadedMiningManager := miningmanager.NewFactory().NewMiningManager(tc, params.MaxMassAcceptedByBlock, true, time.Hour)
+		err = Load(reloadedMiningManager, path)
+		if err != nil {
+			t.Fatalf("Load: %+v", err)
+		}
+		if len(reloadedMiningManager.AllTransactions()) != 0 {
+			t.Fatalf("Expected the double spent transactions to be dropped, but got %d transactions",
+				len(reloadedMiningManager.AllTransactions()))
+		}
+		if len(reloadedMiningManager.OrphanTransactions()) != 1 {
+			t.Fatalf("Expected 1 orphan, but got %d", len(reloadedMiningManager.OrphanTransactions()))
+		}
+	})
+}
+
+func TestLoadMissingAndCorruptedFiles(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, params *dagconfig.Params) {
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(params, false, "TestLoadMissingAndCorruptedFiles")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		dataDir, err := ioutil.TempDir("", "TestLoadMissingAndCorruptedFiles")
+		if err != nil {
+			t.Fatalf("TempDir: %+v", err)
+		}
+		defer os.RemoveAll(dataDir)
+		path := DumpFilePath(dataDir)
+
+		miningManager := miningmanager.NewFactory().NewMiningManager(tc, params.MaxMassAcceptedByBlock, true, time.Hour)
+
+		// A missing file is not an error
+		err = Load(miningManager, path)
+		if err != nil {
+			t.Fatalf("Load: %+v", err)
+		}
+
+		// A corrupted file is moved aside
+		err = ioutil.WriteFile(path, []byte("not a mempool dump"), 0600)
+		if err != nil {
+			t.Fatalf("WriteFile: %+v", err)
+		}
+		err = Load(miningManager, path)
+		if err != nil {
+			t.Fatalf("Load: %+v", err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("Expected the corrupted file to be moved away from '%s'", path)
+		}
+		if _, err := os.Stat(path + ".corrupt"); err != nil {
+			t.Fatalf("Expected the corrupted file to be moved to '%s.corrupt': %+v", path, err)
+		}
+
+		// A file whose checksum doesn't match is treated the same way
+		_, err = Dump(miningManager, path)
+		if err != nil {
+			t.Fatalf("Dump: %+v", err)
+		}
+		fileBytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatalf("ReadFile: %+v", err)
+		}
+		fileBytes[0] ^= 0xff
+		err = ioutil.WriteFile(path, fileBytes, 0600)
+		if err != nil {
+			t.Fatalf("WriteFile: %+v", err)
+		}
+		err = Load(miningManager, path)
+		if err != nil {
+			t.Fatalf("Load: %+v", err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("Expected the corrupted file to be moved away from '%s'", path)
+		}
+		if _, err := os.Stat(filepath.Join(dataDir, dumpFileName+".tmp")); !os.IsNotExist(err) {
+			t.Fatalf("Expected Dump not to leave a temporary file behind")
+		}
+	})
+}
+
+func addBlockAndGetCoinbase(t *testing.T, tc testapi.TestConsensus) *externalapi.DomainTransaction {
+	virtualInfo, err := tc.GetVirtualInfo()
+	if err != nil {
+		t.Fatalf("GetVirtualInfo: %+v", err)
+	}
+	blockHash, _, err := tc.AddBlock(virtualInfo.ParentHashes, nil, nil)
+	if err != nil {
+		t.Fatalf("AddBlock: %+v", err)
+	}
+	block, err := tc.GetBlock(blockHash)
+	if err != nil {
+		t.Fatalf("GetBlock: %+v", err)
+	}
+	return block.Transactions[0]
+}
+
+func createTransactionWithFee(t *testing.T, txToSpend *externalapi.DomainTransaction) *externalapi.DomainTransaction {
+	const fee = 10000
+	tx, err := testutils.CreateTransaction(txToSpend)
+	if err != nil {
+		t.Fatalf("CreateTransaction: %+v", err)
+	}
+	tx.Outputs[0].Value = txToSpend.Outputs[0].Value - fee
+	return tx
+}
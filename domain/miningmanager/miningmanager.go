@@ -11,8 +11,18 @@ type MiningManager interface {
 	GetBlockTemplate(coinbaseData *consensusexternalapi.DomainCoinbaseData) (*consensusexternalapi.DomainBlock, error)
 	GetTransaction(transactionID *consensusexternalapi.DomainTransactionID) (*consensusexternalapi.DomainTransaction, bool)
 	AllTransactions() []*consensusexternalapi.DomainTransaction
+	OrphanTransactions() []*consensusexternalapi.DomainTransaction
 	HandleNewBlockTransactions(txs []*consensusexternalapi.DomainTransaction) ([]*consensusexternalapi.DomainTransaction, error)
-	ValidateAndInsertTransaction(transaction *consensusexternalapi.DomainTransaction, allowOrphan bool) error
+	ValidateAndInsertTransaction(transaction *consensusexternalapi.DomainTransaction, allowOrphan bool) (
+		acceptedTransactions []*consensusexternalapi.DomainTransaction, err error)
+	ValidateTransaction(transaction *consensusexternalapi.DomainTransaction) error
+	UnspentOutputs() []*consensusexternalapi.OutpointAndUTXOEntryPair
+	IsOutpointSpent(outpoint *consensusexternalapi.DomainOutpoint) bool
+	TransactionAncestors(transactionID *consensusexternalapi.DomainTransactionID) (
+		[]*consensusexternalapi.DomainTransaction, bool)
+	TransactionDescendants(transactionID *consensusexternalapi.DomainTransactionID) (
+		[]*consensusexternalapi.DomainTransaction, bool)
+	SetOnTransactionsRemovedHandler(handler miningmanagermodel.OnTransactionsRemovedHandler)
 }
 
 type miningManager struct {
@@ -32,11 +42,21 @@ func (mm *miningManager) HandleNewBlockTransactions(txs []*consensusexternalapi.
 
 // ValidateAndInsertTransaction validates the given transaction, and
 // adds it to the set of known transactions that have not yet been
-// added to any block
-func (mm *miningManager) ValidateAndInsertTransaction(transaction *consensusexternalapi.DomainTransaction, allowOrphan bool) error {
+// added to any block. It returns the transactions that were accepted
+// as a result, which include previously orphaned ones
+func (mm *miningManager) ValidateAndInsertTransaction(transaction *consensusexternalapi.DomainTransaction, allowOrphan bool) (
+	[]*consensusexternalapi.DomainTransaction, error) {
+
 	return mm.mempool.ValidateAndInsertTransaction(transaction, allowOrphan)
 }
 
+// ValidateTransaction checks whether the given transaction would be
+// accepted by ValidateAndInsertTransaction without inserting it.
+// Orphan transactions are rejected
+func (mm *miningManager) ValidateTransaction(transaction *consensusexternalapi.DomainTransaction) error {
+	return mm.mempool.ValidateTransaction(transaction)
+}
+
 func (mm *miningManager) GetTransaction(
 	transactionID *consensusexternalapi.DomainTransactionID) (*consensusexternalapi.DomainTransaction, bool) {
 
@@ -46,3 +66,42 @@ func (mm *miningManager) GetTransaction(
 func (mm *miningManager) AllTransactions() []*consensusexternalapi.DomainTransaction {
 	return mm.mempool.AllTransactions()
 }
+
+// OrphanTransactions returns the transactions in the orphan pool
+func (mm *miningManager) OrphanTransactions() []*consensusexternalapi.DomainTransaction {
+	return mm.mempool.OrphanTransactions()
+}
+
+// UnspentOutputs returns the outputs of the transactions in the mempool
+// that are not spent by other transactions in the mempool
+func (mm *miningManager) UnspentOutputs() []*consensusexternalapi.OutpointAndUTXOEntryPair {
+	return mm.mempool.UnspentOutputs()
+}
+
+// IsOutpointSpent returns whether the given outpoint is spent by a
+// transaction in the mempool
+func (mm *miningManager) IsOutpointSpent(outpoint *consensusexternalapi.DomainOutpoint) bool {
+	return mm.mempool.IsOutpointSpent(outpoint)
+}
+
+// TransactionAncestors returns the transactions in the mempool that the
+// given transaction spends from, directly or indirectly
+func (mm *miningManager) TransactionAncestors(transactionID *consensusexternalapi.DomainTransactionID) (
+	[]*consensusexternalapi.DomainTransaction, bool) {
+
+	return mm.mempool.TransactionAncestors(transactionID)
+}
+
+// TransactionDescendants returns the transactions in the mempool that
+// spend from the given transaction, directly or indirectly
+func (mm *miningManager) TransactionDescendants(transactionID *consensusexternalapi.DomainTransactionID) (
+	[]*consensusexternalapi.DomainTransaction, bool) {
+
+	return mm.mempool.TransactionDescendants(transactionID)
+}
+
+// SetOnTransactionsRemovedHandler sets the handler that's called with
+// the transactions that are removed from the mempool
+func (mm *miningManager) SetOnTransactionsRemovedHandler(handler miningmanagermodel.OnTransactionsRemovedHandler) {
+	mm.mempool.SetOnTransactionsRemovedHandler(handler)
+}
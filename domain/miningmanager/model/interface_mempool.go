@@ -9,8 +9,18 @@ import (
 type Mempool interface {
 	HandleNewBlockTransactions(txs []*consensusexternalapi.DomainTransaction) ([]*consensusexternalapi.DomainTransaction, error)
 	BlockCandidateTransactions() []*consensusexternalapi.DomainTransaction
-	ValidateAndInsertTransaction(transaction *consensusexternalapi.DomainTransaction, allowOrphan bool) error
-	RemoveTransactions(txs []*consensusexternalapi.DomainTransaction) error
+	ValidateAndInsertTransaction(transaction *consensusexternalapi.DomainTransaction, allowOrphan bool) (
+		acceptedTransactions []*consensusexternalapi.DomainTransaction, err error)
+	ValidateTransaction(transaction *consensusexternalapi.DomainTransaction) error
+	RemoveTransactions(txs []*consensusexternalapi.DomainTransaction, reason TransactionRemovalReason) error
 	GetTransaction(transactionID *consensusexternalapi.DomainTransactionID) (*consensusexternalapi.DomainTransaction, bool)
 	AllTransactions() []*consensusexternalapi.DomainTransaction
+	OrphanTransactions() []*consensusexternalapi.DomainTransaction
+	UnspentOutputs() []*consensusexternalapi.OutpointAndUTXOEntryPair
+	IsOutpointSpent(outpoint *consensusexternalapi.DomainOutpoint) bool
+	TransactionAncestors(transactionID *consensusexternalapi.DomainTransactionID) (
+		[]*consensusexternalapi.DomainTransaction, bool)
+	TransactionDescendants(transactionID *consensusexternalapi.DomainTransactionID) (
+		[]*consensusexternalapi.DomainTransaction, bool)
+	SetOnTransactionsRemovedHandler(handler OnTransactionsRemovedHandler)
 }
@@ -0,0 +1,53 @@
+package model
+
+import (
+	consensusexternalapi "github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+)
+
+// TransactionRemovalReason is the reason a transaction was removed from the mempool
+type TransactionRemovalReason uint8
+
+const (
+	// TransactionRemovalReasonMined means that the transaction was included in a block
+	TransactionRemovalReasonMined TransactionRemovalReason = iota
+
+	// TransactionRemovalReasonConflicted means that the transaction, or a transaction it
+	// spends from, double spends a transaction that was included in a block
+	TransactionRemovalReasonConflicted
+
+	// TransactionRemovalReasonExpired means that the transaction, or a transaction it
+	// spends from, stayed in the mempool for longer than the maximum transaction age
+	TransactionRemovalReasonExpired
+
+	// TransactionRemovalReasonEvicted means that the transaction, or a transaction it
+	// spends from, was evicted to keep the mempool within its limits, or because it
+	// was found invalid while building a block template
+	TransactionRemovalReasonEvicted
+)
+
+var transactionRemovalReasonStrings = map[TransactionRemovalReason]string{
+	TransactionRemovalReasonMined:      "mined",
+	TransactionRemovalReasonConflicted: "conflicted",
+	TransactionRemovalReasonExpired:    "expired",
+	TransactionRemovalReasonEvicted:    "evicted",
+}
+
+func (reason TransactionRemovalReason) String() string {
+	if reasonString, ok := transactionRemovalReasonStrings[reason]; ok {
+		return reasonString
+	}
+	return "unknown"
+}
+
+// RemovedTransaction is a transaction that was removed from the mempool,
+// along with the reason it was removed
+type RemovedTransaction struct {
+	Transaction *consensusexternalapi.DomainTransaction
+	Reason      TransactionRemovalReason
+}
+
+// OnTransactionsRemovedHandler is a handler function that's called with
+// the transactions that were removed from the mempool. It's called after
+// the mempool was already changed, so it can't fail the operation that
+// removed them
+type OnTransactionsRemovedHandler func(removedTransactions []*RemovedTransaction)
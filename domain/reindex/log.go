@@ -0,0 +1,7 @@
+package reindex
+
+import (
+	"github.com/kaspanet/kaspad/infrastructure/logger"
+)
+
+var log = logger.RegisterSubSystem("RIDX")
@@ -0,0 +1,392 @@
+package reindex
+
+import (
+	"time"
+
+	"github.com/kaspanet/kaspad/domain/consensus/database"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/pkg/errors"
+)
+
+// ErrInterrupted is returned from Reindex when it's interrupted before it's done.
+// Calling Reindex again with the same source and target resumes the reindex
+// from where it had stopped
+var ErrInterrupted = errors.New("reindex was interrupted")
+
+// ErrMissingBlockBody is returned from Reindex when the source is missing the body
+// of a block it had validated, in which case the reindexed database would be incomplete
+var ErrMissingBlockBody = errors.New("a block body is missing from the source")
+
+const (
+	headersBatchMaxBlueScoreDifference = 1 << 10
+	blocksBatchSize                    = 100
+	pruningPointUTXOsStep              = 1000
+	progressLogInterval                = 10 * time.Second
+)
+
+type reindexer struct {
+	source      externalapi.Consensus
+	target      externalapi.Consensus
+	fromGenesis bool
+	interrupt   <-chan struct{}
+
+	lastProgressLogTime time.Time
+}
+
+// Reindex replays the blocks stored in the source consensus through the validation
+// pipeline of the target consensus, rebuilding all the data that is derived from them.
+//
+// If fromGenesis is true, every block is revalidated starting from genesis, which requires
+// the source to hold the bodies of all the blocks in the DAG. Otherwise, the pruning point
+// UTXO set of the source is imported into the target, and only the blocks above the
+// pruning point are revalidated.
+//
+// Reindex returns ErrInterrupted if the given interrupt channel gets closed before it's
+// done. Everything that was inserted into the target up to that point is kept
+func Reindex(source, target externalapi.Consensus, fromGenesis bool, interrupt <-chan struct{}) error {
+	r := &reindexer{
+		source:      source,
+		target:      target,
+		fromGenesis: fromGenesis,
+		interrupt:   interrupt,
+	}
+
+	if fromGenesis {
+		err := r.checkSourceIsNotPruned()
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Reindexing block headers")
+	err := r.reindexHeaders()
+	if err != nil {
+		return err
+	}
+
+	if !fromGenesis {
+		log.Infof("Importing the pruning point UTXO set")
+		err = r.importPruningPoint()
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Infof("Reindexing block bodies")
+	err = r.reindexBodies()
+	if err != nil {
+		return err
+	}
+
+	sourceVirtualSelectedParent, err := source.GetVirtualSelectedParent()
+	if err != nil {
+		return err
+	}
+	targetVirtualSelectedParent, err := target.GetVirtualSelectedParent()
+	if err != nil {
+		return err
+	}
+	if !sourceVirtualSelectedParent.Equal(targetVirtualSelectedParent) {
+		log.Warnf("The virtual selected parent after the reindex is %s, while it was %s before it",
+			targetVirtualSelectedParent, sourceVirtualSelectedParent)
+	}
+
+	log.Infof("Finished reindexing. The virtual selected parent is %s", targetVirtualSelectedParent)
+	return nil
+}
+
+// checkSourceIsNotPruned returns an error if the source deleted the bodies of
+// the blocks below its pruning point, in which case they cannot be revalidated
+func (r *reindexer) checkSourceIsNotPruned() error {
+	pruningPoint, err := r.source.PruningPoint()
+	if err != nil {
+		return err
+	}
+	pruningPointHeader, err := r.source.GetBlockHeader(pruningPoint)
+	if err != nil {
+		return err
+	}
+	// Pruning deletes the parents of the pruning point first
+	for _, parentHash := range pruningPointHeader.ParentHashes() {
+		_, err := r.source.GetBlock(parentHash)
+		if err != nil {
+			if errors.Is(err, database.ErrNotFound) {
+				return errors.Errorf("the body of block %s was pruned, so the blocks cannot be reindexed "+
+					"from genesis. Reindex without --archival to start from the pruning point instead", parentHash)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *reindexer) isInterrupted() bool {
+	select {
+	case <-r.interrupt:
+		return true
+	default:
+		return false
+	}
+}
+
+// shouldLogProgress returns true at most once every progressLogInterval
+func (r *reindexer) shouldLogProgress() bool {
+	now := time.Now()
+	if now.Sub(r.lastProgressLogTime) < progressLogInterval {
+		return false
+	}
+	r.lastProgressLogTime = now
+	return true
+}
+
+// highHashes returns the blocks of the source which everything that should be
+// reindexed is in the past of: the headers selected tip along with the DAG tips.
+// Like in IBD, headers that are in neither of their pasts are not reindexed
+func (r *reindexer) highHashes() ([]*externalapi.DomainHash, error) {
+	headersSelectedTip, err := r.source.GetHeadersSelectedTip()
+	if err != nil {
+		return nil, err
+	}
+	tips, err := r.source.Tips()
+	if err != nil {
+		return nil, err
+	}
+	return append([]*externalapi.DomainHash{headersSelectedTip}, tips...), nil
+}
+
+func (r *reindexer) reindexHeaders() error {
+	highHashes, err := r.highHashes()
+	if err != nil {
+		return err
+	}
+	for _, highHash := range highHashes {
+		err := r.reindexHeadersUpTo(highHash)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *reindexer) reindexHeadersUpTo(highHash *externalapi.DomainHash) error {
+	highBlockInfo, err := r.source.GetBlockInfo(highHash)
+	if err != nil {
+		return err
+	}
+
+	lowHash, err := r.target.GetHeadersSelectedTip()
+	if err != nil {
+		return err
+	}
+	for {
+		hasHighHash, err := hasBlock(r.target, highHash)
+		if err != nil {
+			return err
+		}
+		if hasHighHash {
+			return nil
+		}
+
+		hashes, err := r.source.GetHashesBetween(lowHash, highHash, headersBatchMaxBlueScoreDifference)
+		if err != nil {
+			return err
+		}
+		if len(hashes) == 0 {
+			return errors.Errorf("no headers were found between %s and %s", lowHash, highHash)
+		}
+
+		headerOnlyBlocks := make([]*externalapi.DomainBlock, 0, len(hashes))
+		for _, hash := range hashes {
+			targetHasBlock, err := hasBlock(r.target, hash)
+			if err != nil {
+				return err
+			}
+			if targetHasBlock {
+				continue
+			}
+			header, err := r.source.GetBlockHeader(hash)
+			if err != nil {
+				return err
+			}
+			headerOnlyBlocks = append(headerOnlyBlocks, &externalapi.DomainBlock{Header: header})
+		}
+		_, err = r.target.ValidateAndInsertBlocks(headerOnlyBlocks)
+		if err != nil {
+			return errors.Wrapf(err, "failed to reindex the headers between %s and %s", lowHash, highHash)
+		}
+		lowHash = hashes[len(hashes)-1]
+
+		if r.shouldLogProgress() {
+			lowBlockInfo, err := r.target.GetBlockInfo(lowHash)
+			if err != nil {
+				return err
+			}
+			log.Infof("Reindexed block headers up to blue score %d out of %d",
+				lowBlockInfo.BlueScore, highBlockInfo.BlueScore)
+		}
+
+		if r.isInterrupted() {
+			return ErrInterrupted
+		}
+	}
+}
+
+func (r *reindexer) importPruningPoint() error {
+	pruningPoint, err := r.source.PruningPoint()
+	if err != nil {
+		return err
+	}
+
+	pruningPointInfo, err := r.target.GetBlockInfo(pruningPoint)
+	if err != nil {
+		return err
+	}
+	if !pruningPointInfo.Exists {
+		return errors.Errorf("the header of the pruning point %s is missing", pruningPoint)
+	}
+	if pruningPointInfo.BlockStatus != externalapi.StatusHeaderOnly {
+		log.Infof("The pruning point %s was already imported", pruningPoint)
+		return nil
+	}
+
+	isValid, err := r.target.IsValidPruningPoint(pruningPoint)
+	if err != nil {
+		return err
+	}
+	if !isValid {
+		return errors.Errorf("block %s is not a valid pruning point", pruningPoint)
+	}
+
+	// Imported pruning point data is not kept across restarts, so an interrupted
+	// import always starts over
+	err = r.target.ClearImportedPruningPointData()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err := r.target.ClearImportedPruningPointData()
+		if err != nil {
+			log.Errorf("Failed to clear the imported pruning point data: %s", err)
+		}
+	}()
+
+	var fromOutpoint *externalapi.DomainOutpoint
+	importedUTXOCount := 0
+	for {
+		pruningPointUTXOs, err := r.source.GetPruningPointUTXOs(pruningPoint, fromOutpoint, pruningPointUTXOsStep)
+		if err != nil {
+			return err
+		}
+		err = r.target.AppendImportedPruningPointUTXOs(pruningPointUTXOs)
+		if err != nil {
+			return err
+		}
+		importedUTXOCount += len(pruningPointUTXOs)
+
+		if len(pruningPointUTXOs) < pruningPointUTXOsStep {
+			break
+		}
+		fromOutpoint = pruningPointUTXOs[len(pruningPointUTXOs)-1].Outpoint
+
+		if r.shouldLogProgress() {
+			log.Infof("Imported %d UTXOs so far", importedUTXOCount)
+		}
+		if r.isInterrupted() {
+			return ErrInterrupted
+		}
+	}
+	log.Infof("Imported the UTXO set of the pruning point %s. Total UTXOs: %d", pruningPoint, importedUTXOCount)
+
+	pruningPointBlock, err := r.source.GetBlock(pruningPoint)
+	if err != nil {
+		return err
+	}
+	return r.target.ValidateAndInsertImportedPruningPoint(pruningPointBlock)
+}
+
+func (r *reindexer) reindexBodies() error {
+	highHashes, err := r.highHashes()
+	if err != nil {
+		return err
+	}
+	for _, highHash := range highHashes {
+		highBlockInfo, err := r.target.GetBlockInfo(highHash)
+		if err != nil {
+			return err
+		}
+		if !highBlockInfo.Exists || highBlockInfo.BlockStatus != externalapi.StatusHeaderOnly {
+			continue
+		}
+
+		hashes, err := r.target.GetMissingBlockBodyHashes(highHash)
+		if err != nil {
+			return err
+		}
+		err = r.reindexBodiesOf(hashes)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *reindexer) reindexBodiesOf(hashes []*externalapi.DomainHash) error {
+	for offset := 0; offset < len(hashes); offset += blocksBatchSize {
+		batchEnd := offset + blocksBatchSize
+		if batchEnd > len(hashes) {
+			batchEnd = len(hashes)
+		}
+
+		blocks := make([]*externalapi.DomainBlock, 0, batchEnd-offset)
+		for _, hash := range hashes[offset:batchEnd] {
+			sourceBlockInfo, err := r.source.GetBlockInfo(hash)
+			if err != nil {
+				return err
+			}
+			// The source never received the bodies of header-only blocks, nor
+			// of the blocks in their future, so they stay header-only
+			if sourceBlockInfo.BlockStatus == externalapi.StatusHeaderOnly {
+				log.Debugf("Block %s is header-only in the source, so its body is not reindexed", hash)
+				continue
+			}
+
+			block, err := r.source.GetBlock(hash)
+			if err != nil {
+				if errors.Is(err, database.ErrNotFound) {
+					return errors.Wrapf(ErrMissingBlockBody, "the body of block %s is missing from the source "+
+						"even though its status is %s", hash, sourceBlockInfo.BlockStatus)
+				}
+				return err
+			}
+			blocks = append(blocks, block)
+		}
+
+		if len(blocks) > 0 {
+			blockInsertionResults, err := r.target.ValidateAndInsertBlocks(blocks)
+			if err != nil {
+				return errors.Wrapf(err, "failed to reindex block %s",
+					consensushashing.BlockHash(blocks[len(blockInsertionResults)]))
+			}
+		}
+
+		if r.shouldLogProgress() {
+			log.Infof("Reindexed %d out of %d block bodies (%.2f%%)",
+				batchEnd, len(hashes), 100*float64(batchEnd)/float64(len(hashes)))
+		}
+		// Header-only blocks are listed again when the reindex is resumed, so
+		// it's interrupted only after batches that inserted something
+		if len(blocks) > 0 && r.isInterrupted() {
+			return ErrInterrupted
+		}
+	}
+	return nil
+}
+
+func hasBlock(consensus externalapi.Consensus, blockHash *externalapi.DomainHash) (bool, error) {
+	blockInfo, err := consensus.GetBlockInfo(blockHash)
+	if err != nil {
+		return false, err
+	}
+	return blockInfo.Exists, nil
+}
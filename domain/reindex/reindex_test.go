@@ -0,0 +1,270 @@
+package reindex_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/domain/consensus"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/model/testapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/testutils"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+	"github.com/kaspanet/kaspad/domain/reindex"
+	"github.com/pkg/errors"
+)
+
+func TestReindexFromGenesis(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, params *dagconfig.Params) {
+		factory := consensus.NewFactory()
+
+		source, teardownSource, err := factory.NewTestConsensus(params, true, "TestReindexFromGenesisSource")
+		if err != nil {
+			t.Fatalf("Error setting up source: %+v", err)
+		}
+		defer teardownSource(false)
+
+		target, teardownTarget, err := factory.NewTestConsensus(params, true, "TestReindexFromGenesisTarget")
+		if err != nil {
+			t.Fatalf("Error setting up target: %+v", err)
+		}
+		defer teardownTarget(false)
+
+		tipHash := params.GenesisHash
+		var sideBlockHash *externalapi.DomainHash
+		for i := 0; i < 20; i++ {
+			parentHashes := []*externalapi.DomainHash{tipHash}
+			if sideBlockHash != nil && i%4 == 0 {
+				parentHashes = append(parentHashes, sideBlockHash)
+			}
+			if i%4 == 1 {
+				sideBlockHash, _, err = source.AddBlock([]*externalapi.DomainHash{tipHash}, nil, nil)
+				if err != nil {
+					t.Fatalf("AddBlock: %+v", err)
+				}
+			}
+			tipHash, _, err = source.AddBlock(parentHashes, nil, nil)
+			if err != nil {
+				t.Fatalf("AddBlock: %+v", err)
+			}
+		}
+
+		// Add a block whose body the source never received
+		headerOnlyBlock, _, err := source.BuildBlockWithParents([]*externalapi.DomainHash{tipHash}, nil, nil)
+		if err != nil {
+			t.Fatalf("BuildBlockWithParents: %+v", err)
+		}
+		_, err = source.ValidateAndInsertBlock(&externalapi.DomainBlock{Header: headerOnlyBlock.Header})
+		if err != nil {
+			t.Fatalf("ValidateAndInsertBlock: %+v", err)
+		}
+		headerOnlyHash := consensushashing.BlockHash(headerOnlyBlock)
+
+		// Interrupt the reindex after every batch, so that every step
+		// of it gets resumed at least once
+		interrupt := make(chan struct{})
+		close(interrupt)
+		const maxRuns = 10
+		for run := 1; ; run++ {
+			err := reindex.Reindex(source, target, true, interrupt)
+			if err == nil {
+				break
+			}
+			if !errors.Is(err, reindex.ErrInterrupted) {
+				t.Fatalf("Reindex: %+v", err)
+			}
+			if run == maxRuns {
+				t.Fatalf("Reindex did not finish after %d runs", maxRuns)
+			}
+		}
+
+		compareConsensuses(t, source, target)
+		blockInfo, err := target.GetBlockInfo(headerOnlyHash)
+		if err != nil {
+			t.Fatalf("GetBlockInfo: %+v", err)
+		}
+		if !blockInfo.Exists || blockInfo.BlockStatus != externalapi.StatusHeaderOnly {
+			t.Fatalf("Expected block %s to be header-only, but got status %s",
+				headerOnlyHash, blockInfo.BlockStatus)
+		}
+	})
+}
+
+func TestReindexMissingBlockBody(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, params *dagconfig.Params) {
+		factory := consensus.NewFactory()
+
+		source, teardownSource, err := factory.NewTestConsensus(params, true, "TestReindexMissingBlockBodySource")
+		if err != nil {
+			t.Fatalf("Error setting up source: %+v", err)
+		}
+		defer teardownSource(false)
+
+		target, teardownTarget, err := factory.NewTestConsensus(params, true, "TestReindexMissingBlockBodyTarget")
+		if err != nil {
+			t.Fatalf("Error setting up target: %+v", err)
+		}
+		defer teardownTarget(false)
+
+		tipHash := params.GenesisHash
+		var missingBodyHash *externalapi.DomainHash
+		for i := 0; i < 10; i++ {
+			tipHash, _, err = source.AddBlock([]*externalapi.DomainHash{tipHash}, nil, nil)
+			if err != nil {
+				t.Fatalf("AddBlock: %+v", err)
+			}
+			if i == 5 {
+				missingBodyHash = tipHash
+			}
+		}
+
+		// Delete the body of a block that the source had validated, as if its database got corrupted
+		source.BlockStore().Delete(missingBodyHash)
+		dbTx, err := source.DatabaseContext().Begin()
+		if err != nil {
+			t.Fatalf("Begin: %+v", err)
+		}
+		err = source.BlockStore().Commit(dbTx)
+		if err != nil {
+			t.Fatalf("Commit: %+v", err)
+		}
+		err = dbTx.Commit()
+		if err != nil {
+			t.Fatalf("Commit: %+v", err)
+		}
+
+		err = reindex.Reindex(source, target, true, make(chan struct{}))
+		if !errors.Is(err, reindex.ErrMissingBlockBody) {
+			t.Fatalf("Expected Reindex to fail with ErrMissingBlockBody, but got: %+v", err)
+		}
+	})
+}
+
+func TestReindexFromPruningPoint(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, params *dagconfig.Params) {
+		// This is done to reduce the pruning depth to 6 blocks
+		finalityDepth := 3
+		params.FinalityDuration = time.Duration(finalityDepth) * params.TargetTimePerBlock
+		params.K = 0
+
+		factory := consensus.NewFactory()
+
+		source, teardownSource, err := factory.NewTestConsensus(params, false, "TestReindexFromPruningPointSource")
+		if err != nil {
+			t.Fatalf("Error setting up source: %+v", err)
+		}
+		defer teardownSource(false)
+
+		tipHash := params.GenesisHash
+		for i := 0; i < 20; i++ {
+			tipHash, _, err = source.AddBlock([]*externalapi.DomainHash{tipHash}, nil, nil)
+			if err != nil {
+				t.Fatalf("AddBlock: %+v", err)
+			}
+		}
+		pruningPoint, err := source.PruningPoint()
+		if err != nil {
+			t.Fatalf("PruningPoint: %+v", err)
+		}
+		if pruningPoint.Equal(params.GenesisHash) {
+			t.Fatalf("The pruning point is expected to have moved")
+		}
+
+		genesisTarget, teardownGenesisTarget, err := factory.NewTestConsensus(params, false,
+			"TestReindexFromPruningPointGenesisTarget")
+		if err != nil {
+			t.Fatalf("Error setting up target: %+v", err)
+		}
+		defer teardownGenesisTarget(false)
+
+		err = reindex.Reindex(source, genesisTarget, true, make(chan struct{}))
+		if err == nil || !strings.Contains(err.Error(), "was pruned") {
+			t.Fatalf("Expected reindexing a pruned source from genesis to fail, but got: %+v", err)
+		}
+
+		target, teardownTarget, err := factory.NewTestConsensus(params, false, "TestReindexFromPruningPointTarget")
+		if err != nil {
+			t.Fatalf("Error setting up target: %+v", err)
+		}
+		defer teardownTarget(false)
+
+		err = reindex.Reindex(source, target, false, make(chan struct{}))
+		if err != nil {
+			t.Fatalf("Reindex: %+v", err)
+		}
+
+		compareConsensuses(t, source, target)
+		targetPruningPoint, err := target.PruningPoint()
+		if err != nil {
+			t.Fatalf("PruningPoint: %+v", err)
+		}
+		if !targetPruningPoint.Equal(pruningPoint) {
+			t.Fatalf("Expected the pruning point to be %s, but got %s", pruningPoint, targetPruningPoint)
+		}
+	})
+}
+
+func compareConsensuses(t *testing.T, expected, actual testapi.TestConsensus) {
+	expectedHeadersSelectedTip, err := expected.GetHeadersSelectedTip()
+	if err != nil {
+		t.Fatalf("GetHeadersSelectedTip: %+v", err)
+	}
+	actualHeadersSelectedTip, err := actual.GetHeadersSelectedTip()
+	if err != nil {
+		t.Fatalf("GetHeadersSelectedTip: %+v", err)
+	}
+	if !actualHeadersSelectedTip.Equal(expectedHeadersSelectedTip) {
+		t.Fatalf("Expected the headers selected tip to be %s, but got %s",
+			expectedHeadersSelectedTip, actualHeadersSelectedTip)
+	}
+
+	expectedVirtualInfo, err := expected.GetVirtualInfo()
+	if err != nil {
+		t.Fatalf("GetVirtualInfo: %+v", err)
+	}
+	actualVirtualInfo, err := actual.GetVirtualInfo()
+	if err != nil {
+		t.Fatalf("GetVirtualInfo: %+v", err)
+	}
+	if !reflect.DeepEqual(actualVirtualInfo, expectedVirtualInfo) {
+		t.Fatalf("Expected the virtual info to be %+v, but got %+v", expectedVirtualInfo, actualVirtualInfo)
+	}
+
+	expectedUTXOs := virtualUTXOs(t, expected, expectedVirtualInfo.ParentHashes)
+	actualUTXOs := virtualUTXOs(t, actual, actualVirtualInfo.ParentHashes)
+	if len(actualUTXOs) != len(expectedUTXOs) {
+		t.Fatalf("Expected %d virtual UTXOs, but got %d", len(expectedUTXOs), len(actualUTXOs))
+	}
+	for outpoint, expectedEntry := range expectedUTXOs {
+		actualEntry, ok := actualUTXOs[outpoint]
+		if !ok {
+			t.Fatalf("Outpoint %s is missing from the virtual UTXO set", outpoint)
+		}
+		if !actualEntry.Equal(expectedEntry) {
+			t.Fatalf("Unexpected UTXO entry for outpoint %s", outpoint)
+		}
+	}
+}
+
+func virtualUTXOs(t *testing.T, tc testapi.TestConsensus,
+	virtualParents []*externalapi.DomainHash) map[externalapi.DomainOutpoint]externalapi.UTXOEntry {
+
+	const step = 1000
+	utxos := make(map[externalapi.DomainOutpoint]externalapi.UTXOEntry)
+	var fromOutpoint *externalapi.DomainOutpoint
+	for {
+		outpointAndUTXOEntryPairs, err := tc.GetVirtualUTXOs(virtualParents, fromOutpoint, step)
+		if err != nil {
+			t.Fatalf("GetVirtualUTXOs: %+v", err)
+		}
+		for _, outpointAndUTXOEntryPair := range outpointAndUTXOEntryPairs {
+			utxos[*outpointAndUTXOEntryPair.Outpoint] = outpointAndUTXOEntryPair.UTXOEntry
+		}
+		if len(outpointAndUTXOEntryPairs) < step {
+			return utxos
+		}
+		fromOutpoint = outpointAndUTXOEntryPairs[len(outpointAndUTXOEntryPairs)-1].Outpoint
+	}
+}
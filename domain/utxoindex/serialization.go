@@ -70,3 +70,19 @@ func deserializeHashes(serializedHashes []byte) ([]*externalapi.DomainHash, erro
 
 	return hashes, nil
 }
+
+const uint64Size = 8
+
+func serializeUint64(value uint64) []byte {
+	serializedValue := make([]byte, uint64Size)
+	binary.LittleEndian.PutUint64(serializedValue, value)
+	return serializedValue
+}
+
+func deserializeUint64(serializedValue []byte) (uint64, error) {
+	if len(serializedValue) != uint64Size {
+		return 0, errors.Wrapf(io.ErrUnexpectedEOF, "unexpected length %d while deserializing a uint64",
+			len(serializedValue))
+	}
+	return binary.LittleEndian.Uint64(serializedValue), nil
+}
@@ -9,20 +9,24 @@ import (
 )
 
 var utxoIndexBucket = database.MakeBucket([]byte("utxo-index"))
+var balanceBucket = database.MakeBucket([]byte("utxo-index-balances"))
 var virtualParentsKey = database.MakeBucket([]byte("")).Key([]byte("utxo-index-virtual-parents"))
+var dustThresholdKey = database.MakeBucket([]byte("")).Key([]byte("utxo-index-dust-threshold"))
 
 type utxoIndexStore struct {
 	database       database.Database
 	toAdd          map[ScriptPublicKeyString]UTXOOutpointEntryPairs
 	toRemove       map[ScriptPublicKeyString]UTXOOutpoints
+	balanceChanges map[ScriptPublicKeyString]int64
 	virtualParents []*externalapi.DomainHash
 }
 
 func newUTXOIndexStore(database database.Database) *utxoIndexStore {
 	return &utxoIndexStore{
-		database: database,
-		toAdd:    make(map[ScriptPublicKeyString]UTXOOutpointEntryPairs),
-		toRemove: make(map[ScriptPublicKeyString]UTXOOutpoints),
+		database:       database,
+		toAdd:          make(map[ScriptPublicKeyString]UTXOOutpointEntryPairs),
+		toRemove:       make(map[ScriptPublicKeyString]UTXOOutpoints),
+		balanceChanges: make(map[ScriptPublicKeyString]int64),
 	}
 }
 
@@ -32,6 +36,8 @@ func (uis *utxoIndexStore) add(scriptPublicKey *externalapi.ScriptPublicKey, out
 	log.Tracef("Adding outpoint %s:%d to scriptPublicKey %s",
 		outpoint.TransactionID, outpoint.Index, key)
 
+	uis.balanceChanges[key] += int64(utxoEntry.Amount())
+
 	// If the outpoint exists in `toRemove` simply remove it from there and return
 	if toRemoveOutpointsOfKey, ok := uis.toRemove[key]; ok {
 		if _, ok := toRemoveOutpointsOfKey[*outpoint]; ok {
@@ -61,11 +67,13 @@ func (uis *utxoIndexStore) add(scriptPublicKey *externalapi.ScriptPublicKey, out
 	return nil
 }
 
-func (uis *utxoIndexStore) remove(scriptPublicKey *externalapi.ScriptPublicKey, outpoint *externalapi.DomainOutpoint) error {
+func (uis *utxoIndexStore) remove(scriptPublicKey *externalapi.ScriptPublicKey, outpoint *externalapi.DomainOutpoint, utxoEntry externalapi.UTXOEntry) error {
 	key := ConvertScriptPublicKeyToString(scriptPublicKey)
 	log.Tracef("Removing outpoint %s:%d from scriptPublicKey %s",
 		outpoint.TransactionID, outpoint.Index, key)
 
+	uis.balanceChanges[key] -= int64(utxoEntry.Amount())
+
 	// If the outpoint exists in `toAdd` simply remove it from there and return
 	if toAddPairsOfKey, ok := uis.toAdd[key]; ok {
 		if _, ok := toAddPairsOfKey[*outpoint]; ok {
@@ -102,6 +110,7 @@ func (uis *utxoIndexStore) updateVirtualParents(virtualParents []*externalapi.Do
 func (uis *utxoIndexStore) discard() {
 	uis.toAdd = make(map[ScriptPublicKeyString]UTXOOutpointEntryPairs)
 	uis.toRemove = make(map[ScriptPublicKeyString]UTXOOutpoints)
+	uis.balanceChanges = make(map[ScriptPublicKeyString]int64)
 	uis.virtualParents = nil
 }
 
@@ -149,6 +158,14 @@ func (uis *utxoIndexStore) commit() error {
 		}
 	}
 
+	for scriptPublicKeyString, balanceChange := range uis.balanceChanges {
+		scriptPublicKey := ConvertStringToScriptPublicKey(scriptPublicKeyString)
+		err := uis.applyBalanceChange(dbTransaction, scriptPublicKey, balanceChange)
+		if err != nil {
+			return err
+		}
+	}
+
 	serializeParentHashes := serializeHashes(uis.virtualParents)
 	err = dbTransaction.Put(virtualParentsKey, serializeParentHashes)
 	if err != nil {
@@ -165,7 +182,11 @@ func (uis *utxoIndexStore) commit() error {
 }
 
 func (uis *utxoIndexStore) addAndCommitOutpointsWithoutTransaction(utxoPairs []*externalapi.OutpointAndUTXOEntryPair) error {
+	balanceChanges := make(map[ScriptPublicKeyString]int64)
 	for _, pair := range utxoPairs {
+		scriptPublicKeyString := ConvertScriptPublicKeyToString(pair.UTXOEntry.ScriptPublicKey())
+		balanceChanges[scriptPublicKeyString] += int64(pair.UTXOEntry.Amount())
+
 		bucket := uis.bucketForScriptPublicKey(pair.UTXOEntry.ScriptPublicKey())
 		key, err := uis.convertOutpointToKey(bucket, pair.Outpoint)
 		if err != nil {
@@ -181,6 +202,14 @@ func (uis *utxoIndexStore) addAndCommitOutpointsWithoutTransaction(utxoPairs []*
 		}
 	}
 
+	for scriptPublicKeyString, balanceChange := range balanceChanges {
+		scriptPublicKey := ConvertStringToScriptPublicKey(scriptPublicKeyString)
+		err := uis.applyBalanceChange(uis.database, scriptPublicKey, balanceChange)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -189,6 +218,51 @@ func (uis *utxoIndexStore) updateAndCommitVirtualParentsWithoutTransaction(virtu
 	return uis.database.Put(virtualParentsKey, serializeParentHashes)
 }
 
+func (uis *utxoIndexStore) updateAndCommitDustThresholdWithoutTransaction(dustThreshold uint64) error {
+	return uis.database.Put(dustThresholdKey, serializeUint64(dustThreshold))
+}
+
+// applyBalanceChange adds the given change to the stored balance of the given scriptPublicKey.
+// Balances that drop to zero are deleted, so that only scriptPublicKeys that have UTXOs take space
+func (uis *utxoIndexStore) applyBalanceChange(dataAccessor database.DataAccessor,
+	scriptPublicKey *externalapi.ScriptPublicKey, balanceChange int64) error {
+
+	if balanceChange == 0 {
+		return nil
+	}
+
+	key := uis.balanceKey(scriptPublicKey)
+	balance, err := uis.getBalanceFrom(dataAccessor, key)
+	if err != nil {
+		return err
+	}
+
+	newBalance := int64(balance) + balanceChange
+	if newBalance < 0 {
+		return errors.Errorf("the balance of scriptPublicKey %x cannot become negative (%d)",
+			scriptPublicKey.Script, newBalance)
+	}
+	if newBalance == 0 {
+		return dataAccessor.Delete(key)
+	}
+	return dataAccessor.Put(key, serializeUint64(uint64(newBalance)))
+}
+
+func (uis *utxoIndexStore) getBalanceFrom(dataAccessor database.DataAccessor, key *database.Key) (uint64, error) {
+	serializedBalance, err := dataAccessor.Get(key)
+	if err != nil {
+		if database.IsNotFoundError(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return deserializeUint64(serializedBalance)
+}
+
+func (uis *utxoIndexStore) balanceKey(scriptPublicKey *externalapi.ScriptPublicKey) *database.Key {
+	return balanceBucket.Key([]byte(ConvertScriptPublicKeyToString(scriptPublicKey)))
+}
+
 func (uis *utxoIndexStore) bucketForScriptPublicKey(scriptPublicKey *externalapi.ScriptPublicKey) *database.Bucket {
 	var scriptPublicKeyBytes = make([]byte, 2+len(scriptPublicKey.Script)) // uint16
 	binary.LittleEndian.PutUint16(scriptPublicKeyBytes[:2], scriptPublicKey.Version)
@@ -273,6 +347,86 @@ func (uis *utxoIndexStore) getUTXOOutpointEntryPairs(scriptPublicKey *externalap
 	return utxoOutpointEntryPairs, nil
 }
 
+// getUTXOOutpointEntryPairsPage returns up to `limit` UTXOs of the given scriptPublicKey, ordered
+// by their serialized outpoints, starting right after fromOutpoint. fromOutpoint doesn't have to
+// be in the index anymore, so that paging keeps working while the UTXOs get spent
+func (uis *utxoIndexStore) getUTXOOutpointEntryPairsPage(scriptPublicKey *externalapi.ScriptPublicKey,
+	fromOutpoint *externalapi.DomainOutpoint, limit int) ([]*externalapi.OutpointAndUTXOEntryPair, error) {
+
+	if uis.isAnythingStaged() {
+		return nil, errors.Errorf("cannot get utxo outpoint entry pairs while staging isn't empty")
+	}
+
+	bucket := uis.bucketForScriptPublicKey(scriptPublicKey)
+	cursor, err := uis.database.Cursor(bucket)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close()
+
+	var ok bool
+	if fromOutpoint == nil {
+		ok = cursor.First()
+	} else {
+		seekKey, err := uis.convertOutpointToKey(bucket, fromOutpoint)
+		if err != nil {
+			return nil, err
+		}
+		err = cursor.Seek(seekKey)
+		if err == nil {
+			ok = cursor.Next()
+		} else {
+			if !database.IsNotFoundError(err) {
+				return nil, err
+			}
+			// fromOutpoint was removed, in which case the cursor points at the outpoint that follows it, if any
+			_, err := cursor.Key()
+			ok = err == nil
+		}
+	}
+
+	pairs := make([]*externalapi.OutpointAndUTXOEntryPair, 0, limit)
+	for ; ok && len(pairs) < limit; ok = cursor.Next() {
+		key, err := cursor.Key()
+		if err != nil {
+			return nil, err
+		}
+		outpoint, err := uis.convertKeyToOutpoint(key)
+		if err != nil {
+			return nil, err
+		}
+		serializedUTXOEntry, err := cursor.Value()
+		if err != nil {
+			return nil, err
+		}
+		utxoEntry, err := deserializeUTXOEntry(serializedUTXOEntry)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, &externalapi.OutpointAndUTXOEntryPair{
+			Outpoint:  outpoint,
+			UTXOEntry: utxoEntry,
+		})
+	}
+	return pairs, nil
+}
+
+func (uis *utxoIndexStore) getBalance(scriptPublicKey *externalapi.ScriptPublicKey) (uint64, error) {
+	if uis.isAnythingStaged() {
+		return 0, errors.Errorf("cannot get the balance while staging isn't empty")
+	}
+
+	return uis.getBalanceFrom(uis.database, uis.balanceKey(scriptPublicKey))
+}
+
+func (uis *utxoIndexStore) getDustThreshold() (uint64, error) {
+	serializedDustThreshold, err := uis.database.Get(dustThresholdKey)
+	if err != nil {
+		return 0, err
+	}
+	return deserializeUint64(serializedDustThreshold)
+}
+
 func (uis *utxoIndexStore) getVirtualParents() ([]*externalapi.DomainHash, error) {
 	if uis.isAnythingStaged() {
 		return nil, errors.Errorf("cannot get the virtual parents while staging isn't empty")
@@ -294,7 +448,23 @@ func (uis *utxoIndexStore) deleteAll() error {
 		return err
 	}
 
-	cursor, err := uis.database.Cursor(utxoIndexBucket)
+	err = uis.database.Delete(dustThresholdKey)
+	if err != nil {
+		return err
+	}
+
+	for _, bucket := range []*database.Bucket{utxoIndexBucket, balanceBucket} {
+		err := uis.deleteBucket(bucket)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (uis *utxoIndexStore) deleteBucket(bucket *database.Bucket) error {
+	cursor, err := uis.database.Cursor(bucket)
 	if err != nil {
 		return err
 	}
@@ -10,19 +10,23 @@ import (
 // UTXOIndex maintains an index between transaction scriptPublicKeys
 // and UTXOs
 type UTXOIndex struct {
-	consensus externalapi.Consensus
-	store     *utxoIndexStore
+	consensus     externalapi.Consensus
+	store         *utxoIndexStore
+	dustThreshold uint64
 
 	mutex sync.Mutex
 }
 
 // New creates a new UTXO index.
+// UTXOs whose amount is below dustThreshold are not indexed, and are not
+// counted in the balances.
 //
 // NOTE: While this is called no new blocks can be added to the consensus.
-func New(consensus externalapi.Consensus, database database.Database) (*UTXOIndex, error) {
+func New(consensus externalapi.Consensus, database database.Database, dustThreshold uint64) (*UTXOIndex, error) {
 	utxoIndex := &UTXOIndex{
-		consensus: consensus,
-		store:     newUTXOIndexStore(database),
+		consensus:     consensus,
+		store:         newUTXOIndexStore(database),
+		dustThreshold: dustThreshold,
 	}
 
 	isSynced, err := utxoIndex.isSynced()
@@ -60,7 +64,13 @@ func (ui *UTXOIndex) Reset() error {
 			return err
 		}
 
-		err = ui.store.addAndCommitOutpointsWithoutTransaction(virtualUTXOs)
+		nonDustUTXOs := make([]*externalapi.OutpointAndUTXOEntryPair, 0, len(virtualUTXOs))
+		for _, virtualUTXO := range virtualUTXOs {
+			if !ui.isDust(virtualUTXO.UTXOEntry) {
+				nonDustUTXOs = append(nonDustUTXOs, virtualUTXO)
+			}
+		}
+		err = ui.store.addAndCommitOutpointsWithoutTransaction(nonDustUTXOs)
 		if err != nil {
 			return err
 		}
@@ -72,6 +82,11 @@ func (ui *UTXOIndex) Reset() error {
 		fromOutpoint = virtualUTXOs[len(virtualUTXOs)-1].Outpoint
 	}
 
+	err = ui.store.updateAndCommitDustThresholdWithoutTransaction(ui.dustThreshold)
+	if err != nil {
+		return err
+	}
+
 	// This has to be done last to mark that the reset went smoothly and no reset has to be called next time.
 	return ui.store.updateAndCommitVirtualParentsWithoutTransaction(virtualInfo.ParentHashes)
 }
@@ -85,6 +100,19 @@ func (ui *UTXOIndex) isSynced() (bool, error) {
 		return false, err
 	}
 
+	// An index that was built with a different dust threshold (or before the
+	// dust threshold and the balances were introduced) has to be rebuilt
+	utxoIndexDustThreshold, err := ui.store.getDustThreshold()
+	if err != nil {
+		if database.IsNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if utxoIndexDustThreshold != ui.dustThreshold {
+		return false, nil
+	}
+
 	virtualInfo, err := ui.consensus.GetVirtualInfo()
 	if err != nil {
 		return false, err
@@ -137,6 +165,9 @@ func (ui *UTXOIndex) addUTXOs(toAdd externalapi.UTXOCollection) error {
 		if err != nil {
 			return err
 		}
+		if ui.isDust(entry) {
+			continue
+		}
 
 		log.Tracef("Adding outpoint %s to UTXO index", outpoint)
 		err = ui.store.add(entry.ScriptPublicKey(), outpoint, entry)
@@ -155,9 +186,12 @@ func (ui *UTXOIndex) removeUTXOs(toRemove externalapi.UTXOCollection) error {
 		if err != nil {
 			return err
 		}
+		if ui.isDust(entry) {
+			continue
+		}
 
 		log.Tracef("Removing outpoint %s from UTXO index", outpoint)
-		err = ui.store.remove(entry.ScriptPublicKey(), outpoint)
+		err = ui.store.remove(entry.ScriptPublicKey(), outpoint, entry)
 		if err != nil {
 			return err
 		}
@@ -175,3 +209,34 @@ func (ui *UTXOIndex) UTXOs(scriptPublicKey *externalapi.ScriptPublicKey) (UTXOOu
 
 	return ui.store.getUTXOOutpointEntryPairs(scriptPublicKey)
 }
+
+// UTXOsPage returns up to `limit` UTXOs for the given scriptPublicKey, starting right
+// after fromOutpoint, or from the first UTXO if fromOutpoint is nil. The UTXOs are
+// always returned in the same order, so the outpoint of the last UTXO of a page
+// may be used as the fromOutpoint of the next page.
+func (ui *UTXOIndex) UTXOsPage(scriptPublicKey *externalapi.ScriptPublicKey,
+	fromOutpoint *externalapi.DomainOutpoint, limit int) ([]*externalapi.OutpointAndUTXOEntryPair, error) {
+
+	onEnd := logger.LogAndMeasureExecutionTime(log, "UTXOIndex.UTXOsPage")
+	defer onEnd()
+
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	return ui.store.getUTXOOutpointEntryPairsPage(scriptPublicKey, fromOutpoint, limit)
+}
+
+// Balance returns the sum of the amounts of all the UTXOs for the given scriptPublicKey
+func (ui *UTXOIndex) Balance(scriptPublicKey *externalapi.ScriptPublicKey) (uint64, error) {
+	onEnd := logger.LogAndMeasureExecutionTime(log, "UTXOIndex.Balance")
+	defer onEnd()
+
+	ui.mutex.Lock()
+	defer ui.mutex.Unlock()
+
+	return ui.store.getBalance(scriptPublicKey)
+}
+
+func (ui *UTXOIndex) isDust(utxoEntry externalapi.UTXOEntry) bool {
+	return utxoEntry.Amount() < ui.dustThreshold
+}
@@ -0,0 +1,138 @@
+package utxoindex
+
+import (
+	"testing"
+
+	"github.com/kaspanet/kaspad/domain/consensus"
+	"github.com/kaspanet/kaspad/domain/consensus/model/externalapi"
+	"github.com/kaspanet/kaspad/domain/consensus/model/testapi"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/testutils"
+	"github.com/kaspanet/kaspad/domain/dagconfig"
+)
+
+func TestUTXOIndexBalancesAndPages(t *testing.T) {
+	testutils.ForAllNets(t, true, func(t *testing.T, params *dagconfig.Params) {
+		factory := consensus.NewFactory()
+		tc, teardown, err := factory.NewTestConsensus(params, false, "TestUTXOIndexBalancesAndPages")
+		if err != nil {
+			t.Fatalf("Error setting up consensus: %+v", err)
+		}
+		defer teardown(false)
+
+		utxoIndex, err := New(tc, tc.Database(), 0)
+		if err != nil {
+			t.Fatalf("New: %+v", err)
+		}
+
+		scriptPublicKey, _ := testutils.OpTrueScript()
+		const blockAmount = 10
+		addBlocksAndUpdate(t, tc, utxoIndex, blockAmount)
+
+		// The balance and the UTXOs that were built by Update
+		// are expected to be identical to the ones built by Reset
+		updatedBalance := balance(t, utxoIndex, scriptPublicKey)
+		updatedUTXOs := allUTXOsByPages(t, utxoIndex, scriptPublicKey, 3)
+		if len(updatedUTXOs) == 0 {
+			t.Fatalf("Expected the UTXO index to contain UTXOs")
+		}
+		var sum uint64
+		for _, pair := range updatedUTXOs {
+			sum += pair.UTXOEntry.Amount()
+		}
+		if sum != updatedBalance {
+			t.Fatalf("Expected the balance to be %d, but got %d", sum, updatedBalance)
+		}
+
+		err = utxoIndex.Reset()
+		if err != nil {
+			t.Fatalf("Reset: %+v", err)
+		}
+		resetBalance := balance(t, utxoIndex, scriptPublicKey)
+		if resetBalance != updatedBalance {
+			t.Fatalf("Expected the balance after Reset to be %d, but got %d", updatedBalance, resetBalance)
+		}
+		resetUTXOs := allUTXOsByPages(t, utxoIndex, scriptPublicKey, 1000)
+		if len(resetUTXOs) != len(updatedUTXOs) {
+			t.Fatalf("Expected %d UTXOs after Reset, but got %d", len(updatedUTXOs), len(resetUTXOs))
+		}
+		for i := range resetUTXOs {
+			if !resetUTXOs[i].Outpoint.Equal(updatedUTXOs[i].Outpoint) {
+				t.Fatalf("Expected UTXO %d to be %s, but got %s", i, updatedUTXOs[i].Outpoint, resetUTXOs[i].Outpoint)
+			}
+		}
+
+		// Reopening the index with a different dust threshold rebuilds it.
+		// Since all the UTXOs in this test have the same amount, they all
+		// become dust
+		dustThreshold := updatedUTXOs[0].UTXOEntry.Amount() + 1
+		utxoIndex, err = New(tc, tc.Database(), dustThreshold)
+		if err != nil {
+			t.Fatalf("New: %+v", err)
+		}
+		addBlocksAndUpdate(t, tc, utxoIndex, blockAmount)
+		dustBalance := balance(t, utxoIndex, scriptPublicKey)
+		if dustBalance != 0 {
+			t.Fatalf("Expected the balance of dust UTXOs to be 0, but got %d", dustBalance)
+		}
+		dustUTXOs := allUTXOsByPages(t, utxoIndex, scriptPublicKey, 3)
+		if len(dustUTXOs) != 0 {
+			t.Fatalf("Expected dust UTXOs not to be indexed, but got %d UTXOs", len(dustUTXOs))
+		}
+
+		// Reopening the index with the original threshold indexes all the UTXOs again
+		utxoIndex, err = New(tc, tc.Database(), 0)
+		if err != nil {
+			t.Fatalf("New: %+v", err)
+		}
+		reopenedUTXOs := allUTXOsByPages(t, utxoIndex, scriptPublicKey, 3)
+		if len(reopenedUTXOs) <= len(updatedUTXOs) {
+			t.Fatalf("Expected more than %d UTXOs, but got %d", len(updatedUTXOs), len(reopenedUTXOs))
+		}
+	})
+}
+
+func addBlocksAndUpdate(t *testing.T, tc testapi.TestConsensus, utxoIndex *UTXOIndex, blockAmount int) {
+	for i := 0; i < blockAmount; i++ {
+		virtualInfo, err := tc.GetVirtualInfo()
+		if err != nil {
+			t.Fatalf("GetVirtualInfo: %+v", err)
+		}
+		_, blockInsertionResult, err := tc.AddBlock(virtualInfo.ParentHashes, nil, nil)
+		if err != nil {
+			t.Fatalf("AddBlock: %+v", err)
+		}
+		_, err = utxoIndex.Update(blockInsertionResult)
+		if err != nil {
+			t.Fatalf("Update: %+v", err)
+		}
+	}
+}
+
+func balance(t *testing.T, utxoIndex *UTXOIndex, scriptPublicKey *externalapi.ScriptPublicKey) uint64 {
+	balance, err := utxoIndex.Balance(scriptPublicKey)
+	if err != nil {
+		t.Fatalf("Balance: %+v", err)
+	}
+	return balance
+}
+
+func allUTXOsByPages(t *testing.T, utxoIndex *UTXOIndex, scriptPublicKey *externalapi.ScriptPublicKey,
+	limit int) []*externalapi.OutpointAndUTXOEntryPair {
+
+	var utxos []*externalapi.OutpointAndUTXOEntryPair
+	var fromOutpoint *externalapi.DomainOutpoint
+	for {
+		page, err := utxoIndex.UTXOsPage(scriptPublicKey, fromOutpoint, limit)
+		if err != nil {
+			t.Fatalf("UTXOsPage: %+v", err)
+		}
+		if len(page) > limit {
+			t.Fatalf("Expected at most %d UTXOs in a page, but got %d", limit, len(page))
+		}
+		utxos = append(utxos, page...)
+		if len(page) < limit {
+			return utxos
+		}
+		fromOutpoint = page[len(page)-1].Outpoint
+	}
+}
@@ -6,6 +6,7 @@ package config
 
 import (
 	"fmt"
+	"math"
 	"net"
 	"os"
 	"path/filepath"
@@ -41,6 +42,7 @@ const (
 	defaultMaxRPCClients         = 10
 	defaultMaxRPCWebsockets      = 25
 	defaultMaxRPCConcurrentReqs  = 20
+	defaultNotificationBacklog   = 1000
 	defaultBlockMaxMass          = 10000000
 	blockMaxMassMin              = 1000
 	blockMaxMassMax              = 10000000
@@ -51,6 +53,10 @@ const (
 	defaultSigCacheMaxSize  = 100000
 	sampleConfigFilename    = "sample-kaspad.conf"
 	defaultMaxUTXOCacheSize = 5000000000
+	defaultHealthMinPeers   = 1
+	defaultHealthMaxTipAge  = time.Hour
+	defaultMempoolExpiry    = time.Hour * 24
+	defaultStratumDiff      = 1 << 30
 )
 
 var (
@@ -72,51 +78,66 @@ var RunServiceCommand func(string) error
 //
 // See loadConfig for details on the configuration load process.
 type Flags struct {
-	ShowVersion          bool          `short:"V" long:"version" description:"Display version information and exit"`
-	ConfigFile           string        `short:"C" long:"configfile" description:"Path to configuration file"`
-	DataDir              string        `short:"b" long:"datadir" description:"Directory to store data"`
-	LogDir               string        `long:"logdir" description:"Directory to log output."`
-	AddPeers             []string      `short:"a" long:"addpeer" description:"Add a peer to connect with at startup"`
-	ConnectPeers         []string      `long:"connect" description:"Connect only to the specified peers at startup"`
-	DisableListen        bool          `long:"nolisten" description:"Disable listening for incoming connections -- NOTE: Listening is automatically disabled if the --connect or --proxy options are used without also specifying listen interfaces via --listen"`
-	Listeners            []string      `long:"listen" description:"Add an interface/port to listen for connections (default all interfaces port: 16111, testnet: 16211)"`
-	TargetOutboundPeers  int           `long:"outpeers" description:"Target number of outbound peers"`
-	MaxInboundPeers      int           `long:"maxinpeers" description:"Max number of inbound peers"`
-	DisableBanning       bool          `long:"nobanning" description:"Disable banning of misbehaving peers"`
-	BanDuration          time.Duration `long:"banduration" description:"How long to ban misbehaving peers. Valid time units are {s, m, h}. Minimum 1 second"`
-	BanThreshold         uint32        `long:"banthreshold" description:"Maximum allowed ban score before disconnecting and banning misbehaving peers."`
-	Whitelists           []string      `long:"whitelist" description:"Add an IP network or IP that will not be banned. (eg. 192.168.1.0/24 or ::1)"`
-	RPCListeners         []string      `long:"rpclisten" description:"Add an interface/port to listen for RPC connections (default port: 16110, testnet: 16210)"`
-	RPCCert              string        `long:"rpccert" description:"File containing the certificate file"`
-	RPCKey               string        `long:"rpckey" description:"File containing the certificate key"`
-	RPCMaxClients        int           `long:"rpcmaxclients" description:"Max number of RPC clients for standard connections"`
-	RPCMaxWebsockets     int           `long:"rpcmaxwebsockets" description:"Max number of RPC websocket connections"`
-	RPCMaxConcurrentReqs int           `long:"rpcmaxconcurrentreqs" description:"Max number of concurrent RPC requests that may be processed concurrently"`
-	DisableRPC           bool          `long:"norpc" description:"Disable built-in RPC server"`
-	DisableDNSSeed       bool          `long:"nodnsseed" description:"Disable DNS seeding for peers"`
-	DNSSeed              string        `long:"dnsseed" description:"Override DNS seeds with specified hostname (Only 1 hostname allowed)"`
-	GRPCSeed             string        `long:"grpcseed" description:"Hostname of gRPC server for seeding peers"`
-	ExternalIPs          []string      `long:"externalip" description:"Add an ip to the list of local addresses we claim to listen on to peers"`
-	Proxy                string        `long:"proxy" description:"Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)"`
-	ProxyUser            string        `long:"proxyuser" description:"Username for proxy server"`
-	ProxyPass            string        `long:"proxypass" default-mask:"-" description:"Password for proxy server"`
-	DbType               string        `long:"dbtype" description:"Database backend to use for the Block DAG"`
-	Profile              string        `long:"profile" description:"Enable HTTP profiling on given port -- NOTE port must be between 1024 and 65536"`
-	LogLevel             string        `short:"d" long:"loglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
-	Upnp                 bool          `long:"upnp" description:"Use UPnP to map our listening port outside of NAT"`
-	MinRelayTxFee        float64       `long:"minrelaytxfee" description:"The minimum transaction fee in KAS/kB to be considered a non-zero fee."`
-	MaxOrphanTxs         int           `long:"maxorphantx" description:"Max number of orphan transactions to keep in memory"`
-	BlockMaxMass         uint64        `long:"blockmaxmass" description:"Maximum transaction mass to be used when creating a block"`
-	UserAgentComments    []string      `long:"uacomment" description:"Comment to add to the user agent -- See BIP 14 for more information."`
-	NoPeerBloomFilters   bool          `long:"nopeerbloomfilters" description:"Disable bloom filtering support"`
-	SigCacheMaxSize      uint          `long:"sigcachemaxsize" description:"The maximum number of entries in the signature verification cache"`
-	BlocksOnly           bool          `long:"blocksonly" description:"Do not accept transactions from remote peers."`
-	RelayNonStd          bool          `long:"relaynonstd" description:"Relay non-standard transactions regardless of the default settings for the active network."`
-	RejectNonStd         bool          `long:"rejectnonstd" description:"Reject non-standard transactions regardless of the default settings for the active network."`
-	ResetDatabase        bool          `long:"reset-db" description:"Reset database before starting node. It's needed when switching between subnetworks."`
-	MaxUTXOCacheSize     uint64        `long:"maxutxocachesize" description:"Max size of loaded UTXO into ram from the disk in bytes"`
-	UTXOIndex            bool          `long:"utxoindex" description:"Enable the UTXO index"`
-	IsArchivalNode       bool          `long:"archival" description:"Run as an archival node: don't delete old block data when moving the pruning point (Warning: heavy disk usage)'"`
+	ShowVersion            bool          `short:"V" long:"version" description:"Display version information and exit"`
+	ConfigFile             string        `short:"C" long:"configfile" description:"Path to configuration file"`
+	DataDir                string        `short:"b" long:"datadir" description:"Directory to store data"`
+	LogDir                 string        `long:"logdir" description:"Directory to log output."`
+	AddPeers               []string      `short:"a" long:"addpeer" description:"Add a peer to connect with at startup"`
+	ConnectPeers           []string      `long:"connect" description:"Connect only to the specified peers at startup"`
+	DisableListen          bool          `long:"nolisten" description:"Disable listening for incoming connections -- NOTE: Listening is automatically disabled if the --connect or --proxy options are used without also specifying listen interfaces via --listen"`
+	Listeners              []string      `long:"listen" description:"Add an interface/port to listen for connections (default all interfaces port: 16111, testnet: 16211)"`
+	TargetOutboundPeers    int           `long:"outpeers" description:"Target number of outbound peers"`
+	MaxInboundPeers        int           `long:"maxinpeers" description:"Max number of inbound peers"`
+	DisableBanning         bool          `long:"nobanning" description:"Disable banning of misbehaving peers"`
+	BanDuration            time.Duration `long:"banduration" description:"How long to ban misbehaving peers. Valid time units are {s, m, h}. Minimum 1 second"`
+	BanThreshold           uint32        `long:"banthreshold" description:"Maximum allowed ban score before disconnecting and banning misbehaving peers."`
+	Whitelists             []string      `long:"whitelist" description:"Add an IP network or IP that will not be banned. (eg. 192.168.1.0/24 or ::1)"`
+	RPCListeners           []string      `long:"rpclisten" description:"Add an interface/port to listen for RPC connections (default port: 16110, testnet: 16210)"`
+	RPCCert                string        `long:"rpccert" description:"File containing the certificate file"`
+	RPCKey                 string        `long:"rpckey" description:"File containing the certificate key"`
+	RPCMaxClients          int           `long:"rpcmaxclients" description:"Max number of RPC clients for standard connections"`
+	RPCMaxWebsockets       int           `long:"rpcmaxwebsockets" description:"Max number of RPC websocket connections"`
+	RPCHTTPListeners       []string      `long:"rpchttplisten" description:"Add an interface/port to listen for REST and WebSocket RPC connections (eg. 127.0.0.1:16113) -- NOTE: The HTTP RPC server is disabled if this option is not specified"`
+	JSONRPCListeners       []string      `long:"jsonrpclisten" description:"Add an interface/port to listen for JSON-RPC 2.0 requests over HTTP (eg. 127.0.0.1:16114) -- NOTE: The JSON-RPC server is disabled if this option is not specified"`
+	RPCMaxConcurrentReqs   int           `long:"rpcmaxconcurrentreqs" description:"Max number of concurrent RPC requests that may be processed concurrently"`
+	RPCNotificationBacklog int           `long:"rpcnotificationbacklog" description:"Number of recent notifications of each RPC notification stream to keep for replaying to clients that resubscribe"`
+	DisableRPC             bool          `long:"norpc" description:"Disable built-in RPC server"`
+	DisableDNSSeed         bool          `long:"nodnsseed" description:"Disable DNS seeding for peers"`
+	DNSSeed                string        `long:"dnsseed" description:"Override DNS seeds with specified hostname (Only 1 hostname allowed)"`
+	GRPCSeed               string        `long:"grpcseed" description:"Hostname of gRPC server for seeding peers"`
+	ExternalIPs            []string      `long:"externalip" description:"Add an ip to the list of local addresses we claim to listen on to peers"`
+	Proxy                  string        `long:"proxy" description:"Connect via SOCKS5 proxy (eg. 127.0.0.1:9050)"`
+	ProxyUser              string        `long:"proxyuser" description:"Username for proxy server"`
+	ProxyPass              string        `long:"proxypass" default-mask:"-" description:"Password for proxy server"`
+	DbType                 string        `long:"dbtype" description:"Database backend to use for the Block DAG"`
+	Profile                string        `long:"profile" description:"Enable HTTP profiling on given port -- NOTE port must be between 1024 and 65536"`
+	LogLevel               string        `short:"d" long:"loglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
+	Upnp                   bool          `long:"upnp" description:"Use UPnP to map our listening port outside of NAT"`
+	MinRelayTxFee          float64       `long:"minrelaytxfee" description:"The minimum transaction fee in KAS/kB to be considered a non-zero fee."`
+	MaxOrphanTxs           int           `long:"maxorphantx" description:"Max number of orphan transactions to keep in memory"`
+	BlockMaxMass           uint64        `long:"blockmaxmass" description:"Maximum transaction mass to be used when creating a block"`
+	UserAgentComments      []string      `long:"uacomment" description:"Comment to add to the user agent -- See BIP 14 for more information."`
+	NoPeerBloomFilters     bool          `long:"nopeerbloomfilters" description:"Disable bloom filtering support"`
+	SigCacheMaxSize        uint          `long:"sigcachemaxsize" description:"The maximum number of entries in the signature verification cache"`
+	BlocksOnly             bool          `long:"blocksonly" description:"Do not accept transactions from remote peers."`
+	Dandelion              bool          `long:"dandelion" description:"Relay transactions along a random path of peers before broadcasting them, in order to hide the node they originated from"`
+	RelayNonStd            bool          `long:"relaynonstd" description:"Relay non-standard transactions regardless of the default settings for the active network."`
+	RejectNonStd           bool          `long:"rejectnonstd" description:"Reject non-standard transactions regardless of the default settings for the active network."`
+	NoPersistMempool       bool          `long:"nopersistmempool" description:"Do not save the mempool to the data directory on shutdown, and do not load it on startup"`
+	MempoolExpiry          time.Duration `long:"mempoolexpiry" description:"How long a transaction may stay in the mempool before it's evicted. Valid time units are {s, m, h}. Minimum 1 minute"`
+	ResetDatabase          bool          `long:"reset-db" description:"Reset database before starting node. It's needed when switching between subnetworks."`
+	Reindex                bool          `long:"reindex" description:"Rebuild the consensus data by revalidating the blocks stored in the database, starting from genesis if --archival is set, or from the pruning point otherwise. The database is rebuilt next to the original one, so about as much free disk space as the database takes is required. An interrupted reindex is resumed by restarting with --reindex"`
+	MaxUTXOCacheSize       uint64        `long:"maxutxocachesize" description:"Max size of loaded UTXO into ram from the disk in bytes"`
+	UTXOIndex              bool          `long:"utxoindex" description:"Enable the UTXO index"`
+	UTXOIndexDustThreshold uint64        `long:"utxoindexdustthreshold" description:"Don't index UTXOs whose amount (in sompi) is below this threshold. Such UTXOs are also excluded from the balances. Changing it rebuilds the UTXO index"`
+	IsArchivalNode         bool          `long:"archival" description:"Run as an archival node: don't delete old block data when moving the pruning point (Warning: heavy disk usage)'"`
+	HealthListen           string        `long:"healthlisten" description:"Interface/port for the HTTP health check server exposing /healthz and /readyz (eg. 127.0.0.1:16112) -- NOTE: The health check server is disabled if this option is not specified"`
+	HealthMinPeers         int           `long:"healthminpeers" description:"Minimum number of connected peers for /readyz to report the node as ready"`
+	HealthMaxTipAge        time.Duration `long:"healthmaxtipage" description:"Maximum age of the virtual selected parent's timestamp for /readyz to report the node as ready. Valid time units are {s, m, h}"`
+	StratumListen          string        `long:"stratumlisten" description:"Interface/port for the built-in stratum mining server (eg. 0.0.0.0:5555) -- NOTE: The stratum server is disabled if this option is not specified"`
+	StratumPayAddress      string        `long:"stratumpayaddress" description:"Address to which the coinbase of blocks mined through the stratum server pays"`
+	StratumDifficulty      float64       `long:"stratumdifficulty" description:"Initial share difficulty of stratum workers, relative to the network's minimum block difficulty. Workers may override it with mining.suggest_difficulty"`
+	StratumAllowUnsynced   bool          `long:"stratumallowunsynced" description:"Hand out stratum jobs even when the node is not synced"`
 	NetworkFlags
 	ServiceOptions *ServiceOptions
 }
@@ -126,12 +147,13 @@ type Flags struct {
 // See loadConfig for details on the configuration load process.
 type Config struct {
 	*Flags
-	Lookup        func(string) ([]net.IP, error)
-	Dial          func(string, string, time.Duration) (net.Conn, error)
-	MiningAddrs   []util.Address
-	MinRelayTxFee util.Amount
-	Whitelists    []*net.IPNet
-	SubnetworkID  *externalapi.DomainSubnetworkID // nil in full nodes
+	Lookup            func(string) ([]net.IP, error)
+	Dial              func(string, string, time.Duration) (net.Conn, error)
+	MiningAddrs       []util.Address
+	MinRelayTxFee     util.Amount
+	Whitelists        []*net.IPNet
+	SubnetworkID      *externalapi.DomainSubnetworkID // nil in full nodes
+	StratumPayAddress util.Address                    // nil if the stratum server is disabled
 }
 
 // ServiceOptions defines the configuration options for the daemon as a service on
@@ -165,25 +187,30 @@ func newConfigParser(cfgFlags *Flags, options flags.Options) *flags.Parser {
 
 func defaultFlags() *Flags {
 	return &Flags{
-		ConfigFile:           defaultConfigFile,
-		LogLevel:             defaultLogLevel,
-		TargetOutboundPeers:  defaultTargetOutboundPeers,
-		MaxInboundPeers:      defaultMaxInboundPeers,
-		BanDuration:          defaultBanDuration,
-		BanThreshold:         defaultBanThreshold,
-		RPCMaxClients:        defaultMaxRPCClients,
-		RPCMaxWebsockets:     defaultMaxRPCWebsockets,
-		RPCMaxConcurrentReqs: defaultMaxRPCConcurrentReqs,
-		DataDir:              defaultDataDir,
-		LogDir:               defaultLogDir,
-		RPCKey:               defaultRPCKeyFile,
-		RPCCert:              defaultRPCCertFile,
-		BlockMaxMass:         defaultBlockMaxMass,
-		MaxOrphanTxs:         defaultMaxOrphanTransactions,
-		SigCacheMaxSize:      defaultSigCacheMaxSize,
-		MinRelayTxFee:        defaultMinRelayTxFee,
-		MaxUTXOCacheSize:     defaultMaxUTXOCacheSize,
-		ServiceOptions:       &ServiceOptions{},
+		ConfigFile:             defaultConfigFile,
+		LogLevel:               defaultLogLevel,
+		TargetOutboundPeers:    defaultTargetOutboundPeers,
+		MaxInboundPeers:        defaultMaxInboundPeers,
+		BanDuration:            defaultBanDuration,
+		BanThreshold:           defaultBanThreshold,
+		RPCMaxClients:          defaultMaxRPCClients,
+		RPCMaxWebsockets:       defaultMaxRPCWebsockets,
+		RPCMaxConcurrentReqs:   defaultMaxRPCConcurrentReqs,
+		RPCNotificationBacklog: defaultNotificationBacklog,
+		DataDir:                defaultDataDir,
+		LogDir:                 defaultLogDir,
+		RPCKey:                 defaultRPCKeyFile,
+		RPCCert:                defaultRPCCertFile,
+		BlockMaxMass:           defaultBlockMaxMass,
+		MaxOrphanTxs:           defaultMaxOrphanTransactions,
+		SigCacheMaxSize:        defaultSigCacheMaxSize,
+		MinRelayTxFee:          defaultMinRelayTxFee,
+		MaxUTXOCacheSize:       defaultMaxUTXOCacheSize,
+		HealthMinPeers:         defaultHealthMinPeers,
+		HealthMaxTipAge:        defaultHealthMaxTipAge,
+		MempoolExpiry:          defaultMempoolExpiry,
+		StratumDifficulty:      defaultStratumDiff,
+		ServiceOptions:         &ServiceOptions{},
 	}
 }
 
@@ -311,6 +338,25 @@ func LoadConfig() (*Config, error) {
 	}
 	cfg.RelayNonStd = relayNonStd
 
+	// Disallow --reset-db and --reindex used together
+	if cfg.ResetDatabase && cfg.Reindex {
+		str := "%s: --reset-db and --reindex can not be used together"
+		err := errors.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Disallow --dandelion and --blocksonly used together, since a blocks-only
+	// node can't be part of a transaction's stem
+	if cfg.Dandelion && cfg.BlocksOnly {
+		str := "%s: --dandelion and --blocksonly can not be used together"
+		err := errors.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
 	// Append the network type to the data directory so it is "namespaced"
 	// per network. In addition to the block database, there are other
 	// pieces of data that are saved to disk such as address manager state.
@@ -440,6 +486,32 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	// The HTTP RPC server serves the same RPC handlers as the RPC server
+	if cfg.DisableRPC && len(cfg.RPCHTTPListeners) > 0 {
+		str := "%s: --rpchttplisten can not be used together with --norpc"
+		err := errors.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// The JSON-RPC server serves the same RPC handlers as the RPC server
+	if cfg.DisableRPC && len(cfg.JSONRPCListeners) > 0 {
+		str := "%s: --jsonrpclisten can not be used together with --norpc"
+		err := errors.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	if cfg.RPCMaxWebsockets < 0 {
+		str := "%s: The rpcmaxwebsockets option may not be less than 0 -- parsed [%d]"
+		err := errors.Errorf(str, funcName, cfg.RPCMaxWebsockets)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
 	if cfg.RPCMaxConcurrentReqs < 0 {
 		str := "%s: The rpcmaxwebsocketconcurrentrequests option may " +
 			"not be less than 0 -- parsed [%d]"
@@ -449,6 +521,63 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	if cfg.RPCNotificationBacklog < 0 {
+		str := "%s: The rpcnotificationbacklog option may not be less than 0 -- parsed [%d]"
+		err := errors.Errorf(str, funcName, cfg.RPCNotificationBacklog)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	if cfg.HealthMinPeers < 0 {
+		str := "%s: The healthminpeers option may not be less than 0 -- parsed [%d]"
+		err := errors.Errorf(str, funcName, cfg.HealthMinPeers)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	if cfg.MempoolExpiry < time.Minute {
+		str := "%s: The mempoolexpiry option may not be less than 1m -- parsed [%s]"
+		err := errors.Errorf(str, funcName, cfg.MempoolExpiry)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	if cfg.HealthMaxTipAge <= 0 {
+		str := "%s: The healthmaxtipage option must be greater than 0 -- parsed [%s]"
+		err := errors.Errorf(str, funcName, cfg.HealthMaxTipAge)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	if cfg.StratumListen != "" {
+		if cfg.Flags.StratumPayAddress == "" {
+			str := "%s: The stratumpayaddress option is required when stratumlisten is set"
+			err := errors.Errorf(str, funcName)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, err
+		}
+		cfg.StratumPayAddress, err = util.DecodeAddress(cfg.Flags.StratumPayAddress, cfg.NetParams().Prefix)
+		if err != nil {
+			str := "%s: invalid stratumpayaddress: %s"
+			err := errors.Errorf(str, funcName, err)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, err
+		}
+		if math.IsNaN(cfg.StratumDifficulty) || math.IsInf(cfg.StratumDifficulty, 0) || cfg.StratumDifficulty <= 0 {
+			str := "%s: The stratumdifficulty option must be a finite number greater than 0 -- parsed [%f]"
+			err := errors.Errorf(str, funcName, cfg.StratumDifficulty)
+			fmt.Fprintln(os.Stderr, err)
+			fmt.Fprintln(os.Stderr, usageMessage)
+			return nil, err
+		}
+	}
+
 	// Validate the the minrelaytxfee.
 	cfg.MinRelayTxFee, err = util.NewAmount(cfg.Flags.MinRelayTxFee)
 	if err != nil {
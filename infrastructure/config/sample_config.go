@@ -17,6 +17,12 @@ var sampleConfig = `[Application Options]
 ; $VARIABLE here. Also, ~ is expanded to $LOCALAPPDATA on Windows.
 ; datadir=~/.kaspad/data
 
+; Rebuild all the consensus data from the blocks that are already stored in the
+; database, revalidating every block. Archival nodes revalidate the blocks
+; starting from genesis, while other nodes start from the pruning point. The
+; reindex may be resumed after an interruption by restarting with this option.
+; reindex=1
+
 
 ; ------------------------------------------------------------------------------
 ; Network settings
@@ -188,10 +194,67 @@ var sampleConfig = `[Application Options]
 ; Specify the maximum number of concurrent RPC clients for standard connections.
 ; rpcmaxclients=10
 
+; Specify the interfaces/ports for the HTTP RPC server to listen on. The HTTP RPC
+; server exposes every RPC request as a REST endpoint (eg. POST /getBlockDagInfo
+; with a JSON body) and a WebSocket endpoint at /ws for notifications. It is
+; disabled if this option is not specified.
+;   rpchttplisten=127.0.0.1:16113
+
+; Specify the maximum number of concurrent WebSocket connections to the HTTP RPC
+; server.
+; rpcmaxwebsockets=25
+
+; Specify the interfaces/ports for the JSON-RPC 2.0 server to listen on. The
+; JSON-RPC server accepts the same requests as the HTTP RPC server, with method
+; names such as getBlockDagInfo, and supports batch requests. It is disabled if
+; this option is not specified.
+;   jsonrpclisten=127.0.0.1:16114
+
+; Specify the number of recent notifications of each RPC notification stream
+; (blockAdded, virtualSelectedParentChainChanged and utxosChanged) to keep, so
+; that clients that resubscribe from a sequence number get the notifications
+; they missed.
+; rpcnotificationbacklog=1000
+
 ; Use the following setting to disable the RPC server.
 ; norpc=1
 
 
+; ------------------------------------------------------------------------------
+; Health check server options - The following options control the built-in
+; HTTP server exposing the /healthz and /readyz endpoints
+; ------------------------------------------------------------------------------
+
+; Specify the interface/port for the health check server to listen on. The
+; health check server will be disabled if this option is not specified.
+;   healthlisten=127.0.0.1:16112
+
+; Minimum number of connected peers for /readyz to report the node as ready.
+; healthminpeers=1
+
+; Maximum age of the virtual selected parent's timestamp for /readyz to report
+; the node as ready.
+; healthmaxtipage=1h
+
+
+; ------------------------------------------------------------------------------
+; Stratum server options - The following options control the built-in stratum
+; mining server
+; ------------------------------------------------------------------------------
+
+; Specify the interface/port for the stratum server to listen on. The stratum
+; server will be disabled if this option is not specified.
+;   stratumlisten=0.0.0.0:5555
+
+; The address to which the coinbase of blocks mined through the stratum server
+; pays. Required when stratumlisten is set.
+; stratumpayaddress=
+
+; Initial share difficulty of stratum workers, relative to the network's minimum
+; block difficulty.
+; stratumdifficulty=1073741824
+
+
 ; ------------------------------------------------------------------------------
 ; Mempool Settings - The following options
 ; ------------------------------------------------------------------------------
@@ -205,12 +268,28 @@ var sampleConfig = `[Application Options]
 ; Do not accept transactions from remote peers.
 ; blocksonly=1
 
+; Relay transactions using the Dandelion stem/fluff protocol. A new transaction
+; is first passed along a random path of outbound peers that support it, and
+; only then broadcast to the whole network, which hides the node it originated
+; from. Transactions on their way along that path, including the ones submitted
+; to this node, are only added to the mempool once they're broadcast.
+; dandelion=1
+
 ; Relay non-standard transactions regardless of default network settings.
 ; relaynonstd=1
 
 ; Reject non-standard transactions regardless of default network settings.
 ; rejectnonstd=1
 
+; Do not save the mempool to mempool.dat in the data directory on shutdown,
+; and do not load it from there on startup.
+; nopersistmempool=1
+
+; How long a transaction may stay in the mempool before it's evicted, along
+; with the transactions that spend its outputs. Valid time units are {s, m, h}.
+; Minimum 1m.
+; mempoolexpiry=24h
+
 
 ; ------------------------------------------------------------------------------
 ; Signature Verification Cache
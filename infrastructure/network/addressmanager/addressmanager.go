@@ -7,6 +7,7 @@ package addressmanager
 import (
 	"github.com/kaspanet/kaspad/infrastructure/db/database"
 	"github.com/kaspanet/kaspad/util/mstime"
+	"math/rand"
 	"net"
 	"sync"
 	"time"
@@ -68,7 +69,7 @@ type AddressManager struct {
 
 // New returns a new Kaspa address manager.
 func New(cfg *Config, database database.Database) (*AddressManager, error) {
-	addressStore, err := newAddressStore(database)
+	addressStore, err := newAddressStore(database, cfg.AcceptUnroutable)
 	if err != nil {
 		return nil, err
 	}
@@ -85,13 +86,13 @@ func New(cfg *Config, database database.Database) (*AddressManager, error) {
 	}, nil
 }
 
-func (am *AddressManager) addAddressNoLock(address *appmessage.NetAddress) error {
+func (am *AddressManager) addAddressNoLock(address *appmessage.NetAddress, source *appmessage.NetAddress) error {
 	if !IsRoutable(address, am.cfg.AcceptUnroutable) {
 		return nil
 	}
 
 	key := netAddressKey(address)
-	return am.store.add(key, address)
+	return am.store.add(key, address, source)
 }
 
 // AddAddress adds address to the address manager
@@ -99,16 +100,33 @@ func (am *AddressManager) AddAddress(address *appmessage.NetAddress) error {
 	am.mutex.Lock()
 	defer am.mutex.Unlock()
 
-	return am.addAddressNoLock(address)
+	return am.addAddressNoLock(address, address)
 }
 
-// AddAddresses adds addresses to the address manager
+// AddAddresses adds addresses to the address manager.
+// Use AddAddressesFromSource for addresses that were sent by a peer.
 func (am *AddressManager) AddAddresses(addresses ...*appmessage.NetAddress) error {
 	am.mutex.Lock()
 	defer am.mutex.Unlock()
 
 	for _, address := range addresses {
-		err := am.addAddressNoLock(address)
+		err := am.addAddressNoLock(address, address)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddAddressesFromSource adds addresses that were sent by the peer at source to the
+// address manager. The addresses a single peer can add are limited to a few buckets
+// of the new table, determined by the netgroup of the peer.
+func (am *AddressManager) AddAddressesFromSource(source *appmessage.NetAddress, addresses ...*appmessage.NetAddress) error {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	for _, address := range addresses {
+		err := am.addAddressNoLock(address, source)
 		if err != nil {
 			return err
 		}
@@ -161,6 +179,128 @@ func (am *AddressManager) RandomAddresses(count int, exceptions []*appmessage.Ne
 	return am.random.RandomAddresses(validAddresses, count)
 }
 
+// RandomOutgoingAddresses returns up to count addresses at random that aren't banned and
+// aren't in exceptions, to open outgoing connections to. Every address is drawn from the
+// tried table or from the new table with equal probability, so that the addresses we
+// successfully connected to in the past aren't outnumbered by addresses an attacker floods
+// us with. Addresses whose netgroup isn't in usedGroups and differs from the netgroups of
+// the other returned addresses are preferred, so that a single network operator can't
+// easily control all our outgoing connections.
+func (am *AddressManager) RandomOutgoingAddresses(count int, exceptions []*appmessage.NetAddress,
+	usedGroups map[string]struct{}) []*appmessage.NetAddress {
+
+	am.mutex.Lock()
+	triedAddresses, newAddresses := am.store.getTriedAndNewWithout(exceptions)
+	am.mutex.Unlock()
+
+	triedAddresses = am.random.RandomAddresses(triedAddresses, len(triedAddresses))
+	newAddresses = am.random.RandomAddresses(newAddresses, len(newAddresses))
+
+	groups := make(map[string]struct{}, len(usedGroups)+count)
+	for group := range usedGroups {
+		groups[group] = struct{}{}
+	}
+	addresses := make([]*appmessage.NetAddress, 0, count)
+	var addressesInUsedGroups []*appmessage.NetAddress
+	for len(addresses) < count && (len(triedAddresses) > 0 || len(newAddresses) > 0) {
+		var address *appmessage.NetAddress
+		if len(newAddresses) == 0 || (len(triedAddresses) > 0 && rand.Intn(2) == 0) {
+			address, triedAddresses = triedAddresses[0], triedAddresses[1:]
+		} else {
+			address, newAddresses = newAddresses[0], newAddresses[1:]
+		}
+
+		group := am.GroupKey(address)
+		if _, ok := groups[group]; ok {
+			addressesInUsedGroups = append(addressesInUsedGroups, address)
+			continue
+		}
+		groups[group] = struct{}{}
+		addresses = append(addresses, address)
+	}
+
+	// Fall back to addresses in netgroups that are already used
+	// if there are not enough addresses in other netgroups
+	for len(addresses) < count && len(addressesInUsedGroups) > 0 {
+		addresses = append(addresses, addressesInUsedGroups[0])
+		addressesInUsedGroups = addressesInUsedGroups[1:]
+	}
+	return addresses
+}
+
+// RandomNewAddress returns a random address from the new table, that is, an address
+// we never connected to, that isn't banned and isn't in exceptions. It returns nil
+// if there's no such address.
+func (am *AddressManager) RandomNewAddress(exceptions []*appmessage.NetAddress) *appmessage.NetAddress {
+	am.mutex.Lock()
+	_, newAddresses := am.store.getTriedAndNewWithout(exceptions)
+	am.mutex.Unlock()
+
+	return am.random.RandomAddress(newAddresses)
+}
+
+// Anchors returns the anchor addresses, which are the addresses of outgoing
+// peers that were saved by SetAnchors, so that the node reconnects to them
+// after a restart.
+func (am *AddressManager) Anchors() ([]*appmessage.NetAddress, error) {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	return am.store.getAnchors()
+}
+
+// SetAnchors replaces the anchor addresses with the given ones
+func (am *AddressManager) SetAnchors(anchors []*appmessage.NetAddress) error {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	return am.store.setAnchors(anchors)
+}
+
+// MarkConnectionAttempt records an attempt to connect to the given address.
+// Addresses that aren't in the address manager are ignored.
+func (am *AddressManager) MarkConnectionAttempt(address *appmessage.NetAddress) error {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	entry, ok := am.store.get(netAddressKey(address))
+	if !ok {
+		return nil
+	}
+	return am.store.markAttempt(entry)
+}
+
+// MarkConnectionSuccess records a successful connection to the given address, and
+// moves it to the tried table. Addresses that aren't in the address manager are ignored.
+func (am *AddressManager) MarkConnectionSuccess(address *appmessage.NetAddress) error {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	entry, ok := am.store.get(netAddressKey(address))
+	if !ok {
+		return nil
+	}
+	return am.store.markSuccess(entry)
+}
+
+// MarkConnectionFailure records a failed attempt to connect to the given address.
+// Addresses in the new table are removed, while addresses in the tried table are
+// kept, since we already connected to them before, until they become terrible.
+func (am *AddressManager) MarkConnectionFailure(address *appmessage.NetAddress) error {
+	am.mutex.Lock()
+	defer am.mutex.Unlock()
+
+	key := netAddressKey(address)
+	entry, ok := am.store.get(key)
+	if !ok {
+		return nil
+	}
+	if entry.isTried && !entry.isTerrible(mstime.Now()) {
+		return nil
+	}
+	return am.store.remove(key)
+}
+
 // BestLocalAddress returns the most appropriate local address to use
 // for the given remote address.
 func (am *AddressManager) BestLocalAddress(remoteAddress *appmessage.NetAddress) *appmessage.NetAddress {
@@ -303,3 +303,276 @@ func TestRestoreAddressManager(t *testing.T) {
 		t.Fatalf("Banned address %s not returned from BannedAddresses()", addressToBan.IP)
 	}
 }
+
+func TestNewTableSourceGroupLimit(t *testing.T) {
+	addressManager, teardown := newAddressManagerForTest(t, "TestNewTableSourceGroupLimit")
+	defer teardown()
+
+	// Flood the address manager with addresses from many netgroups,
+	// all of them sent by peers in the same netgroup
+	source := &appmessage.NetAddress{IP: net.ParseIP("1.2.3.4")}
+	for i := 0; i < 100; i++ {
+		for j := 0; j < 100; j++ {
+			address := &appmessage.NetAddress{IP: net.IPv4(20, byte(i), byte(j), 1), Timestamp: mstime.Now()}
+			err := addressManager.AddAddressesFromSource(source, address)
+			if err != nil {
+				t.Fatalf("AddAddressesFromSource: %s", err)
+			}
+		}
+	}
+
+	usedBuckets := 0
+	for _, bucket := range addressManager.store.newBuckets {
+		for _, entry := range bucket {
+			if entry != nil {
+				usedBuckets++
+				break
+			}
+		}
+	}
+	if usedBuckets > newBucketsPerSourceGroup {
+		t.Fatalf("Expected the addresses sent from a single netgroup to take at most %d buckets, but they take %d",
+			newBucketsPerSourceGroup, usedBuckets)
+	}
+	if len(addressManager.Addresses()) > newBucketsPerSourceGroup*bucketSize {
+		t.Fatalf("Expected at most %d addresses to be stored, but got %d",
+			newBucketsPerSourceGroup*bucketSize, len(addressManager.Addresses()))
+	}
+
+	// Addresses sent from another netgroup to any of the buckets
+	// that the flood didn't reach are still added
+	otherAddress := &appmessage.NetAddress{IP: net.ParseIP("30.1.2.3"), Timestamp: mstime.Now()}
+	var otherSource *appmessage.NetAddress
+	for i := 0; otherSource == nil; i++ {
+		candidateSource := &appmessage.NetAddress{IP: net.IPv4(40, byte(i), 0, 1)}
+		slot := addressManager.store.newBucketSlot(&addressEntry{
+			key:        netAddressKey(otherAddress),
+			netAddress: otherAddress,
+			source:     candidateSource,
+		})
+		if addressManager.store.newBuckets[slot.bucket] == [bucketSize]*addressEntry{} {
+			otherSource = candidateSource
+		}
+	}
+	err := addressManager.AddAddressesFromSource(otherSource, otherAddress)
+	if err != nil {
+		t.Fatalf("AddAddressesFromSource: %s", err)
+	}
+	if !addressManager.store.isNotBanned(netAddressKey(otherAddress)) {
+		t.Fatalf("Expected an address from another source netgroup to be added")
+	}
+}
+
+func TestTriedAddresses(t *testing.T) {
+	cfg := config.DefaultConfig()
+
+	datadir := t.TempDir()
+	database, err := ldb.NewLevelDB(datadir, 8)
+	if err != nil {
+		t.Fatalf("Could not create a database: %s", err)
+	}
+	defer func() {
+		database.Close()
+	}()
+
+	addressManager, err := New(NewConfig(cfg), database)
+	if err != nil {
+		t.Fatalf("Error creating address manager: %s", err)
+	}
+
+	triedAddress := &appmessage.NetAddress{IP: net.ParseIP("1.2.3.4"), Port: 16111, Timestamp: mstime.Now()}
+	newAddress := &appmessage.NetAddress{IP: net.ParseIP("5.6.7.8"), Port: 16111, Timestamp: mstime.Now()}
+	err = addressManager.AddAddresses(triedAddress, newAddress)
+	if err != nil {
+		t.Fatalf("AddAddresses() failed: %s", err)
+	}
+	err = addressManager.MarkConnectionAttempt(triedAddress)
+	if err != nil {
+		t.Fatalf("MarkConnectionAttempt() failed: %s", err)
+	}
+	err = addressManager.MarkConnectionSuccess(triedAddress)
+	if err != nil {
+		t.Fatalf("MarkConnectionSuccess() failed: %s", err)
+	}
+
+	// Reopen the database, and make sure the address is still in the tried table
+	err = database.Close()
+	if err != nil {
+		t.Fatalf("Close() failed: %s", err)
+	}
+	database, err = ldb.NewLevelDB(datadir, 8)
+	if err != nil {
+		t.Fatalf("Could not create a database: %s", err)
+	}
+	addressManager, err = New(NewConfig(cfg), database)
+	if err != nil {
+		t.Fatalf("Error creating address manager: %s", err)
+	}
+	entry, ok := addressManager.store.get(netAddressKey(triedAddress))
+	if !ok || !entry.isTried || entry.attempts != 0 || entry.lastAttempt.IsZero() || entry.lastSuccess.IsZero() {
+		t.Fatalf("Expected %s to be restored as a tried address, but got %+v", triedAddress.IP, entry)
+	}
+	if addressManager.store.triedCount != 1 || addressManager.store.newCount != 1 {
+		t.Fatalf("Expected 1 tried address and 1 new address, but got %d and %d",
+			addressManager.store.triedCount, addressManager.store.newCount)
+	}
+
+	// A failed connection removes a new address, but keeps a tried one
+	for _, address := range []*appmessage.NetAddress{triedAddress, newAddress} {
+		err = addressManager.MarkConnectionAttempt(address)
+		if err != nil {
+			t.Fatalf("MarkConnectionAttempt() failed: %s", err)
+		}
+		err = addressManager.MarkConnectionFailure(address)
+		if err != nil {
+			t.Fatalf("MarkConnectionFailure() failed: %s", err)
+		}
+	}
+	addresses := addressManager.Addresses()
+	if len(addresses) != 1 || !addresses[0].IP.Equal(triedAddress.IP) {
+		t.Fatalf("Expected only the tried address to remain, but got %v", addresses)
+	}
+}
+
+func TestRandomOutgoingAddresses(t *testing.T) {
+	addressManager, teardown := newAddressManagerForTest(t, "TestRandomOutgoingAddresses")
+	defer teardown()
+
+	// Add 3 addresses in each of 5 netgroups. Every address is sent from a
+	// different source netgroup, so that they don't compete for bucket slots
+	for i := 0; i < 5; i++ {
+		for j := 0; j < 3; j++ {
+			address := &appmessage.NetAddress{IP: net.IPv4(20, byte(i), byte(j), 1), Timestamp: mstime.Now()}
+			source := &appmessage.NetAddress{IP: net.IPv4(30, byte(i*3+j), 0, 1)}
+			err := addressManager.AddAddressesFromSource(source, address)
+			if err != nil {
+				t.Fatalf("AddAddressesFromSource: %s", err)
+			}
+		}
+	}
+
+	usedGroups := map[string]struct{}{addressManager.GroupKey(&appmessage.NetAddress{IP: net.IPv4(20, 0, 0, 1)}): {}}
+	addresses := addressManager.RandomOutgoingAddresses(4, nil, usedGroups)
+	if len(addresses) != 4 {
+		t.Fatalf("Expected 4 addresses, but got %d", len(addresses))
+	}
+	groups := make(map[string]struct{})
+	for _, address := range addresses {
+		group := addressManager.GroupKey(address)
+		if _, ok := usedGroups[group]; ok {
+			t.Fatalf("Expected no address in a used netgroup, but got %s", address.IP)
+		}
+		if _, ok := groups[group]; ok {
+			t.Fatalf("Expected the addresses to be in distinct netgroups, but got two in %s", group)
+		}
+		groups[group] = struct{}{}
+	}
+
+	// When there aren't enough distinct netgroups, addresses in used netgroups are returned as well
+	addresses = addressManager.RandomOutgoingAddresses(10, nil, usedGroups)
+	if len(addresses) != 10 {
+		t.Fatalf("Expected 10 addresses, but got %d", len(addresses))
+	}
+}
+
+func TestRandomNewAddress(t *testing.T) {
+	addressManager, teardown := newAddressManagerForTest(t, "TestRandomNewAddress")
+	defer teardown()
+
+	triedAddress := &appmessage.NetAddress{IP: net.ParseIP("1.2.3.4"), Port: 16111, Timestamp: mstime.Now()}
+	newAddress := &appmessage.NetAddress{IP: net.ParseIP("5.6.7.8"), Port: 16111, Timestamp: mstime.Now()}
+	err := addressManager.AddAddresses(triedAddress, newAddress)
+	if err != nil {
+		t.Fatalf("AddAddresses() failed: %s", err)
+	}
+	err = addressManager.MarkConnectionSuccess(triedAddress)
+	if err != nil {
+		t.Fatalf("MarkConnectionSuccess() failed: %s", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		address := addressManager.RandomNewAddress(nil)
+		if address == nil || !address.IP.Equal(newAddress.IP) {
+			t.Fatalf("Expected RandomNewAddress() to return %s, but got %v", newAddress.IP, address)
+		}
+	}
+
+	address := addressManager.RandomNewAddress([]*appmessage.NetAddress{newAddress})
+	if address != nil {
+		t.Fatalf("Expected RandomNewAddress() to return nil when the only new address is excepted, "+
+			"but got %s", address.IP)
+	}
+}
+
+func TestAnchors(t *testing.T) {
+	cfg := config.DefaultConfig()
+
+	datadir := t.TempDir()
+	database, err := ldb.NewLevelDB(datadir, 8)
+	if err != nil {
+		t.Fatalf("Could not create a database: %s", err)
+	}
+	defer func() {
+		database.Close()
+	}()
+
+	addressManager, err := New(NewConfig(cfg), database)
+	if err != nil {
+		t.Fatalf("Error creating address manager: %s", err)
+	}
+	anchors, err := addressManager.Anchors()
+	if err != nil {
+		t.Fatalf("Anchors() failed: %s", err)
+	}
+	if len(anchors) != 0 {
+		t.Fatalf("Expected no anchors, but got %v", anchors)
+	}
+
+	expectedAnchors := []*appmessage.NetAddress{
+		{IP: net.ParseIP("1.2.3.4"), Port: 16111, Timestamp: mstime.Now()},
+		{IP: net.ParseIP("2001:db8::1"), Port: 16112, Timestamp: mstime.Now()},
+	}
+	err = addressManager.SetAnchors(expectedAnchors)
+	if err != nil {
+		t.Fatalf("SetAnchors() failed: %s", err)
+	}
+
+	// Reopen the database, and make sure the anchors were persisted
+	err = database.Close()
+	if err != nil {
+		t.Fatalf("Close() failed: %s", err)
+	}
+	database, err = ldb.NewLevelDB(datadir, 8)
+	if err != nil {
+		t.Fatalf("Could not create a database: %s", err)
+	}
+	addressManager, err = New(NewConfig(cfg), database)
+	if err != nil {
+		t.Fatalf("Error creating address manager: %s", err)
+	}
+	anchors, err = addressManager.Anchors()
+	if err != nil {
+		t.Fatalf("Anchors() failed: %s", err)
+	}
+	if len(anchors) != len(expectedAnchors) {
+		t.Fatalf("Expected %d anchors, but got %d", len(expectedAnchors), len(anchors))
+	}
+	for i, anchor := range anchors {
+		if !anchor.IP.Equal(expectedAnchors[i].IP) || anchor.Port != expectedAnchors[i].Port {
+			t.Fatalf("Expected anchor %s:%d, but got %s:%d",
+				expectedAnchors[i].IP, expectedAnchors[i].Port, anchor.IP, anchor.Port)
+		}
+	}
+
+	err = addressManager.SetAnchors(nil)
+	if err != nil {
+		t.Fatalf("SetAnchors() failed: %s", err)
+	}
+	anchors, err = addressManager.Anchors()
+	if err != nil {
+		t.Fatalf("Anchors() failed: %s", err)
+	}
+	if len(anchors) != 0 {
+		t.Fatalf("Expected no anchors after clearing them, but got %v", anchors)
+	}
+}
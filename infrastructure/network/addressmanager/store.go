@@ -1,29 +1,129 @@
 package addressmanager
 
 import (
+	"crypto/rand"
 	"encoding/binary"
+	"net"
+	"time"
+
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/infrastructure/db/database"
 	"github.com/kaspanet/kaspad/util/mstime"
-	"net"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/blake2b"
 )
 
 var notBannedAddressBucket = database.MakeBucket([]byte("not-banned-addresses"))
 var bannedAddressBucket = database.MakeBucket([]byte("banned-addresses"))
+var addressManagerBucket = database.MakeBucket([]byte("address-manager"))
+var bucketingKeyKey = addressManagerBucket.Key([]byte("bucketing-key"))
+var anchorsKey = addressManagerBucket.Key([]byte("anchors"))
+
+// StoreBuckets are the database buckets in which the address manager stores its data
+var StoreBuckets = []*database.Bucket{notBannedAddressBucket, bannedAddressBucket, addressManagerBucket}
+
+// The not banned addresses are kept in two tables of fixed size buckets, similarly
+// to bitcoind's address manager. The new table holds addresses we've heard about
+// but never connected to, and the tried table holds addresses we successfully
+// connected to at least once.
+//
+// The bucket of an address in the new table is determined by a keyed hash of the
+// netgroup of the peer that sent it to us and of its own netgroup, such that any
+// single source netgroup can only fill a few of the buckets. The bucket of an address
+// in the tried table is determined by a keyed hash of the address and its netgroup,
+// such that any single netgroup can only fill a few of the buckets. Since the hash
+// key is secret, an attacker can't craft addresses that would land in the buckets
+// of its choice, and so can't push out the addresses we already know of by
+// flooding us with addresses of its own.
+const (
+	newBucketCount   = 256
+	triedBucketCount = 64
+	bucketSize       = 64
+
+	// newBucketsPerSourceGroup is the number of new buckets the addresses
+	// sent to us by the peers of a single netgroup can end up in
+	newBucketsPerSourceGroup = 32
+
+	// triedBucketsPerGroup is the number of tried buckets the addresses
+	// of a single netgroup can end up in
+	triedBucketsPerGroup = 8
+
+	bucketingKeyLength = 32
+)
+
+// addressEntry is a not banned address along with the data the address
+// manager needs in order to place it in its buckets and to evict it
+type addressEntry struct {
+	key        addressKey
+	netAddress *appmessage.NetAddress
+
+	// source is the address of the peer that sent us this address. It's the
+	// address itself if the address was discovered in some other way
+	source *appmessage.NetAddress
+
+	isTried     bool
+	attempts    uint32
+	lastAttempt mstime.Time
+	lastSuccess mstime.Time
+}
+
+// isTerrible returns whether the entry is not worth keeping, and may
+// be replaced by a new address that falls in the same bucket slot
+func (entry *addressEntry) isTerrible(now mstime.Time) bool {
+	const (
+		maxTimestampInTheFuture   = 10 * time.Minute
+		maxTimestampAge           = 30 * 24 * time.Hour
+		maxAttemptsWithoutSuccess = 3
+		maxTimeSinceSuccess       = 7 * 24 * time.Hour
+		maxAttemptsSinceSuccess   = 10
+	)
+
+	if entry.netAddress.Timestamp.Sub(now) > maxTimestampInTheFuture {
+		return true
+	}
+	if now.Sub(entry.netAddress.Timestamp) > maxTimestampAge {
+		return true
+	}
+	if entry.lastSuccess.UnixMilliseconds() == 0 && entry.attempts >= maxAttemptsWithoutSuccess {
+		return true
+	}
+	if now.Sub(entry.lastSuccess) > maxTimeSinceSuccess && entry.attempts >= maxAttemptsSinceSuccess {
+		return true
+	}
+	return false
+}
+
+type bucketSlot struct {
+	bucket   int
+	position int
+}
 
 type addressStore struct {
-	database           database.Database
-	notBannedAddresses map[addressKey]*appmessage.NetAddress
+	database         database.Database
+	acceptUnroutable bool
+	bucketingKey     []byte
+
+	notBannedAddresses map[addressKey]*addressEntry
 	bannedAddresses    map[ipv6]*appmessage.NetAddress
+
+	newBuckets   [newBucketCount][bucketSize]*addressEntry
+	triedBuckets [triedBucketCount][bucketSize]*addressEntry
+	newCount     int
+	triedCount   int
 }
 
-func newAddressStore(database database.Database) (*addressStore, error) {
+func newAddressStore(database database.Database, acceptUnroutable bool) (*addressStore, error) {
 	addressStore := &addressStore{
 		database:           database,
-		notBannedAddresses: map[addressKey]*appmessage.NetAddress{},
+		acceptUnroutable:   acceptUnroutable,
+		notBannedAddresses: map[addressKey]*addressEntry{},
 		bannedAddresses:    map[ipv6]*appmessage.NetAddress{},
 	}
-	err := addressStore.restoreNotBannedAddresses()
+	err := addressStore.restoreBucketingKey()
+	if err != nil {
+		return nil, err
+	}
+	err = addressStore.restoreNotBannedAddresses()
 	if err != nil {
 		return nil, err
 	}
@@ -32,18 +132,44 @@ func newAddressStore(database database.Database) (*addressStore, error) {
 		return nil, err
 	}
 
-	log.Infof("Loaded %d addresses and %d banned addresses",
-		len(addressStore.notBannedAddresses), len(addressStore.bannedAddresses))
+	log.Infof("Loaded %d new addresses, %d tried addresses and %d banned addresses",
+		addressStore.newCount, addressStore.triedCount, len(addressStore.bannedAddresses))
 
 	return addressStore, nil
 }
 
+// restoreBucketingKey loads the secret key the buckets of the addresses are
+// derived from, or generates it if this is the first time the node runs
+func (as *addressStore) restoreBucketingKey() error {
+	bucketingKey, err := as.database.Get(bucketingKeyKey)
+	if err == nil {
+		if len(bucketingKey) != bucketingKeyLength {
+			return errors.Errorf("unexpected bucketing key length %d", len(bucketingKey))
+		}
+		as.bucketingKey = bucketingKey
+		return nil
+	}
+	if !database.IsNotFoundError(err) {
+		return err
+	}
+
+	bucketingKey = make([]byte, bucketingKeyLength)
+	_, err = rand.Read(bucketingKey)
+	if err != nil {
+		return err
+	}
+	as.bucketingKey = bucketingKey
+	return as.database.Put(bucketingKeyKey, bucketingKey)
+}
+
 func (as *addressStore) restoreNotBannedAddresses() error {
 	cursor, err := as.database.Cursor(notBannedAddressBucket)
 	if err != nil {
 		return err
 	}
 	defer cursor.Close()
+
+	var entriesToDelete, entriesToUpdate []*addressEntry
 	for ok := cursor.First(); ok; ok = cursor.Next() {
 		databaseKey, err := cursor.Key()
 		if err != nil {
@@ -52,12 +178,40 @@ func (as *addressStore) restoreNotBannedAddresses() error {
 		serializedKey := databaseKey.Suffix()
 		key := as.deserializeAddressKey(serializedKey)
 
-		serializedNetAddress, err := cursor.Value()
+		serializedEntry, err := cursor.Value()
+		if err != nil {
+			return err
+		}
+		entry := as.deserializeAddressEntry(key, serializedEntry)
+
+		// A tried address whose slot is already taken is moved back to the
+		// new table, and a new address whose slot is already taken is dropped
+		if entry.isTried && as.triedBucketEntry(as.triedBucketSlot(entry)) != nil {
+			entry.isTried = false
+			entriesToUpdate = append(entriesToUpdate, entry)
+		}
+		if !entry.isTried && as.newBucketEntry(as.newBucketSlot(entry)) != nil {
+			entriesToDelete = append(entriesToDelete, entry)
+			continue
+		}
+		as.placeEntry(entry)
+	}
+
+	// The database is only modified once the cursor is done with
+	for _, entry := range entriesToDelete {
+		err := as.database.Delete(as.notBannedDatabaseKey(entry.key))
+		if err != nil {
+			return err
+		}
+	}
+	for _, entry := range entriesToUpdate {
+		if _, ok := as.notBannedAddresses[entry.key]; !ok {
+			continue
+		}
+		err := as.putEntry(entry)
 		if err != nil {
 			return err
 		}
-		netAddress := as.deserializeNetAddress(serializedNetAddress)
-		as.notBannedAddresses[key] = netAddress
 	}
 	return nil
 }
@@ -86,29 +240,179 @@ func (as *addressStore) restoreBannedAddresses() error {
 	return nil
 }
 
-func (as *addressStore) add(key addressKey, address *appmessage.NetAddress) error {
+// add adds the given address, that was sent to us by source, to the new table.
+// If the slot of the address in the new table is taken by an address that is not
+// terrible, the given address is dropped.
+func (as *addressStore) add(key addressKey, address *appmessage.NetAddress, source *appmessage.NetAddress) error {
 	if _, ok := as.notBannedAddresses[key]; ok {
 		return nil
 	}
 
-	as.notBannedAddresses[key] = address
+	entry := &addressEntry{
+		key:        key,
+		netAddress: address,
+		source:     source,
+	}
+	occupant := as.newBucketEntry(as.newBucketSlot(entry))
+	if occupant != nil {
+		// Never evict an address we might be trying to connect to right now
+		const minTimeBetweenAttemptAndEviction = time.Minute
+		now := mstime.Now()
+		if now.Sub(occupant.lastAttempt) < minTimeBetweenAttemptAndEviction || !occupant.isTerrible(now) {
+			return nil
+		}
+		err := as.remove(occupant.key)
+		if err != nil {
+			return err
+		}
+	}
 
-	databaseKey := as.notBannedDatabaseKey(key)
-	serializedAddress := as.serializeNetAddress(address)
-	return as.database.Put(databaseKey, serializedAddress)
+	as.placeEntry(entry)
+	return as.putEntry(entry)
 }
 
 func (as *addressStore) remove(key addressKey) error {
-	delete(as.notBannedAddresses, key)
+	entry, ok := as.notBannedAddresses[key]
+	if ok {
+		as.unplaceEntry(entry)
+	}
 
 	databaseKey := as.notBannedDatabaseKey(key)
 	return as.database.Delete(databaseKey)
 }
 
+func (as *addressStore) get(key addressKey) (*addressEntry, bool) {
+	entry, ok := as.notBannedAddresses[key]
+	return entry, ok
+}
+
+// markAttempt records an attempt to connect to the given address
+func (as *addressStore) markAttempt(entry *addressEntry) error {
+	entry.attempts++
+	entry.lastAttempt = mstime.Now()
+	return as.putEntry(entry)
+}
+
+// markSuccess records a successful connection to the given address, and moves
+// it to the tried table. If the slot of the address in the tried table is taken,
+// the address that takes it is moved back to the new table.
+func (as *addressStore) markSuccess(entry *addressEntry) error {
+	entry.attempts = 0
+	entry.lastSuccess = mstime.Now()
+	if entry.isTried {
+		return as.putEntry(entry)
+	}
+
+	as.unplaceEntry(entry)
+	entry.isTried = true
+	if evicted := as.triedBucketEntry(as.triedBucketSlot(entry)); evicted != nil {
+		as.unplaceEntry(evicted)
+		evicted.isTried = false
+
+		// The slot of the evicted address in the new table
+		// might be taken as well, in which case it's dropped
+		if displaced := as.newBucketEntry(as.newBucketSlot(evicted)); displaced != nil {
+			err := as.remove(displaced.key)
+			if err != nil {
+				return err
+			}
+		}
+		as.placeEntry(evicted)
+		err := as.putEntry(evicted)
+		if err != nil {
+			return err
+		}
+	}
+	as.placeEntry(entry)
+	return as.putEntry(entry)
+}
+
+func (as *addressStore) putEntry(entry *addressEntry) error {
+	databaseKey := as.notBannedDatabaseKey(entry.key)
+	serializedEntry := as.serializeAddressEntry(entry)
+	return as.database.Put(databaseKey, serializedEntry)
+}
+
+// placeEntry puts the entry in its slot in the table it belongs to.
+// The slot is expected to be free.
+func (as *addressStore) placeEntry(entry *addressEntry) {
+	if entry.isTried {
+		slot := as.triedBucketSlot(entry)
+		as.triedBuckets[slot.bucket][slot.position] = entry
+		as.triedCount++
+	} else {
+		slot := as.newBucketSlot(entry)
+		as.newBuckets[slot.bucket][slot.position] = entry
+		as.newCount++
+	}
+	as.notBannedAddresses[entry.key] = entry
+}
+
+func (as *addressStore) unplaceEntry(entry *addressEntry) {
+	if entry.isTried {
+		slot := as.triedBucketSlot(entry)
+		as.triedBuckets[slot.bucket][slot.position] = nil
+		as.triedCount--
+	} else {
+		slot := as.newBucketSlot(entry)
+		as.newBuckets[slot.bucket][slot.position] = nil
+		as.newCount--
+	}
+	delete(as.notBannedAddresses, entry.key)
+}
+
+func (as *addressStore) newBucketEntry(slot bucketSlot) *addressEntry {
+	return as.newBuckets[slot.bucket][slot.position]
+}
+
+func (as *addressStore) triedBucketEntry(slot bucketSlot) *addressEntry {
+	return as.triedBuckets[slot.bucket][slot.position]
+}
+
+func (as *addressStore) newBucketSlot(entry *addressEntry) bucketSlot {
+	group := []byte(groupKey(entry.netAddress, as.acceptUnroutable))
+	sourceGroup := []byte(groupKey(entry.source, as.acceptUnroutable))
+
+	sourceGroupBucket := as.keyedHash(group, sourceGroup) % newBucketsPerSourceGroup
+	bucket := as.keyedHash(sourceGroup, uint64ToBytes(sourceGroupBucket)) % newBucketCount
+	position := as.keyedHash([]byte{'N'}, uint64ToBytes(bucket), as.serializeAddressKey(entry.key)) % bucketSize
+	return bucketSlot{bucket: int(bucket), position: int(position)}
+}
+
+func (as *addressStore) triedBucketSlot(entry *addressEntry) bucketSlot {
+	group := []byte(groupKey(entry.netAddress, as.acceptUnroutable))
+	serializedKey := as.serializeAddressKey(entry.key)
+
+	groupBucket := as.keyedHash(serializedKey) % triedBucketsPerGroup
+	bucket := as.keyedHash(group, uint64ToBytes(groupBucket)) % triedBucketCount
+	position := as.keyedHash([]byte{'T'}, uint64ToBytes(bucket), serializedKey) % bucketSize
+	return bucketSlot{bucket: int(bucket), position: int(position)}
+}
+
+func (as *addressStore) keyedHash(parts ...[]byte) uint64 {
+	hasher, err := blake2b.New256(as.bucketingKey)
+	if err != nil {
+		panic(errors.Wrapf(err, "failed to create a keyed hasher"))
+	}
+	for _, part := range parts {
+		// Prefix every part with its length, so that different
+		// parts can't be combined into the same input
+		hasher.Write(uint64ToBytes(uint64(len(part))))
+		hasher.Write(part)
+	}
+	return binary.LittleEndian.Uint64(hasher.Sum(nil))
+}
+
+func uint64ToBytes(value uint64) []byte {
+	serializedValue := make([]byte, 8)
+	binary.LittleEndian.PutUint64(serializedValue, value)
+	return serializedValue
+}
+
 func (as *addressStore) getAllNotBanned() []*appmessage.NetAddress {
 	addresses := make([]*appmessage.NetAddress, 0, len(as.notBannedAddresses))
-	for _, address := range as.notBannedAddresses {
-		addresses = append(addresses, address)
+	for _, entry := range as.notBannedAddresses {
+		addresses = append(addresses, entry.netAddress)
 	}
 	return addresses
 }
@@ -117,14 +421,66 @@ func (as *addressStore) getAllNotBannedWithout(ignoredAddresses []*appmessage.Ne
 	ignoredKeys := netAddressesKeys(ignoredAddresses)
 
 	addresses := make([]*appmessage.NetAddress, 0, len(as.notBannedAddresses))
-	for key, address := range as.notBannedAddresses {
+	for key, entry := range as.notBannedAddresses {
 		if !ignoredKeys[key] {
-			addresses = append(addresses, address)
+			addresses = append(addresses, entry.netAddress)
 		}
 	}
 	return addresses
 }
 
+// getTriedAndNewWithout returns the not banned addresses that aren't in
+// ignoredAddresses, split to the ones in the tried table and the ones in
+// the new table
+func (as *addressStore) getTriedAndNewWithout(ignoredAddresses []*appmessage.NetAddress) (
+	triedAddresses []*appmessage.NetAddress, newAddresses []*appmessage.NetAddress) {
+
+	ignoredKeys := netAddressesKeys(ignoredAddresses)
+
+	triedAddresses = make([]*appmessage.NetAddress, 0, as.triedCount)
+	newAddresses = make([]*appmessage.NetAddress, 0, as.newCount)
+	for key, entry := range as.notBannedAddresses {
+		if ignoredKeys[key] {
+			continue
+		}
+		if entry.isTried {
+			triedAddresses = append(triedAddresses, entry.netAddress)
+		} else {
+			newAddresses = append(newAddresses, entry.netAddress)
+		}
+	}
+	return triedAddresses, newAddresses
+}
+
+// getAnchors returns the anchor addresses that were stored by setAnchors
+func (as *addressStore) getAnchors() ([]*appmessage.NetAddress, error) {
+	serializedAnchors, err := as.database.Get(anchorsKey)
+	if err != nil {
+		if database.IsNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(serializedAnchors)%serializedNetAddressSize != 0 {
+		return nil, errors.Errorf("unexpected serialized anchors length %d", len(serializedAnchors))
+	}
+
+	anchors := make([]*appmessage.NetAddress, 0, len(serializedAnchors)/serializedNetAddressSize)
+	for i := 0; i < len(serializedAnchors); i += serializedNetAddressSize {
+		anchors = append(anchors, as.deserializeNetAddress(serializedAnchors[i:i+serializedNetAddressSize]))
+	}
+	return anchors, nil
+}
+
+// setAnchors replaces the stored anchor addresses with the given ones
+func (as *addressStore) setAnchors(anchors []*appmessage.NetAddress) error {
+	serializedAnchors := make([]byte, 0, len(anchors)*serializedNetAddressSize)
+	for _, anchor := range anchors {
+		serializedAnchors = append(serializedAnchors, as.serializeNetAddress(anchor)...)
+	}
+	return as.database.Put(anchorsKey, serializedAnchors)
+}
+
 func (as *addressStore) isNotBanned(key addressKey) bool {
 	_, ok := as.notBannedAddresses[key]
 	return ok
@@ -198,9 +554,10 @@ func (as *addressStore) deserializeAddressKey(serializedKey []byte) addressKey {
 	}
 }
 
+const serializedNetAddressSize = 16 + 2 + 8 + 8 // ipv6 + port + timestamp + services
+
 func (as *addressStore) serializeNetAddress(netAddress *appmessage.NetAddress) []byte {
-	serializedSize := 16 + 2 + 8 + 8 // ipv6 + port + timestamp + services
-	serializedNetAddress := make([]byte, serializedSize)
+	serializedNetAddress := make([]byte, serializedNetAddressSize)
 
 	copy(serializedNetAddress[:], netAddress.IP[:])
 	binary.LittleEndian.PutUint16(serializedNetAddress[16:], netAddress.Port)
@@ -225,3 +582,45 @@ func (as *addressStore) deserializeNetAddress(serializedNetAddress []byte) *appm
 		Services:  services,
 	}
 }
+
+func (as *addressStore) serializeAddressEntry(entry *addressEntry) []byte {
+	// net address + source ipv6 + isTried + attempts + lastAttempt + lastSuccess
+	serializedSize := serializedNetAddressSize + 16 + 1 + 4 + 8 + 8
+	serializedEntry := make([]byte, serializedSize)
+
+	copy(serializedEntry[:], as.serializeNetAddress(entry.netAddress))
+	copy(serializedEntry[serializedNetAddressSize:], entry.source.IP.To16())
+	if entry.isTried {
+		serializedEntry[serializedNetAddressSize+16] = 1
+	}
+	binary.LittleEndian.PutUint32(serializedEntry[serializedNetAddressSize+17:], entry.attempts)
+	binary.LittleEndian.PutUint64(serializedEntry[serializedNetAddressSize+21:], uint64(entry.lastAttempt.UnixMilliseconds()))
+	binary.LittleEndian.PutUint64(serializedEntry[serializedNetAddressSize+29:], uint64(entry.lastSuccess.UnixMilliseconds()))
+
+	return serializedEntry
+}
+
+func (as *addressStore) deserializeAddressEntry(key addressKey, serializedEntry []byte) *addressEntry {
+	netAddress := as.deserializeNetAddress(serializedEntry)
+	entry := &addressEntry{
+		key:        key,
+		netAddress: netAddress,
+		source:     netAddress,
+	}
+
+	// Entries that were stored before the address manager kept the data
+	// below consist of the net address only
+	if len(serializedEntry) == serializedNetAddressSize {
+		return entry
+	}
+
+	sourceIP := make(net.IP, 16)
+	copy(sourceIP, serializedEntry[serializedNetAddressSize:])
+	entry.source = &appmessage.NetAddress{IP: sourceIP}
+	entry.isTried = serializedEntry[serializedNetAddressSize+16] == 1
+	entry.attempts = binary.LittleEndian.Uint32(serializedEntry[serializedNetAddressSize+17:])
+	entry.lastAttempt = mstime.UnixMilliseconds(int64(binary.LittleEndian.Uint64(serializedEntry[serializedNetAddressSize+21:])))
+	entry.lastSuccess = mstime.UnixMilliseconds(int64(binary.LittleEndian.Uint64(serializedEntry[serializedNetAddressSize+29:])))
+
+	return entry
+}
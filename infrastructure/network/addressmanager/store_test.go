@@ -6,6 +6,7 @@ import (
 	"net"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestAddressKeySerialization(t *testing.T) {
@@ -43,3 +44,41 @@ func TestNetAddressSerialization(t *testing.T) {
 			"testAddress:%+v\ndeserializedTestNetAddress:%+v", testAddress, deserializedTestNetAddress)
 	}
 }
+
+func TestAddressEntrySerialization(t *testing.T) {
+	addressManager, teardown := newAddressManagerForTest(t, "TestAddressEntrySerialization")
+	defer teardown()
+	addressStore := addressManager.store
+
+	testAddress := &appmessage.NetAddress{
+		IP:        net.ParseIP("2602:100:abcd::102"),
+		Port:      12345,
+		Timestamp: mstime.Now(),
+		Services:  appmessage.ServiceFlag(6789),
+	}
+	testEntry := &addressEntry{
+		key:         netAddressKey(testAddress),
+		netAddress:  testAddress,
+		source:      &appmessage.NetAddress{IP: net.ParseIP("2001:470::1")},
+		isTried:     true,
+		attempts:    3,
+		lastAttempt: mstime.Now(),
+		lastSuccess: mstime.Now().Add(-time.Hour),
+	}
+
+	serializedTestEntry := addressStore.serializeAddressEntry(testEntry)
+	deserializedTestEntry := addressStore.deserializeAddressEntry(testEntry.key, serializedTestEntry)
+	if !reflect.DeepEqual(testEntry, deserializedTestEntry) {
+		t.Fatalf("testEntry and deserializedTestEntry are not equal\n"+
+			"testEntry:%+v\ndeserializedTestEntry:%+v", testEntry, deserializedTestEntry)
+	}
+
+	// Entries stored before the address manager kept attempts and successes
+	// consist of the net address only, and are restored as new addresses
+	legacyEntry := addressStore.deserializeAddressEntry(testEntry.key, addressStore.serializeNetAddress(testAddress))
+	if !reflect.DeepEqual(legacyEntry.netAddress, testAddress) || legacyEntry.source != legacyEntry.netAddress ||
+		legacyEntry.isTried || legacyEntry.attempts != 0 {
+
+		t.Fatalf("Unexpected legacy entry %+v", legacyEntry)
+	}
+}
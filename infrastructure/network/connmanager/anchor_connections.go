@@ -0,0 +1,65 @@
+package connmanager
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/infrastructure/network/addressmanager"
+	"github.com/pkg/errors"
+)
+
+// maxAnchors is the maximum number of outgoing peers that are saved as anchors
+// when the ConnectionManager stops, and reconnected to first when it starts again
+const maxAnchors = 2
+
+// loadAnchors loads the anchors that were saved by saveAnchors into pendingAnchors.
+// The anchors are cleared from the address manager, so that a node that crashes
+// doesn't keep reconnecting to the same stale anchors.
+func (c *ConnectionManager) loadAnchors() error {
+	anchors, err := c.addressManager.Anchors()
+	if err != nil {
+		return err
+	}
+	c.pendingAnchors = anchors
+	return c.addressManager.SetAnchors(nil)
+}
+
+// saveAnchors saves up to maxAnchors of the current outgoing connections as anchors.
+// Requested and feeler connections are never saved as anchors.
+func (c *ConnectionManager) saveAnchors() error {
+	var anchors []*appmessage.NetAddress
+	for _, connection := range c.netAdapter.P2PConnections() {
+		if len(anchors) == maxAnchors {
+			break
+		}
+		if !connection.IsOutbound() || c.isRequested(connection.Address()) || c.isFeeler(connection.Address()) {
+			continue
+		}
+		anchors = append(anchors, connection.NetAddress())
+	}
+
+	log.Debugf("Saving %d anchor connections", len(anchors))
+	return c.addressManager.SetAnchors(anchors)
+}
+
+// popPendingAnchors removes up to count anchors from pendingAnchors and returns
+// the ones that are neither connected nor banned
+func (c *ConnectionManager) popPendingAnchors(count int, connectedSet connectionSet) []*appmessage.NetAddress {
+	var anchors []*appmessage.NetAddress
+	for len(c.pendingAnchors) > 0 && len(anchors) < count {
+		anchor := c.pendingAnchors[0]
+		c.pendingAnchors = c.pendingAnchors[1:]
+
+		if _, ok := connectedSet.get(anchor.TCPAddress().String()); ok {
+			continue
+		}
+		isBanned, err := c.addressManager.IsBanned(anchor)
+		if err != nil && !errors.Is(err, addressmanager.ErrAddressNotFound) {
+			log.Warnf("Couldn't check whether anchor %s is banned: %s", anchor.TCPAddress(), err)
+			continue
+		}
+		if isBanned {
+			continue
+		}
+		anchors = append(anchors, anchor)
+	}
+	return anchors
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter"
 
 	"github.com/kaspanet/kaspad/infrastructure/config"
+	"github.com/kaspanet/kaspad/util/random"
 )
 
 // connectionRequest represents a user request (either through CLI or RPC) to connect to a certain node
@@ -36,6 +37,15 @@ type ConnectionManager struct {
 	targetOutgoing   int
 	activeIncoming   map[string]struct{}
 	maxIncoming      int
+	pendingAnchors   []*appmessage.NetAddress
+
+	activeFeelers map[string]*feelerConnection
+	nextFeeler    time.Time
+	feelersLock   sync.Mutex
+
+	evictionSalt                 uint64
+	peerEvictionInfoProvider     PeerEvictionInfoProvider
+	peerEvictionInfoProviderLock sync.Mutex
 
 	stop                   uint32
 	connectionRequestsLock sync.RWMutex
@@ -46,6 +56,13 @@ type ConnectionManager struct {
 
 // New instantiates a new instance of a ConnectionManager
 func New(cfg *config.Config, netAdapter *netadapter.NetAdapter, addressManager *addressmanager.AddressManager) (*ConnectionManager, error) {
+	// The eviction salt must be unpredictable, or else an attacker
+	// could tell which netgroups are protected from eviction
+	evictionSalt, err := random.Uint64()
+	if err != nil {
+		return nil, err
+	}
+
 	c := &ConnectionManager{
 		cfg:              cfg,
 		netAdapter:       netAdapter,
@@ -54,6 +71,8 @@ func New(cfg *config.Config, netAdapter *netadapter.NetAdapter, addressManager *
 		pendingRequested: map[string]*connectionRequest{},
 		activeOutgoing:   map[string]struct{}{},
 		activeIncoming:   map[string]struct{}{},
+		activeFeelers:    map[string]*feelerConnection{},
+		evictionSalt:     evictionSalt,
 		resetLoopChan:    make(chan struct{}),
 		loopTicker:       time.NewTicker(connectionsLoopInterval),
 	}
@@ -73,6 +92,11 @@ func New(cfg *config.Config, netAdapter *netadapter.NetAdapter, addressManager *
 		}
 	}
 
+	err = c.loadAnchors()
+	if err != nil {
+		return nil, err
+	}
+
 	return c, nil
 }
 
@@ -85,6 +109,11 @@ func (c *ConnectionManager) Start() {
 func (c *ConnectionManager) Stop() {
 	atomic.StoreUint32(&c.stop, 1)
 
+	err := c.saveAnchors()
+	if err != nil {
+		log.Warnf("Couldn't save anchor connections: %s", err)
+	}
+
 	for _, connection := range c.netAdapter.P2PConnections() {
 		connection.Disconnect()
 	}
@@ -115,6 +144,8 @@ func (c *ConnectionManager) connectionsLoop() {
 
 		c.checkRequestedConnections(connSet)
 
+		c.checkFeelerConnections(connSet)
+
 		c.checkOutgoingConnections(connSet)
 
 		c.checkIncomingConnections(connSet)
@@ -193,6 +224,18 @@ func (c *ConnectionManager) isPermanent(addressString string) bool {
 	return false
 }
 
+func (c *ConnectionManager) isRequested(addressString string) bool {
+	c.connectionRequestsLock.RLock()
+	defer c.connectionRequestsLock.RUnlock()
+
+	if _, ok := c.activeRequested[addressString]; ok {
+		return true
+	}
+
+	_, ok := c.pendingRequested[addressString]
+	return ok
+}
+
 func (c *ConnectionManager) ipHasPermanentConnection(ip net.IP) (bool, error) {
 	c.connectionRequestsLock.RLock()
 	defer c.connectionRequestsLock.RUnlock()
@@ -0,0 +1,224 @@
+package connmanager
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+	"time"
+)
+
+// The eviction algorithm decides which incoming connection to disconnect once
+// there are more than maxIncoming of them. Before choosing, it protects the
+// connections that are hard for an attacker to imitate:
+// * a few connections from distinct netgroups, chosen by a secret salt, so
+//   that an attacker can't predict which netgroups are protected
+// * the connections with the lowest ping
+// * the connections that most recently relayed a block that was new to us
+// * half of the remaining connections, chosen by how long they are connected
+// It then evicts the youngest connection from the netgroup that has the most
+// remaining connections.
+const (
+	evictionProtectedByNetGroup     = 4
+	evictionProtectedByPing         = 8
+	evictionProtectedByLastBlock    = 4
+	evictionProtectedByTimeFraction = 2
+)
+
+// PeerEvictionInfo holds the data about an incoming peer that the connection
+// manager uses to decide which incoming connection to evict
+type PeerEvictionInfo struct {
+	// PingDuration is the duration of the last ping to the peer. Zero if unknown
+	PingDuration time.Duration
+
+	// LastBlockTime is the time the peer last relayed a block that was new to us.
+	// Zero if the peer never did
+	LastBlockTime time.Time
+
+	// TimeConnected is the time since the connection to the peer was started
+	TimeConnected time.Duration
+}
+
+// PeerEvictionInfoProvider returns the PeerEvictionInfo of all the peers that
+// completed their handshake, keyed by connection address
+type PeerEvictionInfoProvider func() map[string]*PeerEvictionInfo
+
+// SetPeerEvictionInfoProvider sets the function the connection manager uses to
+// get the information about incoming peers when it chooses which ones to evict
+func (c *ConnectionManager) SetPeerEvictionInfoProvider(provider PeerEvictionInfoProvider) {
+	c.peerEvictionInfoProviderLock.Lock()
+	defer c.peerEvictionInfoProviderLock.Unlock()
+
+	c.peerEvictionInfoProvider = provider
+}
+
+type evictionCandidate struct {
+	address       string
+	netGroup      string
+	netGroupHash  uint64
+	pingDuration  time.Duration
+	lastBlockTime time.Time
+	timeConnected time.Duration
+}
+
+func (c *ConnectionManager) evictionCandidates(incomingConnectionSet connectionSet) []*evictionCandidate {
+	c.peerEvictionInfoProviderLock.Lock()
+	provider := c.peerEvictionInfoProvider
+	c.peerEvictionInfoProviderLock.Unlock()
+
+	var peerEvictionInfos map[string]*PeerEvictionInfo
+	if provider != nil {
+		peerEvictionInfos = provider()
+	}
+
+	candidates := make([]*evictionCandidate, 0, len(incomingConnectionSet))
+	for address, connection := range incomingConnectionSet {
+		candidate := newEvictionCandidate(address, c.addressManager.GroupKey(connection.NetAddress()), c.evictionSalt)
+
+		// Connections that didn't complete their handshake yet have
+		// no info, and so get no protection
+		if peerEvictionInfo, ok := peerEvictionInfos[address]; ok {
+			candidate.pingDuration = peerEvictionInfo.PingDuration
+			candidate.lastBlockTime = peerEvictionInfo.LastBlockTime
+			candidate.timeConnected = peerEvictionInfo.TimeConnected
+		}
+		candidates = append(candidates, candidate)
+	}
+	return candidates
+}
+
+func newEvictionCandidate(address string, netGroup string, salt uint64) *evictionCandidate {
+	hasher := fnv.New64a()
+	var saltBytes [8]byte
+	binary.LittleEndian.PutUint64(saltBytes[:], salt)
+	_, _ = hasher.Write(saltBytes[:])
+	_, _ = hasher.Write([]byte(netGroup))
+
+	return &evictionCandidate{
+		address:      address,
+		netGroup:     netGroup,
+		netGroupHash: hasher.Sum64(),
+	}
+}
+
+// selectCandidateToEvict returns the candidate that should be evicted according
+// to the eviction algorithm. If all candidates are protected, it returns the
+// youngest candidate. It returns nil if there are no candidates.
+func selectCandidateToEvict(candidates []*evictionCandidate) *evictionCandidate {
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	remaining := make([]*evictionCandidate, len(candidates))
+	copy(remaining, candidates)
+
+	protectedNetGroups := make(map[string]struct{})
+	remaining = protectCandidates(remaining, evictionProtectedByNetGroup, func(a, b *evictionCandidate) bool {
+		return a.netGroupHash < b.netGroupHash
+	}, func(candidate *evictionCandidate) bool {
+		if _, ok := protectedNetGroups[candidate.netGroup]; ok {
+			return false
+		}
+		protectedNetGroups[candidate.netGroup] = struct{}{}
+		return true
+	})
+
+	// Candidates with an unknown ping are sorted last, and are not protected by their ping
+	remaining = protectCandidates(remaining, evictionProtectedByPing, func(a, b *evictionCandidate) bool {
+		if a.pingDuration == 0 || b.pingDuration == 0 {
+			return b.pingDuration == 0 && a.pingDuration != 0
+		}
+		return a.pingDuration < b.pingDuration
+	}, func(candidate *evictionCandidate) bool {
+		return candidate.pingDuration != 0
+	})
+
+	remaining = protectCandidates(remaining, evictionProtectedByLastBlock, func(a, b *evictionCandidate) bool {
+		return a.lastBlockTime.After(b.lastBlockTime)
+	}, func(candidate *evictionCandidate) bool {
+		return !candidate.lastBlockTime.IsZero()
+	})
+
+	remaining = protectCandidates(remaining, len(remaining)/evictionProtectedByTimeFraction, func(a, b *evictionCandidate) bool {
+		return a.timeConnected > b.timeConnected
+	}, nil)
+
+	if len(remaining) == 0 {
+		return youngestCandidate(candidates)
+	}
+
+	// Find the netgroup with the most remaining candidates. Ties are broken in favor
+	// of the netgroup whose youngest candidate is the youngest of all
+	candidatesByNetGroup := make(map[string][]*evictionCandidate)
+	for _, candidate := range remaining {
+		candidatesByNetGroup[candidate.netGroup] = append(candidatesByNetGroup[candidate.netGroup], candidate)
+	}
+	var mostRepresented []*evictionCandidate
+	for _, netGroupCandidates := range candidatesByNetGroup {
+		if len(netGroupCandidates) > len(mostRepresented) ||
+			(len(netGroupCandidates) == len(mostRepresented) &&
+				youngestCandidate(netGroupCandidates).timeConnected < youngestCandidate(mostRepresented).timeConnected) {
+			mostRepresented = netGroupCandidates
+		}
+	}
+
+	return youngestCandidate(mostRepresented)
+}
+
+// protectCandidates sorts the candidates with the given less function and removes
+// up to count of the first ones, skipping candidates for which isEligible returns
+// false. It returns the candidates that remain unprotected.
+func protectCandidates(candidates []*evictionCandidate, count int,
+	less func(a, b *evictionCandidate) bool, isEligible func(candidate *evictionCandidate) bool) []*evictionCandidate {
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return less(candidates[i], candidates[j])
+	})
+
+	remaining := make([]*evictionCandidate, 0, len(candidates))
+	protectedCount := 0
+	for _, candidate := range candidates {
+		if protectedCount < count && (isEligible == nil || isEligible(candidate)) {
+			protectedCount++
+			continue
+		}
+		remaining = append(remaining, candidate)
+	}
+	return remaining
+}
+
+func youngestCandidate(candidates []*evictionCandidate) *evictionCandidate {
+	var youngest *evictionCandidate
+	for _, candidate := range candidates {
+		if youngest == nil || candidate.timeConnected < youngest.timeConnected {
+			youngest = candidate
+		}
+	}
+	return youngest
+}
+
+// evictIncomingConnections disconnects count connections from incomingConnectionSet
+// according to the eviction algorithm
+func (c *ConnectionManager) evictIncomingConnections(incomingConnectionSet connectionSet, count int) {
+	candidates := c.evictionCandidates(incomingConnectionSet)
+	for i := 0; i < count; i++ {
+		candidate := selectCandidateToEvict(candidates)
+		if candidate == nil {
+			return
+		}
+		candidates = removeCandidate(candidates, candidate)
+
+		connection, _ := incomingConnectionSet.get(candidate.address)
+		log.Debugf("Evicting %s due to exceeding incoming connections", connection)
+		connection.Disconnect()
+	}
+}
+
+func removeCandidate(candidates []*evictionCandidate, toRemove *evictionCandidate) []*evictionCandidate {
+	remaining := make([]*evictionCandidate, 0, len(candidates)-1)
+	for _, candidate := range candidates {
+		if candidate != toRemove {
+			remaining = append(remaining, candidate)
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,126 @@
+package connmanager
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestEvictionCandidate(address string, netGroup string, pingDuration time.Duration,
+	lastBlockTime time.Time, timeConnected time.Duration) *evictionCandidate {
+
+	candidate := newEvictionCandidate(address, netGroup, 0)
+	candidate.pingDuration = pingDuration
+	candidate.lastBlockTime = lastBlockTime
+	candidate.timeConnected = timeConnected
+	return candidate
+}
+
+// evictCandidates evicts up to count candidates one by one, the same way
+// evictIncomingConnections does, and returns the evicted addresses
+func evictCandidates(candidates []*evictionCandidate, count int) map[string]struct{} {
+	evicted := make(map[string]struct{})
+	for i := 0; i < count; i++ {
+		candidate := selectCandidateToEvict(candidates)
+		if candidate == nil {
+			break
+		}
+		evicted[candidate.address] = struct{}{}
+		candidates = removeCandidate(candidates, candidate)
+	}
+	return evicted
+}
+
+func TestSelectCandidateToEvictNoCandidates(t *testing.T) {
+	if candidate := selectCandidateToEvict(nil); candidate != nil {
+		t.Fatalf("Expected no candidate to be selected, but got %s", candidate.address)
+	}
+}
+
+func TestSelectCandidateToEvictAllProtected(t *testing.T) {
+	candidates := []*evictionCandidate{
+		newTestEvictionCandidate("old", "a", time.Millisecond, time.Time{}, time.Hour),
+		newTestEvictionCandidate("young", "b", time.Millisecond, time.Time{}, time.Minute),
+		newTestEvictionCandidate("middle", "c", time.Millisecond, time.Time{}, 30*time.Minute),
+	}
+
+	candidate := selectCandidateToEvict(candidates)
+	if candidate == nil || candidate.address != "young" {
+		t.Fatalf("Expected the youngest candidate to be evicted when all candidates are protected, but got %+v", candidate)
+	}
+}
+
+func TestSelectCandidateToEvictMostRepresentedNetGroup(t *testing.T) {
+	var candidates []*evictionCandidate
+	for i := 0; i < 10; i++ {
+		// The honest candidates are younger than the attacker's ones, and have no
+		// ping, so that only netgroup diversity saves them
+		candidates = append(candidates, newTestEvictionCandidate(
+			fmt.Sprintf("honest-%d", i), fmt.Sprintf("honest-group-%d", i), 0, time.Time{}, time.Duration(i)*time.Second))
+	}
+	for i := 0; i < 50; i++ {
+		candidates = append(candidates, newTestEvictionCandidate(
+			fmt.Sprintf("attacker-%d", i), "attacker-group", 0, time.Time{}, time.Hour+time.Duration(i)*time.Second))
+	}
+
+	evicted := evictCandidates(candidates, 20)
+	for address := range evicted {
+		if !strings.HasPrefix(address, "attacker") {
+			t.Fatalf("Expected only candidates from the most represented netgroup to be evicted, "+
+				"but %s was evicted", address)
+		}
+	}
+	if len(evicted) != 20 {
+		t.Fatalf("Expected 20 candidates to be evicted, but got %d", len(evicted))
+	}
+}
+
+func TestSelectCandidateToEvictProtections(t *testing.T) {
+	now := time.Now()
+	var candidates []*evictionCandidate
+	for i := 0; i < 40; i++ {
+		candidates = append(candidates, newTestEvictionCandidate(
+			fmt.Sprintf("regular-%d", i), "regular-group", 100*time.Millisecond+time.Duration(i)*time.Millisecond,
+			time.Time{}, time.Hour+time.Duration(i)*time.Second))
+	}
+
+	// All the protected candidates are in the same netgroup as the regular candidates,
+	// and are younger than all of them, so that without their protection they would
+	// be the first to be evicted
+	lowPing := newTestEvictionCandidate("low-ping", "regular-group", time.Millisecond, time.Time{}, time.Second)
+	recentBlock := newTestEvictionCandidate("recent-block", "regular-group", 0, now, 2*time.Second)
+	candidates = append(candidates, lowPing, recentBlock)
+
+	evicted := evictCandidates(candidates, 30)
+	if len(evicted) != 30 {
+		t.Fatalf("Expected 30 candidates to be evicted, but got %d", len(evicted))
+	}
+	for _, protected := range []*evictionCandidate{lowPing, recentBlock} {
+		if _, ok := evicted[protected.address]; ok {
+			t.Fatalf("Expected %s to be protected from eviction", protected.address)
+		}
+	}
+}
+
+func TestSelectCandidateToEvictProtectsLongConnected(t *testing.T) {
+	var candidates []*evictionCandidate
+	for i := 0; i < 40; i++ {
+		candidates = append(candidates, newTestEvictionCandidate(
+			fmt.Sprintf("candidate-%d", i), "group", 0, time.Time{}, time.Duration(i)*time.Minute))
+	}
+
+	// Only one candidate is protected by its netgroup and none by their ping or
+	// by the blocks they relayed, so half of the other 39 candidates should be
+	// protected by their connection time, and the youngest ones should be evicted
+	candidate := selectCandidateToEvict(candidates)
+	if candidate == nil || candidate.timeConnected >= 20*time.Minute {
+		t.Fatalf("Expected one of the youngest candidates to be evicted, but got %+v", candidate)
+	}
+
+	evicted := evictCandidates(candidates, 19)
+	oldest := fmt.Sprintf("candidate-%d", len(candidates)-1)
+	if _, ok := evicted[oldest]; ok {
+		t.Fatalf("Expected the oldest candidate to be protected from eviction")
+	}
+}
@@ -0,0 +1,146 @@
+package connmanager
+
+import (
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter"
+)
+
+const (
+	// feelerInterval is the minimum time between two feeler connections
+	feelerInterval = 2 * time.Minute
+
+	// feelerTimeout is the time after which a feeler connection that didn't
+	// complete its handshake is disconnected
+	feelerTimeout = 30 * time.Second
+)
+
+// feelerConnection is a short-lived outgoing connection to an address from the
+// new table of the address manager, that is opened only to test whether the
+// address is alive. It's disconnected as soon as the handshake completes.
+type feelerConnection struct {
+	netAddress           *appmessage.NetAddress
+	started              time.Time
+	isHandshakeCompleted bool
+}
+
+// checkFeelerConnections goes over all activeFeelers, marks the ones that disconnected
+// without completing their handshake as failed, and disconnects the ones that timed out.
+// Then, if all outgoing slots are taken and feelerInterval has passed, it opens a new
+// feeler connection.
+// While doing so, it filters out of connSet all feeler connections
+func (c *ConnectionManager) checkFeelerConnections(connSet connectionSet) {
+	netAddress := c.checkActiveFeelersAndChooseNext(connSet)
+	if netAddress == nil {
+		return
+	}
+	addressString := netAddress.TCPAddress().String()
+
+	log.Debugf("Opening a feeler connection to %s", addressString)
+	err := c.initiateConnection(addressString)
+	if err != nil {
+		log.Infof("Couldn't open a feeler connection to %s: %s", addressString, err)
+
+		c.feelersLock.Lock()
+		delete(c.activeFeelers, addressString)
+		c.feelersLock.Unlock()
+
+		err := c.addressManager.MarkConnectionFailure(netAddress)
+		if err != nil {
+			log.Warnf("Couldn't mark a connection failure to %s: %s", addressString, err)
+		}
+	}
+}
+
+// checkActiveFeelersAndChooseNext does the bookkeeping of checkFeelerConnections under
+// feelersLock, and returns the address of the next feeler connection, or nil if no
+// feeler connection should be opened right now.
+// The next feeler is registered before its connection is initiated, so that
+// DisconnectIfFeeler recognizes it even if the handshake completes before
+// initiateConnection returns
+func (c *ConnectionManager) checkActiveFeelersAndChooseNext(connSet connectionSet) *appmessage.NetAddress {
+	c.feelersLock.Lock()
+	defer c.feelersLock.Unlock()
+
+	now := time.Now()
+
+	for address, feeler := range c.activeFeelers {
+		connection, ok := connSet.get(address)
+		if ok {
+			connSet.remove(connection)
+			if !feeler.isHandshakeCompleted && now.Sub(feeler.started) > feelerTimeout {
+				log.Debugf("Feeler connection to %s timed out. Disconnecting...", address)
+				connection.Disconnect()
+			}
+			continue
+		}
+
+		delete(c.activeFeelers, address)
+		if feeler.isHandshakeCompleted {
+			continue
+		}
+		err := c.addressManager.MarkConnectionFailure(feeler.netAddress)
+		if err != nil {
+			log.Warnf("Couldn't mark a connection failure to %s: %s", address, err)
+		}
+	}
+
+	if c.targetOutgoing == 0 || len(c.activeOutgoing) < c.targetOutgoing || now.Before(c.nextFeeler) {
+		return nil
+	}
+	c.nextFeeler = now.Add(feelerInterval)
+
+	connections := c.netAdapter.P2PConnections()
+	connectedAddresses := make([]*appmessage.NetAddress, len(connections))
+	for i, connection := range connections {
+		connectedAddresses[i] = connection.NetAddress()
+	}
+
+	netAddress := c.addressManager.RandomNewAddress(connectedAddresses)
+	if netAddress == nil {
+		return nil
+	}
+	addressString := netAddress.TCPAddress().String()
+
+	err := c.addressManager.MarkConnectionAttempt(netAddress)
+	if err != nil {
+		log.Warnf("Couldn't mark a connection attempt to %s: %s", addressString, err)
+	}
+
+	c.activeFeelers[addressString] = &feelerConnection{
+		netAddress: netAddress,
+		started:    now,
+	}
+	return netAddress
+}
+
+// DisconnectIfFeeler disconnects the given connection if it's a feeler connection,
+// and returns whether it is one. It should be called once the handshake with the
+// peer completed successfully.
+func (c *ConnectionManager) DisconnectIfFeeler(netConnection *netadapter.NetConnection) bool {
+	if !netConnection.IsOutbound() {
+		return false
+	}
+
+	c.feelersLock.Lock()
+	defer c.feelersLock.Unlock()
+
+	feeler, ok := c.activeFeelers[netConnection.Address()]
+	if !ok {
+		return false
+	}
+	feeler.isHandshakeCompleted = true
+
+	log.Debugf("Feeler connection to %s completed its handshake. Disconnecting...", netConnection)
+	netConnection.Disconnect()
+	return true
+}
+
+func (c *ConnectionManager) isFeeler(address string) bool {
+	c.feelersLock.Lock()
+	defer c.feelersLock.Unlock()
+
+	_, ok := c.activeFeelers[address]
+	return ok
+}
@@ -5,10 +5,14 @@ import "github.com/kaspanet/kaspad/app/appmessage"
 // checkOutgoingConnections goes over all activeOutgoing and makes sure they are still active.
 // Then it opens connections so that we have targetOutgoing active connections
 func (c *ConnectionManager) checkOutgoingConnections(connSet connectionSet) {
+	// Keep track of the netgroups of the outgoing connections, so that
+	// new outgoing connections are opened to other netgroups
+	usedGroups := make(map[string]struct{})
 	for address := range c.activeOutgoing {
 		connection, ok := connSet.get(address)
 		if ok { // connection is still connected
 			connSet.remove(connection)
+			usedGroups[c.addressManager.GroupKey(connection.NetAddress())] = struct{}{}
 			continue
 		}
 
@@ -30,8 +34,14 @@ func (c *ConnectionManager) checkOutgoingConnections(connSet connectionSet) {
 	log.Debugf("Have got %d outgoing connections out of target %d, adding %d more",
 		liveConnections, c.targetOutgoing, c.targetOutgoing-liveConnections)
 
+	// Anchors from the previous run are reconnected to before any other address
 	connectionsNeededCount := c.targetOutgoing - len(c.activeOutgoing)
-	netAddresses := c.addressManager.RandomAddresses(connectionsNeededCount, connectedAddresses)
+	netAddresses := c.popPendingAnchors(connectionsNeededCount, convertToSet(connections))
+	if len(netAddresses) < connectionsNeededCount {
+		exceptions := append(connectedAddresses, netAddresses...)
+		netAddresses = append(netAddresses, c.addressManager.RandomOutgoingAddresses(
+			connectionsNeededCount-len(netAddresses), exceptions, usedGroups)...)
+	}
 
 	for _, netAddress := range netAddresses {
 		addressString := netAddress.TCPAddress().String()
@@ -39,10 +49,18 @@ func (c *ConnectionManager) checkOutgoingConnections(connSet connectionSet) {
 		log.Debugf("Connecting to %s because we have %d outgoing connections and the target is "+
 			"%d", addressString, len(c.activeOutgoing), c.targetOutgoing)
 
-		err := c.initiateConnection(addressString)
+		err := c.addressManager.MarkConnectionAttempt(netAddress)
+		if err != nil {
+			log.Warnf("Couldn't mark a connection attempt to %s: %s", addressString, err)
+		}
+
+		err = c.initiateConnection(addressString)
 		if err != nil {
 			log.Infof("Couldn't connect to %s: %s", addressString, err)
-			c.addressManager.RemoveAddress(netAddress)
+			err := c.addressManager.MarkConnectionFailure(netAddress)
+			if err != nil {
+				log.Warnf("Couldn't mark a connection failure to %s: %s", addressString, err)
+			}
 			continue
 		}
 
@@ -10,6 +10,7 @@ import (
 	routerpkg "github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
 	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/httpserver"
 	"github.com/pkg/errors"
 )
 
@@ -28,6 +29,8 @@ type NetAdapter struct {
 	p2pServer            server.P2PServer
 	p2pRouterInitializer RouterInitializer
 	rpcServer            server.Server
+	httpRPCServer        server.Server
+	jsonRPCServer        server.Server
 	rpcRouterInitializer RouterInitializer
 	stop                 uint32
 
@@ -50,17 +53,29 @@ func NewNetAdapter(cfg *config.Config) (*NetAdapter, error) {
 	if err != nil {
 		return nil, err
 	}
+	httpRPCServer, err := httpserver.NewHTTPServer(cfg.RPCHTTPListeners, cfg.RPCMaxWebsockets)
+	if err != nil {
+		return nil, err
+	}
+	jsonRPCServer, err := httpserver.NewJSONRPCServer(cfg.JSONRPCListeners)
+	if err != nil {
+		return nil, err
+	}
 	adapter := NetAdapter{
-		cfg:       cfg,
-		id:        netAdapterID,
-		p2pServer: p2pServer,
-		rpcServer: rpcServer,
+		cfg:           cfg,
+		id:            netAdapterID,
+		p2pServer:     p2pServer,
+		rpcServer:     rpcServer,
+		httpRPCServer: httpRPCServer,
+		jsonRPCServer: jsonRPCServer,
 
 		p2pConnections: make(map[*NetConnection]struct{}),
 	}
 
 	adapter.p2pServer.SetOnConnectedHandler(adapter.onP2PConnectedHandler)
 	adapter.rpcServer.SetOnConnectedHandler(adapter.onRPCConnectedHandler)
+	adapter.httpRPCServer.SetOnConnectedHandler(adapter.onRPCConnectedHandler)
+	adapter.jsonRPCServer.SetOnConnectedHandler(adapter.onRPCConnectedHandler)
 
 	return &adapter, nil
 }
@@ -82,6 +97,14 @@ func (na *NetAdapter) Start() error {
 	if err != nil {
 		return err
 	}
+	err = na.httpRPCServer.Start()
+	if err != nil {
+		return err
+	}
+	err = na.jsonRPCServer.Start()
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -95,6 +118,14 @@ func (na *NetAdapter) Stop() error {
 	if err != nil {
 		return err
 	}
+	err = na.httpRPCServer.Stop()
+	if err != nil {
+		return err
+	}
+	err = na.jsonRPCServer.Stop()
+	if err != nil {
+		return err
+	}
 	return na.rpcServer.Stop()
 }
 
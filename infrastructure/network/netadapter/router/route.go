@@ -1,6 +1,7 @@
 package router
 
 import (
+	"context"
 	"sync"
 	"time"
 
@@ -87,6 +88,20 @@ func (r *Route) DequeueWithTimeout(timeout time.Duration) (appmessage.Message, e
 	}
 }
 
+// DequeueWithContext attempts to dequeue a message from the Route
+// and returns an error if ctx is done first.
+func (r *Route) DequeueWithContext(ctx context.Context) (appmessage.Message, error) {
+	select {
+	case <-ctx.Done():
+		return nil, errors.WithStack(ctx.Err())
+	case message, isOpen := <-r.channel:
+		if !isOpen {
+			return nil, errors.WithStack(ErrRouteClosed)
+		}
+		return message, nil
+	}
+}
+
 // Close closes this route
 func (r *Route) Close() {
 	r.closeLock.Lock()
@@ -0,0 +1,23 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_DandelionTransaction) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_DandelionTransaction is nil")
+	}
+	msgTx, err := x.DandelionTransaction.toAppMessage()
+	if err != nil {
+		return nil, err
+	}
+	return appmessage.NewMsgDandelionTx(msgTx.(*appmessage.MsgTx)), nil
+}
+
+func (x *KaspadMessage_DandelionTransaction) fromAppMessage(msgDandelionTx *appmessage.MsgDandelionTx) error {
+	x.DandelionTransaction = new(TransactionMessage)
+	x.DandelionTransaction.fromAppMessage(msgDandelionTx.MsgTx)
+	return nil
+}
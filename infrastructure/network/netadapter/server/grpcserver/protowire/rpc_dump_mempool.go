@@ -0,0 +1,59 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_DumpMempoolRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_DumpMempoolRequest is nil")
+	}
+	return &appmessage.DumpMempoolRequestMessage{}, nil
+}
+
+func (x *KaspadMessage_DumpMempoolRequest) fromAppMessage(_ *appmessage.DumpMempoolRequestMessage) error {
+	x.DumpMempoolRequest = &DumpMempoolRequestMessage{}
+	return nil
+}
+
+func (x *KaspadMessage_DumpMempoolResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_DumpMempoolResponse is nil")
+	}
+	return x.DumpMempoolResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_DumpMempoolResponse) fromAppMessage(message *appmessage.DumpMempoolResponseMessage) error {
+	var rpcErr *RPCError
+	if message.Error != nil {
+		rpcErr = &RPCError{Message: message.Error.Message}
+	}
+	x.DumpMempoolResponse = &DumpMempoolResponseMessage{
+		Path:             message.Path,
+		TransactionCount: message.TransactionCount,
+		Error:            rpcErr,
+	}
+	return nil
+}
+
+func (x *DumpMempoolResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "DumpMempoolResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+
+	if rpcErr != nil && x.Path != "" {
+		return nil, errors.New("DumpMempoolResponseMessage contains both an error and a response")
+	}
+
+	return &appmessage.DumpMempoolResponseMessage{
+		Path:             x.Path,
+		TransactionCount: x.TransactionCount,
+		Error:            rpcErr,
+	}, nil
+}
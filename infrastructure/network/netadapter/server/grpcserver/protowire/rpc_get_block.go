@@ -194,6 +194,10 @@ func (x *TransactionVerboseData) toAppMessage() (*appmessage.TransactionVerboseD
 		BlockHash:                 x.BlockHash,
 		Time:                      x.Time,
 		BlockTime:                 x.BlockTime,
+		Fee:                       x.Fee,
+		Mass:                      x.Mass,
+		AcceptingBlockHash:        x.AcceptingBlockHash,
+		Confirmations:             x.Confirmations,
 	}, nil
 }
 
@@ -209,11 +213,14 @@ func (x *TransactionVerboseData) fromAppMessage(message *appmessage.TransactionV
 			OutputIndex: item.OutputIndex,
 			ScriptSig:   scriptSig,
 			Sequence:    item.Sequence,
+			Value:       item.Value,
+			Address:     item.Address,
 		}
 	}
 	outputs := make([]*TransactionVerboseOutput, len(message.TransactionVerboseOutputs))
 	for j, item := range message.TransactionVerboseOutputs {
 		scriptPubKey := &ScriptPublicKeyResult{
+			Asm:     item.ScriptPubKey.Asm,
 			Hex:     item.ScriptPubKey.Hex,
 			Type:    item.ScriptPubKey.Type,
 			Address: item.ScriptPubKey.Address,
@@ -240,6 +247,10 @@ func (x *TransactionVerboseData) fromAppMessage(message *appmessage.TransactionV
 		BlockHash:                 message.BlockHash,
 		Time:                      message.Time,
 		BlockTime:                 message.BlockTime,
+		Fee:                       message.Fee,
+		Mass:                      message.Mass,
+		AcceptingBlockHash:        message.AcceptingBlockHash,
+		Confirmations:             message.Confirmations,
 	}
 	return nil
 }
@@ -257,6 +268,8 @@ func (x *TransactionVerboseInput) toAppMessage() (*appmessage.TransactionVerbose
 		OutputIndex: x.OutputIndex,
 		ScriptSig:   scriptSig,
 		Sequence:    x.Sequence,
+		Value:       x.Value,
+		Address:     x.Address,
 	}, nil
 }
 
@@ -290,6 +303,7 @@ func (x *ScriptPublicKeyResult) toAppMessage() (*appmessage.ScriptPubKeyResult,
 		return nil, errors.Wrap(errorNil, "ScriptPublicKeyResult is nil")
 	}
 	return &appmessage.ScriptPubKeyResult{
+		Asm:     x.Asm,
 		Hex:     x.Hex,
 		Type:    x.Type,
 		Address: x.Address,
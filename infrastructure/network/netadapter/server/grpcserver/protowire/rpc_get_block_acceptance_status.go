@@ -0,0 +1,74 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_GetBlockAcceptanceStatusRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetBlockAcceptanceStatusRequest is nil")
+	}
+	return x.GetBlockAcceptanceStatusRequest.toAppMessage()
+}
+
+func (x *KaspadMessage_GetBlockAcceptanceStatusRequest) fromAppMessage(message *appmessage.GetBlockAcceptanceStatusRequestMessage) error {
+	x.GetBlockAcceptanceStatusRequest = &GetBlockAcceptanceStatusRequestMessage{
+		BlockHash: message.BlockHash,
+	}
+	return nil
+}
+
+func (x *GetBlockAcceptanceStatusRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetBlockAcceptanceStatusRequestMessage is nil")
+	}
+	return &appmessage.GetBlockAcceptanceStatusRequestMessage{
+		BlockHash: x.BlockHash,
+	}, nil
+}
+
+func (x *KaspadMessage_GetBlockAcceptanceStatusResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetBlockAcceptanceStatusResponse is nil")
+	}
+	return x.GetBlockAcceptanceStatusResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_GetBlockAcceptanceStatusResponse) fromAppMessage(message *appmessage.GetBlockAcceptanceStatusResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.GetBlockAcceptanceStatusResponse = &GetBlockAcceptanceStatusResponseMessage{
+		AcceptingBlockHash: message.AcceptingBlockHash,
+		Confirmations:      message.Confirmations,
+		IsBlue:             message.IsBlue,
+		IsChainBlock:       message.IsChainBlock,
+		IsFinalized:        message.IsFinalized,
+		Error:              err,
+	}
+	return nil
+}
+
+func (x *GetBlockAcceptanceStatusResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetBlockAcceptanceStatusResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	if rpcErr != nil && (x.AcceptingBlockHash != "" || x.Confirmations != 0) {
+		return nil, errors.New("GetBlockAcceptanceStatusResponseMessage contains both an error and a response")
+	}
+	return &appmessage.GetBlockAcceptanceStatusResponseMessage{
+		AcceptingBlockHash: x.AcceptingBlockHash,
+		Confirmations:      x.Confirmations,
+		IsBlue:             x.IsBlue,
+		IsChainBlock:       x.IsChainBlock,
+		IsFinalized:        x.IsFinalized,
+		Error:              rpcErr,
+	}, nil
+}
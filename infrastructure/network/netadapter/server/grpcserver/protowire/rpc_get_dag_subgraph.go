@@ -0,0 +1,125 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_GetDAGSubgraphRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetDAGSubgraphRequest is nil")
+	}
+	return x.GetDAGSubgraphRequest.toAppMessage()
+}
+
+func (x *KaspadMessage_GetDAGSubgraphRequest) fromAppMessage(message *appmessage.GetDAGSubgraphRequestMessage) error {
+	x.GetDAGSubgraphRequest = &GetDAGSubgraphRequestMessage{
+		LowBlueScore:    message.LowBlueScore,
+		HighBlueScore:   message.HighBlueScore,
+		AnticoneOfHash:  message.AnticoneOfHash,
+		IncludeDotGraph: message.IncludeDotGraph,
+	}
+	return nil
+}
+
+func (x *GetDAGSubgraphRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetDAGSubgraphRequestMessage is nil")
+	}
+	return &appmessage.GetDAGSubgraphRequestMessage{
+		LowBlueScore:    x.LowBlueScore,
+		HighBlueScore:   x.HighBlueScore,
+		AnticoneOfHash:  x.AnticoneOfHash,
+		IncludeDotGraph: x.IncludeDotGraph,
+	}, nil
+}
+
+func (x *KaspadMessage_GetDAGSubgraphResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetDAGSubgraphResponse is nil")
+	}
+	return x.GetDAGSubgraphResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_GetDAGSubgraphResponse) fromAppMessage(message *appmessage.GetDAGSubgraphResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	blocks := make([]*DAGSubgraphBlock, len(message.Blocks))
+	for i, block := range message.Blocks {
+		protoBlock := &DAGSubgraphBlock{}
+		err := protoBlock.fromAppMessage(block)
+		if err != nil {
+			return err
+		}
+		blocks[i] = protoBlock
+	}
+	x.GetDAGSubgraphResponse = &GetDAGSubgraphResponseMessage{
+		Blocks:      blocks,
+		DotGraph:    message.DotGraph,
+		IsTruncated: message.IsTruncated,
+		Error:       err,
+	}
+	return nil
+}
+
+func (x *GetDAGSubgraphResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetDAGSubgraphResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+
+	if rpcErr != nil && (len(x.Blocks) != 0 || x.DotGraph != "" || x.IsTruncated) {
+		return nil, errors.New("GetDAGSubgraphResponseMessage contains both an error and a response")
+	}
+
+	blocks := make([]*appmessage.DAGSubgraphBlock, len(x.Blocks))
+	for i, block := range x.Blocks {
+		appBlock, err := block.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+		blocks[i] = appBlock
+	}
+	return &appmessage.GetDAGSubgraphResponseMessage{
+		Blocks:      blocks,
+		DotGraph:    x.DotGraph,
+		IsTruncated: x.IsTruncated,
+		Error:       rpcErr,
+	}, nil
+}
+
+func (x *DAGSubgraphBlock) toAppMessage() (*appmessage.DAGSubgraphBlock, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "DAGSubgraphBlock is nil")
+	}
+	return &appmessage.DAGSubgraphBlock{
+		Hash:                x.Hash,
+		BlueScore:           x.BlueScore,
+		ParentHashes:        x.ParentHashes,
+		SelectedParentHash:  x.SelectedParentHash,
+		MergeSetBluesHashes: x.MergeSetBluesHashes,
+		MergeSetRedsHashes:  x.MergeSetRedsHashes,
+		IsChainBlock:        x.IsChainBlock,
+		Color:               x.Color,
+	}, nil
+}
+
+func (x *DAGSubgraphBlock) fromAppMessage(block *appmessage.DAGSubgraphBlock) error {
+	*x = DAGSubgraphBlock{
+		Hash:                block.Hash,
+		BlueScore:           block.BlueScore,
+		ParentHashes:        block.ParentHashes,
+		SelectedParentHash:  block.SelectedParentHash,
+		MergeSetBluesHashes: block.MergeSetBluesHashes,
+		MergeSetRedsHashes:  block.MergeSetRedsHashes,
+		IsChainBlock:        block.IsChainBlock,
+		Color:               block.Color,
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_GetMempoolAncestorsRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetMempoolAncestorsRequest is nil")
+	}
+	return x.GetMempoolAncestorsRequest.toAppMessage()
+}
+
+func (x *KaspadMessage_GetMempoolAncestorsRequest) fromAppMessage(message *appmessage.GetMempoolAncestorsRequestMessage) error {
+	x.GetMempoolAncestorsRequest = &GetMempoolAncestorsRequestMessage{
+		TxId: message.TxID,
+	}
+	return nil
+}
+
+func (x *GetMempoolAncestorsRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetMempoolAncestorsRequestMessage is nil")
+	}
+	return &appmessage.GetMempoolAncestorsRequestMessage{
+		TxID: x.TxId,
+	}, nil
+}
+
+func (x *KaspadMessage_GetMempoolAncestorsResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetMempoolAncestorsResponse is nil")
+	}
+	return x.GetMempoolAncestorsResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_GetMempoolAncestorsResponse) fromAppMessage(message *appmessage.GetMempoolAncestorsResponseMessage) error {
+	var rpcErr *RPCError
+	if message.Error != nil {
+		rpcErr = &RPCError{Message: message.Error.Message}
+	}
+	entries := make([]*MempoolEntry, len(message.Entries))
+	for i, entry := range message.Entries {
+		entries[i] = new(MempoolEntry)
+		err := entries[i].fromAppMessage(entry)
+		if err != nil {
+			return err
+		}
+	}
+	x.GetMempoolAncestorsResponse = &GetMempoolAncestorsResponseMessage{
+		Entries:   entries,
+		TotalMass: message.TotalMass,
+		TotalFee:  message.TotalFee,
+		Error:     rpcErr,
+	}
+	return nil
+}
+
+func (x *GetMempoolAncestorsResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetMempoolAncestorsResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+
+	if rpcErr != nil && len(x.Entries) != 0 {
+		return nil, errors.New("GetMempoolAncestorsResponseMessage contains both an error and a response")
+	}
+	entries := make([]*appmessage.MempoolEntry, len(x.Entries))
+	for i, entry := range x.Entries {
+		entries[i], err = entry.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &appmessage.GetMempoolAncestorsResponseMessage{
+		Entries:   entries,
+		TotalMass: x.TotalMass,
+		TotalFee:  x.TotalFee,
+		Error:     rpcErr,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_GetMempoolDescendantsRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetMempoolDescendantsRequest is nil")
+	}
+	return x.GetMempoolDescendantsRequest.toAppMessage()
+}
+
+func (x *KaspadMessage_GetMempoolDescendantsRequest) fromAppMessage(message *appmessage.GetMempoolDescendantsRequestMessage) error {
+	x.GetMempoolDescendantsRequest = &GetMempoolDescendantsRequestMessage{
+		TxId: message.TxID,
+	}
+	return nil
+}
+
+func (x *GetMempoolDescendantsRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetMempoolDescendantsRequestMessage is nil")
+	}
+	return &appmessage.GetMempoolDescendantsRequestMessage{
+		TxID: x.TxId,
+	}, nil
+}
+
+func (x *KaspadMessage_GetMempoolDescendantsResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetMempoolDescendantsResponse is nil")
+	}
+	return x.GetMempoolDescendantsResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_GetMempoolDescendantsResponse) fromAppMessage(message *appmessage.GetMempoolDescendantsResponseMessage) error {
+	var rpcErr *RPCError
+	if message.Error != nil {
+		rpcErr = &RPCError{Message: message.Error.Message}
+	}
+	entries := make([]*MempoolEntry, len(message.Entries))
+	for i, entry := range message.Entries {
+		entries[i] = new(MempoolEntry)
+		err := entries[i].fromAppMessage(entry)
+		if err != nil {
+			return err
+		}
+	}
+	x.GetMempoolDescendantsResponse = &GetMempoolDescendantsResponseMessage{
+		Entries:   entries,
+		TotalMass: message.TotalMass,
+		TotalFee:  message.TotalFee,
+		Error:     rpcErr,
+	}
+	return nil
+}
+
+func (x *GetMempoolDescendantsResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetMempoolDescendantsResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+
+	if rpcErr != nil && len(x.Entries) != 0 {
+		return nil, errors.New("GetMempoolDescendantsResponseMessage contains both an error and a response")
+	}
+	entries := make([]*appmessage.MempoolEntry, len(x.Entries))
+	for i, entry := range x.Entries {
+		entries[i], err = entry.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &appmessage.GetMempoolDescendantsResponseMessage{
+		Entries:   entries,
+		TotalMass: x.TotalMass,
+		TotalFee:  x.TotalFee,
+		Error:     rpcErr,
+	}, nil
+}
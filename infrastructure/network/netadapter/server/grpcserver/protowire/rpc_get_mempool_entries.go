@@ -9,14 +9,27 @@ func (x *KaspadMessage_GetMempoolEntriesRequest) toAppMessage() (appmessage.Mess
 	if x == nil {
 		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetMempoolEntriesRequest is nil")
 	}
-	return &appmessage.GetMempoolEntriesRequestMessage{}, nil
+	return x.GetMempoolEntriesRequest.toAppMessage()
 }
 
-func (x *KaspadMessage_GetMempoolEntriesRequest) fromAppMessage(_ *appmessage.GetMempoolEntriesRequestMessage) error {
-	x.GetMempoolEntriesRequest = &GetMempoolEntriesRequestMessage{}
+func (x *KaspadMessage_GetMempoolEntriesRequest) fromAppMessage(message *appmessage.GetMempoolEntriesRequestMessage) error {
+	x.GetMempoolEntriesRequest = &GetMempoolEntriesRequestMessage{
+		Addresses:      message.Addresses,
+		MinimumFeeRate: message.MinimumFeeRate,
+	}
 	return nil
 }
 
+func (x *GetMempoolEntriesRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetMempoolEntriesRequestMessage is nil")
+	}
+	return &appmessage.GetMempoolEntriesRequestMessage{
+		Addresses:      x.Addresses,
+		MinimumFeeRate: x.MinimumFeeRate,
+	}, nil
+}
+
 func (x *KaspadMessage_GetMempoolEntriesResponse) toAppMessage() (appmessage.Message, error) {
 	if x == nil {
 		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetMempoolEntriesResponse is nil")
@@ -0,0 +1,91 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_GetMempoolStatsRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetMempoolStatsRequest is nil")
+	}
+	return &appmessage.GetMempoolStatsRequestMessage{}, nil
+}
+
+func (x *KaspadMessage_GetMempoolStatsRequest) fromAppMessage(_ *appmessage.GetMempoolStatsRequestMessage) error {
+	x.GetMempoolStatsRequest = &GetMempoolStatsRequestMessage{}
+	return nil
+}
+
+func (x *KaspadMessage_GetMempoolStatsResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetMempoolStatsResponse is nil")
+	}
+	return x.GetMempoolStatsResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_GetMempoolStatsResponse) fromAppMessage(message *appmessage.GetMempoolStatsResponseMessage) error {
+	var rpcErr *RPCError
+	if message.Error != nil {
+		rpcErr = &RPCError{Message: message.Error.Message}
+	}
+	feeRateHistogram := make([]*MempoolFeeRateBucket, len(message.FeeRateHistogram))
+	for i, bucket := range message.FeeRateHistogram {
+		feeRateHistogram[i] = &MempoolFeeRateBucket{
+			MinimumFeeRate:   bucket.MinimumFeeRate,
+			TransactionCount: bucket.TransactionCount,
+			TotalMass:        bucket.TotalMass,
+		}
+	}
+	x.GetMempoolStatsResponse = &GetMempoolStatsResponseMessage{
+		TransactionCount: message.TransactionCount,
+		OrphanCount:      message.OrphanCount,
+		TotalMass:        message.TotalMass,
+		TotalFee:         message.TotalFee,
+		FeeRateHistogram: feeRateHistogram,
+		Error:            rpcErr,
+	}
+	return nil
+}
+
+func (x *GetMempoolStatsResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetMempoolStatsResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+
+	if rpcErr != nil && len(x.FeeRateHistogram) != 0 {
+		return nil, errors.New("GetMempoolStatsResponseMessage contains both an error and a response")
+	}
+	feeRateHistogram := make([]*appmessage.MempoolFeeRateBucket, len(x.FeeRateHistogram))
+	for i, bucket := range x.FeeRateHistogram {
+		feeRateHistogram[i], err = bucket.toAppMessage()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &appmessage.GetMempoolStatsResponseMessage{
+		TransactionCount: x.TransactionCount,
+		OrphanCount:      x.OrphanCount,
+		TotalMass:        x.TotalMass,
+		TotalFee:         x.TotalFee,
+		FeeRateHistogram: feeRateHistogram,
+		Error:            rpcErr,
+	}, nil
+}
+
+func (x *MempoolFeeRateBucket) toAppMessage() (*appmessage.MempoolFeeRateBucket, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "MempoolFeeRateBucket is nil")
+	}
+	return &appmessage.MempoolFeeRateBucket{
+		MinimumFeeRate:   x.MinimumFeeRate,
+		TransactionCount: x.TransactionCount,
+		TotalMass:        x.TotalMass,
+	}, nil
+}
@@ -0,0 +1,74 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_GetTransactionConfirmationsRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetTransactionConfirmationsRequest is nil")
+	}
+	return x.GetTransactionConfirmationsRequest.toAppMessage()
+}
+
+func (x *KaspadMessage_GetTransactionConfirmationsRequest) fromAppMessage(message *appmessage.GetTransactionConfirmationsRequestMessage) error {
+	x.GetTransactionConfirmationsRequest = &GetTransactionConfirmationsRequestMessage{
+		TransactionId: message.TransactionID,
+		BlockHash:     message.BlockHash,
+	}
+	return nil
+}
+
+func (x *GetTransactionConfirmationsRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetTransactionConfirmationsRequestMessage is nil")
+	}
+	return &appmessage.GetTransactionConfirmationsRequestMessage{
+		TransactionID: x.TransactionId,
+		BlockHash:     x.BlockHash,
+	}, nil
+}
+
+func (x *KaspadMessage_GetTransactionConfirmationsResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_GetTransactionConfirmationsResponse is nil")
+	}
+	return x.GetTransactionConfirmationsResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_GetTransactionConfirmationsResponse) fromAppMessage(message *appmessage.GetTransactionConfirmationsResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.GetTransactionConfirmationsResponse = &GetTransactionConfirmationsResponseMessage{
+		IsAccepted:         message.IsAccepted,
+		AcceptingBlockHash: message.AcceptingBlockHash,
+		Confirmations:      message.Confirmations,
+		IsFinalized:        message.IsFinalized,
+		Error:              err,
+	}
+	return nil
+}
+
+func (x *GetTransactionConfirmationsResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "GetTransactionConfirmationsResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	if rpcErr != nil && (x.IsAccepted || x.AcceptingBlockHash != "" || x.Confirmations != 0) {
+		return nil, errors.New("GetTransactionConfirmationsResponseMessage contains both an error and a response")
+	}
+	return &appmessage.GetTransactionConfirmationsResponseMessage{
+		IsAccepted:         x.IsAccepted,
+		AcceptingBlockHash: x.AcceptingBlockHash,
+		Confirmations:      x.Confirmations,
+		IsFinalized:        x.IsFinalized,
+		Error:              rpcErr,
+	}, nil
+}
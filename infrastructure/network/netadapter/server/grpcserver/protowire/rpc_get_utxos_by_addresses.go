@@ -14,7 +14,10 @@ func (x *KaspadMessage_GetUtxosByAddressesRequest) toAppMessage() (appmessage.Me
 
 func (x *KaspadMessage_GetUtxosByAddressesRequest) fromAppMessage(message *appmessage.GetUTXOsByAddressesRequestMessage) error {
 	x.GetUtxosByAddressesRequest = &GetUtxosByAddressesRequestMessage{
-		Addresses: message.Addresses,
+		Addresses:      message.Addresses,
+		Limit:          message.Limit,
+		Cursor:         message.Cursor,
+		IncludeMempool: message.IncludeMempool,
 	}
 	return nil
 }
@@ -24,7 +27,10 @@ func (x *GetUtxosByAddressesRequestMessage) toAppMessage() (appmessage.Message,
 		return nil, errors.Wrapf(errorNil, "GetUtxosByAddressesRequestMessage is nil")
 	}
 	return &appmessage.GetUTXOsByAddressesRequestMessage{
-		Addresses: x.Addresses,
+		Addresses:      x.Addresses,
+		Limit:          x.Limit,
+		Cursor:         x.Cursor,
+		IncludeMempool: x.IncludeMempool,
 	}, nil
 }
 
@@ -46,8 +52,9 @@ func (x *KaspadMessage_GetUtxosByAddressesResponse) fromAppMessage(message *appm
 		entries[i].fromAppMessage(entry)
 	}
 	x.GetUtxosByAddressesResponse = &GetUtxosByAddressesResponseMessage{
-		Entries: entries,
-		Error:   err,
+		Entries:    entries,
+		NextCursor: message.NextCursor,
+		Error:      err,
 	}
 	return nil
 }
@@ -76,7 +83,8 @@ func (x *GetUtxosByAddressesResponseMessage) toAppMessage() (appmessage.Message,
 	}
 
 	return &appmessage.GetUTXOsByAddressesResponseMessage{
-		Entries: entries,
-		Error:   rpcErr,
+		Entries:    entries,
+		NextCursor: x.NextCursor,
+		Error:      rpcErr,
 	}, nil
 }
@@ -6,14 +6,30 @@ import (
 )
 
 func (x *KaspadMessage_NotifyBlockAddedRequest) toAppMessage() (appmessage.Message, error) {
-	return &appmessage.NotifyBlockAddedRequestMessage{}, nil
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_NotifyBlockAddedRequest is nil")
+	}
+	return x.NotifyBlockAddedRequest.toAppMessage()
 }
 
-func (x *KaspadMessage_NotifyBlockAddedRequest) fromAppMessage(_ *appmessage.NotifyBlockAddedRequestMessage) error {
-	x.NotifyBlockAddedRequest = &NotifyBlockAddedRequestMessage{}
+func (x *KaspadMessage_NotifyBlockAddedRequest) fromAppMessage(message *appmessage.NotifyBlockAddedRequestMessage) error {
+	x.NotifyBlockAddedRequest = &NotifyBlockAddedRequestMessage{
+		StartSequenceNumber: message.StartSequenceNumber,
+		StartStreamId:       message.StartStreamID,
+	}
 	return nil
 }
 
+func (x *NotifyBlockAddedRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "NotifyBlockAddedRequestMessage is nil")
+	}
+	return &appmessage.NotifyBlockAddedRequestMessage{
+		StartSequenceNumber: x.StartSequenceNumber,
+		StartStreamID:       x.StartStreamId,
+	}, nil
+}
+
 func (x *KaspadMessage_NotifyBlockAddedResponse) toAppMessage() (appmessage.Message, error) {
 	if x == nil {
 		return nil, errors.Wrapf(errorNil, "KaspadMessage_NotifyBlockAddedResponse is nil")
@@ -67,6 +83,8 @@ func (x *KaspadMessage_BlockAddedNotification) fromAppMessage(message *appmessag
 	x.BlockAddedNotification = &BlockAddedNotificationMessage{
 		Block:            blockMessage,
 		BlockVerboseData: blockVerboseData,
+		SequenceNumber:   message.SequenceNumber,
+		StreamId:         message.StreamID,
 	}
 	return nil
 }
@@ -86,5 +104,7 @@ func (x *BlockAddedNotificationMessage) toAppMessage() (appmessage.Message, erro
 	return &appmessage.BlockAddedNotificationMessage{
 		Block:            block,
 		BlockVerboseData: blockVerboseData,
+		SequenceNumber:   x.SequenceNumber,
+		StreamID:         x.StreamId,
 	}, nil
 }
@@ -0,0 +1,116 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_NotifyMempoolTransactionsRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_NotifyMempoolTransactionsRequest is nil")
+	}
+	return x.NotifyMempoolTransactionsRequest.toAppMessage()
+}
+
+func (x *KaspadMessage_NotifyMempoolTransactionsRequest) fromAppMessage(message *appmessage.NotifyMempoolTransactionsRequestMessage) error {
+	x.NotifyMempoolTransactionsRequest = &NotifyMempoolTransactionsRequestMessage{
+		Addresses: message.Addresses,
+	}
+	return nil
+}
+
+func (x *NotifyMempoolTransactionsRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "NotifyMempoolTransactionsRequestMessage is nil")
+	}
+	return &appmessage.NotifyMempoolTransactionsRequestMessage{
+		Addresses: x.Addresses,
+	}, nil
+}
+
+func (x *KaspadMessage_NotifyMempoolTransactionsResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_NotifyMempoolTransactionsResponse is nil")
+	}
+	return x.NotifyMempoolTransactionsResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_NotifyMempoolTransactionsResponse) fromAppMessage(message *appmessage.NotifyMempoolTransactionsResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.NotifyMempoolTransactionsResponse = &NotifyMempoolTransactionsResponseMessage{
+		Error: err,
+	}
+	return nil
+}
+
+func (x *NotifyMempoolTransactionsResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "NotifyMempoolTransactionsResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	return &appmessage.NotifyMempoolTransactionsResponseMessage{
+		Error: rpcErr,
+	}, nil
+}
+
+func (x *KaspadMessage_TransactionAddedToMempoolNotification) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_TransactionAddedToMempoolNotification is nil")
+	}
+	return x.TransactionAddedToMempoolNotification.toAppMessage()
+}
+
+func (x *KaspadMessage_TransactionAddedToMempoolNotification) fromAppMessage(message *appmessage.TransactionAddedToMempoolNotificationMessage) error {
+	x.TransactionAddedToMempoolNotification = &TransactionAddedToMempoolNotificationMessage{
+		TransactionId: message.TransactionID,
+		Transaction:   &RpcTransaction{},
+	}
+	x.TransactionAddedToMempoolNotification.Transaction.fromAppMessage(message.Transaction)
+	return nil
+}
+
+func (x *TransactionAddedToMempoolNotificationMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "TransactionAddedToMempoolNotificationMessage is nil")
+	}
+	transaction, err := x.Transaction.toAppMessage()
+	if err != nil {
+		return nil, err
+	}
+	return &appmessage.TransactionAddedToMempoolNotificationMessage{
+		TransactionID: x.TransactionId,
+		Transaction:   transaction,
+	}, nil
+}
+
+func (x *KaspadMessage_TransactionRemovedFromMempoolNotification) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_TransactionRemovedFromMempoolNotification is nil")
+	}
+	return x.TransactionRemovedFromMempoolNotification.toAppMessage()
+}
+
+func (x *KaspadMessage_TransactionRemovedFromMempoolNotification) fromAppMessage(message *appmessage.TransactionRemovedFromMempoolNotificationMessage) error {
+	x.TransactionRemovedFromMempoolNotification = &TransactionRemovedFromMempoolNotificationMessage{
+		TransactionId: message.TransactionID,
+		Reason:        TransactionRemovedFromMempoolNotificationMessage_RemovalReason(message.Reason),
+	}
+	return nil
+}
+
+func (x *TransactionRemovedFromMempoolNotificationMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "TransactionRemovedFromMempoolNotificationMessage is nil")
+	}
+	return &appmessage.TransactionRemovedFromMempoolNotificationMessage{
+		TransactionID: x.TransactionId,
+		Reason:        appmessage.TransactionRemovalReason(x.Reason),
+	}, nil
+}
@@ -14,7 +14,10 @@ func (x *KaspadMessage_NotifyUtxosChangedRequest) toAppMessage() (appmessage.Mes
 
 func (x *KaspadMessage_NotifyUtxosChangedRequest) fromAppMessage(message *appmessage.NotifyUTXOsChangedRequestMessage) error {
 	x.NotifyUtxosChangedRequest = &NotifyUtxosChangedRequestMessage{
-		Addresses: message.Addresses,
+		Addresses:           message.Addresses,
+		IncludeMempool:      message.IncludeMempool,
+		StartSequenceNumber: message.StartSequenceNumber,
+		StartStreamId:       message.StartStreamID,
 	}
 	return nil
 }
@@ -24,7 +27,10 @@ func (x *NotifyUtxosChangedRequestMessage) toAppMessage() (appmessage.Message, e
 		return nil, errors.Wrapf(errorNil, "NotifyUtxosChangedRequestMessage is nil")
 	}
 	return &appmessage.NotifyUTXOsChangedRequestMessage{
-		Addresses: x.Addresses,
+		Addresses:           x.Addresses,
+		IncludeMempool:      x.IncludeMempool,
+		StartSequenceNumber: x.StartSequenceNumber,
+		StartStreamID:       x.StartStreamId,
 	}, nil
 }
 
@@ -81,8 +87,10 @@ func (x *KaspadMessage_UtxosChangedNotification) fromAppMessage(message *appmess
 	}
 
 	x.UtxosChangedNotification = &UtxosChangedNotificationMessage{
-		Added:   added,
-		Removed: removed,
+		Added:          added,
+		Removed:        removed,
+		SequenceNumber: message.SequenceNumber,
+		StreamId:       message.StreamID,
 	}
 	return nil
 }
@@ -114,8 +122,10 @@ func (x *UtxosChangedNotificationMessage) toAppMessage() (appmessage.Message, er
 	}
 
 	return &appmessage.UTXOsChangedNotificationMessage{
-		Added:   added,
-		Removed: removed,
+		Added:          added,
+		Removed:        removed,
+		SequenceNumber: x.SequenceNumber,
+		StreamID:       x.StreamId,
 	}, nil
 }
 
@@ -133,9 +143,10 @@ func (x *UtxosByAddressesEntry) toAppMessage() (*appmessage.UTXOsByAddressesEntr
 		return nil, err
 	}
 	return &appmessage.UTXOsByAddressesEntry{
-		Address:   x.Address,
-		Outpoint:  outpoint,
-		UTXOEntry: entry,
+		Address:       x.Address,
+		Outpoint:      outpoint,
+		UTXOEntry:     entry,
+		IsUnconfirmed: x.IsUnconfirmed,
 	}, nil
 }
 
@@ -154,9 +165,10 @@ func (x *UtxosByAddressesEntry) fromAppMessage(entry *appmessage.UTXOsByAddresse
 		}
 	}
 	*x = UtxosByAddressesEntry{
-		Address:   entry.Address,
-		Outpoint:  outpoint,
-		UtxoEntry: utxoEntry,
+		Address:       entry.Address,
+		Outpoint:      outpoint,
+		UtxoEntry:     utxoEntry,
+		IsUnconfirmed: entry.IsUnconfirmed,
 	}
 }
 
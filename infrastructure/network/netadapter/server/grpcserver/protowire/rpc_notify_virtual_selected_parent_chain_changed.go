@@ -9,14 +9,27 @@ func (x *KaspadMessage_NotifyVirtualSelectedParentChainChangedRequest) toAppMess
 	if x == nil {
 		return nil, errors.Wrapf(errorNil, "KaspadMessage_NotifyVirtualSelectedParentChainChangedRequest is nil")
 	}
-	return &appmessage.NotifyVirtualSelectedParentChainChangedRequestMessage{}, nil
+	return x.NotifyVirtualSelectedParentChainChangedRequest.toAppMessage()
 }
 
-func (x *KaspadMessage_NotifyVirtualSelectedParentChainChangedRequest) fromAppMessage(_ *appmessage.NotifyVirtualSelectedParentChainChangedRequestMessage) error {
-	x.NotifyVirtualSelectedParentChainChangedRequest = &NotifyVirtualSelectedParentChainChangedRequestMessage{}
+func (x *KaspadMessage_NotifyVirtualSelectedParentChainChangedRequest) fromAppMessage(message *appmessage.NotifyVirtualSelectedParentChainChangedRequestMessage) error {
+	x.NotifyVirtualSelectedParentChainChangedRequest = &NotifyVirtualSelectedParentChainChangedRequestMessage{
+		StartSequenceNumber: message.StartSequenceNumber,
+		StartStreamId:       message.StartStreamID,
+	}
 	return nil
 }
 
+func (x *NotifyVirtualSelectedParentChainChangedRequestMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "NotifyVirtualSelectedParentChainChangedRequestMessage is nil")
+	}
+	return &appmessage.NotifyVirtualSelectedParentChainChangedRequestMessage{
+		StartSequenceNumber: x.StartSequenceNumber,
+		StartStreamID:       x.StartStreamId,
+	}, nil
+}
+
 func (x *KaspadMessage_NotifyVirtualSelectedParentChainChangedResponse) toAppMessage() (appmessage.Message, error) {
 	if x == nil {
 		return nil, errors.Wrapf(errorNil, "KaspadMessage_NotifyVirtualSelectedParentChainChangedResponse is nil")
@@ -69,6 +82,8 @@ func (x *KaspadMessage_VirtualSelectedParentChainChangedNotification) fromAppMes
 	x.VirtualSelectedParentChainChangedNotification = &VirtualSelectedParentChainChangedNotificationMessage{
 		RemovedChainBlockHashes: message.RemovedChainBlockHashes,
 		AddedChainBlocks:        addedChainBlocks,
+		SequenceNumber:          message.SequenceNumber,
+		StreamId:                message.StreamID,
 	}
 	return nil
 }
@@ -88,6 +103,8 @@ func (x *VirtualSelectedParentChainChangedNotificationMessage) toAppMessage() (a
 	return &appmessage.VirtualSelectedParentChainChangedNotificationMessage{
 		RemovedChainBlockHashes: x.RemovedChainBlockHashes,
 		AddedChainBlocks:        addedChainBlocks,
+		SequenceNumber:          x.SequenceNumber,
+		StreamID:                x.StreamId,
 	}, nil
 }
 
@@ -0,0 +1,50 @@
+package protowire
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+func (x *KaspadMessage_StopNotifyingMempoolTransactionsRequest) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_StopNotifyingMempoolTransactionsRequest is nil")
+	}
+	return &appmessage.StopNotifyingMempoolTransactionsRequestMessage{}, nil
+}
+
+func (x *KaspadMessage_StopNotifyingMempoolTransactionsRequest) fromAppMessage(_ *appmessage.StopNotifyingMempoolTransactionsRequestMessage) error {
+	x.StopNotifyingMempoolTransactionsRequest = &StopNotifyingMempoolTransactionsRequestMessage{}
+	return nil
+}
+
+func (x *KaspadMessage_StopNotifyingMempoolTransactionsResponse) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "KaspadMessage_StopNotifyingMempoolTransactionsResponse is nil")
+	}
+	return x.StopNotifyingMempoolTransactionsResponse.toAppMessage()
+}
+
+func (x *KaspadMessage_StopNotifyingMempoolTransactionsResponse) fromAppMessage(message *appmessage.StopNotifyingMempoolTransactionsResponseMessage) error {
+	var err *RPCError
+	if message.Error != nil {
+		err = &RPCError{Message: message.Error.Message}
+	}
+	x.StopNotifyingMempoolTransactionsResponse = &StopNotifyingMempoolTransactionsResponseMessage{
+		Error: err,
+	}
+	return nil
+}
+
+func (x *StopNotifyingMempoolTransactionsResponseMessage) toAppMessage() (appmessage.Message, error) {
+	if x == nil {
+		return nil, errors.Wrapf(errorNil, "StopNotifyingMempoolTransactionsResponseMessage is nil")
+	}
+	rpcErr, err := x.Error.toAppMessage()
+	// Error is an optional field
+	if err != nil && !errors.Is(err, errorNil) {
+		return nil, err
+	}
+	return &appmessage.StopNotifyingMempoolTransactionsResponseMessage{
+		Error: rpcErr,
+	}, nil
+}
@@ -177,6 +177,13 @@ func toP2PPayload(message appmessage.Message) (isKaspadMessage_Payload, error) {
 			return nil, err
 		}
 		return payload, nil
+	case *appmessage.MsgDandelionTx:
+		payload := new(KaspadMessage_DandelionTransaction)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
 	case *appmessage.MsgVerAck:
 		payload := new(KaspadMessage_Verack)
 		err := payload.fromAppMessage(message)
@@ -779,6 +786,174 @@ func toRPCPayload(message appmessage.Message) (isKaspadMessage_Payload, error) {
 			return nil, err
 		}
 		return payload, nil
+	case *appmessage.GetDAGSubgraphRequestMessage:
+		payload := new(KaspadMessage_GetDAGSubgraphRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetDAGSubgraphResponseMessage:
+		payload := new(KaspadMessage_GetDAGSubgraphResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetBalanceByAddressRequestMessage:
+		payload := new(KaspadMessage_GetBalanceByAddressRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetBalanceByAddressResponseMessage:
+		payload := new(KaspadMessage_GetBalanceByAddressResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetBalancesByAddressesRequestMessage:
+		payload := new(KaspadMessage_GetBalancesByAddressesRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetBalancesByAddressesResponseMessage:
+		payload := new(KaspadMessage_GetBalancesByAddressesResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.DumpMempoolRequestMessage:
+		payload := new(KaspadMessage_DumpMempoolRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.DumpMempoolResponseMessage:
+		payload := new(KaspadMessage_DumpMempoolResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetMempoolAncestorsRequestMessage:
+		payload := new(KaspadMessage_GetMempoolAncestorsRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetMempoolAncestorsResponseMessage:
+		payload := new(KaspadMessage_GetMempoolAncestorsResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetMempoolDescendantsRequestMessage:
+		payload := new(KaspadMessage_GetMempoolDescendantsRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetMempoolDescendantsResponseMessage:
+		payload := new(KaspadMessage_GetMempoolDescendantsResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetMempoolStatsRequestMessage:
+		payload := new(KaspadMessage_GetMempoolStatsRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetMempoolStatsResponseMessage:
+		payload := new(KaspadMessage_GetMempoolStatsResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.NotifyMempoolTransactionsRequestMessage:
+		payload := new(KaspadMessage_NotifyMempoolTransactionsRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.NotifyMempoolTransactionsResponseMessage:
+		payload := new(KaspadMessage_NotifyMempoolTransactionsResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.StopNotifyingMempoolTransactionsRequestMessage:
+		payload := new(KaspadMessage_StopNotifyingMempoolTransactionsRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.StopNotifyingMempoolTransactionsResponseMessage:
+		payload := new(KaspadMessage_StopNotifyingMempoolTransactionsResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.TransactionAddedToMempoolNotificationMessage:
+		payload := new(KaspadMessage_TransactionAddedToMempoolNotification)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.TransactionRemovedFromMempoolNotificationMessage:
+		payload := new(KaspadMessage_TransactionRemovedFromMempoolNotification)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetBlockAcceptanceStatusRequestMessage:
+		payload := new(KaspadMessage_GetBlockAcceptanceStatusRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetBlockAcceptanceStatusResponseMessage:
+		payload := new(KaspadMessage_GetBlockAcceptanceStatusResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetTransactionConfirmationsRequestMessage:
+		payload := new(KaspadMessage_GetTransactionConfirmationsRequest)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
+	case *appmessage.GetTransactionConfirmationsResponseMessage:
+		payload := new(KaspadMessage_GetTransactionConfirmationsResponse)
+		err := payload.fromAppMessage(message)
+		if err != nil {
+			return nil, err
+		}
+		return payload, nil
 	default:
 		return nil, nil
 	}
@@ -0,0 +1,72 @@
+package httpserver
+
+import (
+	"strings"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver/protowire"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// kaspadMessagePayload is the oneof of KaspadMessage that holds its actual message
+var kaspadMessagePayload = (&protowire.KaspadMessage{}).ProtoReflect().Descriptor().Oneofs().ByName("payload")
+
+// marshalOptions emits unpopulated fields, so that clients always get all the
+// fields of a message, same as they would with the gRPC MessageStream
+var marshalOptions = protojson.MarshalOptions{EmitUnpopulated: true}
+
+// unmarshalPayload decodes a protojson-encoded message of the type of the given
+// KaspadMessage payload field. An empty input decodes into an empty message
+func unmarshalPayload(field protoreflect.FieldDescriptor, data []byte) (appmessage.Message, error) {
+	kaspadMessage := &protowire.KaspadMessage{}
+	payload := kaspadMessage.ProtoReflect().NewField(field)
+	if len(data) > 0 {
+		err := protojson.Unmarshal(data, payload.Message().Interface())
+		if err != nil {
+			return nil, err
+		}
+	}
+	kaspadMessage.ProtoReflect().Set(field, payload)
+
+	return kaspadMessage.ToAppMessage()
+}
+
+// marshalPayload encodes the given message with protojson, without
+// wrapping it in a KaspadMessage
+func marshalPayload(message appmessage.Message) ([]byte, error) {
+	kaspadMessage, err := protowire.FromAppMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	reflectedMessage := kaspadMessage.ProtoReflect()
+	field := reflectedMessage.WhichOneof(kaspadMessagePayload)
+	return marshalOptions.Marshal(reflectedMessage.Get(field).Message().Interface())
+}
+
+// unmarshalKaspadMessage decodes a protojson-encoded KaspadMessage
+func unmarshalKaspadMessage(data []byte) (appmessage.Message, error) {
+	kaspadMessage := &protowire.KaspadMessage{}
+	err := protojson.Unmarshal(data, kaspadMessage)
+	if err != nil {
+		return nil, err
+	}
+	return kaspadMessage.ToAppMessage()
+}
+
+// marshalKaspadMessage encodes the given message with protojson,
+// wrapped in a KaspadMessage
+func marshalKaspadMessage(message appmessage.Message) ([]byte, error) {
+	kaspadMessage, err := protowire.FromAppMessage(message)
+	if err != nil {
+		return nil, err
+	}
+	return marshalOptions.Marshal(kaspadMessage)
+}
+
+// isNotificationRequest returns whether the given name, of either a request
+// message or its command, is of a request to start or stop notifications
+func isNotificationRequest(name string) bool {
+	lowercaseName := strings.ToLower(name)
+	return strings.HasPrefix(lowercaseName, "notify") || strings.HasPrefix(lowercaseName, "stopnotifying")
+}
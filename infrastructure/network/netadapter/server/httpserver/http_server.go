@@ -0,0 +1,172 @@
+package httpserver
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver"
+	"github.com/kaspanet/kaspad/util/panics"
+	"github.com/pkg/errors"
+)
+
+const stopTimeout = 2 * time.Second
+
+// httpServer serves the RPC messages defined in rpc.proto over plain HTTP, for
+// clients that can't easily use the gRPC MessageStream. The connections it
+// creates are passed to the same onConnectedHandler as gRPC RPC connections,
+// so they're served by the same RPC handlers.
+// It's created either by NewHTTPServer or by NewJSONRPCServer, which differ
+// only in the handler that serves the incoming HTTP requests
+type httpServer struct {
+	name               string
+	onConnectedHandler server.OnConnectedHandler
+	listeningAddresses []string
+	handler            http.Handler
+	httpServers        []*http.Server
+
+	maxWebSockets            int
+	webSocketConnections     map[*webSocketConnection]struct{}
+	webSocketConnectionCount int
+	webSocketConnectionsLock sync.Mutex
+}
+
+func newHTTPServer(name string, listeningAddresses []string) *httpServer {
+	return &httpServer{
+		name:                 name,
+		listeningAddresses:   listeningAddresses,
+		webSocketConnections: make(map[*webSocketConnection]struct{}),
+	}
+}
+
+// NewHTTPServer creates a new HTTP RPC server that listens on the given addresses.
+// * Every request message is served by a REST endpoint that accepts the
+//   request and returns the response, both encoded with protojson. See rest.go
+// * webSocketPath is a WebSocket endpoint that carries protojson-encoded
+//   KaspadMessages in both directions, the same way MessageStream does, so
+//   that clients can also subscribe to notifications. See websocket.go
+// At most maxWebSockets WebSocket connections are served at once
+func NewHTTPServer(listeningAddresses []string, maxWebSockets int) (server.Server, error) {
+	s := newHTTPServer("HTTP RPC", listeningAddresses)
+	s.maxWebSockets = maxWebSockets
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(webSocketPath, s.handleWebSocket)
+	mux.HandleFunc("/", s.handleREST)
+	s.handler = mux
+
+	return s, nil
+}
+
+// NewJSONRPCServer creates a new JSON-RPC 2.0 server that listens on the given
+// addresses. See jsonrpc.go
+func NewJSONRPCServer(listeningAddresses []string) (server.Server, error) {
+	s := newHTTPServer("JSON-RPC", listeningAddresses)
+	s.handler = http.HandlerFunc(s.handleJSONRPC)
+
+	return s, nil
+}
+
+func (s *httpServer) Start() error {
+	if s.onConnectedHandler == nil {
+		return errors.New("onConnectedHandler is nil")
+	}
+
+	for _, listenAddress := range s.listeningAddresses {
+		err := s.listenOn(listenAddress)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *httpServer) listenOn(listenAddress string) error {
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return errors.Wrapf(err, "%s error listening on %s", s.name, listenAddress)
+	}
+
+	httpServer := &http.Server{Handler: s.handler}
+	s.httpServers = append(s.httpServers, httpServer)
+
+	spawn(fmt.Sprintf("%s.httpServer.listenOn-Serve", s.name), func() {
+		err := httpServer.Serve(listener)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			panics.Exit(log, fmt.Sprintf("error serving %s on %s: %+v", s.name, listenAddress, err))
+		}
+	})
+
+	log.Infof("%s Server listening on %s", s.name, listenAddress)
+	return nil
+}
+
+func (s *httpServer) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+	defer cancel()
+
+	for _, httpServer := range s.httpServers {
+		err := httpServer.Shutdown(ctx)
+		if err != nil {
+			log.Warnf("Could not gracefully stop %s: %s", s.name, err)
+			err := httpServer.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Shutdown doesn't close hijacked connections, so the WebSocket
+	// connections have to be disconnected separately
+	s.webSocketConnectionsLock.Lock()
+	webSocketConnections := make([]*webSocketConnection, 0, len(s.webSocketConnections))
+	for connection := range s.webSocketConnections {
+		webSocketConnections = append(webSocketConnections, connection)
+	}
+	s.webSocketConnectionsLock.Unlock()
+
+	for _, connection := range webSocketConnections {
+		connection.Disconnect()
+	}
+	return nil
+}
+
+func (s *httpServer) SetOnConnectedHandler(onConnectedHandler server.OnConnectedHandler) {
+	s.onConnectedHandler = onConnectedHandler
+}
+
+func remoteTCPAddress(request *http.Request) (*net.TCPAddr, error) {
+	return net.ResolveTCPAddr("tcp", request.RemoteAddr)
+}
+
+// checkJSONPostRequest makes sure that the given request is a POST request with
+// a JSON content type, and responds with an error if it isn't.
+// Browsers send cross-origin requests with a JSON content type only after a
+// CORS preflight request, which these servers never approve. Requiring it
+// prevents web pages from sending RPC requests through their users' browsers
+func checkJSONPostRequest(writer http.ResponseWriter, request *http.Request) bool {
+	if request.Method != http.MethodPost {
+		writer.Header().Set("Allow", http.MethodPost)
+		http.Error(writer, "RPC requests must be POST requests", http.StatusMethodNotAllowed)
+		return false
+	}
+
+	mediaType, _, err := mime.ParseMediaType(request.Header.Get("Content-Type"))
+	if err != nil || mediaType != "application/json" {
+		http.Error(writer, "RPC requests must have an application/json content type",
+			http.StatusUnsupportedMediaType)
+		return false
+	}
+	return true
+}
+
+func readRequestBody(writer http.ResponseWriter, request *http.Request) ([]byte, error) {
+	return ioutil.ReadAll(http.MaxBytesReader(writer, request.Body, grpcserver.RPCMaxMessageSize))
+}
@@ -0,0 +1,335 @@
+package httpserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver/protowire"
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// The JSON-RPC server serves the RPC requests according to the JSON-RPC 2.0
+// specification (https://www.jsonrpc.org/specification), for tooling that was
+// built for btcd-style nodes.
+// The method of a request is the name of its appmessage RPC command, without the
+// Request suffix and with a lowercase first letter, e.g. getBlockDAGInfo for
+// GetBlockDAGInfoRequest. Methods are matched case-insensitively, so that the
+// names of the REST endpoints, such as getBlockDagInfo, work as well.
+// The params of a request are the fields of its request message encoded with
+// protojson, given either as an object or as an array. An array that holds a
+// single object is that object, and otherwise its items are the values of the
+// fields in the order of their field numbers, e.g. ["<hash>", true] for getBlock.
+// The result is the response message, encoded the same way.
+// Notification requests are not supported, since notifications can't be
+// delivered over plain HTTP.
+
+const jsonRPCVersion = "2.0"
+
+// The error codes defined by the JSON-RPC 2.0 specification
+const (
+	jsonRPCParseError     = -32700
+	jsonRPCInvalidRequest = -32600
+	jsonRPCMethodNotFound = -32601
+	jsonRPCInvalidParams  = -32602
+	jsonRPCInternalError  = -32603
+
+	// jsonRPCRPCError is the code of the errors that the RPC handlers
+	// respond with, that is, of the appmessage.RPCError of a response
+	jsonRPCRPCError = -32000
+)
+
+type jsonRPCRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params"`
+
+	// ID is nil if the request has no id, which means that it's
+	// a JSON-RPC notification, and must not be responded to
+	ID json.RawMessage `json:"id"`
+}
+
+type jsonRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *jsonRPCError   `json:"error,omitempty"`
+	ID      json.RawMessage `json:"id"`
+}
+
+type jsonRPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// jsonRPCMethods maps the lowercase name of every JSON-RPC method
+// to the KaspadMessage payload field of its request message
+var jsonRPCMethods = buildJSONRPCMethods()
+
+func buildJSONRPCMethods() map[string]protoreflect.FieldDescriptor {
+	fieldsByMessageName := make(map[string]protoreflect.FieldDescriptor)
+	fields := kaspadMessagePayload.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if field.Message().ParentFile().Path() == "rpc.proto" {
+			fieldsByMessageName[strings.ToLower(string(field.Message().Name()))] = field
+		}
+	}
+
+	methods := make(map[string]protoreflect.FieldDescriptor)
+	for _, commandString := range appmessage.RPCMessageCommandToString {
+		if !strings.HasSuffix(commandString, "Request") || isNotificationRequest(commandString) {
+			continue
+		}
+		field, ok := fieldsByMessageName[strings.ToLower(commandString+"Message")]
+		if !ok {
+			continue
+		}
+		methods[strings.ToLower(strings.TrimSuffix(commandString, "Request"))] = field
+	}
+	return methods
+}
+
+func (s *httpServer) handleJSONRPC(writer http.ResponseWriter, request *http.Request) {
+	if !checkJSONPostRequest(writer, request) {
+		return
+	}
+
+	body, err := readRequestBody(writer, request)
+	if err != nil {
+		http.Error(writer, fmt.Sprintf("could not read the request body: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	var response interface{}
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		responses := s.handleJSONRPCBatch(request, body)
+		if responses != nil {
+			response = responses
+		}
+	} else {
+		singleResponse := s.handleJSONRPCRequest(request, body)
+		if singleResponse != nil {
+			response = singleResponse
+		}
+	}
+
+	// Nothing is responded to requests that hold only notifications
+	if response == nil {
+		writer.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	err = json.NewEncoder(writer).Encode(response)
+	if err != nil {
+		log.Debugf("Could not write the response to JSON-RPC request from %s: %s", request.RemoteAddr, err)
+	}
+}
+
+// handleJSONRPCBatch handles a batch of JSON-RPC requests. It returns a slice
+// with the response of every request that isn't a notification, a single
+// response if the batch is invalid, or nil if there's nothing to respond
+func (s *httpServer) handleJSONRPCBatch(request *http.Request, body []byte) interface{} {
+	var batch []json.RawMessage
+	err := json.Unmarshal(body, &batch)
+	if err != nil {
+		return newJSONRPCErrorResponse(nil, jsonRPCParseError, err.Error())
+	}
+	if len(batch) == 0 {
+		return newJSONRPCErrorResponse(nil, jsonRPCInvalidRequest, "empty batch")
+	}
+
+	responses := make([]*jsonRPCResponse, 0, len(batch))
+	for _, rawRequest := range batch {
+		response := s.handleJSONRPCRequest(request, rawRequest)
+		if response != nil {
+			responses = append(responses, response)
+		}
+	}
+	if len(responses) == 0 {
+		return nil
+	}
+	return responses
+}
+
+// handleJSONRPCRequest handles a single JSON-RPC request, and returns its
+// response, or nil if the request is a notification
+func (s *httpServer) handleJSONRPCRequest(request *http.Request, rawRequest []byte) *jsonRPCResponse {
+	if !json.Valid(rawRequest) {
+		return newJSONRPCErrorResponse(nil, jsonRPCParseError, "invalid JSON")
+	}
+	var rpcRequest jsonRPCRequest
+	err := json.Unmarshal(rawRequest, &rpcRequest)
+	if err != nil {
+		return newJSONRPCErrorResponse(nil, jsonRPCInvalidRequest, err.Error())
+	}
+	if !isValidJSONRPCID(rpcRequest.ID) {
+		return newJSONRPCErrorResponse(nil, jsonRPCInvalidRequest, "id must be a string, a number or null")
+	}
+	if rpcRequest.JSONRPC != jsonRPCVersion || rpcRequest.Method == "" {
+		return newJSONRPCErrorResponse(rpcRequest.ID, jsonRPCInvalidRequest,
+			fmt.Sprintf("requests must have a method and a jsonrpc member of %s", jsonRPCVersion))
+	}
+
+	response := s.executeJSONRPCRequest(request, &rpcRequest)
+	if rpcRequest.ID == nil {
+		return nil
+	}
+	return response
+}
+
+func (s *httpServer) executeJSONRPCRequest(request *http.Request, rpcRequest *jsonRPCRequest) *jsonRPCResponse {
+	field, ok := jsonRPCMethods[strings.ToLower(rpcRequest.Method)]
+	if !ok {
+		return newJSONRPCErrorResponse(rpcRequest.ID, jsonRPCMethodNotFound,
+			fmt.Sprintf("method %s not found", rpcRequest.Method))
+	}
+
+	params, err := jsonRPCParamsObject(field.Message(), rpcRequest.Params)
+	if err != nil {
+		return newJSONRPCErrorResponse(rpcRequest.ID, jsonRPCInvalidParams, err.Error())
+	}
+	requestMessage, err := unmarshalPayload(field, params)
+	if err != nil {
+		return newJSONRPCErrorResponse(rpcRequest.ID, jsonRPCInvalidParams, err.Error())
+	}
+
+	responseMessage, err := s.handleRequest(request, requestMessage)
+	if err != nil {
+		log.Warnf("Could not handle JSON-RPC request %s from %s: %s", rpcRequest.Method, request.RemoteAddr, err)
+		return newJSONRPCErrorResponse(rpcRequest.ID, jsonRPCInternalError, err.Error())
+	}
+	result, rpcError, err := marshalJSONRPCResult(responseMessage)
+	if err != nil {
+		log.Warnf("Could not encode the response to JSON-RPC request %s: %s", rpcRequest.Method, err)
+		return newJSONRPCErrorResponse(rpcRequest.ID, jsonRPCInternalError, err.Error())
+	}
+	if rpcError != nil {
+		return newJSONRPCErrorResponse(rpcRequest.ID, jsonRPCRPCError, rpcError.Message)
+	}
+
+	return &jsonRPCResponse{
+		JSONRPC: jsonRPCVersion,
+		Result:  result,
+		ID:      rpcRequest.ID,
+	}
+}
+
+func newJSONRPCErrorResponse(id json.RawMessage, code int, message string) *jsonRPCResponse {
+	return &jsonRPCResponse{
+		JSONRPC: jsonRPCVersion,
+		Error: &jsonRPCError{
+			Code:    code,
+			Message: message,
+		},
+		ID: id,
+	}
+}
+
+func isValidJSONRPCID(id json.RawMessage) bool {
+	if id == nil {
+		return true
+	}
+	var value interface{}
+	err := json.Unmarshal(id, &value)
+	if err != nil {
+		return false
+	}
+	switch value.(type) {
+	case nil, string, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+// jsonRPCParamsObject returns the params of a JSON-RPC request for the given request
+// message as a JSON object, or nil if the request has no params
+func jsonRPCParamsObject(requestMessage protoreflect.MessageDescriptor, params json.RawMessage) ([]byte, error) {
+	params = bytes.TrimSpace(params)
+	if len(params) == 0 || bytes.Equal(params, []byte("null")) {
+		return nil, nil
+	}
+	switch params[0] {
+	case '{':
+		return params, nil
+	case '[':
+		var positionalParams []json.RawMessage
+		err := json.Unmarshal(params, &positionalParams)
+		if err != nil {
+			return nil, err
+		}
+		if len(positionalParams) == 0 {
+			return nil, nil
+		}
+		if len(positionalParams) == 1 {
+			param := bytes.TrimSpace(positionalParams[0])
+			if len(param) > 0 && param[0] == '{' {
+				return param, nil
+			}
+		}
+		return positionalParamsObject(requestMessage, positionalParams)
+	}
+	return nil, errors.New("params must be an object or an array")
+}
+
+// positionalParamsObject maps the given positional params onto the fields of the
+// given request message in the order of their field numbers, and returns them
+// as a JSON object. Null params leave their fields unset
+func positionalParamsObject(requestMessage protoreflect.MessageDescriptor,
+	positionalParams []json.RawMessage) ([]byte, error) {
+
+	fields := requestMessage.Fields()
+	if len(positionalParams) > fields.Len() {
+		return nil, errors.Errorf("%s takes at most %d params, but got %d",
+			requestMessage.Name(), fields.Len(), len(positionalParams))
+	}
+
+	fieldsByNumber := make([]protoreflect.FieldDescriptor, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		fieldsByNumber[i] = fields.Get(i)
+	}
+	sort.Slice(fieldsByNumber, func(i, j int) bool {
+		return fieldsByNumber[i].Number() < fieldsByNumber[j].Number()
+	})
+
+	paramsObject := make(map[string]json.RawMessage, len(positionalParams))
+	for i, param := range positionalParams {
+		if bytes.Equal(bytes.TrimSpace(param), []byte("null")) {
+			continue
+		}
+		paramsObject[fieldsByNumber[i].JSONName()] = param
+	}
+	return json.Marshal(paramsObject)
+}
+
+// marshalJSONRPCResult encodes the given response message with protojson. If the
+// response holds an error, that error is returned instead
+func marshalJSONRPCResult(message appmessage.Message) (json.RawMessage, *protowire.RPCError, error) {
+	kaspadMessage, err := protowire.FromAppMessage(message)
+	if err != nil {
+		return nil, nil, err
+	}
+	reflectedMessage := kaspadMessage.ProtoReflect()
+	payload := reflectedMessage.Get(reflectedMessage.WhichOneof(kaspadMessagePayload)).Message()
+
+	errorField := payload.Descriptor().Fields().ByName("error")
+	if errorField != nil && payload.Has(errorField) {
+		rpcError, ok := payload.Get(errorField).Message().Interface().(*protowire.RPCError)
+		if ok {
+			return nil, rpcError, nil
+		}
+	}
+
+	result, err := marshalOptions.Marshal(payload.Interface())
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, nil, nil
+}
@@ -0,0 +1,122 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+)
+
+func TestJSONRPCMethods(t *testing.T) {
+	// Every RPC request, other than the notification requests, should have a method
+	for _, commandString := range appmessage.RPCMessageCommandToString {
+		if !strings.HasSuffix(commandString, "Request") || isNotificationRequest(commandString) {
+			continue
+		}
+		method := strings.ToLower(strings.TrimSuffix(commandString, "Request"))
+		if _, ok := jsonRPCMethods[method]; !ok {
+			t.Errorf("Command %s has no JSON-RPC method", commandString)
+		}
+	}
+
+	for _, method := range []string{"notifyblockadded", "stopnotifyingutxoschanged"} {
+		if _, ok := jsonRPCMethods[method]; ok {
+			t.Errorf("Notification request %s unexpectedly has a JSON-RPC method", method)
+		}
+	}
+
+	field, ok := jsonRPCMethods["getblockdaginfo"]
+	if !ok || field.JSONName() != "getBlockDagInfoRequest" {
+		t.Fatalf("Expected getBlockDagInfo to map to the getBlockDagInfoRequest field")
+	}
+}
+
+func TestJSONRPCParamsObject(t *testing.T) {
+	getBlockRequest := jsonRPCMethods["getblock"].Message()
+
+	tests := []struct {
+		params        string
+		expected      string
+		expectedError bool
+	}{
+		{params: "", expected: ""},
+		{params: "null", expected: ""},
+		{params: "[]", expected: ""},
+		{params: `{"hash": "00"}`, expected: `{"hash": "00"}`},
+		{params: `[{"hash": "00"}]`, expected: `{"hash": "00"}`},
+		{params: `["00"]`, expected: `{"hash":"00"}`},
+		{params: `["00", true]`, expected: `{"hash":"00","includeTransactionVerboseData":true}`},
+		{params: `[null, true]`, expected: `{"includeTransactionVerboseData":true}`},
+		{params: `["00", true, 1]`, expectedError: true},
+		{params: `"00"`, expectedError: true},
+	}
+
+	for _, test := range tests {
+		params, err := jsonRPCParamsObject(getBlockRequest, json.RawMessage(test.params))
+		if test.expectedError {
+			if err == nil {
+				t.Errorf("Expected an error for params %s", test.params)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Unexpected error for params %s: %s", test.params, err)
+			continue
+		}
+		if string(params) != test.expected {
+			t.Errorf("Expected params %s to be parsed as %s, but got %s", test.params, test.expected, params)
+		}
+	}
+}
+
+func TestIsValidJSONRPCID(t *testing.T) {
+	tests := []struct {
+		id       json.RawMessage
+		expected bool
+	}{
+		{id: nil, expected: true},
+		{id: json.RawMessage(`null`), expected: true},
+		{id: json.RawMessage(`1`), expected: true},
+		{id: json.RawMessage(`"abc"`), expected: true},
+		{id: json.RawMessage(`{}`), expected: false},
+		{id: json.RawMessage(`[1]`), expected: false},
+		{id: json.RawMessage(`true`), expected: false},
+	}
+
+	for _, test := range tests {
+		if isValidJSONRPCID(test.id) != test.expected {
+			t.Errorf("Expected isValidJSONRPCID(%s) to be %t", test.id, test.expected)
+		}
+	}
+}
+
+func TestJSONRPCPositionalParams(t *testing.T) {
+	field := jsonRPCMethods["getblock"]
+	params, err := jsonRPCParamsObject(field.Message(), json.RawMessage(`["0a", true]`))
+	if err != nil {
+		t.Fatalf("jsonRPCParamsObject: %s", err)
+	}
+	message, err := unmarshalPayload(field, params)
+	if err != nil {
+		t.Fatalf("unmarshalPayload: %s", err)
+	}
+	request, ok := message.(*appmessage.GetBlockRequestMessage)
+	if !ok {
+		t.Fatalf("Expected a GetBlockRequestMessage, but got %T", message)
+	}
+	if request.Hash != "0a" || !request.IncludeTransactionVerboseData {
+		t.Fatalf("Expected the positional params to set the hash and includeTransactionVerboseData, "+
+			"but got %+v", request)
+	}
+
+	// A param of the wrong type for its field is rejected
+	params, err = jsonRPCParamsObject(field.Message(), json.RawMessage(`[true, "0a"]`))
+	if err != nil {
+		t.Fatalf("jsonRPCParamsObject: %s", err)
+	}
+	_, err = unmarshalPayload(field, params)
+	if err == nil {
+		t.Fatalf("Expected params of the wrong types to be rejected")
+	}
+}
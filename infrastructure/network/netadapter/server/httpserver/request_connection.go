@@ -0,0 +1,94 @@
+package httpserver
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
+)
+
+// requestTimeout is the time after which a request that arrived over
+// HTTP and that the RPC handlers didn't respond to is aborted
+const requestTimeout = 2 * time.Minute
+
+// handleRequest passes the given request message to the RPC handlers through
+// a new connection that lives only until the response arrives
+func (s *httpServer) handleRequest(request *http.Request, requestMessage appmessage.Message) (
+	appmessage.Message, error) {
+
+	address, err := remoteTCPAddress(request)
+	if err != nil {
+		return nil, err
+	}
+	connection := &requestConnection{
+		address:     address,
+		isConnected: 1,
+	}
+	err = s.onConnectedHandler(connection)
+	if err != nil {
+		return nil, err
+	}
+	defer connection.Disconnect()
+
+	requestMessage.SetMessageNumber(1)
+	requestMessage.SetReceivedAt(time.Now())
+	log.Debugf("incoming '%s' message from %s", requestMessage.Command(), connection)
+
+	err = connection.router.EnqueueIncomingMessage(requestMessage)
+	if err != nil {
+		return nil, err
+	}
+	return connection.router.OutgoingRoute().DequeueWithTimeout(requestTimeout)
+}
+
+// requestConnection is the connection through which a single request that arrived
+// over HTTP is passed to the RPC handlers. Its router is read directly by handleRequest
+type requestConnection struct {
+	address               *net.TCPAddr
+	router                *router.Router
+	onDisconnectedHandler server.OnDisconnectedHandler
+	isConnected           uint32
+}
+
+func (c *requestConnection) Start(router *router.Router) {
+	c.router = router
+}
+
+func (c *requestConnection) String() string {
+	return fmt.Sprintf("HTTP %s", c.address)
+}
+
+func (c *requestConnection) Disconnect() {
+	if !atomic.CompareAndSwapUint32(&c.isConnected, 1, 0) {
+		return
+	}
+	if c.onDisconnectedHandler != nil {
+		c.onDisconnectedHandler()
+	}
+}
+
+func (c *requestConnection) IsConnected() bool {
+	return atomic.LoadUint32(&c.isConnected) != 0
+}
+
+func (c *requestConnection) IsOutbound() bool {
+	return false
+}
+
+func (c *requestConnection) SetOnDisconnectedHandler(onDisconnectedHandler server.OnDisconnectedHandler) {
+	c.onDisconnectedHandler = onDisconnectedHandler
+}
+
+// SetOnInvalidMessageHandler does nothing, since the request of a
+// requestConnection is parsed before the connection is created
+func (c *requestConnection) SetOnInvalidMessageHandler(_ server.OnInvalidMessageHandler) {
+}
+
+func (c *requestConnection) Address() *net.TCPAddr {
+	return c.address
+}
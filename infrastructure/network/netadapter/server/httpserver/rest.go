@@ -0,0 +1,74 @@
+package httpserver
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// restEndpoints maps the path of every REST endpoint to the KaspadMessage payload
+// field of its request message. The path of an endpoint is the JSON name of the
+// field without its Request suffix, e.g. /getBlockDagInfo for getBlockDagInfoRequest.
+// Notification requests have no REST endpoints, since notifications can only be
+// delivered over a WebSocket
+var restEndpoints = buildRESTEndpoints()
+
+func buildRESTEndpoints() map[string]protoreflect.FieldDescriptor {
+	endpoints := make(map[string]protoreflect.FieldDescriptor)
+	fields := kaspadMessagePayload.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if field.Message().ParentFile().Path() != "rpc.proto" {
+			continue
+		}
+		name := field.JSONName()
+		if !strings.HasSuffix(name, "Request") || isNotificationRequest(name) {
+			continue
+		}
+		endpoints["/"+strings.TrimSuffix(name, "Request")] = field
+	}
+	return endpoints
+}
+
+func (s *httpServer) handleREST(writer http.ResponseWriter, request *http.Request) {
+	field, ok := restEndpoints[request.URL.Path]
+	if !ok {
+		http.Error(writer, fmt.Sprintf("unknown RPC endpoint %s", request.URL.Path), http.StatusNotFound)
+		return
+	}
+	if !checkJSONPostRequest(writer, request) {
+		return
+	}
+
+	body, err := readRequestBody(writer, request)
+	if err != nil {
+		http.Error(writer, fmt.Sprintf("could not read the request body: %s", err), http.StatusBadRequest)
+		return
+	}
+	requestMessage, err := unmarshalPayload(field, body)
+	if err != nil {
+		http.Error(writer, fmt.Sprintf("could not parse the request: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	responseMessage, err := s.handleRequest(request, requestMessage)
+	if err != nil {
+		log.Warnf("Could not handle REST request %s from %s: %s", request.URL.Path, request.RemoteAddr, err)
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	response, err := marshalPayload(responseMessage)
+	if err != nil {
+		log.Warnf("Could not encode the response to REST request %s: %s", request.URL.Path, err)
+		http.Error(writer, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	_, err = writer.Write(response)
+	if err != nil {
+		log.Debugf("Could not write the response to REST request %s: %s", request.URL.Path, err)
+	}
+}
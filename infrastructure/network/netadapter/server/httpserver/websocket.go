@@ -0,0 +1,252 @@
+package httpserver
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server"
+	"github.com/kaspanet/kaspad/infrastructure/network/netadapter/server/grpcserver"
+	"github.com/pkg/errors"
+	"golang.org/x/net/websocket"
+)
+
+// webSocketPath is the path of the WebSocket endpoint. Every text frame sent
+// over it, in either direction, is a protojson-encoded KaspadMessage
+const webSocketPath = "/ws"
+
+func (s *httpServer) handleWebSocket(writer http.ResponseWriter, request *http.Request) {
+	if !s.reserveWebSocketConnection() {
+		http.Error(writer, fmt.Sprintf("the maximum of %d WebSocket connections is reached", s.maxWebSockets),
+			http.StatusServiceUnavailable)
+		return
+	}
+	defer s.releaseWebSocketConnection()
+
+	webSocketServer := websocket.Server{
+		Handshake: checkOrigin,
+		Handler: func(conn *websocket.Conn) {
+			s.serveWebSocket(conn, request)
+		},
+	}
+	webSocketServer.ServeHTTP(writer, request)
+}
+
+// checkOrigin rejects the WebSocket connections that web pages open through
+// their users' browsers to a different origin. Clients other than browsers
+// don't send an Origin header, and are always accepted
+func checkOrigin(_ *websocket.Config, request *http.Request) error {
+	originHeader := request.Header.Get("Origin")
+	if originHeader == "" {
+		return nil
+	}
+	origin, err := url.Parse(originHeader)
+	if err != nil {
+		return err
+	}
+	if origin.Host != request.Host {
+		return errors.Errorf("cross-origin WebSocket connection from %s", originHeader)
+	}
+	return nil
+}
+
+func (s *httpServer) reserveWebSocketConnection() bool {
+	s.webSocketConnectionsLock.Lock()
+	defer s.webSocketConnectionsLock.Unlock()
+
+	if s.webSocketConnectionCount >= s.maxWebSockets {
+		return false
+	}
+	s.webSocketConnectionCount++
+	return true
+}
+
+func (s *httpServer) releaseWebSocketConnection() {
+	s.webSocketConnectionsLock.Lock()
+	defer s.webSocketConnectionsLock.Unlock()
+
+	s.webSocketConnectionCount--
+}
+
+// serveWebSocket serves the given WebSocket until it's disconnected.
+// It must not return before that, since the WebSocket is closed once it does
+func (s *httpServer) serveWebSocket(conn *websocket.Conn, request *http.Request) {
+	address, err := remoteTCPAddress(request)
+	if err != nil {
+		log.Warnf("Could not serve WebSocket connection from %s: %s", request.RemoteAddr, err)
+		return
+	}
+	conn.MaxPayloadBytes = grpcserver.RPCMaxMessageSize
+	connection := &webSocketConnection{
+		conn:        conn,
+		address:     address,
+		stopChan:    make(chan struct{}),
+		isConnected: 1,
+	}
+
+	s.webSocketConnectionsLock.Lock()
+	s.webSocketConnections[connection] = struct{}{}
+	s.webSocketConnectionsLock.Unlock()
+	defer func() {
+		s.webSocketConnectionsLock.Lock()
+		delete(s.webSocketConnections, connection)
+		s.webSocketConnectionsLock.Unlock()
+	}()
+
+	err = s.onConnectedHandler(connection)
+	if err != nil {
+		log.Warnf("Could not serve WebSocket connection from %s: %s", address, err)
+		return
+	}
+	<-connection.stopChan
+}
+
+type webSocketConnection struct {
+	conn                    *websocket.Conn
+	address                 *net.TCPAddr
+	router                  *router.Router
+	stopChan                chan struct{}
+	onDisconnectedHandler   server.OnDisconnectedHandler
+	onInvalidMessageHandler server.OnInvalidMessageHandler
+	isConnected             uint32
+}
+
+func (c *webSocketConnection) Start(router *router.Router) {
+	if c.onDisconnectedHandler == nil {
+		panic(errors.New("onDisconnectedHandler is nil"))
+	}
+
+	c.router = router
+
+	spawn("webSocketConnection.Start-connectionLoops", func() {
+		err := c.connectionLoops()
+		if err != nil {
+			log.Warnf("Error from connectionLoops for %s: %s", c, err)
+		}
+	})
+}
+
+func (c *webSocketConnection) connectionLoops() error {
+	errChan := make(chan error, 1) // buffered channel because one of the loops might try write after disconnect
+
+	spawn("webSocketConnection.receiveLoop", func() { errChan <- c.receiveLoop() })
+	spawn("webSocketConnection.sendLoop", func() { errChan <- c.sendLoop() })
+
+	err := <-errChan
+
+	c.Disconnect()
+
+	return err
+}
+
+func (c *webSocketConnection) sendLoop() error {
+	outgoingRoute := c.router.OutgoingRoute()
+	for c.IsConnected() {
+		message, err := outgoingRoute.Dequeue()
+		if err != nil {
+			if errors.Is(err, router.ErrRouteClosed) {
+				return nil
+			}
+			return err
+		}
+
+		log.Debugf("outgoing '%s' message to %s", message.Command(), c)
+
+		data, err := marshalKaspadMessage(message)
+		if err != nil {
+			return err
+		}
+		err = websocket.Message.Send(c.conn, string(data))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *webSocketConnection) receiveLoop() error {
+	messageNumber := uint64(0)
+	for c.IsConnected() {
+		var data []byte
+		err := websocket.Message.Receive(c.conn, &data)
+		if err != nil {
+			if err == io.EOF || !c.IsConnected() {
+				return nil
+			}
+			return err
+		}
+		message, err := unmarshalKaspadMessage(data)
+		if err != nil {
+			if c.onInvalidMessageHandler != nil {
+				c.onInvalidMessageHandler(err)
+			}
+			return err
+		}
+
+		messageNumber++
+		message.SetMessageNumber(messageNumber)
+		message.SetReceivedAt(time.Now())
+
+		log.Debugf("incoming '%s' message from %s (message number %d)", message.Command(), c,
+			message.MessageNumber())
+
+		err = c.router.EnqueueIncomingMessage(message)
+		if err != nil {
+			if errors.Is(err, router.ErrRouteClosed) {
+				return nil
+			}
+			if c.onInvalidMessageHandler != nil {
+				c.onInvalidMessageHandler(err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *webSocketConnection) String() string {
+	return fmt.Sprintf("WebSocket %s", c.address)
+}
+
+// Disconnect disconnects the connection
+// Calling this function a second time doesn't do anything
+//
+// This is part of the Connection interface
+func (c *webSocketConnection) Disconnect() {
+	if !atomic.CompareAndSwapUint32(&c.isConnected, 1, 0) {
+		return
+	}
+
+	close(c.stopChan)
+	_ = c.conn.Close()
+
+	log.Debugf("Disconnecting from %s", c)
+	if c.onDisconnectedHandler != nil {
+		c.onDisconnectedHandler()
+	}
+}
+
+func (c *webSocketConnection) IsConnected() bool {
+	return atomic.LoadUint32(&c.isConnected) != 0
+}
+
+func (c *webSocketConnection) IsOutbound() bool {
+	return false
+}
+
+func (c *webSocketConnection) SetOnDisconnectedHandler(onDisconnectedHandler server.OnDisconnectedHandler) {
+	c.onDisconnectedHandler = onDisconnectedHandler
+}
+
+func (c *webSocketConnection) SetOnInvalidMessageHandler(onInvalidMessageHandler server.OnInvalidMessageHandler) {
+	c.onInvalidMessageHandler = onInvalidMessageHandler
+}
+
+func (c *webSocketConnection) Address() *net.TCPAddr {
+	return c.address
+}
@@ -0,0 +1,302 @@
+package rpcclient
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/kaspanet/kaspad/infrastructure/network/rpcclient/grpcclient"
+	"github.com/pkg/errors"
+)
+
+const (
+	minReconnectInterval = 500 * time.Millisecond
+	maxReconnectInterval = time.Minute
+)
+
+// ErrClientClosed indicates that a call was made after the client was closed
+var ErrClientClosed = errors.New("rpc client is closed")
+
+// ErrConnectionLost indicates that the connection to the RPC server was lost
+// while waiting for a response. Calls that are made afterwards wait for the
+// client to reconnect
+var ErrConnectionLost = errors.New("connection to the rpc server was lost")
+
+// ConnectionState is the state of the connection of an RPCClient to the RPC server
+type ConnectionState byte
+
+// ConnectionState constants
+const (
+	// ConnectionStateConnected means that the client reconnected to the
+	// RPC server, and re-issued its notification registrations
+	ConnectionStateConnected ConnectionState = iota
+
+	// ConnectionStateDisconnected means that the connection to the RPC
+	// server was lost, and that the client is reconnecting
+	ConnectionStateDisconnected
+
+	// ConnectionStateClosed means that the client was closed
+	ConnectionStateClosed
+)
+
+var connectionStateToString = map[ConnectionState]string{
+	ConnectionStateConnected:    "Connected",
+	ConnectionStateDisconnected: "Disconnected",
+	ConnectionStateClosed:       "Closed",
+}
+
+func (s ConnectionState) String() string {
+	return connectionStateToString[s]
+}
+
+// OnConnectionStateChangedHandler is a handler function that's called
+// whenever the connection of an RPCClient changes its state
+type OnConnectionStateChangedHandler func(state ConnectionState)
+
+// connection is the state that's shared by an RPCClient and the
+// copies of it that WithContext returns
+type connection struct {
+	rpcAddress string
+
+	// timeout is the time.Duration to wait for RPC responses. It's
+	// accessed atomically, since copies made by WithContext share it
+	timeout int64
+
+	// grpcClient and rpcRouter are replaced whenever the client reconnects.
+	// connected is closed once they're usable, and replaced with an open
+	// channel once the connection is lost
+	connectionLock sync.RWMutex
+	grpcClient     *grpcclient.GRPCClient
+	rpcRouter      *rpcRouter
+	connected      chan struct{}
+
+	closeOnce sync.Once
+	closed    chan struct{}
+
+	// subscriptionsLock is held while subscriptions are issued,
+	// so that registrations don't interleave with resubscribing
+	subscriptionsLock sync.Mutex
+	subscriptions     []*subscription
+
+	// onErrorHandler and onDisconnectedHandler are the handlers of the API RPCClient
+	// had before it reconnected. They're called on connection state changes as well
+	onConnectionStateChangedHandlerLock sync.RWMutex
+	onConnectionStateChangedHandler     OnConnectionStateChangedHandler
+	onErrorHandler                      grpcclient.OnErrorHandler
+	onDisconnectedHandler               grpcclient.OnDisconnectedHandler
+
+	onNotificationsLostHandlerLock sync.RWMutex
+	onNotificationsLostHandler     OnNotificationsLostHandler
+}
+
+func newConnection(rpcAddress string) *connection {
+	return &connection{
+		rpcAddress: rpcAddress,
+		timeout:    int64(defaultTimeout),
+		connected:  make(chan struct{}),
+		closed:     make(chan struct{}),
+	}
+}
+
+// SetOnConnectionStateChangedHandler sets the handler that's called whenever the
+// client loses its connection to the RPC server, restores it, or is closed
+func (c *connection) SetOnConnectionStateChangedHandler(handler OnConnectionStateChangedHandler) {
+	c.onConnectionStateChangedHandlerLock.Lock()
+	defer c.onConnectionStateChangedHandlerLock.Unlock()
+
+	c.onConnectionStateChangedHandler = handler
+}
+
+// SetOnErrorHandler sets a handler that's called with ErrConnectionLost whenever the
+// client loses its connection to the RPC server. The client reconnects by itself
+//
+// Deprecated: Use SetOnConnectionStateChangedHandler instead
+func (c *connection) SetOnErrorHandler(onErrorHandler grpcclient.OnErrorHandler) {
+	c.onConnectionStateChangedHandlerLock.Lock()
+	defer c.onConnectionStateChangedHandlerLock.Unlock()
+
+	c.onErrorHandler = onErrorHandler
+}
+
+// SetOnDisconnectedHandler sets a handler that's called whenever the client loses
+// its connection to the RPC server. The client reconnects by itself
+//
+// Deprecated: Use SetOnConnectionStateChangedHandler instead
+func (c *connection) SetOnDisconnectedHandler(onDisconnectedHandler grpcclient.OnDisconnectedHandler) {
+	c.onConnectionStateChangedHandlerLock.Lock()
+	defer c.onConnectionStateChangedHandlerLock.Unlock()
+
+	c.onDisconnectedHandler = onDisconnectedHandler
+}
+
+func (c *connection) notifyConnectionStateChanged(state ConnectionState) {
+	c.onConnectionStateChangedHandlerLock.RLock()
+	handler := c.onConnectionStateChangedHandler
+	onErrorHandler := c.onErrorHandler
+	onDisconnectedHandler := c.onDisconnectedHandler
+	c.onConnectionStateChangedHandlerLock.RUnlock()
+
+	if handler != nil {
+		handler(state)
+	}
+	if state != ConnectionStateDisconnected {
+		return
+	}
+	if onErrorHandler != nil {
+		onErrorHandler(errors.Wrapf(ErrConnectionLost, "lost connection to %s", c.rpcAddress))
+	}
+	if onDisconnectedHandler != nil {
+		onDisconnectedHandler()
+	}
+}
+
+func (c *connection) getTimeout() time.Duration {
+	return time.Duration(atomic.LoadInt64(&c.timeout))
+}
+
+func (c *connection) setTimeout(timeout time.Duration) {
+	atomic.StoreInt64(&c.timeout, int64(timeout))
+}
+
+// Close closes the RPC client. Calls that wait for a response fail,
+// and the client no longer reconnects
+func (c *connection) Close() {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+
+		c.connectionLock.Lock()
+		c.rpcRouter.router.Close()
+		c.connectionLock.Unlock()
+
+		c.notifyConnectionStateChanged(ConnectionStateClosed)
+	})
+}
+
+// Disconnect closes the RPC client. It always returns nil
+//
+// Deprecated: Use Close instead
+func (c *connection) Disconnect() error {
+	c.Close()
+	return nil
+}
+
+func (c *connection) isClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// connect connects to the RPC server, and makes the new connection
+// the one calls are sent through
+func (c *connection) connect() error {
+	grpcClient, err := grpcclient.Connect(c.rpcAddress)
+	if err != nil {
+		return errors.Wrapf(err, "error connecting to address %s", c.rpcAddress)
+	}
+	rpcRouter, err := buildRPCRouter()
+	if err != nil {
+		return errors.Wrapf(err, "error creating the RPC router")
+	}
+	grpcClient.SetOnDisconnectedHandler(func() {
+		c.handleDisconnection(grpcClient)
+	})
+	grpcClient.AttachRouter(rpcRouter.router)
+
+	c.connectionLock.Lock()
+	defer c.connectionLock.Unlock()
+
+	// The client might have been closed while connecting
+	if c.isClosed() {
+		rpcRouter.router.Close()
+		return errors.WithStack(ErrClientClosed)
+	}
+	c.grpcClient = grpcClient
+	c.rpcRouter = rpcRouter
+	close(c.connected)
+	return nil
+}
+
+// handleDisconnection is called once the stream of the given gRPC client ends. Unless
+// the client was closed, it fails the calls that wait for a response and reconnects
+func (c *connection) handleDisconnection(grpcClient *grpcclient.GRPCClient) {
+	if c.isClosed() {
+		return
+	}
+
+	c.connectionLock.Lock()
+	if grpcClient != c.grpcClient {
+		c.connectionLock.Unlock()
+		return
+	}
+	c.rpcRouter.router.Close()
+	c.connected = make(chan struct{})
+	c.connectionLock.Unlock()
+
+	log.Warnf("Lost connection to %s. Reconnecting", c.rpcAddress)
+	c.notifyConnectionStateChanged(ConnectionStateDisconnected)
+
+	spawn("connection.handleDisconnection-reconnect", c.reconnect)
+}
+
+// reconnect tries to connect to the RPC server with exponential
+// back-off until it succeeds, and then re-issues the subscriptions
+func (c *connection) reconnect() {
+	reconnectInterval := minReconnectInterval
+	for {
+		err := c.connect()
+		if err == nil {
+			break
+		}
+		if errors.Is(err, ErrClientClosed) {
+			return
+		}
+
+		log.Warnf("Could not reconnect to %s: %s. Trying again in %s", c.rpcAddress, err, reconnectInterval)
+		select {
+		case <-time.After(reconnectInterval):
+		case <-c.closed:
+			return
+		}
+		reconnectInterval *= 2
+		if reconnectInterval > maxReconnectInterval {
+			reconnectInterval = maxReconnectInterval
+		}
+	}
+
+	log.Infof("Reconnected to %s", c.rpcAddress)
+	c.resubscribe()
+	c.notifyConnectionStateChanged(ConnectionStateConnected)
+}
+
+// waitForConnection waits until the client is connected or ctx
+// is done, and returns the router that calls should use
+func (c *connection) waitForConnection(ctx context.Context) (*rpcRouter, error) {
+	for {
+		if c.isClosed() {
+			return nil, errors.WithStack(ErrClientClosed)
+		}
+
+		c.connectionLock.RLock()
+		rpcRouter, connected := c.rpcRouter, c.connected
+		c.connectionLock.RUnlock()
+
+		select {
+		case <-connected:
+		default:
+			// Wait for the connection, and then get its router
+			select {
+			case <-connected:
+				continue
+			case <-c.closed:
+				return nil, errors.WithStack(ErrClientClosed)
+			case <-ctx.Done():
+				return nil, errors.WithStack(ctx.Err())
+			}
+		}
+		return rpcRouter, nil
+	}
+}
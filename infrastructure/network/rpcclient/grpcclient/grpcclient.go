@@ -16,10 +16,16 @@ import (
 // OnErrorHandler defines a handler function for when errors occur
 type OnErrorHandler func(err error)
 
+// OnDisconnectedHandler defines a handler function for when the stream
+// to the RPC server of an attached router ends
+type OnDisconnectedHandler func()
+
 // GRPCClient is a gRPC-based RPC client
 type GRPCClient struct {
-	stream         protowire.RPC_MessageStreamClient
-	onErrorHandler OnErrorHandler
+	connection            *grpc.ClientConn
+	stream                protowire.RPC_MessageStreamClient
+	onErrorHandler        OnErrorHandler
+	onDisconnectedHandler OnDisconnectedHandler
 }
 
 // Connect connects to the RPC server with the given address
@@ -37,9 +43,13 @@ func Connect(address string) (*GRPCClient, error) {
 	stream, err := grpcClient.MessageStream(context.Background(), grpc.UseCompressor(gzip.Name),
 		grpc.MaxCallRecvMsgSize(grpcserver.RPCMaxMessageSize), grpc.MaxCallSendMsgSize(grpcserver.RPCMaxMessageSize))
 	if err != nil {
+		closeErr := gRPCConnection.Close()
+		if closeErr != nil {
+			log.Warnf("Error closing the connection to %s: %s", address, closeErr)
+		}
 		return nil, errors.Wrapf(err, "error getting client stream for %s", address)
 	}
-	return &GRPCClient{stream: stream}, nil
+	return &GRPCClient{connection: gRPCConnection, stream: stream}, nil
 }
 
 // Disconnect disconnects from the RPC server
@@ -52,6 +62,11 @@ func (c *GRPCClient) SetOnErrorHandler(onErrorHandler OnErrorHandler) {
 	c.onErrorHandler = onErrorHandler
 }
 
+// SetOnDisconnectedHandler sets the client's onDisconnectedHandler
+func (c *GRPCClient) SetOnDisconnectedHandler(onDisconnectedHandler OnDisconnectedHandler) {
+	c.onDisconnectedHandler = onDisconnectedHandler
+}
+
 // AttachRouter attaches the given router to the client and starts
 // sending/receiving messages via it
 func (c *GRPCClient) AttachRouter(router *router.Router) {
@@ -73,7 +88,7 @@ func (c *GRPCClient) AttachRouter(router *router.Router) {
 		for {
 			message, err := c.receive()
 			if err != nil {
-				c.handleError(err)
+				c.handleDisconnection(err)
 				return
 			}
 			err = router.EnqueueIncomingMessage(message)
@@ -101,6 +116,23 @@ func (c *GRPCClient) receive() (appmessage.Message, error) {
 	return response.ToAppMessage()
 }
 
+// handleDisconnection is called once the stream ends, either because the
+// client disconnected, or because of the given error
+func (c *GRPCClient) handleDisconnection(err error) {
+	closeErr := c.connection.Close()
+	if closeErr != nil {
+		log.Warnf("Error closing the connection: %s", closeErr)
+	}
+	if c.onDisconnectedHandler != nil {
+		if !errors.Is(err, io.EOF) {
+			log.Warnf("Disconnected from the RPC server: %s", err)
+		}
+		c.onDisconnectedHandler()
+		return
+	}
+	c.handleError(err)
+}
+
 func (c *GRPCClient) handleError(err error) {
 	if errors.Is(err, io.EOF) {
 		return
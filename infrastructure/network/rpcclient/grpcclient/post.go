@@ -62,3 +62,14 @@ func (c *GRPCClient) Post(request *protowire.KaspadMessage) (*protowire.KaspadMe
 	}
 	return response, nil
 }
+
+// ReceiveMessage blocks until the next message arrives from the RPC
+// server and returns it. It is meant to be used to read notifications
+// once a Notify request was sent with Post
+func (c *GRPCClient) ReceiveMessage() (*protowire.KaspadMessage, error) {
+	message, err := c.stream.Recv()
+	if err != nil {
+		return nil, errors.Wrapf(err, "error receiving a message from the RPC server")
+	}
+	return message, nil
+}
@@ -0,0 +1,45 @@
+package rpcclient
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+	routerpkg "github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
+	"github.com/pkg/errors"
+)
+
+// startNotificationHandler spawns a goroutine that calls onNotification with every
+// notification of the given command that arrives through the given router. It's
+// meant to be called before the respective notify request is sent, since the
+// notifications that the server replays precede its response, and there may be
+// more of them than the route could hold.
+// The goroutine exits once the router is closed. The returned function stops it
+// earlier, and should be called if the registration failed or was unregistered
+func startNotificationHandler(rpcRouter *rpcRouter, name string, command appmessage.MessageCommand,
+	onNotification func(notification appmessage.Message)) (stop func()) {
+
+	route := rpcRouter.route(command)
+	spawn(name, func() {
+		for {
+			notification, err := route.Dequeue()
+			if err != nil {
+				if errors.Is(err, routerpkg.ErrRouteClosed) {
+					break
+				}
+				panic(err)
+			}
+			// A nil notification is enqueued by stop. The server sends
+			// no notifications of a failed registration, so there are no
+			// notifications of this registration left in the route
+			if notification == nil {
+				break
+			}
+			onNotification(notification)
+		}
+	})
+
+	return func() {
+		err := route.Enqueue(nil)
+		if err != nil && !errors.Is(err, routerpkg.ErrRouteClosed) {
+			log.Warnf("Couldn't stop the %s notification handler: %s", command, err)
+		}
+	}
+}
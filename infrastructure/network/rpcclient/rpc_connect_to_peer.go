@@ -4,11 +4,8 @@ import "github.com/kaspanet/kaspad/app/appmessage"
 
 // AddPeer sends an RPC request respective to the function's name and returns the RPC server's response
 func (c *RPCClient) AddPeer(address string, isPermanent bool) error {
-	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewAddPeerRequestMessage(address, isPermanent))
-	if err != nil {
-		return err
-	}
-	response, err := c.route(appmessage.CmdAddPeerResponseMessage).DequeueWithTimeout(c.timeout)
+	request := appmessage.NewAddPeerRequestMessage(address, isPermanent)
+	response, err := c.call(request, appmessage.CmdAddPeerResponseMessage)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,17 @@
+package rpcclient
+
+import "github.com/kaspanet/kaspad/app/appmessage"
+
+// DumpMempool sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) DumpMempool() (*appmessage.DumpMempoolResponseMessage, error) {
+	request := appmessage.NewDumpMempoolRequestMessage()
+	response, err := c.call(request, appmessage.CmdDumpMempoolResponseMessage)
+	if err != nil {
+		return nil, err
+	}
+	dumpMempoolResponse := response.(*appmessage.DumpMempoolResponseMessage)
+	if dumpMempoolResponse.Error != nil {
+		return nil, c.convertRPCError(dumpMempoolResponse.Error)
+	}
+	return dumpMempoolResponse, nil
+}
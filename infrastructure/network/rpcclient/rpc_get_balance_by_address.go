@@ -0,0 +1,17 @@
+package rpcclient
+
+import "github.com/kaspanet/kaspad/app/appmessage"
+
+// GetBalanceByAddress sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetBalanceByAddress(address string) (*appmessage.GetBalanceByAddressResponseMessage, error) {
+	request := appmessage.NewGetBalanceByAddressRequestMessage(address)
+	response, err := c.call(request, appmessage.CmdGetBalanceByAddressResponseMessage)
+	if err != nil {
+		return nil, err
+	}
+	getBalanceByAddressResponse := response.(*appmessage.GetBalanceByAddressResponseMessage)
+	if getBalanceByAddressResponse.Error != nil {
+		return nil, c.convertRPCError(getBalanceByAddressResponse.Error)
+	}
+	return getBalanceByAddressResponse, nil
+}
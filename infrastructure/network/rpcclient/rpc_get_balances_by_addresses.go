@@ -0,0 +1,17 @@
+package rpcclient
+
+import "github.com/kaspanet/kaspad/app/appmessage"
+
+// GetBalancesByAddresses sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetBalancesByAddresses(addresses []string) (*appmessage.GetBalancesByAddressesResponseMessage, error) {
+	request := appmessage.NewGetBalancesByAddressesRequestMessage(addresses)
+	response, err := c.call(request, appmessage.CmdGetBalancesByAddressesResponseMessage)
+	if err != nil {
+		return nil, err
+	}
+	getBalancesByAddressesResponse := response.(*appmessage.GetBalancesByAddressesResponseMessage)
+	if getBalancesByAddressesResponse.Error != nil {
+		return nil, c.convertRPCError(getBalancesByAddressesResponse.Error)
+	}
+	return getBalancesByAddressesResponse, nil
+}
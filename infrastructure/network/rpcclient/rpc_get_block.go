@@ -6,12 +6,8 @@ import "github.com/kaspanet/kaspad/app/appmessage"
 func (c *RPCClient) GetBlock(hash string, includeTransactionVerboseData bool) (
 	*appmessage.GetBlockResponseMessage, error) {
 
-	err := c.rpcRouter.outgoingRoute().Enqueue(
-		appmessage.NewGetBlockRequestMessage(hash, includeTransactionVerboseData))
-	if err != nil {
-		return nil, err
-	}
-	response, err := c.route(appmessage.CmdGetBlockResponseMessage).DequeueWithTimeout(c.timeout)
+	request := appmessage.NewGetBlockRequestMessage(hash, includeTransactionVerboseData)
+	response, err := c.call(request, appmessage.CmdGetBlockResponseMessage)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,17 @@
+package rpcclient
+
+import "github.com/kaspanet/kaspad/app/appmessage"
+
+// GetBlockAcceptanceStatus sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetBlockAcceptanceStatus(blockHash string) (*appmessage.GetBlockAcceptanceStatusResponseMessage, error) {
+	request := appmessage.NewGetBlockAcceptanceStatusRequestMessage(blockHash)
+	response, err := c.call(request, appmessage.CmdGetBlockAcceptanceStatusResponseMessage)
+	if err != nil {
+		return nil, err
+	}
+	getBlockAcceptanceStatusResponse := response.(*appmessage.GetBlockAcceptanceStatusResponseMessage)
+	if getBlockAcceptanceStatusResponse.Error != nil {
+		return nil, c.convertRPCError(getBlockAcceptanceStatusResponse.Error)
+	}
+	return getBlockAcceptanceStatusResponse, nil
+}
@@ -4,11 +4,8 @@ import "github.com/kaspanet/kaspad/app/appmessage"
 
 // GetBlockTemplate sends an RPC request respective to the function's name and returns the RPC server's response
 func (c *RPCClient) GetBlockTemplate(miningAddress string) (*appmessage.GetBlockTemplateResponseMessage, error) {
-	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetBlockTemplateRequestMessage(miningAddress))
-	if err != nil {
-		return nil, err
-	}
-	response, err := c.route(appmessage.CmdGetBlockTemplateResponseMessage).DequeueWithTimeout(c.timeout)
+	request := appmessage.NewGetBlockTemplateRequestMessage(miningAddress)
+	response, err := c.call(request, appmessage.CmdGetBlockTemplateResponseMessage)
 	if err != nil {
 		return nil, err
 	}
@@ -6,12 +6,8 @@ import "github.com/kaspanet/kaspad/app/appmessage"
 func (c *RPCClient) GetBlocks(lowHash string, includeBlockVerboseData bool,
 	includeTransactionVerboseData bool) (*appmessage.GetBlocksResponseMessage, error) {
 
-	err := c.rpcRouter.outgoingRoute().Enqueue(
-		appmessage.NewGetBlocksRequestMessage(lowHash, includeBlockVerboseData, includeTransactionVerboseData))
-	if err != nil {
-		return nil, err
-	}
-	response, err := c.route(appmessage.CmdGetBlocksResponseMessage).DequeueWithTimeout(c.timeout)
+	request := appmessage.NewGetBlocksRequestMessage(lowHash, includeBlockVerboseData, includeTransactionVerboseData)
+	response, err := c.call(request, appmessage.CmdGetBlocksResponseMessage)
 	if err != nil {
 		return nil, err
 	}
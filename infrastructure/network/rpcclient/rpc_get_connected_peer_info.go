@@ -4,11 +4,8 @@ import "github.com/kaspanet/kaspad/app/appmessage"
 
 // GetConnectedPeerInfo sends an RPC request respective to the function's name and returns the RPC server's response
 func (c *RPCClient) GetConnectedPeerInfo() (*appmessage.GetConnectedPeerInfoResponseMessage, error) {
-	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetConnectedPeerInfoRequestMessage())
-	if err != nil {
-		return nil, err
-	}
-	response, err := c.route(appmessage.CmdGetConnectedPeerInfoResponseMessage).DequeueWithTimeout(c.timeout)
+	request := appmessage.NewGetConnectedPeerInfoRequestMessage()
+	response, err := c.call(request, appmessage.CmdGetConnectedPeerInfoResponseMessage)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,19 @@
+package rpcclient
+
+import "github.com/kaspanet/kaspad/app/appmessage"
+
+// GetDAGSubgraph sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetDAGSubgraph(lowBlueScore uint64, highBlueScore uint64, anticoneOfHash string,
+	includeDotGraph bool) (*appmessage.GetDAGSubgraphResponseMessage, error) {
+
+	request := appmessage.NewGetDAGSubgraphRequestMessage(lowBlueScore, highBlueScore, anticoneOfHash, includeDotGraph)
+	response, err := c.call(request, appmessage.CmdGetDAGSubgraphResponseMessage)
+	if err != nil {
+		return nil, err
+	}
+	getDAGSubgraphResponse := response.(*appmessage.GetDAGSubgraphResponseMessage)
+	if getDAGSubgraphResponse.Error != nil {
+		return nil, c.convertRPCError(getDAGSubgraphResponse.Error)
+	}
+	return getDAGSubgraphResponse, nil
+}
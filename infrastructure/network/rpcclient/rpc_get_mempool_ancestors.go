@@ -0,0 +1,17 @@
+package rpcclient
+
+import "github.com/kaspanet/kaspad/app/appmessage"
+
+// GetMempoolAncestors sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetMempoolAncestors(txID string) (*appmessage.GetMempoolAncestorsResponseMessage, error) {
+	request := appmessage.NewGetMempoolAncestorsRequestMessage(txID)
+	response, err := c.call(request, appmessage.CmdGetMempoolAncestorsResponseMessage)
+	if err != nil {
+		return nil, err
+	}
+	getMempoolAncestorsResponse := response.(*appmessage.GetMempoolAncestorsResponseMessage)
+	if getMempoolAncestorsResponse.Error != nil {
+		return nil, c.convertRPCError(getMempoolAncestorsResponse.Error)
+	}
+	return getMempoolAncestorsResponse, nil
+}
@@ -0,0 +1,17 @@
+package rpcclient
+
+import "github.com/kaspanet/kaspad/app/appmessage"
+
+// GetMempoolDescendants sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetMempoolDescendants(txID string) (*appmessage.GetMempoolDescendantsResponseMessage, error) {
+	request := appmessage.NewGetMempoolDescendantsRequestMessage(txID)
+	response, err := c.call(request, appmessage.CmdGetMempoolDescendantsResponseMessage)
+	if err != nil {
+		return nil, err
+	}
+	getMempoolDescendantsResponse := response.(*appmessage.GetMempoolDescendantsResponseMessage)
+	if getMempoolDescendantsResponse.Error != nil {
+		return nil, c.convertRPCError(getMempoolDescendantsResponse.Error)
+	}
+	return getMempoolDescendantsResponse, nil
+}
@@ -4,11 +4,22 @@ import "github.com/kaspanet/kaspad/app/appmessage"
 
 // GetMempoolEntries sends an RPC request respective to the function's name and returns the RPC server's response
 func (c *RPCClient) GetMempoolEntries() (*appmessage.GetMempoolEntriesResponseMessage, error) {
-	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetMempoolEntriesRequestMessage())
-	if err != nil {
-		return nil, err
-	}
-	response, err := c.route(appmessage.CmdGetMempoolEntriesResponseMessage).DequeueWithTimeout(c.timeout)
+	return c.getMempoolEntries(appmessage.NewGetMempoolEntriesRequestMessage(nil, 0))
+}
+
+// GetMempoolEntriesWithFilter sends an RPC request respective to the function's name and returns the RPC server's response.
+// Only transactions that spend from or pay to one of the given addresses, and whose fee rate is at least the given
+// minimum fee rate are returned. An empty addresses slice or a zero minimum fee rate disable their respective filter
+func (c *RPCClient) GetMempoolEntriesWithFilter(addresses []string, minimumFeeRate float64) (
+	*appmessage.GetMempoolEntriesResponseMessage, error) {
+
+	return c.getMempoolEntries(appmessage.NewGetMempoolEntriesRequestMessage(addresses, minimumFeeRate))
+}
+
+func (c *RPCClient) getMempoolEntries(request *appmessage.GetMempoolEntriesRequestMessage) (
+	*appmessage.GetMempoolEntriesResponseMessage, error) {
+
+	response, err := c.call(request, appmessage.CmdGetMempoolEntriesResponseMessage)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,17 @@
+package rpcclient
+
+import "github.com/kaspanet/kaspad/app/appmessage"
+
+// GetMempoolStats sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetMempoolStats() (*appmessage.GetMempoolStatsResponseMessage, error) {
+	request := appmessage.NewGetMempoolStatsRequestMessage()
+	response, err := c.call(request, appmessage.CmdGetMempoolStatsResponseMessage)
+	if err != nil {
+		return nil, err
+	}
+	getMempoolStatsResponse := response.(*appmessage.GetMempoolStatsResponseMessage)
+	if getMempoolStatsResponse.Error != nil {
+		return nil, c.convertRPCError(getMempoolStatsResponse.Error)
+	}
+	return getMempoolStatsResponse, nil
+}
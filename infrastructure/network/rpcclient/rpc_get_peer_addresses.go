@@ -4,11 +4,8 @@ import "github.com/kaspanet/kaspad/app/appmessage"
 
 // GetPeerAddresses sends an RPC request respective to the function's name and returns the RPC server's response
 func (c *RPCClient) GetPeerAddresses() (*appmessage.GetPeerAddressesResponseMessage, error) {
-	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetPeerAddressesRequestMessage())
-	if err != nil {
-		return nil, err
-	}
-	response, err := c.route(appmessage.CmdGetPeerAddressesResponseMessage).DequeueWithTimeout(c.timeout)
+	request := appmessage.NewGetPeerAddressesRequestMessage()
+	response, err := c.call(request, appmessage.CmdGetPeerAddressesResponseMessage)
 	if err != nil {
 		return nil, err
 	}
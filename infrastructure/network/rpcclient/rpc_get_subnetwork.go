@@ -4,11 +4,8 @@ import "github.com/kaspanet/kaspad/app/appmessage"
 
 // GetSubnetwork sends an RPC request respective to the function's name and returns the RPC server's response
 func (c *RPCClient) GetSubnetwork(subnetworkID string) (*appmessage.GetSubnetworkResponseMessage, error) {
-	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetSubnetworkRequestMessage(subnetworkID))
-	if err != nil {
-		return nil, err
-	}
-	response, err := c.route(appmessage.CmdGetSubnetworkResponseMessage).DequeueWithTimeout(c.timeout)
+	request := appmessage.NewGetSubnetworkRequestMessage(subnetworkID)
+	response, err := c.call(request, appmessage.CmdGetSubnetworkResponseMessage)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,19 @@
+package rpcclient
+
+import "github.com/kaspanet/kaspad/app/appmessage"
+
+// GetTransactionConfirmations sends an RPC request respective to the function's name and returns the RPC server's response
+func (c *RPCClient) GetTransactionConfirmations(transactionID string, blockHash string) (
+	*appmessage.GetTransactionConfirmationsResponseMessage, error) {
+
+	request := appmessage.NewGetTransactionConfirmationsRequestMessage(transactionID, blockHash)
+	response, err := c.call(request, appmessage.CmdGetTransactionConfirmationsResponseMessage)
+	if err != nil {
+		return nil, err
+	}
+	getTransactionConfirmationsResponse := response.(*appmessage.GetTransactionConfirmationsResponseMessage)
+	if getTransactionConfirmationsResponse.Error != nil {
+		return nil, c.convertRPCError(getTransactionConfirmationsResponse.Error)
+	}
+	return getTransactionConfirmationsResponse, nil
+}
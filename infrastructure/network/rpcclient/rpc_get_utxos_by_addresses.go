@@ -4,11 +4,32 @@ import "github.com/kaspanet/kaspad/app/appmessage"
 
 // GetUTXOsByAddresses sends an RPC request respective to the function's name and returns the RPC server's response
 func (c *RPCClient) GetUTXOsByAddresses(addresses []string) (*appmessage.GetUTXOsByAddressesResponseMessage, error) {
-	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewGetUTXOsByAddressesRequestMessage(addresses))
-	if err != nil {
-		return nil, err
-	}
-	response, err := c.route(appmessage.CmdGetUTXOsByAddressesResponseMessage).DequeueWithTimeout(c.timeout)
+	return c.getUTXOsByAddresses(appmessage.NewGetUTXOsByAddressesRequestMessage(addresses))
+}
+
+// GetUTXOsByAddressesIncludingMempool sends an RPC request for the UTXOs of the given addresses,
+// overlaid with the mempool: UTXOs spent by mempool transactions are excluded, and the outputs
+// of mempool transactions are included and marked as unconfirmed
+func (c *RPCClient) GetUTXOsByAddressesIncludingMempool(addresses []string) (
+	*appmessage.GetUTXOsByAddressesResponseMessage, error) {
+
+	request := appmessage.NewGetUTXOsByAddressesRequestMessage(addresses)
+	request.IncludeMempool = true
+	return c.getUTXOsByAddresses(request)
+}
+
+// GetUTXOsByAddressesPage sends an RPC request for a single page of the UTXOs of the given addresses.
+// cursor is the NextCursor of the response for the previous page, or empty for the first page
+func (c *RPCClient) GetUTXOsByAddressesPage(addresses []string, limit uint32, cursor string) (
+	*appmessage.GetUTXOsByAddressesResponseMessage, error) {
+
+	return c.getUTXOsByAddresses(appmessage.NewGetUTXOsByAddressesPageRequestMessage(addresses, limit, cursor))
+}
+
+func (c *RPCClient) getUTXOsByAddresses(request *appmessage.GetUTXOsByAddressesRequestMessage) (
+	*appmessage.GetUTXOsByAddressesResponseMessage, error) {
+
+	response, err := c.call(request, appmessage.CmdGetUTXOsByAddressesResponseMessage)
 	if err != nil {
 		return nil, err
 	}
@@ -2,37 +2,46 @@ package rpcclient
 
 import (
 	"github.com/kaspanet/kaspad/app/appmessage"
-	routerpkg "github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
-	"github.com/pkg/errors"
 )
 
 // RegisterForBlockAddedNotifications sends an RPC request respective to the function's name and returns the RPC server's response.
 // Additionally, it starts listening for the appropriate notification using the given handler function
 func (c *RPCClient) RegisterForBlockAddedNotifications(onBlockAdded func(notification *appmessage.BlockAddedNotificationMessage)) error {
-	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewNotifyBlockAddedRequestMessage())
-	if err != nil {
-		return err
-	}
-	response, err := c.route(appmessage.CmdNotifyBlockAddedResponseMessage).DequeueWithTimeout(c.timeout)
-	if err != nil {
-		return err
-	}
-	notifyBlockAddedResponse := response.(*appmessage.NotifyBlockAddedResponseMessage)
-	if notifyBlockAddedResponse.Error != nil {
-		return c.convertRPCError(notifyBlockAddedResponse.Error)
-	}
-	spawn("RegisterForBlockAddedNotifications", func() {
-		for {
-			notification, err := c.route(appmessage.CmdBlockAddedNotificationMessage).Dequeue()
-			if err != nil {
-				if errors.Is(err, routerpkg.ErrRouteClosed) {
-					break
-				}
-				panic(err)
-			}
-			blockAddedNotification := notification.(*appmessage.BlockAddedNotificationMessage)
-			onBlockAdded(blockAddedNotification)
-		}
+	return c.registerForBlockAddedNotifications("", 0, onBlockAdded)
+}
+
+// RegisterForBlockAddedNotificationsFromSequenceNumber is like RegisterForBlockAddedNotifications,
+// but first replays the notifications of the given stream from startSequenceNumber and onwards
+// that were already sent. It returns an error if some of these notifications are no longer kept
+// by the RPC server, or if it restarted since, in which case the stream ID changed
+func (c *RPCClient) RegisterForBlockAddedNotificationsFromSequenceNumber(startStreamID string, startSequenceNumber uint64,
+	onBlockAdded func(notification *appmessage.BlockAddedNotificationMessage)) error {
+
+	return c.registerForBlockAddedNotifications(startStreamID, startSequenceNumber, onBlockAdded)
+}
+
+func (c *RPCClient) registerForBlockAddedNotifications(startStreamID string, startSequenceNumber uint64,
+	onBlockAdded func(notification *appmessage.BlockAddedNotificationMessage)) error {
+
+	sequenceNumbers := newSequenceNumberTracker(startStreamID, startSequenceNumber)
+	return c.subscribe(&subscription{
+		name: "RegisterForBlockAddedNotifications",
+		request: func() appmessage.Message {
+			request := appmessage.NewNotifyBlockAddedRequestMessage()
+			request.StartStreamID, request.StartSequenceNumber = sequenceNumbers.next()
+			return request
+		},
+		responseCommand: appmessage.CmdNotifyBlockAddedResponseMessage,
+		responseError: func(response appmessage.Message) *appmessage.RPCError {
+			return response.(*appmessage.NotifyBlockAddedResponseMessage).Error
+		},
+		notificationHandlers: map[appmessage.MessageCommand]func(notification appmessage.Message){
+			appmessage.CmdBlockAddedNotificationMessage: func(notification appmessage.Message) {
+				blockAddedNotification := notification.(*appmessage.BlockAddedNotificationMessage)
+				sequenceNumbers.update(blockAddedNotification.StreamID, blockAddedNotification.SequenceNumber)
+				onBlockAdded(blockAddedNotification)
+			},
+		},
+		sequenceNumbers: sequenceNumbers,
 	})
-	return nil
 }
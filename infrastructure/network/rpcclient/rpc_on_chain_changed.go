@@ -2,37 +2,48 @@ package rpcclient
 
 import (
 	"github.com/kaspanet/kaspad/app/appmessage"
-	routerpkg "github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
-	"github.com/pkg/errors"
 )
 
 // RegisterForVirtualSelectedParentChainChangedNotifications sends an RPC request respective to the function's name and returns the RPC server's response.
 // Additionally, it starts listening for the appropriate notification using the given handler function
 func (c *RPCClient) RegisterForVirtualSelectedParentChainChangedNotifications(onChainChanged func(notification *appmessage.VirtualSelectedParentChainChangedNotificationMessage)) error {
-	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewNotifyVirtualSelectedParentChainChangedRequestMessage())
-	if err != nil {
-		return err
-	}
-	response, err := c.route(appmessage.CmdNotifyVirtualSelectedParentChainChangedResponseMessage).DequeueWithTimeout(c.timeout)
-	if err != nil {
-		return err
-	}
-	notifyChainChangedResponse := response.(*appmessage.NotifyVirtualSelectedParentChainChangedResponseMessage)
-	if notifyChainChangedResponse.Error != nil {
-		return c.convertRPCError(notifyChainChangedResponse.Error)
-	}
-	spawn("RegisterForVirtualSelectedParentChainChangedNotifications", func() {
-		for {
-			notification, err := c.route(appmessage.CmdVirtualSelectedParentChainChangedNotificationMessage).Dequeue()
-			if err != nil {
-				if errors.Is(err, routerpkg.ErrRouteClosed) {
-					break
-				}
-				panic(err)
-			}
-			ChainChangedNotification := notification.(*appmessage.VirtualSelectedParentChainChangedNotificationMessage)
-			onChainChanged(ChainChangedNotification)
-		}
+	return c.registerForVirtualSelectedParentChainChangedNotifications("", 0, onChainChanged)
+}
+
+// RegisterForVirtualSelectedParentChainChangedNotificationsFromSequenceNumber is like
+// RegisterForVirtualSelectedParentChainChangedNotifications, but first replays the notifications
+// of the given stream from startSequenceNumber and onwards that were already sent. It returns an
+// error if some of these notifications are no longer kept by the RPC server, or if it restarted
+// since, in which case the stream ID changed
+func (c *RPCClient) RegisterForVirtualSelectedParentChainChangedNotificationsFromSequenceNumber(startStreamID string,
+	startSequenceNumber uint64,
+	onChainChanged func(notification *appmessage.VirtualSelectedParentChainChangedNotificationMessage)) error {
+
+	return c.registerForVirtualSelectedParentChainChangedNotifications(startStreamID, startSequenceNumber, onChainChanged)
+}
+
+func (c *RPCClient) registerForVirtualSelectedParentChainChangedNotifications(startStreamID string, startSequenceNumber uint64,
+	onChainChanged func(notification *appmessage.VirtualSelectedParentChainChangedNotificationMessage)) error {
+
+	sequenceNumbers := newSequenceNumberTracker(startStreamID, startSequenceNumber)
+	return c.subscribe(&subscription{
+		name: "RegisterForVirtualSelectedParentChainChangedNotifications",
+		request: func() appmessage.Message {
+			request := appmessage.NewNotifyVirtualSelectedParentChainChangedRequestMessage()
+			request.StartStreamID, request.StartSequenceNumber = sequenceNumbers.next()
+			return request
+		},
+		responseCommand: appmessage.CmdNotifyVirtualSelectedParentChainChangedResponseMessage,
+		responseError: func(response appmessage.Message) *appmessage.RPCError {
+			return response.(*appmessage.NotifyVirtualSelectedParentChainChangedResponseMessage).Error
+		},
+		notificationHandlers: map[appmessage.MessageCommand]func(notification appmessage.Message){
+			appmessage.CmdVirtualSelectedParentChainChangedNotificationMessage: func(notification appmessage.Message) {
+				chainChangedNotification := notification.(*appmessage.VirtualSelectedParentChainChangedNotificationMessage)
+				sequenceNumbers.update(chainChangedNotification.StreamID, chainChangedNotification.SequenceNumber)
+				onChainChanged(chainChangedNotification)
+			},
+		},
+		sequenceNumbers: sequenceNumbers,
 	})
-	return nil
 }
@@ -0,0 +1,43 @@
+package rpcclient
+
+import (
+	"github.com/kaspanet/kaspad/app/appmessage"
+)
+
+// RegisterForMempoolTransactionsNotifications sends an RPC request respective to the function's name and returns the RPC server's response.
+// Additionally, it starts listening for the appropriate notifications using the given handler functions.
+// If addresses is not empty, only transactions that pay to or spend from one of them are notified about
+func (c *RPCClient) RegisterForMempoolTransactionsNotifications(addresses []string,
+	onTransactionAdded func(notification *appmessage.TransactionAddedToMempoolNotificationMessage),
+	onTransactionRemoved func(notification *appmessage.TransactionRemovedFromMempoolNotificationMessage)) error {
+
+	return c.subscribe(&subscription{
+		name: "RegisterForMempoolTransactionsNotifications",
+		request: func() appmessage.Message {
+			return appmessage.NewNotifyMempoolTransactionsRequestMessage(addresses)
+		},
+		responseCommand: appmessage.CmdNotifyMempoolTransactionsResponseMessage,
+		responseError: func(response appmessage.Message) *appmessage.RPCError {
+			return response.(*appmessage.NotifyMempoolTransactionsResponseMessage).Error
+		},
+		notificationHandlers: map[appmessage.MessageCommand]func(notification appmessage.Message){
+			appmessage.CmdTransactionAddedToMempoolNotificationMessage: func(notification appmessage.Message) {
+				onTransactionAdded(notification.(*appmessage.TransactionAddedToMempoolNotificationMessage))
+			},
+			appmessage.CmdTransactionRemovedFromMempoolNotificationMessage: func(notification appmessage.Message) {
+				onTransactionRemoved(notification.(*appmessage.TransactionRemovedFromMempoolNotificationMessage))
+			},
+		},
+	})
+}
+
+// UnregisterFromMempoolTransactionsNotifications sends an RPC request respective to the function's name and returns the RPC server's response.
+// Additionally, it stops listening for the appropriate notifications
+func (c *RPCClient) UnregisterFromMempoolTransactionsNotifications() error {
+	return c.unsubscribe("RegisterForMempoolTransactionsNotifications",
+		appmessage.NewStopNotifyingMempoolTransactionsRequestMessage(),
+		appmessage.CmdStopNotifyingMempoolTransactionsResponseMessage,
+		func(response appmessage.Message) *appmessage.RPCError {
+			return response.(*appmessage.StopNotifyingMempoolTransactionsResponseMessage).Error
+		})
+}
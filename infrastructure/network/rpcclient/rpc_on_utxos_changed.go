@@ -2,8 +2,6 @@ package rpcclient
 
 import (
 	"github.com/kaspanet/kaspad/app/appmessage"
-	routerpkg "github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
-	"github.com/pkg/errors"
 )
 
 // RegisterForUTXOsChangedNotifications sends an RPC request respective to the function's name and returns the RPC server's response.
@@ -11,30 +9,51 @@ import (
 func (c *RPCClient) RegisterForUTXOsChangedNotifications(addresses []string,
 	onUTXOsChanged func(notification *appmessage.UTXOsChangedNotificationMessage)) error {
 
-	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewNotifyUTXOsChangedRequestMessage(addresses))
-	if err != nil {
-		return err
-	}
-	response, err := c.route(appmessage.CmdNotifyUTXOsChangedResponseMessage).DequeueWithTimeout(c.timeout)
-	if err != nil {
-		return err
-	}
-	notifyUTXOsChangedResponse := response.(*appmessage.NotifyUTXOsChangedResponseMessage)
-	if notifyUTXOsChangedResponse.Error != nil {
-		return c.convertRPCError(notifyUTXOsChangedResponse.Error)
-	}
-	spawn("RegisterForUTXOsChangedNotifications", func() {
-		for {
-			notification, err := c.route(appmessage.CmdUTXOsChangedNotificationMessage).Dequeue()
-			if err != nil {
-				if errors.Is(err, routerpkg.ErrRouteClosed) {
-					break
-				}
-				panic(err)
-			}
-			UTXOsChangedNotification := notification.(*appmessage.UTXOsChangedNotificationMessage)
-			onUTXOsChanged(UTXOsChangedNotification)
-		}
+	return c.registerForUTXOsChangedNotifications(addresses, false, "", 0, onUTXOsChanged)
+}
+
+// RegisterForUTXOsChangedNotificationsIncludingMempool is like RegisterForUTXOsChangedNotifications,
+// but additionally receives notifications whenever transactions that touch the given addresses are
+// added to the mempool
+func (c *RPCClient) RegisterForUTXOsChangedNotificationsIncludingMempool(addresses []string,
+	onUTXOsChanged func(notification *appmessage.UTXOsChangedNotificationMessage)) error {
+
+	return c.registerForUTXOsChangedNotifications(addresses, true, "", 0, onUTXOsChanged)
+}
+
+// RegisterForUTXOsChangedNotificationsFromSequenceNumber is like RegisterForUTXOsChangedNotifications,
+// but first replays the notifications of changes to the given addresses of the given stream from
+// startSequenceNumber and onwards. It returns an error if some of these notifications are no longer
+// kept by the RPC server, or if it restarted since, in which case the stream ID changed
+func (c *RPCClient) RegisterForUTXOsChangedNotificationsFromSequenceNumber(addresses []string, startStreamID string,
+	startSequenceNumber uint64, onUTXOsChanged func(notification *appmessage.UTXOsChangedNotificationMessage)) error {
+
+	return c.registerForUTXOsChangedNotifications(addresses, false, startStreamID, startSequenceNumber, onUTXOsChanged)
+}
+
+func (c *RPCClient) registerForUTXOsChangedNotifications(addresses []string, includeMempool bool, startStreamID string,
+	startSequenceNumber uint64, onUTXOsChanged func(notification *appmessage.UTXOsChangedNotificationMessage)) error {
+
+	sequenceNumbers := newSequenceNumberTracker(startStreamID, startSequenceNumber)
+	return c.subscribe(&subscription{
+		name: "RegisterForUTXOsChangedNotifications",
+		request: func() appmessage.Message {
+			request := appmessage.NewNotifyUTXOsChangedRequestMessage(addresses)
+			request.IncludeMempool = includeMempool
+			request.StartStreamID, request.StartSequenceNumber = sequenceNumbers.next()
+			return request
+		},
+		responseCommand: appmessage.CmdNotifyUTXOsChangedResponseMessage,
+		responseError: func(response appmessage.Message) *appmessage.RPCError {
+			return response.(*appmessage.NotifyUTXOsChangedResponseMessage).Error
+		},
+		notificationHandlers: map[appmessage.MessageCommand]func(notification appmessage.Message){
+			appmessage.CmdUTXOsChangedNotificationMessage: func(notification appmessage.Message) {
+				utxosChangedNotification := notification.(*appmessage.UTXOsChangedNotificationMessage)
+				sequenceNumbers.update(utxosChangedNotification.StreamID, utxosChangedNotification.SequenceNumber)
+				onUTXOsChanged(utxosChangedNotification)
+			},
+		},
+		sequenceNumbers: sequenceNumbers,
 	})
-	return nil
 }
@@ -4,11 +4,8 @@ import "github.com/kaspanet/kaspad/app/appmessage"
 
 // ResolveFinalityConflict sends an RPC request respective to the function's name and returns the RPC server's response
 func (c *RPCClient) ResolveFinalityConflict(finalityBlockHash string) (*appmessage.ResolveFinalityConflictResponseMessage, error) {
-	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewResolveFinalityConflictRequestMessage(finalityBlockHash))
-	if err != nil {
-		return nil, err
-	}
-	response, err := c.route(appmessage.CmdResolveFinalityConflictResponseMessage).DequeueWithTimeout(c.timeout)
+	request := appmessage.NewResolveFinalityConflictRequestMessage(finalityBlockHash)
+	response, err := c.call(request, appmessage.CmdResolveFinalityConflictResponseMessage)
 	if err != nil {
 		return nil, err
 	}
@@ -6,11 +6,8 @@ import (
 
 // SubmitTransaction sends an RPC request respective to the function's name and returns the RPC server's response
 func (c *RPCClient) SubmitTransaction(transaction *appmessage.RPCTransaction) (*appmessage.SubmitTransactionResponseMessage, error) {
-	err := c.rpcRouter.outgoingRoute().Enqueue(appmessage.NewSubmitTransactionRequestMessage(transaction))
-	if err != nil {
-		return nil, err
-	}
-	response, err := c.route(appmessage.CmdSubmitTransactionResponseMessage).DequeueWithTimeout(c.timeout)
+	request := appmessage.NewSubmitTransactionRequestMessage(transaction)
+	response, err := c.call(request, appmessage.CmdSubmitTransactionResponseMessage)
 	if err != nil {
 		return nil, err
 	}
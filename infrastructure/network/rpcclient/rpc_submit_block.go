@@ -7,12 +7,8 @@ import (
 
 // SubmitBlock sends an RPC request respective to the function's name and returns the RPC server's response
 func (c *RPCClient) SubmitBlock(block *externalapi.DomainBlock) (appmessage.RejectReason, error) {
-	err := c.rpcRouter.outgoingRoute().Enqueue(
-		appmessage.NewSubmitBlockRequestMessage(appmessage.DomainBlockToMsgBlock(block)))
-	if err != nil {
-		return appmessage.RejectReasonNone, err
-	}
-	response, err := c.route(appmessage.CmdSubmitBlockResponseMessage).DequeueWithTimeout(c.timeout)
+	request := appmessage.NewSubmitBlockRequestMessage(appmessage.DomainBlockToMsgBlock(block))
+	response, err := c.call(request, appmessage.CmdSubmitBlockResponseMessage)
 	if err != nil {
 		return appmessage.RejectReasonNone, err
 	}
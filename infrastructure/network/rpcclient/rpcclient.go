@@ -1,10 +1,10 @@
 package rpcclient
 
 import (
+	"context"
 	"github.com/kaspanet/kaspad/app/appmessage"
 	"github.com/kaspanet/kaspad/infrastructure/logger"
 	routerpkg "github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
-	"github.com/kaspanet/kaspad/infrastructure/network/rpcclient/grpcclient"
 	"github.com/kaspanet/kaspad/util/panics"
 	"github.com/pkg/errors"
 	"time"
@@ -12,46 +12,42 @@ import (
 
 const defaultTimeout = 30 * time.Second
 
-// RPCClient is an RPC client
+// RPCClient is an RPC client. If the connection to the RPC server is lost,
+// the client reconnects and re-issues its notification registrations
 type RPCClient struct {
-	*grpcclient.GRPCClient
+	*connection
 
-	rpcAddress string
-	rpcRouter  *rpcRouter
-
-	timeout time.Duration
+	ctx context.Context
 }
 
 // NewRPCClient creates a new RPC client
 func NewRPCClient(rpcAddress string) (*RPCClient, error) {
-	rpcClient, err := grpcclient.Connect(rpcAddress)
-	if err != nil {
-		return nil, errors.Wrapf(err, "error connecting to address %s", rpcAddress)
-	}
-	rpcRouter, err := buildRPCRouter()
+	connection := newConnection(rpcAddress)
+	err := connection.connect()
 	if err != nil {
-		return nil, errors.Wrapf(err, "error creating the RPC router")
+		return nil, err
 	}
-	rpcClient.AttachRouter(rpcRouter.router)
 
 	log.Infof("Connected to server %s", rpcAddress)
 
 	return &RPCClient{
-		GRPCClient: rpcClient,
-		rpcAddress: rpcAddress,
-		rpcRouter:  rpcRouter,
-		timeout:    defaultTimeout,
+		connection: connection,
+		ctx:        context.Background(),
 	}, nil
 }
 
-// SetTimeout sets the timeout by which to wait for RPC responses
-func (c *RPCClient) SetTimeout(timeout time.Duration) {
-	c.timeout = timeout
+// WithContext returns a shallow copy of the client whose calls are canceled once
+// ctx is done. The copy shares the connection and the settings of the client
+func (c *RPCClient) WithContext(ctx context.Context) *RPCClient {
+	return &RPCClient{
+		connection: c.connection,
+		ctx:        ctx,
+	}
 }
 
-// Close closes the RPC client
-func (c *RPCClient) Close() {
-	c.rpcRouter.router.Close()
+// SetTimeout sets the timeout by which to wait for RPC responses
+func (c *RPCClient) SetTimeout(timeout time.Duration) {
+	c.setTimeout(timeout)
 }
 
 // Address returns the address the RPC client connected to
@@ -59,14 +55,46 @@ func (c *RPCClient) Address() string {
 	return c.rpcAddress
 }
 
-func (c *RPCClient) route(command appmessage.MessageCommand) *routerpkg.Route {
-	return c.rpcRouter.routes[command]
+// call sends the given request and waits for the response with the given command.
+// If the client is reconnecting, it first waits for the connection to be restored.
+// It returns routerpkg.ErrTimeout if the client's timeout expires first, and
+// ErrConnectionLost if the connection is lost before the response arrives
+func (c *RPCClient) call(request appmessage.Message, responseCommand appmessage.MessageCommand) (appmessage.Message, error) {
+	timeout := c.getTimeout()
+	ctx, cancel := context.WithTimeout(c.ctx, timeout)
+	defer cancel()
+
+	rpcRouter, err := c.waitForConnection(ctx)
+	if err != nil {
+		return nil, c.convertCallError(err, timeout)
+	}
+	response, err := rpcRouter.post(ctx, request, responseCommand)
+	if err != nil {
+		return nil, c.convertCallError(err, timeout)
+	}
+	return response, nil
+}
+
+// convertCallError converts errors of contexts derived by call to
+// routerpkg.ErrTimeout, unless they're caused by the client's context,
+// and errors of routers that were closed to the reason they were closed
+func (c *RPCClient) convertCallError(err error, timeout time.Duration) error {
+	if errors.Is(err, context.DeadlineExceeded) && c.ctx.Err() == nil {
+		return errors.Wrapf(routerpkg.ErrTimeout, "got timeout after %s", timeout)
+	}
+	if errors.Is(err, routerpkg.ErrRouteClosed) {
+		if c.isClosed() {
+			return errors.WithStack(ErrClientClosed)
+		}
+		return errors.Wrapf(ErrConnectionLost, "lost connection to %s", c.rpcAddress)
+	}
+	return err
 }
 
 // ErrRPC is an error in the RPC protocol
 var ErrRPC = errors.New("rpc error")
 
-func (c *RPCClient) convertRPCError(rpcError *appmessage.RPCError) error {
+func (c *connection) convertRPCError(rpcError *appmessage.RPCError) error {
 	return errors.Wrap(ErrRPC, rpcError.Message)
 }
 
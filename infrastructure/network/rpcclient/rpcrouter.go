@@ -1,6 +1,8 @@
 package rpcclient
 
 import (
+	"context"
+
 	"github.com/kaspanet/kaspad/app/appmessage"
 	routerpkg "github.com/kaspanet/kaspad/infrastructure/network/netadapter/router"
 )
@@ -30,3 +32,19 @@ func buildRPCRouter() (*rpcRouter, error) {
 func (r *rpcRouter) outgoingRoute() *routerpkg.Route {
 	return r.router.OutgoingRoute()
 }
+
+func (r *rpcRouter) route(command appmessage.MessageCommand) *routerpkg.Route {
+	return r.routes[command]
+}
+
+// post sends the given request and waits until ctx is done
+// for the response with the given command
+func (r *rpcRouter) post(ctx context.Context, request appmessage.Message,
+	responseCommand appmessage.MessageCommand) (appmessage.Message, error) {
+
+	err := r.outgoingRoute().Enqueue(request)
+	if err != nil {
+		return nil, err
+	}
+	return r.route(responseCommand).DequeueWithContext(ctx)
+}
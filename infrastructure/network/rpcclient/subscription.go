@@ -0,0 +1,237 @@
+package rpcclient
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/pkg/errors"
+)
+
+// subscription is a registration for notifications. The server forgets the
+// registrations of a connection once it's lost, so the client re-issues its
+// subscriptions whenever it reconnects
+type subscription struct {
+	name string
+
+	// request returns the request that registers for the notifications
+	request         func() appmessage.Message
+	responseCommand appmessage.MessageCommand
+	// responseError returns the error in the server's response, if any
+	responseError func(response appmessage.Message) *appmessage.RPCError
+
+	notificationHandlers map[appmessage.MessageCommand]func(notification appmessage.Message)
+
+	// sequenceNumbers is set for notifications that the server can replay,
+	// so that re-issuing the subscription resumes where the lost connection
+	// stopped
+	sequenceNumbers *sequenceNumberTracker
+
+	// rpcRouter is the router of the connection that the subscription
+	// was last issued through
+	rpcRouter                *rpcRouter
+	stopNotificationHandlers func()
+}
+
+// sequenceNumberTracker tracks the sequence number of the next notification
+// of a subscription, along with the ID of the stream it belongs to. The RPC
+// server chooses a new stream ID whenever it restarts
+type sequenceNumberTracker struct {
+	lock               sync.Mutex
+	streamID           string
+	nextSequenceNumber uint64
+}
+
+func newSequenceNumberTracker(startStreamID string, startSequenceNumber uint64) *sequenceNumberTracker {
+	return &sequenceNumberTracker{
+		streamID:           startStreamID,
+		nextSequenceNumber: startSequenceNumber,
+	}
+}
+
+// next returns the stream ID and sequence number to request the notifications
+// from. The sequence number is zero if no notifications should be replayed
+func (t *sequenceNumberTracker) next() (streamID string, sequenceNumber uint64) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.streamID, t.nextSequenceNumber
+}
+
+// update records that the notification with the given stream ID and sequence
+// number arrived. Notifications without a sequence number are ignored
+func (t *sequenceNumberTracker) update(streamID string, sequenceNumber uint64) {
+	if sequenceNumber == 0 {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.streamID = streamID
+	t.nextSequenceNumber = sequenceNumber + 1
+}
+
+func (t *sequenceNumberTracker) reset() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.streamID = ""
+	t.nextSequenceNumber = 0
+}
+
+func (t *sequenceNumberTracker) isResuming() bool {
+	_, sequenceNumber := t.next()
+	return sequenceNumber != 0
+}
+
+// OnNotificationsLostHandler is a handler function that's called when a
+// subscription is re-issued without the notifications that were sent while
+// the client was disconnected
+type OnNotificationsLostHandler func(subscriptionName string)
+
+// SetOnNotificationsLostHandler sets the handler that's called whenever the client
+// reconnects, but the RPC server can't replay the notifications of a subscription
+// that were sent while it was disconnected. This happens when the RPC server
+// restarted, or when the client was disconnected for too long. The subscription is
+// then re-issued without replaying them, and the handler should resynchronize
+// whatever state is derived from the notifications from scratch
+func (c *connection) SetOnNotificationsLostHandler(handler OnNotificationsLostHandler) {
+	c.onNotificationsLostHandlerLock.Lock()
+	defer c.onNotificationsLostHandlerLock.Unlock()
+
+	c.onNotificationsLostHandler = handler
+}
+
+func (c *connection) notifyNotificationsLost(subscriptionName string) {
+	c.onNotificationsLostHandlerLock.RLock()
+	handler := c.onNotificationsLostHandler
+	c.onNotificationsLostHandlerLock.RUnlock()
+
+	if handler != nil {
+		handler(subscriptionName)
+	}
+}
+
+// subscribe issues the given subscription, and keeps it so that
+// it's re-issued whenever the client reconnects
+func (c *RPCClient) subscribe(s *subscription) error {
+	timeout := c.getTimeout()
+	ctx, cancel := context.WithTimeout(c.ctx, timeout)
+	defer cancel()
+
+	c.subscriptionsLock.Lock()
+	defer c.subscriptionsLock.Unlock()
+
+	rpcRouter, err := c.waitForConnection(ctx)
+	if err != nil {
+		return c.convertCallError(err, timeout)
+	}
+	err = c.issueSubscription(ctx, rpcRouter, s)
+	if err != nil {
+		return c.convertCallError(err, timeout)
+	}
+	c.subscriptions = append(c.subscriptions, s)
+	return nil
+}
+
+// unsubscribe sends the given request that unregisters from notifications, and
+// stops re-issuing the subscriptions with the given name once it succeeds
+func (c *RPCClient) unsubscribe(name string, request appmessage.Message, responseCommand appmessage.MessageCommand,
+	responseError func(response appmessage.Message) *appmessage.RPCError) error {
+
+	c.subscriptionsLock.Lock()
+	defer c.subscriptionsLock.Unlock()
+
+	response, err := c.call(request, responseCommand)
+	if err != nil {
+		return err
+	}
+	if rpcError := responseError(response); rpcError != nil {
+		return c.convertRPCError(rpcError)
+	}
+
+	remainingSubscriptions := make([]*subscription, 0, len(c.subscriptions))
+	for _, s := range c.subscriptions {
+		if s.name != name {
+			remainingSubscriptions = append(remainingSubscriptions, s)
+			continue
+		}
+		s.stopNotificationHandlers()
+	}
+	c.subscriptions = remainingSubscriptions
+	return nil
+}
+
+// issueSubscription starts the notification handlers of the given subscription
+// on the given router, and sends its request through it.
+//
+// This function MUST be called with subscriptionsLock held
+func (c *connection) issueSubscription(ctx context.Context, rpcRouter *rpcRouter, s *subscription) error {
+	stops := make([]func(), 0, len(s.notificationHandlers))
+	for command, onNotification := range s.notificationHandlers {
+		stops = append(stops, startNotificationHandler(rpcRouter, s.name, command, onNotification))
+	}
+	stopNotificationHandlers := func() {
+		for _, stop := range stops {
+			stop()
+		}
+	}
+
+	response, err := rpcRouter.post(ctx, s.request(), s.responseCommand)
+	if err != nil {
+		stopNotificationHandlers()
+		return err
+	}
+	if rpcError := s.responseError(response); rpcError != nil {
+		stopNotificationHandlers()
+		return c.convertRPCError(rpcError)
+	}
+	s.rpcRouter = rpcRouter
+	s.stopNotificationHandlers = stopNotificationHandlers
+	return nil
+}
+
+// resubscribe re-issues the subscriptions that weren't issued through the
+// current connection. Subscriptions to notifications that can no longer be
+// replayed, as happens when the RPC server restarts, are re-issued without
+// replaying the notifications that were missed, and the OnNotificationsLost
+// handler is called so that the state derived from them is resynchronized
+func (c *connection) resubscribe() {
+	c.subscriptionsLock.Lock()
+	defer c.subscriptionsLock.Unlock()
+
+	for _, s := range c.subscriptions {
+		err := c.reissueSubscription(s)
+		if err != nil {
+			log.Warnf("Could not re-issue the %s subscription: %s", s.name, err)
+		}
+	}
+}
+
+func (c *connection) reissueSubscription(s *subscription) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.getTimeout())
+	defer cancel()
+
+	rpcRouter, err := c.waitForConnection(ctx)
+	if err != nil {
+		return err
+	}
+	if s.rpcRouter == rpcRouter {
+		return nil
+	}
+	err = c.issueSubscription(ctx, rpcRouter, s)
+	if err == nil || s.sequenceNumbers == nil || !s.sequenceNumbers.isResuming() || !errors.Is(err, ErrRPC) {
+		return err
+	}
+
+	log.Warnf("Could not resume the %s subscription: %s. Notifications that were sent "+
+		"while disconnected are lost", s.name, err)
+	s.sequenceNumbers.reset()
+	err = c.issueSubscription(ctx, rpcRouter, s)
+	if err != nil {
+		return err
+	}
+	c.notifyNotificationsLost(s.name)
+	return nil
+}
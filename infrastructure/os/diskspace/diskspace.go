@@ -0,0 +1,24 @@
+package diskspace
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// DirectorySize returns the total size in bytes of the regular files under the given path
+func DirectorySize(path string) (uint64, error) {
+	var size uint64
+	err := filepath.Walk(path, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.Mode().IsRegular() {
+			size += uint64(info.Size())
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return size, nil
+}
@@ -0,0 +1,16 @@
+// +build !windows,!plan9
+
+package diskspace
+
+import "syscall"
+
+// FreeSpace returns the number of bytes available to unprivileged users on the
+// filesystem that contains the given path
+func FreeSpace(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	err := syscall.Statfs(path, &stat)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
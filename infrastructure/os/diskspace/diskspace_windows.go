@@ -0,0 +1,24 @@
+package diskspace
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var getDiskFreeSpaceExProc = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+// FreeSpace returns the number of bytes available to the current user on the
+// volume that contains the given path
+func FreeSpace(path string) (uint64, error) {
+	pathPointer, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	var freeBytesAvailable uint64
+	result, _, err := getDiskFreeSpaceExProc.Call(uintptr(unsafe.Pointer(pathPointer)),
+		uintptr(unsafe.Pointer(&freeBytesAvailable)), 0, 0)
+	if result == 0 {
+		return 0, err
+	}
+	return freeBytesAvailable, nil
+}
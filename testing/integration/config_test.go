@@ -18,6 +18,9 @@ const (
 	rpcAddress2 = "127.0.0.1:12346"
 	rpcAddress3 = "127.0.0.1:12347"
 
+	httpRPCAddress1 = "127.0.0.1:12348"
+	jsonRPCAddress1 = "127.0.0.1:12349"
+
 	miningAddress1           = "kaspasim:qzpj2cfa9m40w9m2cmr8pvfuqpp32mzzwsuw6ukhfd"
 	miningAddress1PrivateKey = "be9e9884f03e687166479e22d21b064db7903d69b5a46878aae66521c01a6094"
 
@@ -35,7 +38,14 @@ func setConfig(t *testing.T, harness *appHarness) {
 	harness.config.DataDir = randomDirectory(t)
 	harness.config.Listeners = []string{harness.p2pAddress}
 	harness.config.RPCListeners = []string{harness.rpcAddress}
+	if harness.httpRPCAddress != "" {
+		harness.config.RPCHTTPListeners = []string{harness.httpRPCAddress}
+	}
+	if harness.jsonRPCAddress != "" {
+		harness.config.JSONRPCListeners = []string{harness.jsonRPCAddress}
+	}
 	harness.config.UTXOIndex = harness.utxoIndex
+	harness.config.Dandelion = harness.dandelion
 
 	if harness.overrideDAGParams != nil {
 		harness.config.ActiveNetParams = harness.overrideDAGParams
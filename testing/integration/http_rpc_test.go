@@ -0,0 +1,151 @@
+package integration
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"golang.org/x/net/websocket"
+)
+
+func TestHTTPRPC(t *testing.T) {
+	harness, teardown := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		httpRPCAddress:          httpRPCAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+	})
+	defer teardown()
+
+	baseURL := "http://" + httpRPCAddress1
+
+	response, err := http.Post(baseURL+"/getBlockDagInfo", "application/json", strings.NewReader("{}"))
+	if err != nil {
+		t.Fatalf("Post: %s", err)
+	}
+	body := readResponseBody(t, response)
+	if response.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status %d, but got %d: %s", http.StatusOK, response.StatusCode, body)
+	}
+	var blockDAGInfo struct {
+		NetworkName string `json:"networkName"`
+	}
+	err = json.Unmarshal(body, &blockDAGInfo)
+	if err != nil {
+		t.Fatalf("Unmarshal: %s", err)
+	}
+	if blockDAGInfo.NetworkName != harness.config.NetParams().Name {
+		t.Fatalf("Expected network name %s, but got %s", harness.config.NetParams().Name, blockDAGInfo.NetworkName)
+	}
+
+	badRequests := []struct {
+		name           string
+		method         string
+		path           string
+		contentType    string
+		expectedStatus int
+	}{
+		{"unknown endpoint", http.MethodPost, "/noSuchRequest", "application/json", http.StatusNotFound},
+		{"notification endpoint", http.MethodPost, "/notifyBlockAdded", "application/json", http.StatusNotFound},
+		{"GET request", http.MethodGet, "/getBlockDagInfo", "", http.StatusMethodNotAllowed},
+		{"non-JSON content type", http.MethodPost, "/getBlockDagInfo", "text/plain", http.StatusUnsupportedMediaType},
+	}
+	for _, badRequest := range badRequests {
+		request, err := http.NewRequest(badRequest.method, baseURL+badRequest.path, bytes.NewReader([]byte("{}")))
+		if err != nil {
+			t.Fatalf("NewRequest: %s", err)
+		}
+		if badRequest.contentType != "" {
+			request.Header.Set("Content-Type", badRequest.contentType)
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatalf("Do: %s", err)
+		}
+		readResponseBody(t, response)
+		if response.StatusCode != badRequest.expectedStatus {
+			t.Fatalf("%s: expected status %d, but got %d", badRequest.name, badRequest.expectedStatus, response.StatusCode)
+		}
+	}
+}
+
+func TestHTTPRPCWebSocketNotifications(t *testing.T) {
+	harness, teardown := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		httpRPCAddress:          httpRPCAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+	})
+	defer teardown()
+
+	conn, err := websocket.Dial("ws://"+httpRPCAddress1+"/ws", "", "http://"+httpRPCAddress1)
+	if err != nil {
+		t.Fatalf("Dial: %s", err)
+	}
+	defer conn.Close()
+
+	err = websocket.Message.Send(conn, `{"notifyBlockAddedRequest": {}}`)
+	if err != nil {
+		t.Fatalf("Send: %s", err)
+	}
+	message := receiveWebSocketMessage(t, conn)
+	if _, ok := message["notifyBlockAddedResponse"]; !ok {
+		t.Fatalf("Expected a notifyBlockAddedResponse, but got %v", message)
+	}
+
+	block := mineNextBlock(t, harness)
+
+	message = receiveWebSocketMessage(t, conn)
+	notification, ok := message["blockAddedNotification"]
+	if !ok {
+		t.Fatalf("Expected a blockAddedNotification, but got %v", message)
+	}
+	var blockAddedNotification struct {
+		BlockVerboseData struct {
+			Hash string `json:"hash"`
+		} `json:"blockVerboseData"`
+	}
+	err = json.Unmarshal(notification, &blockAddedNotification)
+	if err != nil {
+		t.Fatalf("Unmarshal: %s", err)
+	}
+	expectedHash := consensushashing.BlockHash(block).String()
+	if blockAddedNotification.BlockVerboseData.Hash != expectedHash {
+		t.Fatalf("Expected a notification for block %s, but got one for %s",
+			expectedHash, blockAddedNotification.BlockVerboseData.Hash)
+	}
+}
+
+func readResponseBody(t *testing.T, response *http.Response) []byte {
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.Fatalf("ReadAll: %s", err)
+	}
+	return body
+}
+
+func receiveWebSocketMessage(t *testing.T, conn *websocket.Conn) map[string]json.RawMessage {
+	err := conn.SetReadDeadline(time.Now().Add(defaultTimeout))
+	if err != nil {
+		t.Fatalf("SetReadDeadline: %s", err)
+	}
+	var data []byte
+	err = websocket.Message.Receive(conn, &data)
+	if err != nil {
+		t.Fatalf("Receive: %s", err)
+	}
+	message := make(map[string]json.RawMessage)
+	err = json.Unmarshal(data, &message)
+	if err != nil {
+		t.Fatalf("Unmarshal: %s", err)
+	}
+	return message
+}
@@ -0,0 +1,121 @@
+package integration
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type testJSONRPCResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	Result  json.RawMessage `json:"result"`
+	Error   *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+	ID json.RawMessage `json:"id"`
+}
+
+func TestJSONRPC(t *testing.T) {
+	harness, teardown := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		jsonRPCAddress:          jsonRPCAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+	})
+	defer teardown()
+
+	var response testJSONRPCResponse
+	statusCode := postJSONRPC(t, `{"jsonrpc": "2.0", "method": "getBlockDagInfo", "id": 1}`, &response)
+	if statusCode != http.StatusOK {
+		t.Fatalf("Expected status %d, but got %d", http.StatusOK, statusCode)
+	}
+	if response.Error != nil || string(response.ID) != "1" {
+		t.Fatalf("Unexpected response %+v", response)
+	}
+	var blockDAGInfo struct {
+		NetworkName string `json:"networkName"`
+	}
+	err := json.Unmarshal(response.Result, &blockDAGInfo)
+	if err != nil {
+		t.Fatalf("Unmarshal: %s", err)
+	}
+	if blockDAGInfo.NetworkName != harness.config.NetParams().Name {
+		t.Fatalf("Expected network name %s, but got %s", harness.config.NetParams().Name, blockDAGInfo.NetworkName)
+	}
+
+	// An RPC error of the handler should be mapped to an error response
+	response = testJSONRPCResponse{}
+	postJSONRPC(t, `{"jsonrpc": "2.0", "method": "getBlock", "params": [{"hash": "invalid"}], "id": "a"}`, &response)
+	if response.Error == nil || response.Error.Code != -32000 || string(response.ID) != `"a"` {
+		t.Fatalf("Expected an RPC error response, but got %+v", response)
+	}
+
+	var batchResponses []testJSONRPCResponse
+	postJSONRPC(t, `[
+		{"jsonrpc": "2.0", "method": "getBlockCount", "id": 1},
+		{"jsonrpc": "2.0", "method": "getBlockCount"},
+		{"jsonrpc": "2.0", "method": "noSuchMethod", "id": 2},
+		{"jsonrpc": "2.0", "method": "getBlock", "params": "invalid", "id": 3},
+		{"jsonrpc": "1.0", "method": "getBlockCount", "id": 4},
+		5
+	]`, &batchResponses)
+
+	expectedResponses := []struct {
+		id   string
+		code int
+	}{
+		{"1", 0},
+		{"2", -32601},
+		{"3", -32602},
+		{"4", -32600},
+		{"null", -32600},
+	}
+	if len(batchResponses) != len(expectedResponses) {
+		t.Fatalf("Expected %d responses, but got %d", len(expectedResponses), len(batchResponses))
+	}
+	for i, expected := range expectedResponses {
+		batchResponse := batchResponses[i]
+		if string(batchResponse.ID) != expected.id {
+			t.Fatalf("Expected response %d to have id %s, but got %s", i, expected.id, batchResponse.ID)
+		}
+		if expected.code == 0 {
+			if batchResponse.Error != nil {
+				t.Fatalf("Unexpected error in response %d: %+v", i, batchResponse.Error)
+			}
+			continue
+		}
+		if batchResponse.Error == nil || batchResponse.Error.Code != expected.code {
+			t.Fatalf("Expected response %d to have error code %d, but got %+v", i, expected.code, batchResponse.Error)
+		}
+	}
+
+	// Nothing should be responded to a batch of notifications
+	statusCode = postJSONRPC(t, `[{"jsonrpc": "2.0", "method": "getBlockCount"}]`, nil)
+	if statusCode != http.StatusNoContent {
+		t.Fatalf("Expected status %d, but got %d", http.StatusNoContent, statusCode)
+	}
+
+	response = testJSONRPCResponse{}
+	postJSONRPC(t, `{"jsonrpc": "2.0", "method"`, &response)
+	if response.Error == nil || response.Error.Code != -32700 {
+		t.Fatalf("Expected a parse error response, but got %+v", response)
+	}
+}
+
+func postJSONRPC(t *testing.T, body string, response interface{}) int {
+	httpResponse, err := http.Post("http://"+jsonRPCAddress1, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Post: %s", err)
+	}
+	responseBody := readResponseBody(t, httpResponse)
+	if response != nil {
+		err = json.Unmarshal(responseBody, response)
+		if err != nil {
+			t.Fatalf("Unmarshal %s: %s", responseBody, err)
+		}
+	}
+	return httpResponse.StatusCode
+}
@@ -0,0 +1,92 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+)
+
+func TestMempoolTransactionsNotifications(t *testing.T) {
+	harness, teardown := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+		utxoIndex:               true,
+	})
+	defer teardown()
+
+	// skip the first block because it's paying to genesis script,
+	// which contains no outputs. Then mine enough blocks for the
+	// first coinbase UTXO to mature
+	mineNextBlock(t, harness)
+	const blockAmountToMine = 20
+	for i := 0; i < blockAmountToMine; i++ {
+		mineNextBlock(t, harness)
+	}
+	utxosByAddressesResponse, err := harness.rpcClient.GetUTXOsByAddresses([]string{miningAddress1})
+	if err != nil {
+		t.Fatalf("Failed to get UTXOs: %s", err)
+	}
+	oldestEntry := utxosByAddressesResponse.Entries[0]
+	for _, entry := range utxosByAddressesResponse.Entries {
+		if entry.UTXOEntry.BlockBlueScore < oldestEntry.UTXOEntry.BlockBlueScore {
+			oldestEntry = entry
+		}
+	}
+
+	onTransactionAddedChan := make(chan *appmessage.TransactionAddedToMempoolNotificationMessage, 10)
+	onTransactionRemovedChan := make(chan *appmessage.TransactionRemovedFromMempoolNotificationMessage, 10)
+	err = harness.rpcClient.RegisterForMempoolTransactionsNotifications([]string{miningAddress1},
+		func(notification *appmessage.TransactionAddedToMempoolNotificationMessage) {
+			onTransactionAddedChan <- notification
+		},
+		func(notification *appmessage.TransactionRemovedFromMempoolNotificationMessage) {
+			onTransactionRemovedChan <- notification
+		})
+	if err != nil {
+		t.Fatalf("Failed to register for mempool transactions notifications: %s", err)
+	}
+
+	submitTransactionResponse, err := harness.rpcClient.SubmitTransaction(buildTransactionForUTXOIndexTest(t, oldestEntry))
+	if err != nil {
+		t.Fatalf("Error submitting transaction: %s", err)
+	}
+	select {
+	case notification := <-onTransactionAddedChan:
+		if notification.TransactionID != submitTransactionResponse.TransactionID {
+			t.Fatalf("Expected transaction %s to be added to the mempool, but got %s",
+				submitTransactionResponse.TransactionID, notification.TransactionID)
+		}
+		if len(notification.Transaction.Inputs) != 1 ||
+			*notification.Transaction.Inputs[0].PreviousOutpoint != *oldestEntry.Outpoint {
+
+			t.Fatalf("Expected the added transaction to spend %s:%d",
+				oldestEntry.Outpoint.TransactionID, oldestEntry.Outpoint.Index)
+		}
+	case <-time.After(defaultTimeout):
+		t.Fatalf("Timed out waiting for the transaction added to mempool notification")
+	}
+
+	// Mining a block includes the transaction and removes it from the mempool
+	mineNextBlock(t, harness)
+	select {
+	case notification := <-onTransactionRemovedChan:
+		if notification.TransactionID != submitTransactionResponse.TransactionID {
+			t.Fatalf("Expected transaction %s to be removed from the mempool, but got %s",
+				submitTransactionResponse.TransactionID, notification.TransactionID)
+		}
+		if notification.Reason != appmessage.TransactionRemovalReasonMined {
+			t.Fatalf("Expected the transaction to be removed because it was %s, but it was %s",
+				appmessage.TransactionRemovalReasonMined, notification.Reason)
+		}
+	case <-time.After(defaultTimeout):
+		t.Fatalf("Timed out waiting for the transaction removed from mempool notification")
+	}
+
+	err = harness.rpcClient.UnregisterFromMempoolTransactionsNotifications()
+	if err != nil {
+		t.Fatalf("Failed to unregister from mempool transactions notifications: %s", err)
+	}
+}
@@ -0,0 +1,120 @@
+package integration
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+)
+
+func TestBlockAddedNotificationReplay(t *testing.T) {
+	harness, teardown := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+	})
+	defer teardown()
+
+	onBlockAddedChan := make(chan *appmessage.BlockAddedNotificationMessage, 10)
+	setOnBlockAddedHandler(t, harness, func(notification *appmessage.BlockAddedNotificationMessage) {
+		onBlockAddedChan <- notification
+	})
+
+	const blockCount = 3
+	var notifications []*appmessage.BlockAddedNotificationMessage
+	for i := 0; i < blockCount; i++ {
+		mineNextBlock(t, harness)
+		notifications = append(notifications, receiveBlockAddedNotification(t, onBlockAddedChan))
+	}
+	for i := 1; i < len(notifications); i++ {
+		if notifications[i].StreamID != notifications[0].StreamID {
+			t.Fatalf("Expected all notifications to be of stream %s, but got %s",
+				notifications[0].StreamID, notifications[i].StreamID)
+		}
+		if notifications[i].SequenceNumber != notifications[i-1].SequenceNumber+1 {
+			t.Fatalf("Expected consecutive sequence numbers, but got %d after %d",
+				notifications[i].SequenceNumber, notifications[i-1].SequenceNumber)
+		}
+	}
+
+	// A client that resubscribes after missing the last two notifications
+	// should get them before any new notification
+	resubscribingClient, err := newTestRPCClient(harness.rpcAddress)
+	if err != nil {
+		t.Fatalf("Error creating RPC client: %s", err)
+	}
+	defer resubscribingClient.Close()
+
+	replayedChan := make(chan *appmessage.BlockAddedNotificationMessage, 10)
+	err = resubscribingClient.RegisterForBlockAddedNotificationsFromSequenceNumber(notifications[1].StreamID,
+		notifications[1].SequenceNumber, func(notification *appmessage.BlockAddedNotificationMessage) {
+			replayedChan <- notification
+		})
+	if err != nil {
+		t.Fatalf("Error from RegisterForBlockAddedNotificationsFromSequenceNumber: %s", err)
+	}
+
+	newBlock := mineNextBlock(t, harness)
+	expectedHashes := []string{
+		notifications[1].BlockVerboseData.Hash,
+		notifications[2].BlockVerboseData.Hash,
+		consensushashing.BlockHash(newBlock).String(),
+	}
+	for i, expectedHash := range expectedHashes {
+		notification := receiveBlockAddedNotification(t, replayedChan)
+		expectedSequenceNumber := notifications[1].SequenceNumber + uint64(i)
+		if notification.SequenceNumber != expectedSequenceNumber {
+			t.Fatalf("Expected notification with sequence number %d, but got %d",
+				expectedSequenceNumber, notification.SequenceNumber)
+		}
+		if notification.BlockVerboseData.Hash != expectedHash {
+			t.Fatalf("Expected notification %d to be of block %s, but got %s",
+				i, expectedHash, notification.BlockVerboseData.Hash)
+		}
+	}
+
+	// Resubscribing from a sequence number that wasn't reached should fail
+	unreachedClient, err := newTestRPCClient(harness.rpcAddress)
+	if err != nil {
+		t.Fatalf("Error creating RPC client: %s", err)
+	}
+	defer unreachedClient.Close()
+
+	err = unreachedClient.RegisterForBlockAddedNotificationsFromSequenceNumber(notifications[2].StreamID,
+		notifications[2].SequenceNumber+10, func(notification *appmessage.BlockAddedNotificationMessage) {
+			t.Errorf("Unexpected notification with sequence number %d", notification.SequenceNumber)
+		})
+	if err == nil {
+		t.Fatalf("Expected an error when resubscribing from a sequence number that wasn't reached")
+	}
+
+	// Resubscribing to the stream of another run of kaspad should fail, even
+	// if the sequence number is still kept
+	restartedClient, err := newTestRPCClient(harness.rpcAddress)
+	if err != nil {
+		t.Fatalf("Error creating RPC client: %s", err)
+	}
+	defer restartedClient.Close()
+
+	err = restartedClient.RegisterForBlockAddedNotificationsFromSequenceNumber("previous-run",
+		notifications[1].SequenceNumber, func(notification *appmessage.BlockAddedNotificationMessage) {
+			t.Errorf("Unexpected notification with sequence number %d", notification.SequenceNumber)
+		})
+	if err == nil {
+		t.Fatalf("Expected an error when resubscribing to the stream of another run of kaspad")
+	}
+}
+
+func receiveBlockAddedNotification(t *testing.T,
+	onBlockAddedChan chan *appmessage.BlockAddedNotificationMessage) *appmessage.BlockAddedNotificationMessage {
+
+	select {
+	case notification := <-onBlockAddedChan:
+		return notification
+	case <-time.After(defaultTimeout):
+		t.Fatalf("Timed out waiting for a block added notification")
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kaspanet/kaspad/app/appmessage"
+	"github.com/kaspanet/kaspad/domain/consensus/utils/consensushashing"
+	"github.com/kaspanet/kaspad/infrastructure/network/rpcclient"
+	"github.com/pkg/errors"
+)
+
+func TestRPCClientReconnect(t *testing.T) {
+	harness, teardown := setupHarness(t, &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+	})
+	defer teardown()
+
+	rpcClient, err := newTestRPCClient(harness.rpcAddress)
+	if err != nil {
+		t.Fatalf("Error creating RPC client: %s", err)
+	}
+	defer rpcClient.Close()
+
+	connectionStateChan := make(chan rpcclient.ConnectionState, 10)
+	rpcClient.SetOnConnectionStateChangedHandler(func(state rpcclient.ConnectionState) {
+		connectionStateChan <- state
+	})
+
+	disconnectedChan := make(chan struct{}, 10)
+	rpcClient.SetOnDisconnectedHandler(func() {
+		disconnectedChan <- struct{}{}
+	})
+
+	notificationsLostChan := make(chan string, 10)
+	rpcClient.SetOnNotificationsLostHandler(func(subscriptionName string) {
+		notificationsLostChan <- subscriptionName
+	})
+
+	onBlockAddedChan := make(chan *appmessage.BlockAddedNotificationMessage, 10)
+	err = rpcClient.RegisterForBlockAddedNotifications(func(notification *appmessage.BlockAddedNotificationMessage) {
+		onBlockAddedChan <- notification
+	})
+	if err != nil {
+		t.Fatalf("Error from RegisterForBlockAddedNotifications: %s", err)
+	}
+
+	mineNextBlock(t, harness)
+	receiveBlockAddedNotification(t, onBlockAddedChan)
+
+	// The harness's own client reconnects as well, and is used to mine
+	harnessConnectionStateChan := make(chan rpcclient.ConnectionState, 10)
+	harness.rpcClient.SetOnConnectionStateChangedHandler(func(state rpcclient.ConnectionState) {
+		harnessConnectionStateChan <- state
+	})
+
+	restartHarness(t, harness)
+	receiveConnectionState(t, connectionStateChan, rpcclient.ConnectionStateDisconnected)
+	receiveConnectionState(t, connectionStateChan, rpcclient.ConnectionStateConnected)
+	receiveConnectionState(t, harnessConnectionStateChan, rpcclient.ConnectionStateDisconnected)
+	receiveConnectionState(t, harnessConnectionStateChan, rpcclient.ConnectionStateConnected)
+	select {
+	case <-disconnectedChan:
+	case <-time.After(defaultTimeout):
+		t.Fatalf("Timed out waiting for the disconnected handler to be called")
+	}
+
+	// The restarted kaspad can't replay the notifications of its previous run,
+	// so the registration should have been re-issued without replaying them
+	select {
+	case subscriptionName := <-notificationsLostChan:
+		if subscriptionName != "RegisterForBlockAddedNotifications" {
+			t.Fatalf("Expected the notifications of RegisterForBlockAddedNotifications to be lost, "+
+				"but got %s", subscriptionName)
+		}
+	case <-time.After(defaultTimeout):
+		t.Fatalf("Timed out waiting for the notifications lost handler to be called")
+	}
+
+	// The registration should have been re-issued through the new connection
+	block := mineNextBlock(t, harness)
+	notification := receiveBlockAddedNotification(t, onBlockAddedChan)
+	blockHash := consensushashing.BlockHash(block).String()
+	if notification.BlockVerboseData.Hash != blockHash {
+		t.Fatalf("Expected a notification of block %s, but got %s", blockHash, notification.BlockVerboseData.Hash)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = rpcClient.WithContext(ctx).GetBlockCount()
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected a call with a canceled context to fail with context.Canceled, but got: %v", err)
+	}
+
+	rpcClient.Close()
+	receiveConnectionState(t, connectionStateChan, rpcclient.ConnectionStateClosed)
+	_, err = rpcClient.GetBlockCount()
+	if !errors.Is(err, rpcclient.ErrClientClosed) {
+		t.Fatalf("Expected a call after Close to fail with ErrClientClosed, but got: %v", err)
+	}
+}
+
+// restartHarness stops the harness's kaspad and starts it again with the same
+// config and data directory
+func restartHarness(t *testing.T, harness *appHarness) {
+	harness.app.Stop()
+	err := harness.database.Close()
+	if err != nil {
+		t.Fatalf("Error closing database context: %+v", err)
+	}
+
+	setDatabaseContext(t, harness)
+	setApp(t, harness)
+	harness.app.Start()
+}
+
+func receiveConnectionState(t *testing.T, connectionStateChan chan rpcclient.ConnectionState,
+	expectedState rpcclient.ConnectionState) {
+
+	select {
+	case state := <-connectionStateChan:
+		if state != expectedState {
+			t.Fatalf("Expected connection state %s, but got %s", expectedState, state)
+		}
+	case <-time.After(defaultTimeout):
+		t.Fatalf("Timed out waiting for connection state %s", expectedState)
+	}
+}
@@ -18,20 +18,26 @@ type appHarness struct {
 	rpcClient               *testRPCClient
 	p2pAddress              string
 	rpcAddress              string
+	httpRPCAddress          string
+	jsonRPCAddress          string
 	miningAddress           string
 	miningAddressPrivateKey string
 	config                  *config.Config
 	database                database.Database
 	utxoIndex               bool
+	dandelion               bool
 	overrideDAGParams       *dagconfig.Params
 }
 
 type harnessParams struct {
 	p2pAddress              string
 	rpcAddress              string
+	httpRPCAddress          string
+	jsonRPCAddress          string
 	miningAddress           string
 	miningAddressPrivateKey string
 	utxoIndex               bool
+	dandelion               bool
 	overrideDAGParams       *dagconfig.Params
 }
 
@@ -40,9 +46,12 @@ func setupHarness(t *testing.T, params *harnessParams) (harness *appHarness, tea
 	harness = &appHarness{
 		p2pAddress:              params.p2pAddress,
 		rpcAddress:              params.rpcAddress,
+		httpRPCAddress:          params.httpRPCAddress,
+		jsonRPCAddress:          params.jsonRPCAddress,
 		miningAddress:           params.miningAddress,
 		miningAddressPrivateKey: params.miningAddressPrivateKey,
 		utxoIndex:               params.utxoIndex,
+		dandelion:               params.dandelion,
 		overrideDAGParams:       params.overrideDAGParams,
 	}
 
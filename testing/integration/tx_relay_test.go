@@ -25,6 +25,48 @@ func TestTxRelay(t *testing.T) {
 	connect(t, payer, mediator)
 	connect(t, mediator, payee)
 
+	testTransactionPropagation(t, payer, payee)
+}
+
+func TestDandelionTxRelay(t *testing.T) {
+	harnessesParams := []*harnessParams{
+		{
+			p2pAddress:              p2pAddress1,
+			rpcAddress:              rpcAddress1,
+			miningAddress:           miningAddress1,
+			miningAddressPrivateKey: miningAddress1PrivateKey,
+			dandelion:               true,
+		},
+		{
+			p2pAddress:              p2pAddress2,
+			rpcAddress:              rpcAddress2,
+			miningAddress:           miningAddress2,
+			miningAddressPrivateKey: miningAddress2PrivateKey,
+			dandelion:               true,
+		},
+		{
+			p2pAddress:              p2pAddress3,
+			rpcAddress:              rpcAddress3,
+			miningAddress:           miningAddress3,
+			miningAddressPrivateKey: miningAddress3PrivateKey,
+			dandelion:               true,
+		},
+	}
+	harnesses, teardown := setupHarnesses(t, harnessesParams)
+	defer teardown()
+	payer, mediator, payee := harnesses[0], harnesses[1], harnesses[2]
+
+	// Connect nodes in chain: payer --> mediator --> payee
+	// Stem peers are chosen among outbound peers only, so the transaction
+	// is passed along the stem from the payer to the mediator, and is then
+	// either fluffed by the mediator or passed along the stem to the payee
+	connect(t, mediator, payer)
+	connect(t, payee, mediator)
+
+	testTransactionPropagation(t, payer, payee)
+}
+
+func testTransactionPropagation(t *testing.T, payer, payee *appHarness) {
 	payeeBlockAddedChan := make(chan *appmessage.MsgBlockHeader)
 	setOnBlockAddedHandler(t, payee, func(notification *appmessage.BlockAddedNotificationMessage) {
 		payeeBlockAddedChan <- &notification.Block.Header
@@ -9,6 +9,7 @@ import (
 	"github.com/kaspanet/kaspad/domain/consensus/utils/transactionid"
 	"github.com/kaspanet/kaspad/domain/consensus/utils/txscript"
 	"github.com/kaspanet/kaspad/util"
+	"os"
 	"testing"
 )
 
@@ -144,6 +145,176 @@ func TestUTXOIndex(t *testing.T) {
 				notificationEntry.UTXOEntry, foundResponseEntry.UTXOEntry)
 		}
 	}
+
+	// Make sure the balance equals the sum of the UTXOs
+	var expectedBalance uint64
+	for _, notificationEntry := range notificationEntries {
+		expectedBalance += notificationEntry.UTXOEntry.Amount
+	}
+	balanceByAddressResponse, err := kaspad.rpcClient.GetBalanceByAddress(miningAddress1)
+	if err != nil {
+		t.Fatalf("Failed to get balance: %s", err)
+	}
+	if balanceByAddressResponse.Balance != expectedBalance {
+		t.Fatalf("Unexpected balance. Want: %d, got: %d", expectedBalance, balanceByAddressResponse.Balance)
+	}
+	balancesByAddressesResponse, err := kaspad.rpcClient.GetBalancesByAddresses([]string{miningAddress1})
+	if err != nil {
+		t.Fatalf("Failed to get balances: %s", err)
+	}
+	if len(balancesByAddressesResponse.Entries) != 1 ||
+		balancesByAddressesResponse.Entries[0].Address != miningAddress1 ||
+		balancesByAddressesResponse.Entries[0].Balance != expectedBalance {
+
+		t.Fatalf("Unexpected balances. Want: %s:%d, got: %+v",
+			miningAddress1, expectedBalance, balancesByAddressesResponse.Entries)
+	}
+
+	// Get all the UTXOs page by page and make sure the pages
+	// together are equivalent to the full response
+	const pageLimit = 7
+	pagedOutpoints := make(map[appmessage.RPCOutpoint]struct{})
+	cursor := ""
+	for {
+		pageResponse, err := kaspad.rpcClient.GetUTXOsByAddressesPage([]string{miningAddress1}, pageLimit, cursor)
+		if err != nil {
+			t.Fatalf("Failed to get a page of UTXOs: %s", err)
+		}
+		if len(pageResponse.Entries) > pageLimit {
+			t.Fatalf("Unexpected amount of UTXOs in a page. Want at most: %d, got: %d",
+				pageLimit, len(pageResponse.Entries))
+		}
+		for _, entry := range pageResponse.Entries {
+			if _, ok := pagedOutpoints[*entry.Outpoint]; ok {
+				t.Fatalf("Entry %s:%d was returned in more than one page",
+					entry.Outpoint.TransactionID, entry.Outpoint.Index)
+			}
+			pagedOutpoints[*entry.Outpoint] = struct{}{}
+		}
+		if pageResponse.NextCursor == "" {
+			break
+		}
+		cursor = pageResponse.NextCursor
+	}
+	if len(pagedOutpoints) != len(utxosByAddressesResponse.Entries) {
+		t.Fatalf("Unexpected amount of paged UTXOs. Want: %d, got: %d",
+			len(utxosByAddressesResponse.Entries), len(pagedOutpoints))
+	}
+}
+
+func TestUTXOIndexIncludingMempool(t *testing.T) {
+	// Setup a single kaspad instance
+	harnessParams := &harnessParams{
+		p2pAddress:              p2pAddress1,
+		rpcAddress:              rpcAddress1,
+		miningAddress:           miningAddress1,
+		miningAddressPrivateKey: miningAddress1PrivateKey,
+		utxoIndex:               true,
+	}
+	kaspad, teardown := setupHarness(t, harnessParams)
+	defer teardown()
+
+	// skip the first block because it's paying to genesis script,
+	// which contains no outputs
+	mineNextBlock(t, kaspad)
+
+	// Register for UTXO changes, including the ones made by mempool transactions.
+	// Enough blocks are mined for the first coinbase UTXOs to mature
+	const blockAmountToMine = 20
+	onUTXOsChangedChan := make(chan *appmessage.UTXOsChangedNotificationMessage, blockAmountToMine)
+	err := kaspad.rpcClient.RegisterForUTXOsChangedNotificationsIncludingMempool([]string{miningAddress1}, func(
+		notification *appmessage.UTXOsChangedNotificationMessage) {
+
+		onUTXOsChangedChan <- notification
+	})
+	if err != nil {
+		t.Fatalf("Failed to register for UTXO change notifications: %s", err)
+	}
+
+	// Mine some blocks and collect their UTXOs
+	for i := 0; i < blockAmountToMine; i++ {
+		mineNextBlock(t, kaspad)
+	}
+	var notificationEntries []*appmessage.UTXOsByAddressesEntry
+	for i := 0; i < blockAmountToMine; i++ {
+		notification := <-onUTXOsChangedChan
+		for _, added := range notification.Added {
+			if added.IsUnconfirmed {
+				t.Fatalf("Unexpectedly received an unconfirmed UTXO")
+			}
+			notificationEntries = append(notificationEntries, added)
+		}
+	}
+
+	// Submit a transaction that spends one of the UTXOs
+	// and make sure the mempool notification is received
+	spentEntry := notificationEntries[0]
+	_, err = kaspad.rpcClient.SubmitTransaction(buildTransactionForUTXOIndexTest(t, spentEntry))
+	if err != nil {
+		t.Fatalf("Error submitting transaction: %s", err)
+	}
+	notification := <-onUTXOsChangedChan
+	if len(notification.Removed) != 1 || *notification.Removed[0].Outpoint != *spentEntry.Outpoint {
+		t.Fatalf("Expected the mempool notification to remove %s:%d, but got %+v",
+			spentEntry.Outpoint.TransactionID, spentEntry.Outpoint.Index, notification.Removed)
+	}
+	if len(notification.Added) != 1 || !notification.Added[0].IsUnconfirmed {
+		t.Fatalf("Expected the mempool notification to add a single unconfirmed UTXO, but got %+v",
+			notification.Added)
+	}
+	unconfirmedEntry := notification.Added[0]
+
+	// The UTXOs without the mempool are not expected to change
+	utxosByAddressesResponse, err := kaspad.rpcClient.GetUTXOsByAddresses([]string{miningAddress1})
+	if err != nil {
+		t.Fatalf("Failed to get UTXOs: %s", err)
+	}
+	if !containsUTXOsByAddressesEntry(utxosByAddressesResponse.Entries, spentEntry) ||
+		containsUTXOsByAddressesEntry(utxosByAddressesResponse.Entries, unconfirmedEntry) {
+
+		t.Fatalf("Expected the UTXOs without the mempool to include only the spent UTXO")
+	}
+
+	// The UTXOs including the mempool are expected to exclude the spent
+	// UTXO and to include the unconfirmed one
+	utxosByAddressesResponse, err = kaspad.rpcClient.GetUTXOsByAddressesIncludingMempool([]string{miningAddress1})
+	if err != nil {
+		t.Fatalf("Failed to get UTXOs: %s", err)
+	}
+	if containsUTXOsByAddressesEntry(utxosByAddressesResponse.Entries, spentEntry) ||
+		!containsUTXOsByAddressesEntry(utxosByAddressesResponse.Entries, unconfirmedEntry) {
+
+		t.Fatalf("Expected the UTXOs including the mempool to include only the unconfirmed UTXO")
+	}
+	for _, entry := range utxosByAddressesResponse.Entries {
+		if entry.IsUnconfirmed != (*entry.Outpoint == *unconfirmedEntry.Outpoint) {
+			t.Fatalf("Unexpected IsUnconfirmed for outpoint %s:%d",
+				entry.Outpoint.TransactionID, entry.Outpoint.Index)
+		}
+	}
+
+	// Dumping the mempool is expected to save the submitted transaction
+	dumpMempoolResponse, err := kaspad.rpcClient.DumpMempool()
+	if err != nil {
+		t.Fatalf("Failed to dump the mempool: %s", err)
+	}
+	if dumpMempoolResponse.TransactionCount != 1 {
+		t.Fatalf("Expected 1 transaction to be dumped, but got %d", dumpMempoolResponse.TransactionCount)
+	}
+	if _, err := os.Stat(dumpMempoolResponse.Path); err != nil {
+		t.Fatalf("Expected the mempool dump file to exist at '%s': %s", dumpMempoolResponse.Path, err)
+	}
+}
+
+func containsUTXOsByAddressesEntry(entries []*appmessage.UTXOsByAddressesEntry,
+	entry *appmessage.UTXOsByAddressesEntry) bool {
+
+	for _, otherEntry := range entries {
+		if *otherEntry.Outpoint == *entry.Outpoint {
+			return true
+		}
+	}
+	return false
 }
 
 func buildTransactionForUTXOIndexTest(t *testing.T, entry *appmessage.UTXOsByAddressesEntry) *appmessage.RPCTransaction {